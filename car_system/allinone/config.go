@@ -0,0 +1,147 @@
+package main
+
+import (
+	"car_system/common/database"
+	"car_system/common/deprecation"
+	"car_system/common/httpserver"
+	"car_system/common/idempotency"
+	"car_system/common/settings"
+	usercontrollers "car_system/user_service/controllers"
+	userserver "car_system/user_service/server"
+	"car_system/vehicle_service/booking"
+	"car_system/vehicle_service/charging"
+	"car_system/vehicle_service/geofence"
+	"car_system/vehicle_service/maintenance"
+	"car_system/vehicle_service/telemetry"
+	"car_system/vehicle_service/trip"
+	"fmt"
+)
+
+// Modes in which the services can be mounted
+const (
+	// modePorts serves every service on its own port, like the standalone binaries
+	modePorts = "ports"
+	// modePrefix serves every service on one port under its own path prefix
+	modePrefix = "prefix"
+)
+
+// Path prefixes of vehicle_service and billing_service in prefix mode.
+// user_service stays at the root so the static pages keep working.
+const (
+	vehiclePrefix = "/vehicle-service"
+	billingPrefix = "/billing-service"
+)
+
+// Config is the shared configuration of the all-in-one process
+type Config struct {
+	Mode             string `env:"ALLINONE_MODE" default:"ports" usage:"ports (one port per service) or prefix (one port, path prefixes)"`
+	UserPort         int    `env:"USER_PORT" default:"8080" usage:"Port of user_service, and of everything in prefix mode"`
+	VehiclePort      int    `env:"VEHICLE_PORT" default:"8081" usage:"Port of vehicle_service in ports mode"`
+	BillingPort      int    `env:"BILLING_PORT" default:"8082" usage:"Port of billing_service in ports mode"`
+	InProcessProxies bool   `env:"IN_PROCESS_PROXIES" usage:"Serve the user_service proxies in-process instead of over HTTP"`
+	LogLevel         string `env:"LOG_LEVEL" default:"info" usage:"Minimum log level (debug, info, warn, error)"`
+
+	StorageBackend string `env:"STORAGE_BACKEND" default:"mysql" usage:"Storage backend (mysql or memory)"`
+	UserDBName     string `env:"USER_DB_NAME" default:"user_service" usage:"Database of user_service"`
+	VehicleDBName  string `env:"VEHICLE_DB_NAME" default:"vehicle_service" usage:"Database of vehicle_service"`
+	BillingDBName  string `env:"BILLING_DB_NAME" default:"billing_service" usage:"Database of billing_service"`
+	AutoMigrate    bool   `env:"DB_AUTO_MIGRATE" default:"true" usage:"Apply pending migrations of every service on startup"`
+
+	SessionSecret      string   `env:"SESSION_SECRET" secret:"true" usage:"Key used to sign session cookies"`
+	ServiceToken       string   `env:"SERVICE_TOKEN" secret:"true" usage:"Token user_service sends to vehicle_service with the membership of the users; a random one is used while it is empty"`
+	AdminToken         string   `env:"ADMIN_TOKEN" secret:"true" usage:"Bearer token of the vehicle_service admin routes; they are refused while it is empty"`
+	StaticDir          string   `env:"USER_STATIC_DIR" default:"../user_service/static/" usage:"Directory of the user_service static pages"`
+	CORSAllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS" default:"http://localhost:8080" usage:"Comma-separated origins allowed to call vehicle_service and billing_service"`
+	ValidateRequests   bool     `env:"OPENAPI_VALIDATE_REQUESTS" usage:"Reject requests that do not match the OpenAPI document of each service"`
+	SessionCookie      usercontrollers.SessionCookie
+
+	// DB holds the connection parameters shared by the three databases;
+	// DB_NAME is ignored in favour of the per-service names above
+	DB          database.Settings
+	Pool        database.PoolConfig
+	HTTP        httpserver.Config
+	Legacy      deprecation.Policy
+	Idempotency idempotency.Settings
+	// RateLimits apply to the public user_service routes
+	RateLimits userserver.RateLimits
+	// Booking holds the reservation rules of vehicle_service
+	Booking booking.Settings
+	// Charging holds the charge forecast of vehicle_service
+	Charging charging.Settings
+	// Range holds the range estimates of vehicle_service
+	Range trip.Settings
+	// Telemetry holds the telemetry tokens and retention of vehicle_service
+	Telemetry telemetry.Settings
+	// Geofence holds the return zone policy of vehicle_service
+	Geofence geofence.Settings
+	// Maintenance holds the service interval and work order start of
+	// vehicle_service
+	Maintenance maintenance.Settings
+}
+
+// loadConfig resolves the configuration from defaults, the optional .env
+// file, the environment and the command-line flags in args
+func loadConfig(args []string) (Config, []string, error) {
+	var cfg Config
+	rest, err := settings.Load("allinone", &cfg, args)
+	return cfg, rest, err
+}
+
+// Validate checks the resolved configuration
+func (c *Config) Validate() error {
+	if c.Mode != modePorts && c.Mode != modePrefix {
+		return fmt.Errorf("ALLINONE_MODE must be %q or %q, got %q", modePorts, modePrefix, c.Mode)
+	}
+	if c.SessionSecret == "" {
+		return fmt.Errorf("SESSION_SECRET is required")
+	}
+	if err := c.SessionCookie.Validate(); err != nil {
+		return err
+	}
+	if _, err := c.Booking.Policy(); err != nil {
+		return err
+	}
+	if _, err := c.Charging.Forecaster(); err != nil {
+		return err
+	}
+	if _, err := c.Range.Estimator(); err != nil {
+		return err
+	}
+	if err := c.Telemetry.Validate(); err != nil {
+		return err
+	}
+	if _, err := c.Geofence.Policy(); err != nil {
+		return err
+	}
+	if err := c.Maintenance.Validate(); err != nil {
+		return err
+	}
+	switch c.StorageBackend {
+	case "memory":
+		return nil
+	case "mysql":
+		return c.dbSettings(c.UserDBName).Validate()
+	default:
+		return fmt.Errorf("STORAGE_BACKEND must be mysql or memory, got %q", c.StorageBackend)
+	}
+}
+
+// dbSettings returns the connection parameters of one service database
+func (c Config) dbSettings(dbName string) database.Settings {
+	s := c.DB
+	s.Name = dbName
+	return s
+}
+
+// upstreamURLs returns the base URLs used by the user_service proxies. With
+// in-process proxies only the path matters, so no prefix is added.
+func (c Config) upstreamURLs() (vehicle, billing string) {
+	if c.InProcessProxies {
+		return "http://vehicle_service", "http://billing_service"
+	}
+	if c.Mode == modePrefix {
+		base := fmt.Sprintf("http://localhost:%d", c.UserPort)
+		return base + vehiclePrefix, base + billingPrefix
+	}
+	return fmt.Sprintf("http://localhost:%d", c.VehiclePort), fmt.Sprintf("http://localhost:%d", c.BillingPort)
+}
@@ -0,0 +1,106 @@
+// Command allinone runs user_service, vehicle_service and billing_service in a
+// single process, either on their usual ports or on one port with path
+// prefixes. The standalone binaries are unaffected.
+package main
+
+import (
+	"car_system/common/httpserver"
+	"car_system/common/logging"
+	"car_system/common/settings"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+func main() {
+	// A single optional .env file holds the shared configuration
+	cfg, args, err := loadConfig(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// Print the resolved configuration with secrets redacted
+	if len(args) > 0 && args[0] == "config" {
+		settings.Print(os.Stdout, &cfg)
+		return
+	}
+
+	// Set up structured logging
+	logger := logging.New("allinone", cfg.LogLevel)
+	slog.SetDefault(logger)
+	logger.Info("Configuration loaded", "config", settings.Map(&cfg))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	svcs, err := buildServices(ctx, cfg)
+	if err != nil {
+		logger.Error("Failed to start services", "error", err)
+		os.Exit(1)
+	}
+	defer svcs.Close()
+
+	var servers []*http.Server
+	if cfg.Mode == modePrefix {
+		servers = append(servers, httpserver.New(fmt.Sprintf(":%d", cfg.UserPort), svcs.mount(), cfg.HTTP))
+	} else {
+		servers = append(servers,
+			httpserver.New(fmt.Sprintf(":%d", cfg.UserPort), svcs.user, cfg.HTTP),
+			httpserver.New(fmt.Sprintf(":%d", cfg.VehiclePort), svcs.vehicle, cfg.HTTP),
+			httpserver.New(fmt.Sprintf(":%d", cfg.BillingPort), svcs.billing, cfg.HTTP),
+		)
+	}
+
+	logger.Info("All-in-one running",
+		"mode", cfg.Mode,
+		"storage", cfg.StorageBackend,
+		"in_process_proxies", cfg.InProcessProxies,
+	)
+	if err := runAll(ctx, servers, cfg.HTTP.ShutdownTimeout); err != nil {
+		logger.Error("Server stopped", "error", err)
+		return
+	}
+	logger.Info("All-in-one shut down gracefully")
+}
+
+// runAll serves every server until ctx is cancelled or one of them fails, then
+// shuts all of them down
+func runAll(ctx context.Context, servers []*http.Server, shutdownTimeout time.Duration) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for _, srv := range servers {
+		wg.Add(1)
+		go func(srv *http.Server) {
+			defer wg.Done()
+			slog.Info("Listening", "addr", srv.Addr)
+			if err := httpserver.Run(ctx, srv, shutdownTimeout); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("%s: %w", srv.Addr, err)
+				}
+				mu.Unlock()
+				cancel()
+			}
+		}(srv)
+	}
+	wg.Wait()
+	return firstErr
+}
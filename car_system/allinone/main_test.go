@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPrefixModeWithInProcessProxies(t *testing.T) {
+	svcs, err := buildServices(context.Background(), Config{
+		Mode:             modePrefix,
+		UserPort:         1, // never dialled: the proxies run in-process
+		InProcessProxies: true,
+		StorageBackend:   "memory",
+		SessionSecret:    "allinone-test-secret",
+		LogLevel:         "error",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer svcs.Close()
+
+	srv := httptest.NewServer(svcs.mount())
+	defer srv.Close()
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar}
+	post := func(path string, payload interface{}) *http.Response {
+		t.Helper()
+		body, _ := json.Marshal(payload)
+		resp, err := client.Post(srv.URL+path, "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	user := map[string]string{
+		"name":     "Alice Tan",
+		"email":    "alice@example.com",
+		"phone_no": "91234567",
+		"password": "s3cret-pass",
+		"dob":      "1995-04-01",
+	}
+	if resp := post("/v1/users", user); resp.StatusCode != http.StatusOK {
+		t.Fatalf("register: got %d", resp.StatusCode)
+	}
+	if resp := post("/v1/sessions", map[string]string{"email": user["email"], "password": user["password"]}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("login: got %d", resp.StatusCode)
+	}
+
+	resp, err := client.Get(srv.URL + "/v1/vehicles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Vehicles []interface{} `json:"vehicles"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != http.StatusOK || len(body.Vehicles) != 5 {
+		t.Fatalf("vehicles: got %d with %d vehicles, want 200 with 5", resp.StatusCode, len(body.Vehicles))
+	}
+
+	// vehicle_service is also reachable directly under its prefix
+	resp, err = client.Get(srv.URL + vehiclePrefix + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("%s/healthz: got %d, want 200", vehiclePrefix, resp.StatusCode)
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	billingmigrations "car_system/billing_service/migrations"
+	billingmodels "car_system/billing_service/models"
+	billingserver "car_system/billing_service/server"
+	"car_system/common/database"
+	"car_system/common/inprocess"
+	"car_system/common/logging"
+	"car_system/common/metrics"
+	"car_system/common/migrate"
+	usercontrollers "car_system/user_service/controllers"
+	usermigrations "car_system/user_service/migrations"
+	usermodels "car_system/user_service/models"
+	userserver "car_system/user_service/server"
+	vehiclemigrations "car_system/vehicle_service/migrations"
+	vehiclemodels "car_system/vehicle_service/models"
+	vehicleserver "car_system/vehicle_service/server"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	_ "github.com/go-sql-driver/mysql" // MySQL driver
+)
+
+// services holds the handlers of the three services and the databases they use
+type services struct {
+	user    http.Handler
+	vehicle http.Handler
+	billing http.Handler
+	dbs     []*sql.DB
+}
+
+// Close releases the database connections
+func (s *services) Close() {
+	for _, db := range s.dbs {
+		db.Close()
+	}
+}
+
+// buildServices wires the storage of every service and builds their handlers.
+// vehicle_service and billing_service are built first so that their handlers
+// can back the user_service proxies in-process.
+func buildServices(ctx context.Context, cfg Config) (*services, error) {
+	s := &services{}
+
+	// Both ends of the service token run here, so any token will do
+	if cfg.ServiceToken == "" {
+		token := make([]byte, 32)
+		rand.Read(token)
+		cfg.ServiceToken = hex.EncodeToString(token)
+	}
+
+	vehicleDB, err := s.openDB(ctx, cfg, cfg.VehicleDBName, vehiclemigrations.New)
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("vehicle_service: %w", err)
+	}
+	if vehicleDB == nil {
+		store := vehiclemodels.NewMemoryStore()
+		store.SeedSampleFleet()
+		vehiclemodels.UseRepositories(store.Repositories())
+	} else {
+		vehiclemodels.UseRepositories(vehiclemodels.NewMySQLRepositories(vehicleDB))
+	}
+	bookingPolicy, err := cfg.Booking.Policy()
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("vehicle_service: %w", err)
+	}
+	forecaster, err := cfg.Charging.Forecaster()
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("vehicle_service: %w", err)
+	}
+	estimator, err := cfg.Range.Estimator()
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("vehicle_service: %w", err)
+	}
+	zonePolicy, err := cfg.Geofence.Policy()
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("vehicle_service: %w", err)
+	}
+	vehicleLogger := logging.New("vehicle_service", cfg.LogLevel)
+	s.vehicle = vehicleserver.NewHandler(vehicleserver.Options{
+		Logger:           vehicleLogger,
+		Metrics:          newMetrics("vehicle_service", vehicleDB, cfg.VehicleDBName),
+		DB:               vehicleDB,
+		CORSOrigins:      cfg.CORSAllowedOrigins,
+		ValidateRequests: cfg.ValidateRequests,
+		Legacy:           cfg.Legacy,
+		Idempotency:      cfg.Idempotency,
+		Booking:          bookingPolicy,
+		Charging:         forecaster,
+		Range:            estimator,
+		Telemetry:        cfg.Telemetry,
+		Geofence:         zonePolicy,
+		Maintenance:      cfg.Maintenance,
+		ServiceToken:     cfg.ServiceToken,
+		AdminToken:       cfg.AdminToken,
+	})
+	// Downsample and delete expired telemetry until the process stops
+	go cfg.Telemetry.RunCompaction(ctx, vehicleLogger)
+	// Start the work orders whose planned window has opened
+	go cfg.Maintenance.RunSync(ctx, vehicleLogger)
+
+	billingDB, err := s.openDB(ctx, cfg, cfg.BillingDBName, billingmigrations.New)
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("billing_service: %w", err)
+	}
+	if billingDB == nil {
+		billingmodels.UseRepositories(billingmodels.NewMemoryRepositories())
+	} else {
+		billingmodels.UseRepositories(billingmodels.NewMySQLRepositories(billingDB))
+	}
+	s.billing = billingserver.NewHandler(billingserver.Options{
+		Logger:           logging.New("billing_service", cfg.LogLevel),
+		Metrics:          newMetrics("billing_service", billingDB, cfg.BillingDBName),
+		DB:               billingDB,
+		CORSOrigins:      cfg.CORSAllowedOrigins,
+		ValidateRequests: cfg.ValidateRequests,
+		Legacy:           cfg.Legacy,
+		Idempotency:      cfg.Idempotency,
+	})
+
+	userDB, err := s.openDB(ctx, cfg, cfg.UserDBName, usermigrations.New)
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("user_service: %w", err)
+	}
+	if userDB == nil {
+		usermodels.UseRepositories(usermodels.NewMemoryRepositories())
+	} else {
+		usermodels.UseRepositories(usermodels.NewMySQLRepositories(userDB))
+	}
+
+	usercontrollers.UseSessionSecret(cfg.SessionSecret)
+	usercontrollers.UseSessionCookie(cfg.SessionCookie)
+	usercontrollers.VehicleServiceURL, usercontrollers.BillingServiceURL = cfg.upstreamURLs()
+	usercontrollers.UseServiceToken(cfg.ServiceToken)
+
+	userOpts := userserver.Options{
+		Logger:           logging.New("user_service", cfg.LogLevel),
+		Metrics:          newMetrics("user_service", userDB, cfg.UserDBName),
+		DB:               userDB,
+		StaticDir:        cfg.StaticDir,
+		ValidateRequests: cfg.ValidateRequests,
+		Legacy:           cfg.Legacy,
+		Idempotency:      cfg.Idempotency,
+		RateLimits:       cfg.RateLimits,
+	}
+	if cfg.InProcessProxies {
+		userOpts.VehicleTransport = inprocess.Transport(s.vehicle)
+		userOpts.BillingTransport = inprocess.Transport(s.billing)
+	}
+	s.user = userserver.NewHandler(userOpts)
+
+	return s, nil
+}
+
+// openDB connects to one service database and brings its schema up to date.
+// It returns a nil DB when the in-memory backend is selected.
+func (s *services) openDB(ctx context.Context, cfg Config, dbName string, newMigrator func(*sql.DB) (*migrate.Migrator, error)) (*sql.DB, error) {
+	if cfg.StorageBackend == "memory" {
+		return nil, nil
+	}
+
+	db, err := sql.Open("mysql", cfg.dbSettings(dbName).DSN())
+	if err != nil {
+		return nil, err
+	}
+	s.dbs = append(s.dbs, db)
+
+	database.ConfigurePool(db, cfg.Pool)
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", dbName, err)
+	}
+
+	if cfg.AutoMigrate {
+		migrator, err := newMigrator(db)
+		if err != nil {
+			return nil, err
+		}
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("migrating %s: %w", dbName, err)
+		}
+		slog.Info("Database schema is up to date", "database", dbName, "applied", applied)
+	}
+	return db, nil
+}
+
+// newMetrics creates the registry of one service, including its pool statistics
+func newMetrics(service string, db *sql.DB, dbName string) *metrics.Metrics {
+	m := metrics.New(service)
+	if db != nil {
+		m.RegisterDB(db, dbName)
+	}
+	return m
+}
+
+// mount returns the handler serving all three services on one port, with
+// vehicle_service and billing_service under their path prefixes
+func (s *services) mount() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(vehiclePrefix+"/", http.StripPrefix(vehiclePrefix, s.vehicle))
+	mux.Handle(billingPrefix+"/", http.StripPrefix(billingPrefix, s.billing))
+	mux.Handle("/", s.user)
+	return mux
+}
@@ -0,0 +1,578 @@
+// Package client provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+// Defines values for BillingRequestStatus.
+const (
+	Paid     BillingRequestStatus = "Paid"
+	Pending  BillingRequestStatus = "Pending"
+	Refunded BillingRequestStatus = "Refunded"
+)
+
+// BillingRequest defines model for BillingRequest.
+type BillingRequest struct {
+	Amount        float64              `json:"amount"`
+	PromoId       *int                 `json:"promo_id"`
+	ReservationId int                  `json:"reservation_id"`
+	Status        BillingRequestStatus `json:"status"`
+	UserId        int                  `json:"user_id"`
+}
+
+// BillingRequestStatus defines model for BillingRequest.Status.
+type BillingRequestStatus string
+
+// CalculateRentalFeeRequest defines model for CalculateRentalFeeRequest.
+type CalculateRentalFeeRequest struct {
+	EndTime time.Time `json:"end_time"`
+
+	// OneWayFee Fee of a one-way trip, added to the rental fee
+	OneWayFee *float64 `json:"one_way_fee,omitempty"`
+
+	// OutOfZoneFee Fee of a return outside the allowed return zone, added to the rental fee
+	OutOfZoneFee  *float64  `json:"out_of_zone_fee,omitempty"`
+	RentalRate    float64   `json:"rental_rate"`
+	ReservationId *int      `json:"reservation_id,omitempty"`
+	StartTime     time.Time `json:"start_time"`
+}
+
+// Error defines model for Error.
+type Error struct {
+	Code      string        `json:"code"`
+	Fields    *[]FieldError `json:"fields,omitempty"`
+	Message   string        `json:"message"`
+	RequestId *string       `json:"request_id,omitempty"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Message defines model for Message.
+type Message struct {
+	Message string `json:"message"`
+}
+
+// RentalFee defines model for RentalFee.
+type RentalFee struct {
+	Message      string  `json:"message"`
+	OneWayFee    float64 `json:"one_way_fee"`
+	OutOfZoneFee float64 `json:"out_of_zone_fee"`
+
+	// RentalFee Rental rate times the duration in hours
+	RentalFee float64 `json:"rental_fee"`
+
+	// TotalFee Sum of the rental, one-way and out-of-zone fees
+	TotalFee float64 `json:"total_fee"`
+}
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// CreateBillParams defines parameters for CreateBill.
+type CreateBillParams struct {
+	// IdempotencyKey Unique key of the request. Retries with the same key and body replay the first response with an Idempotent-Replayed: true header; reusing the key for a different request returns 409.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// CalculateRentalFeeParams defines parameters for CalculateRentalFee.
+type CalculateRentalFeeParams struct {
+	// IdempotencyKey Unique key of the request. Retries with the same key and body replay the first response with an Idempotent-Replayed: true header; reusing the key for a different request returns 409.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// CreateBillJSONRequestBody defines body for CreateBill for application/json ContentType.
+type CreateBillJSONRequestBody = BillingRequest
+
+// CalculateRentalFeeJSONRequestBody defines body for CalculateRentalFee for application/json ContentType.
+type CalculateRentalFeeJSONRequestBody = CalculateRentalFeeRequest
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// CreateBillWithBody request with any body
+	CreateBillWithBody(ctx context.Context, params *CreateBillParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CreateBill(ctx context.Context, params *CreateBillParams, body CreateBillJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CalculateRentalFeeWithBody request with any body
+	CalculateRentalFeeWithBody(ctx context.Context, params *CalculateRentalFeeParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CalculateRentalFee(ctx context.Context, params *CalculateRentalFeeParams, body CalculateRentalFeeJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) CreateBillWithBody(ctx context.Context, params *CreateBillParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateBillRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateBill(ctx context.Context, params *CreateBillParams, body CreateBillJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateBillRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CalculateRentalFeeWithBody(ctx context.Context, params *CalculateRentalFeeParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCalculateRentalFeeRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CalculateRentalFee(ctx context.Context, params *CalculateRentalFeeParams, body CalculateRentalFeeJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCalculateRentalFeeRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewCreateBillRequest calls the generic CreateBill builder with application/json body
+func NewCreateBillRequest(server string, params *CreateBillParams, body CreateBillJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCreateBillRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewCreateBillRequestWithBody generates requests for CreateBill with any type of body
+func NewCreateBillRequestWithBody(server string, params *CreateBillParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/bills")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.IdempotencyKey != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, *params.IdempotencyKey)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Idempotency-Key", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewCalculateRentalFeeRequest calls the generic CalculateRentalFee builder with application/json body
+func NewCalculateRentalFeeRequest(server string, params *CalculateRentalFeeParams, body CalculateRentalFeeJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCalculateRentalFeeRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewCalculateRentalFeeRequestWithBody generates requests for CalculateRentalFee with any type of body
+func NewCalculateRentalFeeRequestWithBody(server string, params *CalculateRentalFeeParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/rental-fees")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.IdempotencyKey != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, *params.IdempotencyKey)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Idempotency-Key", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// CreateBillWithBodyWithResponse request with any body
+	CreateBillWithBodyWithResponse(ctx context.Context, params *CreateBillParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateBillResponse, error)
+
+	CreateBillWithResponse(ctx context.Context, params *CreateBillParams, body CreateBillJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateBillResponse, error)
+
+	// CalculateRentalFeeWithBodyWithResponse request with any body
+	CalculateRentalFeeWithBodyWithResponse(ctx context.Context, params *CalculateRentalFeeParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CalculateRentalFeeResponse, error)
+
+	CalculateRentalFeeWithResponse(ctx context.Context, params *CalculateRentalFeeParams, body CalculateRentalFeeJSONRequestBody, reqEditors ...RequestEditorFn) (*CalculateRentalFeeResponse, error)
+}
+
+type CreateBillResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *Message
+	JSON400      *Error
+	JSON409      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r CreateBillResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CreateBillResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CalculateRentalFeeResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *RentalFee
+	JSON400      *Error
+	JSON409      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r CalculateRentalFeeResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CalculateRentalFeeResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// CreateBillWithBodyWithResponse request with arbitrary body returning *CreateBillResponse
+func (c *ClientWithResponses) CreateBillWithBodyWithResponse(ctx context.Context, params *CreateBillParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateBillResponse, error) {
+	rsp, err := c.CreateBillWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateBillResponse(rsp)
+}
+
+func (c *ClientWithResponses) CreateBillWithResponse(ctx context.Context, params *CreateBillParams, body CreateBillJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateBillResponse, error) {
+	rsp, err := c.CreateBill(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateBillResponse(rsp)
+}
+
+// CalculateRentalFeeWithBodyWithResponse request with arbitrary body returning *CalculateRentalFeeResponse
+func (c *ClientWithResponses) CalculateRentalFeeWithBodyWithResponse(ctx context.Context, params *CalculateRentalFeeParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CalculateRentalFeeResponse, error) {
+	rsp, err := c.CalculateRentalFeeWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCalculateRentalFeeResponse(rsp)
+}
+
+func (c *ClientWithResponses) CalculateRentalFeeWithResponse(ctx context.Context, params *CalculateRentalFeeParams, body CalculateRentalFeeJSONRequestBody, reqEditors ...RequestEditorFn) (*CalculateRentalFeeResponse, error) {
+	rsp, err := c.CalculateRentalFee(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCalculateRentalFeeResponse(rsp)
+}
+
+// ParseCreateBillResponse parses an HTTP response from a CreateBillWithResponse call
+func ParseCreateBillResponse(rsp *http.Response) (*CreateBillResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CreateBillResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest Message
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCalculateRentalFeeResponse parses an HTTP response from a CalculateRentalFeeWithResponse call
+func ParseCalculateRentalFeeResponse(rsp *http.Response) (*CalculateRentalFeeResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CalculateRentalFeeResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest RentalFee
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"car_system/common/apierror"
+	"net/http"
+)
+
+// Error codes returned by billing_service in addition to the shared apierror codes
+const (
+	CodeInvalidTimeRange = "INVALID_TIME_RANGE"
+	CodeBillExists       = "BILL_ALREADY_EXISTS"
+)
+
+var (
+	errInvalidTimeRange = apierror.New(http.StatusBadRequest, CodeInvalidTimeRange, "End time must be after start time")
+	errBillExists       = apierror.New(http.StatusConflict, CodeBillExists, "A bill has already been recorded for this reservation")
+)
@@ -2,58 +2,78 @@ package controllers
 
 import (
 	"car_system/billing_service/models"
+	"car_system/common/apierror"
+	"car_system/common/logging"
 	"encoding/json"
-	"log"
+	"errors"
 	"net/http"
 	"time"
 )
 
-// CalculateRentalFee calculates the total rental fee based on reservation details
+// CalculateRentalFee calculates the total rental fee based on reservation
+// details: the rental rate for the duration, plus the fee of a one-way trip
+// and the fee of a return outside the allowed return zone
 func CalculateRentalFee(w http.ResponseWriter, r *http.Request) {
 	var request struct {
 		ReservationID int     `json:"reservation_id"`
 		StartTime     string  `json:"start_time"`
 		EndTime       string  `json:"end_time"`
 		RentalRate    float64 `json:"rental_rate"`
+		OneWayFee     float64 `json:"one_way_fee"`
+		OutOfZoneFee  float64 `json:"out_of_zone_fee"`
 	}
 
 	// Decode the request payload
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, `{"message":"Invalid request payload"}`, http.StatusBadRequest)
+		apierror.Write(w, r, apierror.ErrInvalidJSON)
 		return
 	}
 
 	// Parse start and end times
+	var v apierror.Validation
 	startTime, err := time.Parse(time.RFC3339, request.StartTime)
-	if err != nil {
-		http.Error(w, `{"message":"Invalid start time format"}`, http.StatusBadRequest)
-		return
-	}
+	v.Check(err == nil, "start_time", "must be an RFC 3339 timestamp")
 	endTime, err := time.Parse(time.RFC3339, request.EndTime)
-	if err != nil {
-		http.Error(w, `{"message":"Invalid end time format"}`, http.StatusBadRequest)
+	v.Check(err == nil, "end_time", "must be an RFC 3339 timestamp")
+	v.Check(request.RentalRate >= 0, "rental_rate", "must not be negative")
+	v.Check(request.OneWayFee >= 0, "one_way_fee", "must not be negative")
+	v.Check(request.OutOfZoneFee >= 0, "out_of_zone_fee", "must not be negative")
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
 	// Calculate duration in hours
 	duration := endTime.Sub(startTime).Hours()
 	if duration <= 0 {
-		http.Error(w, `{"message":"Invalid time range"}`, http.StatusBadRequest)
+		apierror.Write(w, r, errInvalidTimeRange)
 		return
 	}
 
 	// Calculate total fee
-	totalFee := duration * request.RentalRate
-	log.Printf("CalculateRentalFee: Duration: %.2f hours, Rate: %.2f, Total Fee: %.2f", duration, request.RentalRate, totalFee)
+	rentalFee := duration * request.RentalRate
+	totalFee := rentalFee + request.OneWayFee + request.OutOfZoneFee
+	logging.FromContext(r.Context()).Info("Rental fee calculated",
+		"reservation_id", request.ReservationID,
+		"duration_hours", duration,
+		"rental_rate", request.RentalRate,
+		"one_way_fee", request.OneWayFee,
+		"out_of_zone_fee", request.OutOfZoneFee,
+		"total_fee", totalFee,
+	)
+
+	feesCalculatedTotal.Inc()
+	feesCalculatedAmount.Observe(totalFee)
 
 	// Respond with the calculated fee
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message":   "Rental fee calculated successfully",
-		"total_fee": totalFee,
+		"message":         "Rental fee calculated successfully",
+		"rental_fee":      rentalFee,
+		"one_way_fee":     request.OneWayFee,
+		"out_of_zone_fee": request.OutOfZoneFee,
+		"total_fee":       totalFee,
 	})
-
-	log.Printf("Received payload for fee calculation: %+v", request)
 }
 
 // InsertBillingHandler handles inserting a new billing record
@@ -68,13 +88,18 @@ func InsertBillingHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Parse JSON request body
 	if err := json.NewDecoder(r.Body).Decode(&billingRequest); err != nil {
-		http.Error(w, `{"message":"Invalid request payload"}`, http.StatusBadRequest)
+		apierror.Write(w, r, apierror.ErrInvalidJSON)
 		return
 	}
 
 	// Validate required fields
-	if billingRequest.UserID == 0 || billingRequest.ReservationID == 0 || billingRequest.Amount <= 0 || billingRequest.Status == "" {
-		http.Error(w, `{"message":"Missing or invalid fields in request"}`, http.StatusBadRequest)
+	var v apierror.Validation
+	v.Check(billingRequest.UserID > 0, "user_id", "is required")
+	v.Check(billingRequest.ReservationID > 0, "reservation_id", "is required")
+	v.Check(billingRequest.Amount > 0, "amount", "must be greater than 0")
+	v.Check(models.IsValidBillingStatus(billingRequest.Status), "status", "must be Pending, Paid or Refunded")
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -87,11 +112,19 @@ func InsertBillingHandler(w http.ResponseWriter, r *http.Request) {
 		Status:        billingRequest.Status,
 	}
 
-	// Insert into the database
-	if err := models.InsertBilling(&billing); err != nil {
-		http.Error(w, `{"message":"Failed to insert billing record"}`, http.StatusInternalServerError)
+	// Insert into the database; a reservation is billed at most once
+	err := models.InsertBilling(&billing)
+	if errors.Is(err, models.ErrDuplicateBill) {
+		apierror.Write(w, r, errBillExists)
 		return
 	}
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error inserting billing record", "reservation_id", billing.ReservationID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to insert billing record"))
+		return
+	}
+
+	billsInsertedTotal.WithLabelValues(billing.Status).Inc()
 
 	// Respond with success
 	w.Header().Set("Content-Type", "application/json")
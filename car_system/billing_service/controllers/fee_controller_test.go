@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"bytes"
+	"car_system/billing_service/models"
+	"car_system/common/apierror"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCalculateRentalFee(t *testing.T) {
+	models.UseRepositories(models.NewMemoryRepositories())
+
+	body := `{"reservation_id":1,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T13:30:00Z","rental_rate":40}`
+	rec := httptest.NewRecorder()
+	CalculateRentalFee(rec, httptest.NewRequest("POST", "/calculate-rental-fee", bytes.NewBufferString(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		TotalFee float64 `json:"total_fee"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if math.Abs(resp.TotalFee-140) > 1e-9 {
+		t.Errorf("total_fee = %v, want 140", resp.TotalFee)
+	}
+
+	// A one-way trip adds its fee to the rental
+	body = `{"reservation_id":2,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z","rental_rate":40,"one_way_fee":11.39}`
+	rec = httptest.NewRecorder()
+	CalculateRentalFee(rec, httptest.NewRequest("POST", "/v1/rental-fees", bytes.NewBufferString(body)))
+	var oneWay struct {
+		RentalFee float64 `json:"rental_fee"`
+		OneWayFee float64 `json:"one_way_fee"`
+		TotalFee  float64 `json:"total_fee"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &oneWay)
+	if rec.Code != http.StatusOK || oneWay.RentalFee != 80 || oneWay.OneWayFee != 11.39 || math.Abs(oneWay.TotalFee-91.39) > 1e-9 {
+		t.Errorf("one-way trip: got %d %s, want 80 + 11.39", rec.Code, rec.Body.String())
+	}
+
+	// So does a return outside the allowed return zone
+	body = `{"reservation_id":3,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z","rental_rate":40,"out_of_zone_fee":50}`
+	rec = httptest.NewRecorder()
+	CalculateRentalFee(rec, httptest.NewRequest("POST", "/v1/rental-fees", bytes.NewBufferString(body)))
+	var outOfZone struct {
+		OutOfZoneFee float64 `json:"out_of_zone_fee"`
+		TotalFee     float64 `json:"total_fee"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &outOfZone)
+	if rec.Code != http.StatusOK || outOfZone.OutOfZoneFee != 50 || outOfZone.TotalFee != 130 {
+		t.Errorf("return outside the zone: got %d %s, want 80 + 50", rec.Code, rec.Body.String())
+	}
+
+	for name, body := range map[string]string{
+		"bad start":      `{"start_time":"tomorrow","end_time":"2030-01-01T13:30:00Z","rental_rate":40}`,
+		"reversed range": `{"start_time":"2030-01-01T13:30:00Z","end_time":"2030-01-01T10:00:00Z","rental_rate":40}`,
+		"negative fee":   `{"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T13:30:00Z","rental_rate":40,"one_way_fee":-5}`,
+		"negative zone":  `{"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T13:30:00Z","rental_rate":40,"out_of_zone_fee":-5}`,
+		"not json":       `{`,
+	} {
+		rec := httptest.NewRecorder()
+		CalculateRentalFee(rec, httptest.NewRequest("POST", "/calculate-rental-fee", bytes.NewBufferString(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400", name, rec.Code)
+		}
+	}
+}
+
+func TestInsertBillingHandler(t *testing.T) {
+	memory := models.NewMemoryStore()
+	models.UseRepositories(memory.Repositories())
+
+	body := `{"user_id":7,"reservation_id":3,"amount":140,"status":"Pending"}`
+	rec := httptest.NewRecorder()
+	InsertBillingHandler(rec, httptest.NewRequest("POST", "/billing", bytes.NewBufferString(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	bills := memory.Bills()
+	if len(bills) != 1 || bills[0].ReservationID != 3 || bills[0].Amount != 140 {
+		t.Errorf("stored bills = %+v", bills)
+	}
+
+	rec = httptest.NewRecorder()
+	InsertBillingHandler(rec, httptest.NewRequest("POST", "/billing", bytes.NewBufferString(body)))
+	if rec.Code != http.StatusConflict || len(memory.Bills()) != 1 {
+		t.Fatalf("second bill for the reservation: got %d with %d bills stored, want 409 and 1", rec.Code, len(memory.Bills()))
+	}
+
+	rec = httptest.NewRecorder()
+	InsertBillingHandler(rec, httptest.NewRequest("POST", "/billing", bytes.NewBufferString(`{"user_id":7,"amount":140,"status":"Unknown"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid fields: got %d, want 400", rec.Code)
+	}
+	var apiErr apierror.Error
+	json.Unmarshal(rec.Body.Bytes(), &apiErr)
+	if apiErr.Code != apierror.CodeValidationFailed || len(apiErr.Fields) != 2 {
+		t.Errorf("invalid fields: got %+v, want VALIDATION_FAILED for reservation_id and status", apiErr)
+	}
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// feesCalculatedTotal counts successful rental fee calculations
+	feesCalculatedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "billing_fees_calculated_total",
+		Help: "Number of rental fees calculated.",
+	})
+
+	// feesCalculatedAmount tracks the distribution of calculated fees
+	feesCalculatedAmount = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "billing_fee_amount",
+		Help:    "Distribution of calculated rental fees.",
+		Buckets: []float64{10, 25, 50, 100, 200, 500, 1000, 2500},
+	})
+
+	// billsInsertedTotal counts billing records inserted by their status
+	billsInsertedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "billing_bills_inserted_total",
+		Help: "Billing records inserted by status (Pending, Paid, Refunded).",
+	}, []string{"status"})
+)
+
+// RegisterMetrics registers the billing_service domain metrics
+func RegisterMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(feesCalculatedTotal, feesCalculatedAmount, billsInsertedTotal)
+}
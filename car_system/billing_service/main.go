@@ -2,38 +2,120 @@ package main
 
 import (
 	"car_system/billing_service/config"
-	"car_system/billing_service/controllers"
-	"log"
-	"net/http"
-
-	"github.com/gorilla/handlers"
-	"github.com/gorilla/mux"
+	"car_system/billing_service/migrations"
+	"car_system/billing_service/models"
+	"car_system/billing_service/server"
+	"car_system/common/httpserver"
+	"car_system/common/logging"
+	"car_system/common/metrics"
+	"car_system/common/migrate"
+	"car_system/common/settings"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
-	// Connect to the database
-	config.ConnectDB()
-	defer config.DB.Close()
-
-	// Set up router
-	router := mux.NewRouter()
-
-	// Define API routes
-	router.HandleFunc("/calculate-rental-fee", controllers.CalculateRentalFee).Methods("POST")
-	router.HandleFunc("/billing", controllers.InsertBillingHandler).Methods("POST")
-
-	// Serve static files if needed (adjust directory as per your frontend setup)
-	staticDir := "./static/" // Directory where your static files are located
-	router.PathPrefix("/").Handler(http.StripPrefix("/", http.FileServer(http.Dir(staticDir))))
-
-	// Enable CORS for cross-origin requests
-	cors := handlers.CORS(
-		handlers.AllowedOrigins([]string{"http://localhost:8080"}), // Frontend origin
-		handlers.AllowedMethods([]string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
-		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
-		handlers.AllowCredentials(),
-	)
-
-	log.Println("Billing service running on port 8082...")
-	log.Fatal(http.ListenAndServe(":8082", cors(router)))
+	// Resolve the configuration from defaults, .env, the environment and flags
+	cfg, args, err := config.Load(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// Print the resolved configuration with secrets redacted
+	if len(args) > 0 && args[0] == "config" {
+		settings.Print(os.Stdout, &cfg)
+		return
+	}
+
+	// Set up structured logging
+	logger := logging.New("billing_service", cfg.LogLevel)
+	slog.SetDefault(logger)
+	logger.Info("Configuration loaded", "config", settings.Map(&cfg))
+
+	// Select the storage backend: MySQL by default, in-memory with STORAGE_BACKEND=memory
+	if cfg.StorageBackend == "memory" {
+		logger.Info("Using in-memory storage")
+		models.UseRepositories(models.NewMemoryRepositories())
+	} else {
+		// Connect to the database
+		config.ConnectDB(cfg)
+		defer config.DB.Close()
+
+		if !prepareDatabase(logger, cfg, args) {
+			return
+		}
+		models.UseRepositories(models.NewMySQLRepositories(config.DB))
+	}
+
+	// Expose request, billing and connection pool metrics
+	appMetrics := metrics.New("billing_service")
+	if config.DB != nil {
+		appMetrics.RegisterDB(config.DB, cfg.DB.Name)
+	}
+
+	// Set up the router and middleware
+	handler := server.NewHandler(server.Options{
+		Logger:           logger,
+		Metrics:          appMetrics,
+		DB:               config.DB,
+		StaticDir:        cfg.StaticDir,
+		CORSOrigins:      cfg.CORSAllowedOrigins,
+		ValidateRequests: cfg.ValidateRequests,
+		Legacy:           cfg.Legacy,
+		Idempotency:      cfg.Idempotency,
+	})
+
+	// Start the server and drain in-flight requests on SIGINT/SIGTERM
+	srv := httpserver.New(fmt.Sprintf(":%d", cfg.Port), handler, cfg.HTTP)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("Billing service running", "port", cfg.Port)
+	if err := httpserver.Run(ctx, srv, cfg.HTTP.ShutdownTimeout); err != nil {
+		logger.Error("Server stopped", "error", err)
+		return
+	}
+	logger.Info("Billing service shut down gracefully")
+}
+
+// prepareDatabase runs a migrate subcommand (up, down, status, seed, force) or
+// applies pending migrations. It returns false when the service should not
+// start serving requests.
+func prepareDatabase(logger *slog.Logger, cfg config.Config, args []string) bool {
+	migrator, err := migrations.New(config.DB)
+	if err != nil {
+		logger.Error("Failed to load migrations", "error", err)
+		return false
+	}
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := migrate.RunCommand(context.Background(), migrator, args[1:], os.Stdout); err != nil {
+			logger.Error("Migration command failed", "error", err)
+			config.DB.Close()
+			os.Exit(1)
+		}
+		return false
+	}
+
+	// Bring the schema up to date before serving requests
+	if cfg.AutoMigrate {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			logger.Error("Failed to apply migrations", "error", err)
+			return false
+		}
+		logger.Info("Database schema is up to date", "applied", applied)
+	}
+	return true
 }
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS Promotion;
+DROP TABLE IF EXISTS Billing;
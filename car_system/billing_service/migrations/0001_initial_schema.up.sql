@@ -0,0 +1,23 @@
+-- Baseline of the billing_service schema. IF NOT EXISTS lets databases created
+-- from the old schema.sql adopt the migration history without data loss.
+
+-- Billing Table
+CREATE TABLE IF NOT EXISTS Billing (
+    bill_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    reservation_id INT NOT NULL,
+    promo_id INT DEFAULT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    status ENUM('Pending', 'Paid', 'Refunded') NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Promotion Table
+CREATE TABLE IF NOT EXISTS Promotion (
+    promo_id SERIAL PRIMARY KEY,
+    code VARCHAR(50) UNIQUE NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    discount_rate DECIMAL(5, 2) NOT NULL,
+    valid_from TIMESTAMP NOT NULL,
+    valid_to TIMESTAMP NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE Billing DROP INDEX uq_billing_reservation;
@@ -0,0 +1,4 @@
+-- A reservation is billed at most once. Databases that already hold several
+-- bills for one reservation must be cleaned up before this migration applies:
+--   SELECT reservation_id, COUNT(*) FROM Billing GROUP BY reservation_id HAVING COUNT(*) > 1;
+ALTER TABLE Billing ADD CONSTRAINT uq_billing_reservation UNIQUE (reservation_id);
@@ -0,0 +1,19 @@
+-- Sample data for local development. Apply with: go run . migrate seed
+
+-- Promotion Data
+INSERT IGNORE INTO Promotion (code, description, discount_rate, valid_from, valid_to)
+VALUES
+('HOLIDAY10', '10% off during holiday season', 10.00, '2024-12-01 00:00:00', '2024-12-31 23:59:59'),
+('NEWYEAR20', '20% off for New Year', 20.00, '2024-12-25 00:00:00', '2025-01-05 23:59:59'),
+('WEEKEND5', '5% off on weekends', 5.00, '2024-01-01 00:00:00', '2024-12-31 23:59:59'),
+('VIP25', '25% discount for VIP members', 25.00, '2024-01-01 00:00:00', '2024-12-31 23:59:59'),
+('SUMMER15', '15% off during summer', 15.00, '2024-06-01 00:00:00', '2024-08-31 23:59:59');
+
+-- Billing Data
+INSERT INTO Billing (user_id, reservation_id, promo_id, amount, status)
+VALUES
+(101, 1, 1, 180.00, 'Paid'),
+(102, 2, 2, 192.00, 'Paid'),
+(103, 3, NULL, 200.00, 'Pending'),
+(104, 4, 3, 261.25, 'Refunded'),
+(105, 5, 4, 123.75, 'Paid');
@@ -1,7 +1,6 @@
 package models
 
 import (
-	"car_system/billing_service/config"
 	"time"
 )
 
@@ -16,12 +15,15 @@ type Billing struct {
 	CreatedAt     time.Time `json:"created_at"`
 }
 
+// validBillingStatuses mirrors the ENUM of the Billing.status column
+var validBillingStatuses = map[string]bool{"Pending": true, "Paid": true, "Refunded": true}
+
+// IsValidBillingStatus reports whether status is one of Pending, Paid or Refunded
+func IsValidBillingStatus(status string) bool {
+	return validBillingStatuses[status]
+}
+
 // InsertBilling inserts a new billing record into the database
 func InsertBilling(billing *Billing) error {
-	query := `
-		INSERT INTO Billing (user_id, reservation_id, promo_id, amount, status)
-		VALUES (?, ?, ?, ?, ?)
-	`
-	_, err := config.DB.Exec(query, billing.UserID, billing.ReservationID, billing.PromoID, billing.Amount, billing.Status)
-	return err
+	return repos.Billing.Insert(billing)
 }
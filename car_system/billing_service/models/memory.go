@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory backend for local development and tests
+type MemoryStore struct {
+	mu     sync.RWMutex
+	nextID int
+	bills  []Billing
+}
+
+// NewMemoryStore returns an empty store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{nextID: 1}
+}
+
+// NewMemoryRepositories returns repositories backed by a new MemoryStore
+func NewMemoryRepositories() Repositories {
+	return NewMemoryStore().Repositories()
+}
+
+// Repositories returns the repository views over the store
+func (s *MemoryStore) Repositories() Repositories {
+	return Repositories{
+		Billing: memoryBillingRepository{s},
+	}
+}
+
+// Bills returns a copy of every stored billing record
+func (s *MemoryStore) Bills() []Billing {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Billing(nil), s.bills...)
+}
+
+type memoryBillingRepository struct {
+	s *MemoryStore
+}
+
+func (r memoryBillingRepository) Insert(billing *Billing) error {
+	if !IsValidBillingStatus(billing.Status) {
+		return fmt.Errorf("invalid billing status %q", billing.Status)
+	}
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, existing := range r.s.bills {
+		if existing.ReservationID == billing.ReservationID {
+			return ErrDuplicateBill
+		}
+	}
+	billing.BillID = r.s.nextID
+	r.s.nextID++
+	billing.CreatedAt = time.Now().UTC()
+	r.s.bills = append(r.s.bills, *billing)
+	return nil
+}
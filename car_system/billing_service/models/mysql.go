@@ -0,0 +1,45 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// errDuplicateEntry is the MySQL error number of a unique key violation
+const errDuplicateEntry = 1062
+
+// NewMySQLRepositories returns repositories backed by the billing_service database
+func NewMySQLRepositories(db *sql.DB) Repositories {
+	return Repositories{
+		Billing: &mysqlBillingRepository{db: db},
+	}
+}
+
+type mysqlBillingRepository struct {
+	db *sql.DB
+}
+
+func (r *mysqlBillingRepository) Insert(billing *Billing) error {
+	query := `
+		INSERT INTO Billing (user_id, reservation_id, promo_id, amount, status)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, billing.UserID, billing.ReservationID, billing.PromoID, billing.Amount, billing.Status)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry {
+		return ErrDuplicateBill
+	}
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	billing.BillID = int(id)
+	billing.CreatedAt = time.Now().UTC()
+	return nil
+}
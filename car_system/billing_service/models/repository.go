@@ -0,0 +1,26 @@
+package models
+
+import "errors"
+
+// ErrDuplicateBill is returned by Insert when the reservation already has a bill
+var ErrDuplicateBill = errors.New("reservation already has a bill")
+
+// BillingRepository stores billing records
+type BillingRepository interface {
+	// Insert stores billing and sets its BillID and CreatedAt. It returns
+	// ErrDuplicateBill when the reservation already has a bill.
+	Insert(billing *Billing) error
+}
+
+// Repositories groups the storage backends used by the model functions
+type Repositories struct {
+	Billing BillingRepository
+}
+
+// repos is the storage backend selected at startup with UseRepositories
+var repos Repositories
+
+// UseRepositories selects the storage backend used by the model functions
+func UseRepositories(r Repositories) {
+	repos = r
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"car_system/billing_service/api"
+	"car_system/billing_service/controllers"
+	"car_system/common/apispec"
+	"car_system/common/deprecation"
+	"car_system/common/health"
+	"car_system/common/idempotency"
+	"car_system/common/logging"
+	"car_system/common/metrics"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+)
+
+// spec is the OpenAPI document of billing_service, served at /openapi.json
+var spec = apispec.MustLoad(api.Spec)
+
+// Options holds the dependencies of a billing_service instance
+type Options struct {
+	Logger  *slog.Logger
+	Metrics *metrics.Metrics
+	// DB is nil when the service runs on the in-memory backend
+	DB        *sql.DB
+	StaticDir string
+	// CORSOrigins are the browser origins allowed to call the API
+	CORSOrigins []string
+	// ValidateRequests rejects requests that do not match api/openapi.json
+	ValidateRequests bool
+	// Legacy holds the dates announced by the unversioned legacy routes
+	Legacy deprecation.Policy
+	// Idempotency configures the replay of requests retried with the same
+	// Idempotency-Key; the keys are stored in DB when it is set
+	Idempotency idempotency.Settings
+}
+
+// NewHandler builds the billing_service router wrapped in the CORS, logging
+// and metrics middleware
+func NewHandler(opts Options) http.Handler {
+	controllers.RegisterMetrics(opts.Metrics.Registerer)
+	router := newRouter(opts)
+
+	// Enable CORS for cross-origin requests
+	cors := handlers.CORS(
+		handlers.AllowedOrigins(opts.CORSOrigins),
+		handlers.AllowedMethods([]string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
+		handlers.AllowedHeaders([]string{"Content-Type", "Authorization", idempotency.KeyHeader}),
+		handlers.AllowCredentials(),
+	)
+
+	var handler http.Handler = router
+	if opts.ValidateRequests {
+		handler = spec.ValidateRequests(handler)
+	}
+	handler = idempotency.Middleware(idempotency.Options{
+		Store: idempotency.NewStore(opts.DB),
+		TTL:   opts.Idempotency.TTL,
+	})(handler)
+
+	return logging.Middleware(opts.Logger)(opts.Metrics.InstrumentRouter(router, cors(handler)))
+}
+
+// newRouter registers the API, probe, metrics and static routes
+func newRouter(opts Options) *mux.Router {
+	// Set up router
+	router := mux.NewRouter()
+
+	// Define API routes
+	router.HandleFunc("/v1/rental-fees", controllers.CalculateRentalFee).Methods("POST")
+	router.HandleFunc("/v1/bills", controllers.InsertBillingHandler).Methods("POST")
+
+	// Legacy aliases of the routes above, kept until the sunset date
+	legacy := deprecation.New(opts.Metrics.Registerer, opts.Legacy)
+	legacy.Handle(router, "/calculate-rental-fee", "/v1/rental-fees", controllers.CalculateRentalFee).Methods("POST")
+	legacy.Handle(router, "/billing", "/v1/bills", controllers.InsertBillingHandler).Methods("POST")
+
+	// OpenAPI document
+	router.Handle("/openapi.json", spec.Handler()).Methods("GET")
+
+	// Prometheus metrics
+	router.Handle("/metrics", opts.Metrics.Handler()).Methods("GET")
+
+	// Liveness and readiness probes
+	checker := health.New("billing_service", 2*time.Second)
+	if opts.DB != nil {
+		checker.Add("database", health.DBCheck(opts.DB))
+	}
+	router.Handle("/healthz", checker.LiveHandler()).Methods("GET")
+	router.Handle("/readyz", checker.ReadyHandler()).Methods("GET")
+
+	// Serve static files
+	router.PathPrefix("/").Handler(http.StripPrefix("/", http.FileServer(http.Dir(opts.StaticDir))))
+
+	return router
+}
@@ -0,0 +1,15 @@
+package server
+
+import (
+	"car_system/common/metrics"
+	"testing"
+)
+
+// TestRoutesMatchSpec fails when a route is added without documenting it in
+// api/openapi.json, or the other way round
+func TestRoutesMatchSpec(t *testing.T) {
+	router := newRouter(Options{Metrics: metrics.New("billing_service")})
+	for _, problem := range spec.Diff(router, "/metrics", "/healthz", "/readyz", "/openapi.json") {
+		t.Error(problem)
+	}
+}
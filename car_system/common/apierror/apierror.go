@@ -0,0 +1,146 @@
+// Package apierror defines the JSON error envelope shared by every service.
+//
+// An error response always has the form
+//
+//	{
+//	  "code": "VEHICLE_UNAVAILABLE",
+//	  "message": "Vehicle not available for the selected time range",
+//	  "fields": [{"field": "end_time", "message": "must be after start_time"}],
+//	  "request_id": "4f2c9a1be07d3e55"
+//	}
+//
+// where code is stable and meant for programs, message is meant for people,
+// fields is only present for validation errors and request_id matches the
+// X-Request-ID response header.
+package apierror
+
+import (
+	"car_system/common/logging"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Codes shared by all services. Services define their own domain codes next
+// to their handlers.
+const (
+	CodeInvalidRequest      = "INVALID_REQUEST"
+	CodeValidationFailed    = "VALIDATION_FAILED"
+	CodeUnauthorized        = "UNAUTHORIZED"
+	CodeNotFound            = "NOT_FOUND"
+	CodeMethodNotAllowed    = "METHOD_NOT_ALLOWED"
+	CodeConflict            = "CONFLICT"
+	CodeInternal            = "INTERNAL_ERROR"
+	CodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+)
+
+// FieldError describes why one request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is an API error and its HTTP status
+type Error struct {
+	Status    int          `json:"-"`
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// New creates an error with the given status, code and message
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// WithField returns a copy of e with one more field error
+func (e *Error) WithField(field, message string) *Error {
+	copied := *e
+	copied.Fields = append(append([]FieldError(nil), e.Fields...), FieldError{Field: field, Message: message})
+	return &copied
+}
+
+// Common errors. Handlers may use them as they are or with a more specific message.
+var (
+	ErrInvalidJSON  = New(http.StatusBadRequest, CodeInvalidRequest, "Invalid request payload")
+	ErrUnauthorized = New(http.StatusUnauthorized, CodeUnauthorized, "Unauthorized access. Please log in again.")
+	ErrInternal     = New(http.StatusInternalServerError, CodeInternal, "Internal server error")
+)
+
+// Validation collects field errors before they are written as one
+// VALIDATION_FAILED response
+type Validation struct {
+	fields []FieldError
+}
+
+// Add records a field error
+func (v *Validation) Add(field, message string) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: message})
+}
+
+// Check records a field error when ok is false
+func (v *Validation) Check(ok bool, field, message string) {
+	if !ok {
+		v.Add(field, message)
+	}
+}
+
+// Err returns the VALIDATION_FAILED error, or nil when no field was rejected
+func (v *Validation) Err() *Error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &Error{
+		Status:  http.StatusBadRequest,
+		Code:    CodeValidationFailed,
+		Message: "One or more fields are invalid",
+		Fields:  v.fields,
+	}
+}
+
+// Write sends e as a JSON response tagged with the ID of the current request
+func Write(w http.ResponseWriter, r *http.Request, e *Error) {
+	body := *e
+	body.RequestID = logging.RequestID(r.Context())
+	if body.Status == 0 {
+		body.Status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(body.Status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// Handler returns an http.Handler that always writes e, e.g. for a router's
+// NotFoundHandler or MethodNotAllowedHandler
+func Handler(e *Error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, r, e)
+	})
+}
+
+// Decode reads an error envelope from an upstream response so that it can be
+// passed on unchanged. Bodies that are not envelopes are reported as a 502
+// UPSTREAM_UNAVAILABLE error.
+func Decode(resp *http.Response) *Error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	return Parse(resp.StatusCode, body)
+}
+
+// Parse is Decode for a response body that has already been read, e.g. by a
+// generated API client
+func Parse(status int, body []byte) *Error {
+	var e Error
+	if json.Unmarshal(body, &e) != nil || e.Code == "" {
+		return New(http.StatusBadGateway, CodeUpstreamUnavailable, fmt.Sprintf("Upstream service returned status %d", status))
+	}
+	e.Status = status
+	return &e
+}
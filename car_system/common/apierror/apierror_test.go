@@ -0,0 +1,61 @@
+package apierror
+
+import (
+	"car_system/common/logging"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteIncludesRequestID(t *testing.T) {
+	handler := logging.Middleware(logging.NewWithWriter("test", io.Discard, slog.LevelError))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v Validation
+		v.Check(false, "end_time", "must be after start_time")
+		v.Check(true, "start_time", "is required")
+		Write(w, r, v.Err())
+	}))
+
+	req := httptest.NewRequest("POST", "/create-reservation", nil)
+	req.Header.Set(logging.RequestIDHeader, "req-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var body Error
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Code != CodeValidationFailed || body.RequestID != "req-123" {
+		t.Errorf("body = %+v, want VALIDATION_FAILED with request_id req-123", body)
+	}
+	if len(body.Fields) != 1 || body.Fields[0].Field != "end_time" {
+		t.Errorf("fields = %+v, want only end_time", body.Fields)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	envelope := &http.Response{
+		StatusCode: http.StatusConflict,
+		Body:       io.NopCloser(strings.NewReader(`{"code":"VEHICLE_UNAVAILABLE","message":"taken"}`)),
+	}
+	if e := Decode(envelope); e.Status != http.StatusConflict || e.Code != "VEHICLE_UNAVAILABLE" {
+		t.Errorf("Decode(envelope) = %+v", e)
+	}
+
+	plain := &http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Body:       io.NopCloser(strings.NewReader("boom")),
+	}
+	if e := Decode(plain); e.Status != http.StatusBadGateway || e.Code != CodeUpstreamUnavailable {
+		t.Errorf("Decode(plain) = %+v", e)
+	}
+}
@@ -0,0 +1,279 @@
+// Package apispec loads the OpenAPI document of a service, serves it and
+// checks traffic against it so that handlers and spec cannot drift apart.
+//
+// Requests are validated by the ValidateRequests middleware, which answers
+// with a VALIDATION_FAILED error envelope. Responses are checked by
+// ReportResponses, which is meant for tests and reports every response that
+// the spec does not describe.
+package apispec
+
+import (
+	"bytes"
+	"car_system/common/apierror"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/mux"
+)
+
+// ErrUndocumented is returned by ValidateResponse for requests that match no
+// operation of the spec
+var ErrUndocumented = errors.New("apispec: operation is not documented")
+
+// Spec is a loaded and validated OpenAPI 3 document
+type Spec struct {
+	raw    []byte
+	doc    *openapi3.T
+	router routers.Router
+}
+
+// Operation identifies one documented method and path template
+type Operation struct {
+	Method string
+	Path   string
+}
+
+func (o Operation) String() string {
+	return o.Method + " " + o.Path
+}
+
+// Load parses and validates an OpenAPI 3 document
+func Load(data []byte) (*Spec, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("apispec: parsing document: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("apispec: invalid document: %w", err)
+	}
+
+	// Route on paths only: the servers list describes the default local
+	// addresses, not every host a service is reached on
+	routed := *doc
+	routed.Servers = nil
+	router, err := gorillamux.NewRouter(&routed)
+	if err != nil {
+		return nil, fmt.Errorf("apispec: building router: %w", err)
+	}
+	return &Spec{raw: data, doc: doc, router: router}, nil
+}
+
+// MustLoad is Load for embedded documents, which are known at build time
+func MustLoad(data []byte) *Spec {
+	spec, err := Load(data)
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+// Handler serves the document as application/json, e.g. at /openapi.json
+func (s *Spec) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(s.raw)
+	})
+}
+
+// Operations returns every documented operation sorted by path and method
+func (s *Spec) Operations() []Operation {
+	var ops []Operation
+	for path, item := range s.doc.Paths.Map() {
+		for method := range item.Operations() {
+			ops = append(ops, Operation{Method: strings.ToUpper(method), Path: path})
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+		return ops[i].Method < ops[j].Method
+	})
+	return ops
+}
+
+// Diff compares the routes of router with the documented operations and
+// describes every route that is not documented and every operation that is not
+// routed. Routes without a method, such as a static file catch-all, and the
+// paths in ignore are skipped.
+func (s *Spec) Diff(router *mux.Router, ignore ...string) []string {
+	skip := make(map[string]bool)
+	for _, path := range ignore {
+		skip[path] = true
+	}
+
+	routed := make(map[Operation]bool)
+	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		methods, merr := route.GetMethods()
+		if err != nil || merr != nil || skip[path] {
+			return nil
+		}
+		for _, method := range methods {
+			routed[Operation{Method: method, Path: path}] = true
+		}
+		return nil
+	})
+
+	var problems []string
+	documented := make(map[Operation]bool)
+	for _, op := range s.Operations() {
+		documented[op] = true
+		if !routed[op] {
+			problems = append(problems, op.String()+" is documented but not routed")
+		}
+	}
+	for op := range routed {
+		if !documented[op] {
+			problems = append(problems, op.String()+" is routed but not documented")
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// options configures openapi3filter. Authentication is left to the handlers,
+// which report it with their own error codes.
+func options() *openapi3filter.Options {
+	return &openapi3filter.Options{
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		IncludeResponseStatus: true,
+		MultiError:            true,
+	}
+}
+
+// ValidateRequests rejects requests to documented operations whose parameters
+// or body do not match the spec. Undocumented paths, such as /metrics or
+// static files, are passed through unchecked.
+func (s *Spec) ValidateRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, params, err := s.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: params,
+			Route:      route,
+			Options:    options(),
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			apierror.Write(w, r, requestError(err))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ValidateResponse checks a response to r against the spec. It returns
+// ErrUndocumented when r matches no documented operation.
+func (s *Spec) ValidateResponse(ctx context.Context, r *http.Request, status int, header http.Header, body []byte) error {
+	route, params, err := s.router.FindRoute(r)
+	if err != nil {
+		return ErrUndocumented
+	}
+	return openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: params,
+			Route:      route,
+			Options:    options(),
+		},
+		Status:  status,
+		Header:  header,
+		Body:    io.NopCloser(bytes.NewReader(body)),
+		Options: options(),
+	})
+}
+
+// ReportResponses passes each response of a documented operation to report
+// when it does not match the spec. The response is buffered and then sent on
+// unchanged.
+func (s *Spec) ReportResponses(next http.Handler, report func(r *http.Request, err error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		next.ServeHTTP(rec, r)
+
+		err := s.ValidateResponse(r.Context(), r, rec.Code, rec.Header(), rec.Body.Bytes())
+		if err != nil && !errors.Is(err, ErrUndocumented) {
+			report(r, err)
+		}
+
+		for key, values := range rec.Header() {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	})
+}
+
+// requestError converts a validation failure into a VALIDATION_FAILED error
+// with one entry per rejected parameter or body property
+func requestError(err error) *apierror.Error {
+	var v apierror.Validation
+	var errs openapi3.MultiError
+	if !errors.As(err, &errs) {
+		errs = openapi3.MultiError{err}
+	}
+	for _, e := range errs {
+		addFieldErrors(&v, e)
+	}
+	if apiErr := v.Err(); apiErr != nil {
+		return apiErr
+	}
+	return apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Request does not match the API specification")
+}
+
+func addFieldErrors(v *apierror.Validation, err error) {
+	var reqErr *openapi3filter.RequestError
+	if !errors.As(err, &reqErr) {
+		v.Add("request", err.Error())
+		return
+	}
+	if reqErr.Parameter != nil {
+		v.Add(reqErr.Parameter.Name, reason(reqErr))
+		return
+	}
+
+	var schemaErrs openapi3.MultiError
+	if !errors.As(reqErr.Err, &schemaErrs) {
+		schemaErrs = openapi3.MultiError{reqErr.Err}
+	}
+	for _, e := range schemaErrs {
+		var schemaErr *openapi3.SchemaError
+		if errors.As(e, &schemaErr) {
+			field := strings.Join(schemaErr.JSONPointer(), ".")
+			if field == "" {
+				field = "body"
+			}
+			v.Add(field, schemaErr.Reason)
+			continue
+		}
+		v.Add("body", reason(reqErr))
+	}
+}
+
+// reason is the message of a request error without the spec excerpt that
+// kin-openapi appends to schema errors
+func reason(err *openapi3filter.RequestError) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err.Err, &schemaErr) {
+		return schemaErr.Reason
+	}
+	if err.Err != nil {
+		return err.Err.Error()
+	}
+	return err.Reason
+}
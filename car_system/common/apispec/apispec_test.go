@@ -0,0 +1,166 @@
+package apispec
+
+import (
+	"car_system/common/apierror"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+const testSpec = `{
+  "openapi": "3.0.3",
+  "info": { "title": "test", "version": "1.0.0" },
+  "servers": [{ "url": "http://localhost:9999" }],
+  "paths": {
+    "/items": {
+      "post": {
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "type": "object",
+                "required": ["name"],
+                "additionalProperties": false,
+                "properties": { "name": { "type": "string" }, "count": { "type": "integer" } }
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "ok",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object",
+                  "required": ["message"],
+                  "properties": { "message": { "type": "string" } }
+                }
+              }
+            }
+          }
+        }
+      },
+      "get": {
+        "parameters": [{ "name": "limit", "in": "query", "schema": { "type": "integer" } }],
+        "responses": { "200": { "description": "ok" } }
+      }
+    }
+  }
+}`
+
+func TestLoadRejectsInvalidDocument(t *testing.T) {
+	if _, err := Load([]byte(`{"openapi":"3.0.3","paths":{}}`)); err == nil {
+		t.Error("document without info was accepted")
+	}
+	if _, err := Load([]byte(`{`)); err == nil {
+		t.Error("malformed JSON was accepted")
+	}
+}
+
+func TestOperations(t *testing.T) {
+	spec := MustLoad([]byte(testSpec))
+	var got []string
+	for _, op := range spec.Operations() {
+		got = append(got, op.String())
+	}
+	if strings.Join(got, ",") != "GET /items,POST /items" {
+		t.Errorf("operations = %v", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	spec := MustLoad([]byte(testSpec))
+	noop := func(http.ResponseWriter, *http.Request) {}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/items", noop).Methods("POST")
+	router.HandleFunc("/items/{id}", noop).Methods("DELETE")
+	router.HandleFunc("/metrics", noop).Methods("GET")
+	router.PathPrefix("/").HandlerFunc(noop)
+
+	got := spec.Diff(router, "/metrics")
+	want := []string{
+		"DELETE /items/{id} is routed but not documented",
+		"GET /items is documented but not routed",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Diff = %q, want %q", got, want)
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	spec := MustLoad([]byte(testSpec))
+	handler := spec.ValidateRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+
+	for _, tc := range []struct {
+		name, method, target, body string
+		status                     int
+		field                      string
+	}{
+		{"valid body", "POST", "/items", `{"name":"a","count":2}`, http.StatusOK, ""},
+		{"missing property", "POST", "/items", `{"count":2}`, http.StatusBadRequest, "name"},
+		{"unknown property", "POST", "/items", `{"name":"a","total":2}`, http.StatusBadRequest, "body"},
+		{"wrong type", "POST", "/items", `{"name":"a","count":"two"}`, http.StatusBadRequest, "count"},
+		{"bad query", "GET", "/items?limit=x", "", http.StatusBadRequest, "limit"},
+		{"undocumented path", "GET", "/metrics", "", http.StatusOK, ""},
+	} {
+		req := httptest.NewRequest(tc.method, "http://example.com"+tc.target, strings.NewReader(tc.body))
+		if tc.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tc.status {
+			t.Errorf("%s: got %d, want %d: %s", tc.name, rec.Code, tc.status, rec.Body.String())
+			continue
+		}
+		if tc.field == "" {
+			continue
+		}
+		var apiErr apierror.Error
+		json.Unmarshal(rec.Body.Bytes(), &apiErr)
+		if apiErr.Code != apierror.CodeValidationFailed || len(apiErr.Fields) == 0 || apiErr.Fields[0].Field != tc.field {
+			t.Errorf("%s: got %+v, want VALIDATION_FAILED on %s", tc.name, apiErr, tc.field)
+		}
+	}
+}
+
+func TestReportResponses(t *testing.T) {
+	spec := MustLoad([]byte(testSpec))
+
+	var reported []error
+	report := func(r *http.Request, err error) { reported = append(reported, err) }
+	respond := func(status int, body string) http.Handler {
+		return spec.ReportResponses(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}), report)
+	}
+	post := func() *http.Request {
+		req := httptest.NewRequest("POST", "/items", strings.NewReader(`{"name":"a"}`))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	rec := httptest.NewRecorder()
+	respond(http.StatusOK, `{"message":"ok"}`).ServeHTTP(rec, post())
+	if len(reported) != 0 || rec.Body.String() != `{"message":"ok"}` {
+		t.Fatalf("valid response: reported %v, body %q", reported, rec.Body.String())
+	}
+
+	respond(http.StatusOK, `{"msg":"ok"}`).ServeHTTP(httptest.NewRecorder(), post())
+	respond(http.StatusTeapot, `{"message":"ok"}`).ServeHTTP(httptest.NewRecorder(), post())
+	if len(reported) != 2 {
+		t.Errorf("got %d reports for a drifted body and an undocumented status, want 2: %v", len(reported), reported)
+	}
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Settings holds the connection parameters of a MySQL database
+type Settings struct {
+	User     string `env:"DB_USER" usage:"MySQL user"`
+	Password string `env:"DB_PASSWORD" secret:"true" usage:"MySQL password"`
+	Host     string `env:"DB_HOST" default:"localhost" usage:"MySQL host"`
+	Port     int    `env:"DB_PORT" default:"3306" usage:"MySQL port"`
+	Name     string `env:"DB_NAME" usage:"MySQL database name"`
+}
+
+// DSN builds the go-sql-driver/mysql data source name
+func (s Settings) DSN() string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", s.User, s.Password, s.Host, s.Port, s.Name)
+}
+
+// Validate reports missing connection parameters
+func (s Settings) Validate() error {
+	switch {
+	case s.User == "":
+		return fmt.Errorf("DB_USER is required")
+	case s.Host == "":
+		return fmt.Errorf("DB_HOST is required")
+	case s.Port <= 0 || s.Port > 65535:
+		return fmt.Errorf("DB_PORT must be between 1 and 65535, got %d", s.Port)
+	case s.Name == "":
+		return fmt.Errorf("DB_NAME is required")
+	}
+	return nil
+}
+
+// PoolConfig holds the connection pool limits of a *sql.DB
+type PoolConfig struct {
+	MaxOpenConns    int           `env:"DB_MAX_OPEN_CONNS" default:"25" usage:"Maximum open connections"`
+	MaxIdleConns    int           `env:"DB_MAX_IDLE_CONNS" default:"10" usage:"Maximum idle connections"`
+	ConnMaxLifetime time.Duration `env:"DB_CONN_MAX_LIFETIME" default:"5m" usage:"Maximum lifetime of a connection"`
+	ConnMaxIdleTime time.Duration `env:"DB_CONN_MAX_IDLE_TIME" default:"1m" usage:"Maximum idle time of a connection"`
+}
+
+// ConfigurePool applies cfg to db
+func ConfigurePool(db *sql.DB, cfg PoolConfig) {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+}
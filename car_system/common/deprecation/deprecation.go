@@ -0,0 +1,75 @@
+// Package deprecation keeps legacy routes working as aliases of their
+// versioned successors while telling clients to move.
+//
+// Every response of a legacy route carries
+//
+//	Deprecation: @1792368000                          (RFC 9745)
+//	Sunset: Fri, 30 Apr 2027 00:00:00 GMT             (RFC 8594)
+//	Link: </v1/reservations>; rel="successor-version"
+//
+// and is counted in http_deprecated_requests_total so that the remaining
+// callers can be found before the sunset date.
+package deprecation
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Policy holds the dates announced by legacy routes. A zero date omits its
+// header.
+type Policy struct {
+	Deprecated time.Time `env:"LEGACY_ROUTES_DEPRECATED" default:"2026-10-19" usage:"Date announced in the Deprecation header of legacy routes"`
+	Sunset     time.Time `env:"LEGACY_ROUTES_SUNSET" default:"2027-04-30" usage:"Date after which legacy routes may be removed, announced in the Sunset header"`
+}
+
+// Aliases registers legacy routes on a router
+type Aliases struct {
+	policy   Policy
+	requests *prometheus.CounterVec
+}
+
+// New creates the aliases of one service and registers their counter with reg
+func New(reg prometheus.Registerer, policy Policy) *Aliases {
+	a := &Aliases{
+		policy: policy,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_deprecated_requests_total",
+			Help: "Requests served by legacy routes, by legacy route and successor.",
+		}, []string{"route", "successor"}),
+	}
+	reg.MustRegister(a.requests)
+	return a
+}
+
+// Handle registers handler at the legacy path as an alias of successor, the
+// path of the versioned route. Methods and other matchers are added to the
+// returned route as usual.
+func (a *Aliases) Handle(router *mux.Router, path, successor string, handler http.HandlerFunc) *mux.Route {
+	return router.Handle(path, a.Wrap(path, successor, handler))
+}
+
+// Wrap adds the deprecation headers and the request count to next, the
+// handler of the legacy route at path
+func (a *Aliases) Wrap(path, successor string, next http.Handler) http.Handler {
+	counter := a.requests.WithLabelValues(path, successor)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter.Inc()
+
+		h := w.Header()
+		if !a.policy.Deprecated.IsZero() {
+			h.Set("Deprecation", "@"+strconv.FormatInt(a.policy.Deprecated.Unix(), 10))
+		}
+		if !a.policy.Sunset.IsZero() {
+			h.Set("Sunset", a.policy.Sunset.UTC().Format(http.TimeFormat))
+		}
+		h.Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+
+		next.ServeHTTP(w, r)
+	})
+}
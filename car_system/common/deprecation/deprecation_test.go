@@ -0,0 +1,63 @@
+package deprecation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHandle(t *testing.T) {
+	policy := Policy{
+		Deprecated: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		Sunset:     time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC),
+	}
+	aliases := New(prometheus.NewRegistry(), policy)
+
+	router := mux.NewRouter()
+	ok := func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) }
+	router.HandleFunc("/v1/bills", ok).Methods("POST")
+	aliases.Handle(router, "/billing", "/v1/bills", ok).Methods("POST")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("POST", "/billing", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Fatalf("legacy route: got %d %q, want the successor's response", rec.Code, rec.Body.String())
+	}
+	for header, want := range map[string]string{
+		"Deprecation": "@1792368000",
+		"Sunset":      "Fri, 30 Apr 2027 00:00:00 GMT",
+		"Link":        `</v1/bills>; rel="successor-version"`,
+	} {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("POST", "/v1/bills", nil))
+	if rec.Header().Get("Deprecation") != "" {
+		t.Error("versioned route is marked as deprecated")
+	}
+
+	if n := testutil.ToFloat64(aliases.requests.WithLabelValues("/billing", "/v1/bills")); n != 1 {
+		t.Errorf("http_deprecated_requests_total = %v, want 1", n)
+	}
+}
+
+func TestZeroPolicyOmitsDates(t *testing.T) {
+	aliases := New(prometheus.NewRegistry(), Policy{})
+	rec := httptest.NewRecorder()
+	aliases.Wrap("/old", "/v1/new", http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest("GET", "/old", nil))
+
+	if rec.Header().Get("Deprecation") != "" || rec.Header().Get("Sunset") != "" {
+		t.Errorf("zero policy set date headers: %v", rec.Header())
+	}
+	if rec.Header().Get("Link") == "" {
+		t.Error("successor link missing")
+	}
+}
@@ -0,0 +1,58 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Config holds the timeouts of an HTTP server. The write timeout defaults
+// above the 10s proxy client timeout so proxied calls can finish.
+type Config struct {
+	ReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT" default:"10s" usage:"Maximum time to read a request"`
+	ReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" default:"5s" usage:"Maximum time to read request headers"`
+	WriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT" default:"15s" usage:"Maximum time to write a response"`
+	IdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT" default:"60s" usage:"Keep-alive idle timeout"`
+	ShutdownTimeout   time.Duration `env:"HTTP_SHUTDOWN_TIMEOUT" default:"20s" usage:"Time allowed for in-flight requests to drain"`
+}
+
+// New creates an http.Server listening on addr with the configured timeouts
+func New(addr string, handler http.Handler, cfg Config) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// Run serves srv until ctx is cancelled, then stops accepting connections and
+// waits up to shutdownTimeout for in-flight requests to finish
+func Run(ctx context.Context, srv *http.Server, shutdownTimeout time.Duration) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
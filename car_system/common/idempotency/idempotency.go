@@ -0,0 +1,247 @@
+// Package idempotency lets clients retry state-changing requests safely.
+//
+// A client sends a unique Idempotency-Key header with a POST, PUT, PATCH or
+// DELETE request. The first response to the key is stored and replayed, with
+// an Idempotent-Replayed: true header, for every retry with the same method,
+// path and body. Reusing the key for a different request is answered with 409
+// IDEMPOTENCY_KEY_REUSED, and a retry that arrives while the first request is
+// still running with 409 IDEMPOTENCY_KEY_IN_USE. Requests without the header
+// are passed through unchanged.
+package idempotency
+
+import (
+	"bytes"
+	"car_system/common/apierror"
+	"car_system/common/logging"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Header names of the idempotency protocol
+const (
+	KeyHeader      = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// Error codes returned by the middleware
+const (
+	CodeKeyReused = "IDEMPOTENCY_KEY_REUSED"
+	CodeKeyInUse  = "IDEMPOTENCY_KEY_IN_USE"
+)
+
+// MaxKeyLength is the longest accepted Idempotency-Key
+const MaxKeyLength = 255
+
+// DefaultTTL is used when Options.TTL is zero
+const DefaultTTL = 24 * time.Hour
+
+var (
+	errKeyReused = apierror.New(http.StatusConflict, CodeKeyReused, "Idempotency-Key was already used for a different request")
+	errKeyInUse  = apierror.New(http.StatusConflict, CodeKeyInUse, "A request with this Idempotency-Key is still being processed")
+	errKeyLength = apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Invalid Idempotency-Key").
+			WithField(KeyHeader, "must be between 1 and 255 characters")
+)
+
+// Record is the stored outcome of the first request made with a key
+type Record struct {
+	// Fingerprint identifies the method, path and body of the request
+	Fingerprint string
+	// Status is 0 while the first request is still being processed
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Store keeps the records of the keys seen by a service
+type Store interface {
+	// Reserve claims key for a request with fingerprint until expires. It
+	// returns nil when the key was free, or the record of the earlier request.
+	Reserve(ctx context.Context, key, fingerprint string, expires time.Time) (*Record, error)
+	// Save stores the response of the request that reserved key
+	Save(ctx context.Context, key string, record Record) error
+	// Release forgets key so that the request can be retried
+	Release(ctx context.Context, key string) error
+}
+
+// Settings holds the configurable part of Options
+type Settings struct {
+	TTL time.Duration `env:"IDEMPOTENCY_KEY_TTL" default:"24h" usage:"How long an Idempotency-Key and its response are remembered"`
+}
+
+// Options configures the middleware
+type Options struct {
+	Store Store
+	// TTL is how long a key is remembered after its first use. Zero means
+	// DefaultTTL.
+	TTL time.Duration
+	// Scope partitions the keys, e.g. by authenticated user, so that clients
+	// cannot replay each other's responses. nil puts all keys in one scope.
+	Scope func(r *http.Request) string
+}
+
+// Middleware applies the idempotency protocol to state-changing requests that
+// carry an Idempotency-Key header. Server errors and rejections that happen
+// before a request is processed (401, 403 and 429) are not stored, so such a
+// request can be retried with the same key once the cause is gone.
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	if opts.TTL == 0 {
+		opts.TTL = DefaultTTL
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := r.Header[KeyHeader]; !ok || !stateChanging(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := r.Header.Get(KeyHeader)
+			if key == "" || len(key) > MaxKeyLength {
+				apierror.Write(w, r, errKeyLength)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				apierror.Write(w, r, apierror.ErrInvalidJSON)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			scope := ""
+			if opts.Scope != nil {
+				scope = opts.Scope(r)
+			}
+			id := ScopedKey(scope, key)
+			ctx := r.Context()
+			logger := logging.FromContext(ctx)
+
+			sum := fingerprint(r, body)
+			existing, err := opts.Store.Reserve(ctx, id, sum, time.Now().Add(opts.TTL))
+			if err != nil {
+				logger.Error("Error reserving idempotency key", "error", err)
+				apierror.Write(w, r, apierror.ErrInternal)
+				return
+			}
+			if existing != nil {
+				replay(w, r, existing, sum)
+				return
+			}
+
+			// The request context may be cancelled by the time the handler
+			// returns, so the outcome is stored with a fresh one
+			storeCtx := context.WithoutCancel(ctx)
+			defer func() {
+				// A panicking handler never produced an outcome, so free the
+				// key for a retry instead of leaving it in use until the TTL
+				if v := recover(); v != nil {
+					if err := opts.Store.Release(storeCtx, id); err != nil {
+						logger.Error("Error releasing idempotency key", "error", err)
+					}
+					panic(v)
+				}
+			}()
+
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			// Keep the key reserved only for outcomes worth replaying
+			if !replayable(rec.status) {
+				if err := opts.Store.Release(storeCtx, id); err != nil {
+					logger.Error("Error releasing idempotency key", "error", err)
+				}
+				return
+			}
+			record := Record{Status: rec.status, Header: storedHeader(w.Header()), Body: rec.body.Bytes()}
+			if err := opts.Store.Save(storeCtx, id, record); err != nil {
+				logger.Error("Error saving idempotent response", "error", err)
+			}
+		})
+	}
+}
+
+// replay answers a retry from the record of the first request
+func replay(w http.ResponseWriter, r *http.Request, record *Record, fingerprint string) {
+	switch {
+	case record.Fingerprint != fingerprint:
+		apierror.Write(w, r, errKeyReused)
+	case record.Status == 0:
+		w.Header().Set("Retry-After", "1")
+		apierror.Write(w, r, errKeyInUse)
+	default:
+		logging.FromContext(r.Context()).Info("Replaying idempotent response", "status", record.Status)
+		for name, values := range record.Header {
+			w.Header()[name] = append([]string(nil), values...)
+		}
+		w.Header().Set(ReplayedHeader, "true")
+		w.WriteHeader(record.Status)
+		w.Write(record.Body)
+	}
+}
+
+// replayable reports whether a response with status is stored for the key
+func replayable(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+func stateChanging(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// ScopedKey hashes a scope and a client's key into a fixed-length key. It is
+// used as the storage ID, and by proxies that pass a client's key on to
+// another service without letting the keys of different users collide.
+func ScopedKey(scope, key string) string {
+	sum := sha256.Sum256([]byte(scope + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// fingerprint hashes what makes two requests the same: method, path and body
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.Path+"\x00")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// storedHeader returns the response headers worth replaying. Cookies are left
+// out so that a stored response never hands out a session.
+func storedHeader(header http.Header) http.Header {
+	stored := header.Clone()
+	for _, name := range []string{"Set-Cookie", logging.RequestIDHeader, ReplayedHeader} {
+		stored.Del(name)
+	}
+	return stored
+}
+
+// recorder passes a response through while keeping a copy of it
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
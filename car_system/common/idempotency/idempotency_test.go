@@ -0,0 +1,173 @@
+package idempotency
+
+import (
+	"car_system/common/apierror"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// counter creates a resource per request and reports how many it created
+type counter struct {
+	calls  int
+	status int
+}
+
+func (c *counter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.calls++
+	body, _ := io.ReadAll(r.Body)
+	http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(c.status)
+	fmt.Fprintf(w, `{"id":%d,"echo":%q}`, c.calls, body)
+}
+
+func send(h http.Handler, method, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(KeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func errorCode(rec *httptest.ResponseRecorder) string {
+	var e apierror.Error
+	json.Unmarshal(rec.Body.Bytes(), &e)
+	return e.Code
+}
+
+func TestReplay(t *testing.T) {
+	next := &counter{status: http.StatusCreated}
+	h := Middleware(Options{Store: NewMemoryStore(), TTL: time.Hour})(next)
+
+	first := send(h, "POST", "/items", "k1", `{"a":1}`)
+	retry := send(h, "POST", "/items", "k1", `{"a":1}`)
+	if next.calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", next.calls)
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry = %d %s, want %d %s", retry.Code, retry.Body, first.Code, first.Body)
+	}
+	if retry.Header().Get(ReplayedHeader) != "true" || first.Header().Get(ReplayedHeader) != "" {
+		t.Errorf("%s header: first %q, retry %q", ReplayedHeader, first.Header().Get(ReplayedHeader), retry.Header().Get(ReplayedHeader))
+	}
+	if retry.Header().Get("Set-Cookie") != "" {
+		t.Error("replayed response carries the cookie of the first response")
+	}
+
+	if rec := send(h, "POST", "/items", "k2", `{"a":1}`); rec.Code != http.StatusCreated || next.calls != 2 {
+		t.Errorf("new key: got %d after %d calls, want a fresh response", rec.Code, next.calls)
+	}
+	if send(h, "POST", "/items", "", `{"a":1}`); next.calls != 3 {
+		t.Error("request without a key was not passed through")
+	}
+	if send(h, "GET", "/items", "k1", ""); next.calls != 4 {
+		t.Error("GET with a key was not passed through")
+	}
+}
+
+func TestKeyReuse(t *testing.T) {
+	next := &counter{status: http.StatusOK}
+	h := Middleware(Options{Store: NewMemoryStore(), TTL: time.Hour})(next)
+
+	send(h, "POST", "/items", "k1", `{"a":1}`)
+	for name, rec := range map[string]*httptest.ResponseRecorder{
+		"different body": send(h, "POST", "/items", "k1", `{"a":2}`),
+		"different path": send(h, "POST", "/other", "k1", `{"a":1}`),
+	} {
+		if rec.Code != http.StatusConflict || errorCode(rec) != CodeKeyReused {
+			t.Errorf("%s: got %d %s, want 409 %s", name, rec.Code, rec.Body, CodeKeyReused)
+		}
+	}
+	if next.calls != 1 {
+		t.Errorf("handler ran %d times, want 1", next.calls)
+	}
+
+	if rec := send(h, "POST", "/items", strings.Repeat("k", MaxKeyLength+1), `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("overlong key: got %d, want 400", rec.Code)
+	}
+}
+
+func TestKeyInUse(t *testing.T) {
+	store := NewMemoryStore()
+	var retry *httptest.ResponseRecorder
+	var h http.Handler
+	h = Middleware(Options{Store: store, TTL: time.Hour})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A retry arrives while the first request is still running
+		if retry == nil {
+			retry = send(h, "POST", "/items", "k1", `{}`)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	send(h, "POST", "/items", "k1", `{}`)
+	if retry.Code != http.StatusConflict || errorCode(retry) != CodeKeyInUse || retry.Header().Get("Retry-After") == "" {
+		t.Errorf("concurrent retry: got %d %s, want 409 %s with Retry-After", retry.Code, retry.Body, CodeKeyInUse)
+	}
+}
+
+func TestServerErrorsAndRejectionsAreNotStored(t *testing.T) {
+	for _, status := range []int{http.StatusInternalServerError, http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests} {
+		next := &counter{status: status}
+		h := Middleware(Options{Store: NewMemoryStore(), TTL: time.Hour})(next)
+
+		send(h, "POST", "/items", "k1", `{}`)
+		next.status = http.StatusCreated
+		if rec := send(h, "POST", "/items", "k1", `{}`); rec.Code != http.StatusCreated || next.calls != 2 {
+			t.Errorf("retry after %d: got %d after %d calls, want a fresh 201", status, rec.Code, next.calls)
+		}
+	}
+}
+
+func TestPanicReleasesKey(t *testing.T) {
+	next := &counter{status: http.StatusCreated}
+	panicking := true
+	h := Middleware(Options{Store: NewMemoryStore(), TTL: time.Hour})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if panicking {
+			panic("boom")
+		}
+		next.ServeHTTP(w, r)
+	}))
+
+	func() {
+		defer func() {
+			if v := recover(); v != "boom" {
+				t.Errorf("recovered %v, want the handler's panic to propagate", v)
+			}
+		}()
+		send(h, "POST", "/items", "k1", `{}`)
+	}()
+
+	panicking = false
+	if rec := send(h, "POST", "/items", "k1", `{}`); rec.Code != http.StatusCreated || next.calls != 1 {
+		t.Errorf("retry after panic: got %d %s after %d calls, want a fresh 201", rec.Code, rec.Body, next.calls)
+	}
+}
+
+func TestScopeAndExpiry(t *testing.T) {
+	next := &counter{status: http.StatusOK}
+	store := NewMemoryStore()
+	h := Middleware(Options{
+		Store: store,
+		TTL:   time.Hour,
+		Scope: func(r *http.Request) string { return r.URL.Query().Get("user") },
+	})(next)
+
+	send(h, "POST", "/items?user=1", "k1", `{}`)
+	if rec := send(h, "POST", "/items?user=2", "k1", `{}`); rec.Header().Get(ReplayedHeader) != "" || next.calls != 2 {
+		t.Error("key of one scope was replayed in another")
+	}
+
+	expiring := Middleware(Options{Store: store, TTL: -time.Second})(next)
+	send(expiring, "POST", "/items", "k3", `{}`)
+	if rec := send(expiring, "POST", "/items", "k3", `{}`); rec.Header().Get(ReplayedHeader) != "" || next.calls != 4 {
+		t.Error("expired key was replayed")
+	}
+}
@@ -0,0 +1,59 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps the records in process memory, for the in-memory backend
+// and tests. Expired keys are dropped when new keys are reserved.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]*memoryRecord
+}
+
+type memoryRecord struct {
+	Record
+	expires time.Time
+}
+
+// NewMemoryStore returns an empty store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]*memoryRecord)}
+}
+
+func (s *MemoryStore) Reserve(_ context.Context, key, fingerprint string, expires time.Time) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, record := range s.records {
+		if !record.expires.After(now) {
+			delete(s.records, k)
+		}
+	}
+	if record, ok := s.records[key]; ok {
+		copied := record.Record
+		return &copied, nil
+	}
+	s.records[key] = &memoryRecord{Record: Record{Fingerprint: fingerprint}, expires: expires}
+	return nil, nil
+}
+
+func (s *MemoryStore) Save(_ context.Context, key string, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[key]; ok {
+		record.Fingerprint = existing.Fingerprint
+		existing.Record = record
+	}
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
@@ -0,0 +1,91 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// SQLStore keeps the records in the IdempotencyKey table of a service
+// database, so that every instance of the service sees the same keys. The
+// table is created by the migrations of each service.
+type SQLStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore returns a store backed by db
+func NewSQLStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+func (s *SQLStore) Reserve(ctx context.Context, key, fingerprint string, expires time.Time) (*Record, error) {
+	now := time.Now().UTC()
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM IdempotencyKey WHERE expires_at <= ?", now); err != nil {
+		return nil, err
+	}
+
+	// The primary key makes the insert the point where concurrent requests
+	// with the same key are told apart
+	result, err := s.db.ExecContext(ctx, `
+		INSERT IGNORE INTO IdempotencyKey (idempotency_key, fingerprint, expires_at)
+		VALUES (?, ?, ?)
+	`, key, fingerprint, expires.UTC())
+	if err != nil {
+		return nil, err
+	}
+	if inserted, err := result.RowsAffected(); err != nil || inserted == 1 {
+		return nil, err
+	}
+
+	var record Record
+	var status sql.NullInt64
+	var header []byte
+	err = s.db.QueryRowContext(ctx, `
+		SELECT fingerprint, status_code, response_header, response_body
+		FROM IdempotencyKey
+		WHERE idempotency_key = ?
+	`, key).Scan(&record.Fingerprint, &status, &header, &record.Body)
+	if err == sql.ErrNoRows {
+		// The earlier request released the key in the meantime: report it as
+		// still running so that the client retries
+		return &Record{Fingerprint: fingerprint}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	record.Status = int(status.Int64)
+	if len(header) > 0 {
+		if err := json.Unmarshal(header, &record.Header); err != nil {
+			return nil, err
+		}
+	}
+	return &record, nil
+}
+
+func (s *SQLStore) Save(ctx context.Context, key string, record Record) error {
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE IdempotencyKey
+		SET status_code = ?, response_header = ?, response_body = ?
+		WHERE idempotency_key = ?
+	`, record.Status, header, record.Body, key)
+	return err
+}
+
+func (s *SQLStore) Release(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM IdempotencyKey WHERE idempotency_key = ?", key)
+	return err
+}
+
+// NewStore returns a SQLStore for db, or a MemoryStore when db is nil because
+// the service runs on the in-memory backend
+func NewStore(db *sql.DB) Store {
+	if db == nil {
+		return NewMemoryStore()
+	}
+	return NewSQLStore(db)
+}
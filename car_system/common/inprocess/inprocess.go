@@ -0,0 +1,38 @@
+// Package inprocess lets an http.Client call a handler mounted in the same
+// process without going through the network stack
+package inprocess
+
+import (
+	"net/http"
+	"net/http/httptest"
+)
+
+// Transport returns a RoundTripper that serves every request with handler.
+// The scheme and host of the request URL are ignored.
+func Transport(handler http.Handler) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+
+		inbound := req.Clone(req.Context())
+		inbound.RequestURI = req.URL.RequestURI()
+		inbound.RemoteAddr = "in-process"
+		if inbound.Body == nil {
+			inbound.Body = http.NoBody
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, inbound)
+
+		resp := rec.Result()
+		resp.Request = req
+		return resp, nil
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type contextKey struct{}
+
+// New creates a JSON logger for the given service. Every record carries the
+// service name and is passed through the redaction layer before it is written.
+// level is the minimum level name (debug, info, warn or error).
+func New(service, level string) *slog.Logger {
+	return NewWithWriter(service, os.Stderr, ParseLevel(level))
+}
+
+// NewWithWriter creates a redacting JSON logger that writes to w
+func NewWithWriter(service string, w io.Writer, level slog.Level) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	})
+	return slog.New(handler).With(slog.String("service", service))
+}
+
+// ParseLevel converts a level name into a slog.Level, defaulting to info
+func ParseLevel(name string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithContext returns a copy of ctx that carries the given logger
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger stored in ctx, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
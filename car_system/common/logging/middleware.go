@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+
+	"github.com/felixge/httpsnoop"
+)
+
+// RequestIDHeader is the header used to propagate request IDs between services
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// Middleware attaches a request-scoped logger to every request and writes one
+// access log record per request once the handler has finished
+func Middleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if requestID == "" {
+				requestID = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			reqLogger := logger.With(
+				slog.String("request_id", requestID),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+			)
+			ctx := WithContext(r.Context(), reqLogger)
+			ctx = context.WithValue(ctx, requestIDKey{}, requestID)
+
+			m := httpsnoop.CaptureMetrics(next, w, r.WithContext(ctx))
+
+			level := slog.LevelInfo
+			if m.Code >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			reqLogger.Log(ctx, level, "request completed",
+				slog.Int("status", m.Code),
+				slog.Int64("bytes", m.Written),
+				slog.Float64("duration_ms", float64(m.Duration.Microseconds())/1000),
+				slog.String("remote_addr", r.RemoteAddr),
+			)
+		})
+	}
+}
+
+// RequestID returns the ID assigned to the current request by Middleware
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces values that must never reach the log output
+const Redacted = "[REDACTED]"
+
+var (
+	emailPattern   = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phonePattern   = regexp.MustCompile(`\+?\b\d{8,15}\b`)
+	licensePattern = regexp.MustCompile(`\b[A-Z]{1,3}\d{6,10}\b`)
+)
+
+// secretKeys are attribute keys whose values are dropped entirely
+var secretKeys = map[string]bool{
+	"cookie":         true,
+	"set_cookie":     true,
+	"session":        true,
+	"session_id":     true,
+	"session_cookie": true,
+	"authorization":  true,
+	"password":       true,
+	"secret":         true,
+	"session_secret": true,
+	"token":          true,
+	"csrf_token":     true,
+}
+
+// redactAttr is the slog ReplaceAttr hook that masks sensitive attributes.
+// Every attribute of a group named like a secret key is dropped as well.
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	key := normalizeKey(a.Key)
+
+	if secretKeys[key] {
+		return slog.String(a.Key, Redacted)
+	}
+	for _, group := range groups {
+		if secretKeys[normalizeKey(group)] {
+			return slog.String(a.Key, Redacted)
+		}
+	}
+
+	value := a.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redactByKey(key, value.String()))
+	case slog.KindAny:
+		switch v := value.Any().(type) {
+		case error:
+			return slog.String(a.Key, RedactString(v.Error()))
+		case http.Header:
+			return slog.Any(a.Key, RedactHeaders(v))
+		}
+	}
+	return a
+}
+
+// redactByKey masks a string value according to what its key says it holds
+func redactByKey(key, value string) string {
+	switch key {
+	case "email":
+		return MaskEmail(value)
+	case "phone", "phone_no", "phone_number":
+		return maskTail(value, 2)
+	case "license", "license_no", "license_number", "driver_license":
+		return maskTail(value, 2)
+	default:
+		return RedactString(value)
+	}
+}
+
+// RedactString masks emails, phone numbers and license numbers embedded in free text
+func RedactString(s string) string {
+	s = emailPattern.ReplaceAllStringFunc(s, MaskEmail)
+	s = licensePattern.ReplaceAllStringFunc(s, func(m string) string { return maskTail(m, 2) })
+	s = phonePattern.ReplaceAllStringFunc(s, func(m string) string { return maskTail(m, 2) })
+	return s
+}
+
+// RedactHeaders returns a copy of the headers with credentials removed
+func RedactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		if secretKeys[normalizeKey(name)] {
+			out[name] = Redacted
+			continue
+		}
+		out[name] = RedactString(strings.Join(values, ", "))
+	}
+	return out
+}
+
+// MaskEmail keeps the first character of the local part and the domain
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return Redacted
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// maskTail hides everything but the last n characters of a value
+func maskTail(value string, n int) string {
+	if len(value) <= n {
+		return Redacted
+	}
+	return strings.Repeat("*", len(value)-n) + value[len(value)-n:]
+}
+
+func normalizeKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "-", "_")
+}
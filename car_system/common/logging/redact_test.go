@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+)
+
+// logLine logs one record through the redacting logger and returns it decoded
+func logLine(t *testing.T, log func(*slog.Logger)) map[string]interface{} {
+	t.Helper()
+	var buf bytes.Buffer
+	log(NewWithWriter("test", &buf, slog.LevelInfo))
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("log line is not JSON: %v (%q)", err, buf.String())
+	}
+	return line
+}
+
+func TestRedactByKey(t *testing.T) {
+	line := logLine(t, func(l *slog.Logger) {
+		l.Info("user registered",
+			"email", "alice@example.com",
+			"phone_no", "91234567",
+			"driver_license", "S1234567D",
+			"user_id", 7,
+		)
+	})
+	for key, want := range map[string]interface{}{
+		"email":          "a***@example.com",
+		"phone_no":       "******67",
+		"driver_license": "*******7D",
+		"user_id":        float64(7),
+		"service":        "test",
+	} {
+		if line[key] != want {
+			t.Errorf("%s = %v, want %v", key, line[key], want)
+		}
+	}
+}
+
+func TestRedactSecretKeys(t *testing.T) {
+	line := logLine(t, func(l *slog.Logger) {
+		l.Info("login", "Password", "s3cret-pass", "Set-Cookie", "session=abc", "csrf_token", "xyz", "token", 42)
+	})
+	for _, key := range []string{"Password", "Set-Cookie", "csrf_token", "token"} {
+		if line[key] != Redacted {
+			t.Errorf("%s = %v, want it redacted", key, line[key])
+		}
+	}
+}
+
+func TestRedactFreeText(t *testing.T) {
+	line := logLine(t, func(l *slog.Logger) {
+		l.Info("lookup failed",
+			"detail", "no user alice@example.com with phone +6591234567 or license AB1234567",
+			"error", errors.New("duplicate email bob@example.org"),
+		)
+	})
+	if want := "no user a***@example.com with phone *********67 or license *******67"; line["detail"] != want {
+		t.Errorf("detail = %v, want %q", line["detail"], want)
+	}
+	if want := "duplicate email b***@example.org"; line["error"] != want {
+		t.Errorf("error = %v, want %q", line["error"], want)
+	}
+	if got := RedactString("reservation 42 from 10:00"); got != "reservation 42 from 10:00" {
+		t.Errorf("RedactString changed text without personal data: %q", got)
+	}
+	if got := MaskEmail("not-an-email"); got != Redacted {
+		t.Errorf("MaskEmail of a malformed address = %q, want it redacted", got)
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{
+		"Authorization":   {"Bearer abc"},
+		"Cookie":          {"session=abc"},
+		"X-Forwarded-For": {"10.0.0.1"},
+		"From":            {"alice@example.com"},
+	}
+	line := logLine(t, func(l *slog.Logger) { l.Info("request", "headers", header) })
+	headers, _ := line["headers"].(map[string]interface{})
+	for name, want := range map[string]string{
+		"Authorization":   Redacted,
+		"Cookie":          Redacted,
+		"X-Forwarded-For": "10.0.0.1",
+		"From":            "a***@example.com",
+	} {
+		if headers[name] != want {
+			t.Errorf("header %s = %v, want %q", name, headers[name], want)
+		}
+	}
+}
+
+func TestRedactGroups(t *testing.T) {
+	line := logLine(t, func(l *slog.Logger) {
+		l.Info("nested",
+			slog.Group("user", slog.String("email", "alice@example.com"), slog.Group("contact", slog.String("phone", "91234567"))),
+			slog.Group("session", slog.String("id", "abc"), slog.Int("user_id", 7)),
+		)
+	})
+	user, _ := line["user"].(map[string]interface{})
+	contact, _ := user["contact"].(map[string]interface{})
+	if user["email"] != "a***@example.com" || contact["phone"] != "******67" {
+		t.Errorf("user = %v, want the email and phone masked", user)
+	}
+	session, _ := line["session"].(map[string]interface{})
+	if session["id"] != Redacted || session["user_id"] != Redacted {
+		t.Errorf("session = %v, want every attribute of the secret group redacted", session)
+	}
+}
+
+func TestRedactWith(t *testing.T) {
+	line := logLine(t, func(l *slog.Logger) {
+		l.With("email", "alice@example.com", "token", "abc").WithGroup("auth").With("password", "s3cret").Info("login", "attempt", 2)
+	})
+	if line["email"] != "a***@example.com" || line["token"] != Redacted {
+		t.Errorf("With attributes = %v, want them redacted", line)
+	}
+	auth, _ := line["auth"].(map[string]interface{})
+	if auth["password"] != Redacted || auth["attempt"] != float64(2) {
+		t.Errorf("auth = %v, want the password redacted and the attempt kept", auth)
+	}
+}
@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/felixge/httpsnoop"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the Prometheus registry and the HTTP collectors of one service
+type Metrics struct {
+	registry *prometheus.Registry
+
+	// Registerer registers collectors under the service label
+	Registerer prometheus.Registerer
+
+	httpRequests     *prometheus.CounterVec
+	httpDuration     *prometheus.HistogramVec
+	upstreamRequests *prometheus.CounterVec
+	upstreamDuration *prometheus.HistogramVec
+}
+
+// New creates a registry for the given service with HTTP, upstream, Go runtime
+// and process collectors already registered
+func New(service string) *Metrics {
+	registry := prometheus.NewRegistry()
+	registerer := prometheus.WrapRegistererWith(prometheus.Labels{"service": service}, registry)
+
+	m := &Metrics{
+		registry:   registry,
+		Registerer: registerer,
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests by route, method and status code.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route, method and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		upstreamRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "upstream_requests_total",
+			Help: "Total number of calls to upstream services by outcome.",
+		}, []string{"upstream", "outcome"}),
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "upstream_request_duration_seconds",
+			Help:    "Latency of calls to upstream services.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"upstream"}),
+	}
+
+	registerer.MustRegister(
+		m.httpRequests,
+		m.httpDuration,
+		m.upstreamRequests,
+		m.upstreamDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RegisterDB exposes the connection pool statistics of db under the given name
+func (m *Metrics) RegisterDB(db *sql.DB, dbName string) {
+	m.Registerer.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// InstrumentRouter records request counts and latencies for every request
+// served by router. Requests are labelled with the matched route template so
+// that path parameters do not create new series.
+func (m *Metrics) InstrumentRouter(router *mux.Router, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unmatched"
+		var match mux.RouteMatch
+		if router.Match(r, &match) && match.Route != nil {
+			if tpl, err := match.Route.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+
+		snoop := httpsnoop.CaptureMetrics(next, w, r)
+
+		status := strconv.Itoa(snoop.Code)
+		m.httpRequests.WithLabelValues(route, r.Method, status).Inc()
+		m.httpDuration.WithLabelValues(route, r.Method, status).Observe(snoop.Duration.Seconds())
+	})
+}
+
+// Transport wraps base so that every call is counted against the named upstream
+func (m *Metrics) Transport(upstream string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := base.RoundTrip(req)
+		m.upstreamDuration.WithLabelValues(upstream).Observe(time.Since(start).Seconds())
+		m.upstreamRequests.WithLabelValues(upstream, outcome(resp, err)).Inc()
+		return resp, err
+	})
+}
+
+// outcome classifies an upstream call for the upstream_requests_total counter
+func outcome(resp *http.Response, err error) string {
+	switch {
+	case err != nil:
+		return "error"
+	case resp.StatusCode >= 500:
+		return "server_error"
+	case resp.StatusCode >= 400:
+		return "client_error"
+	default:
+		return "success"
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,96 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Usage describes the migrate subcommand
+const Usage = `usage: migrate <command>
+
+commands:
+  up             apply all pending migrations
+  down [steps]   roll back the last applied migration, or the last <steps>
+  status         list migrations and whether they are applied
+  seed           load the optional sample data
+  force VERSION  clear the dirty flag of VERSION after a manual repair`
+
+// RunCommand executes a migrate subcommand such as "up" or "down 2" and
+// writes a human readable summary to out
+func RunCommand(ctx context.Context, m *Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command\n%s", Usage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(out, "No pending migrations")
+		}
+		for _, version := range applied {
+			fmt.Fprintf(out, "Applied migration %d\n", version)
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+		reverted, err := m.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		for _, version := range reverted {
+			fmt.Fprintf(out, "Rolled back migration %d\n", version)
+		}
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Dirty {
+				state = "dirty"
+			} else if s.Applied {
+				state = "applied"
+			}
+			fmt.Fprintf(out, "%04d_%s\t%s\n", s.Version, s.Name, state)
+		}
+	case "seed":
+		applied, err := m.Seed(ctx)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(out, "No pending seed files")
+		}
+		for _, name := range applied {
+			fmt.Fprintf(out, "Applied seed %s\n", name)
+		}
+	case "force":
+		if len(args) < 2 {
+			return fmt.Errorf("force requires a version\n%s", Usage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if err := m.Force(ctx, version); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Cleared dirty flag of migration %d\n", version)
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], Usage)
+	}
+	return nil
+}
@@ -0,0 +1,417 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration is one versioned schema change with its rollback
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Seed is an optional data file applied after the schema is up to date
+type Seed struct {
+	Name string
+	SQL  string
+}
+
+// Status describes whether a migration has been applied
+type Status struct {
+	Version int
+	Name    string
+	Applied bool
+	Dirty   bool
+}
+
+// ErrDirty is returned when a previous migration failed halfway and the
+// schema has to be repaired by hand before migrating again
+var ErrDirty = errors.New("database schema is dirty")
+
+const (
+	versionTable = "schema_migrations"
+	seedTable    = "schema_seeds"
+)
+
+// migrationFile matches names such as 0001_initial_schema.up.sql
+var migrationFile = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migrator applies the embedded migrations of one service to its database
+type Migrator struct {
+	db         *sql.DB
+	lockName   string
+	migrations []Migration
+	seeds      []Seed
+}
+
+// New loads the migrations found at the root of migrationsFS and the seed
+// files found at the root of seedFS (which may be nil). lockName identifies
+// the advisory lock that keeps concurrent instances from migrating at once.
+func New(db *sql.DB, lockName string, migrationsFS, seedFS fs.FS) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationsFS)
+	if err != nil {
+		return nil, err
+	}
+	var seeds []Seed
+	if seedFS != nil {
+		if seeds, err = loadSeeds(seedFS); err != nil {
+			return nil, err
+		}
+	}
+	return &Migrator{db: db, lockName: lockName, migrations: migrations, seeds: seeds}, nil
+}
+
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %v", err)
+	}
+
+	byVersion := map[int]*Migration{}
+	seen := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		if version < 1 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+		// 1_init.up.sql and 0001_init.up.sql would both be version 1
+		key := fmt.Sprintf("%d.%s", version, match[3])
+		if other, ok := seen[key]; ok {
+			return nil, fmt.Errorf("migration %d has duplicate %s files %q and %q", version, match[3], other, entry.Name())
+		}
+		seen[key] = entry.Name()
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %v", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if _, ok := seen[fmt.Sprintf("%d.up", m.Version)]; !ok {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	// A gap usually means a file was lost in a merge; applying the later
+	// versions without it would record a history that cannot be replayed
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %d is missing before %d_%s", i+1, m.Version, m.Name)
+		}
+	}
+	return migrations, nil
+}
+
+func loadSeeds(fsys fs.FS) ([]Seed, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed files: %v", err)
+	}
+	var seeds []Seed
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read seed %s: %v", entry.Name(), err)
+		}
+		seeds = append(seeds, Seed{Name: entry.Name(), SQL: string(body)})
+	}
+	// fs.ReadDir already returns entries sorted by file name
+	return seeds, nil
+}
+
+// Migrations returns the loaded migrations in version order
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up applies every pending migration in order and returns the versions applied
+func (m *Migrator) Up(ctx context.Context) ([]int, error) {
+	var applied []int
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := current[migration.Version]; ok {
+				continue
+			}
+			if err := apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			applied = append(applied, migration.Version)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the last steps applied migrations and returns their versions
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int, error) {
+	var reverted []int
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := current[migration.Version]; !ok {
+				continue
+			}
+			if err := revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration.Version)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status reports every known migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := appliedVersions(ctx, conn)
+		if err != nil && !errors.Is(err, ErrDirty) {
+			return err
+		}
+		for _, migration := range m.migrations {
+			dirty, applied := current[migration.Version]
+			statuses = append(statuses, Status{
+				Version: migration.Version,
+				Name:    migration.Name,
+				Applied: applied,
+				Dirty:   dirty,
+			})
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Force clears the dirty flag of version after the schema was repaired by hand
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		res, err := conn.ExecContext(ctx, "UPDATE "+versionTable+" SET dirty = FALSE WHERE version = ?", version)
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %v", versionTable, err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("migration %d is not recorded", version)
+		}
+		return nil
+	})
+}
+
+// Seed applies every seed file that has not been applied yet and returns their names
+func (m *Migrator) Seed(ctx context.Context) ([]string, error) {
+	var applied []string
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		if _, err := conn.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS `+seedTable+` (
+				name VARCHAR(255) PRIMARY KEY,
+				applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			)`); err != nil {
+			return fmt.Errorf("failed to create %s: %v", seedTable, err)
+		}
+		for _, seed := range m.seeds {
+			var count int
+			if err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+seedTable+" WHERE name = ?", seed.Name).Scan(&count); err != nil {
+				return fmt.Errorf("failed to read %s: %v", seedTable, err)
+			}
+			if count > 0 {
+				continue
+			}
+			if err := execScript(ctx, conn, seed.SQL); err != nil {
+				return fmt.Errorf("seed %s failed: %v", seed.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx, "INSERT INTO "+seedTable+" (name) VALUES (?)", seed.Name); err != nil {
+				return fmt.Errorf("failed to record seed %s: %v", seed.Name, err)
+			}
+			applied = append(applied, seed.Name)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// withLock runs fn on a single connection holding a MySQL advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 30)", m.lockName).Scan(&locked); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %v", err)
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		return fmt.Errorf("timed out waiting for migration lock %q", m.lockName)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", m.lockName)
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS `+versionTable+` (
+			version INT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			dirty BOOLEAN NOT NULL DEFAULT FALSE,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+		return fmt.Errorf("failed to create %s: %v", versionTable, err)
+	}
+	return fn(conn)
+}
+
+// appliedVersions returns the applied versions mapped to their dirty flag
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int]bool, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, dirty FROM "+versionTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", versionTable, err)
+	}
+	defer rows.Close()
+
+	versions := map[int]bool{}
+	var dirtyErr error
+	for rows.Next() {
+		var version int
+		var dirty bool
+		if err := rows.Scan(&version, &dirty); err != nil {
+			return nil, err
+		}
+		versions[version] = dirty
+		if dirty {
+			dirtyErr = fmt.Errorf("%w: migration %d did not complete", ErrDirty, version)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return versions, dirtyErr
+}
+
+// apply runs one up migration. MySQL commits DDL implicitly, so the version
+// row is written as dirty first and only cleared once every statement ran.
+func apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	if _, err := conn.ExecContext(ctx, "INSERT INTO "+versionTable+" (version, name, dirty) VALUES (?, ?, TRUE)", migration.Version, migration.Name); err != nil {
+		return fmt.Errorf("failed to record migration %d: %v", migration.Version, err)
+	}
+	if err := execScript(ctx, conn, migration.Up); err != nil {
+		return fmt.Errorf("migration %d_%s failed: %v", migration.Version, migration.Name, err)
+	}
+	if _, err := conn.ExecContext(ctx, "UPDATE "+versionTable+" SET dirty = FALSE WHERE version = ?", migration.Version); err != nil {
+		return fmt.Errorf("failed to record migration %d: %v", migration.Version, err)
+	}
+	return nil
+}
+
+// revert runs one down migration and removes its version row
+func revert(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+	}
+	if _, err := conn.ExecContext(ctx, "UPDATE "+versionTable+" SET dirty = TRUE WHERE version = ?", migration.Version); err != nil {
+		return fmt.Errorf("failed to mark migration %d: %v", migration.Version, err)
+	}
+	if err := execScript(ctx, conn, migration.Down); err != nil {
+		return fmt.Errorf("rollback of %d_%s failed: %v", migration.Version, migration.Name, err)
+	}
+	if _, err := conn.ExecContext(ctx, "DELETE FROM "+versionTable+" WHERE version = ?", migration.Version); err != nil {
+		return fmt.Errorf("failed to remove migration %d: %v", migration.Version, err)
+	}
+	return nil
+}
+
+// execScript runs each statement of a SQL script in order
+func execScript(ctx context.Context, conn *sql.Conn, script string) error {
+	for _, stmt := range SplitStatements(script) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SplitStatements splits a script into statements on semicolons outside of
+// quoted strings and identifiers. "--" comments running to the end of a line
+// are dropped, as are empty statements.
+func SplitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	var quote byte
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case quote != 0:
+			current.WriteByte(c)
+			if c == '\\' && quote != '`' && i+1 < len(script) {
+				i++
+				current.WriteByte(script[i])
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			current.WriteByte(c)
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end - 1
+			}
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+	return statements
+}
@@ -0,0 +1,381 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitStatements(t *testing.T) {
+	cases := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "one statement per line",
+			script: "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n",
+			want:   []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:   "statement over several lines",
+			script: "CREATE TABLE a (\n  id INT\n);",
+			want:   []string{"CREATE TABLE a (\n  id INT\n)"},
+		},
+		{
+			name:   "comments and blank lines",
+			script: "-- the a table\n\nCREATE TABLE a (id INT); -- trailing\n-- done;\n",
+			want:   []string{"CREATE TABLE a (id INT)"},
+		},
+		{
+			name:   "several statements on one line",
+			script: "DELETE FROM a; DELETE FROM b;",
+			want:   []string{"DELETE FROM a", "DELETE FROM b"},
+		},
+		{
+			name:   "last statement without semicolon",
+			script: "DELETE FROM a;\nDELETE FROM b",
+			want:   []string{"DELETE FROM a", "DELETE FROM b"},
+		},
+		{
+			name:   "semicolon inside a string",
+			script: "INSERT INTO a (note) VALUES ('one; two');",
+			want:   []string{"INSERT INTO a (note) VALUES ('one; two')"},
+		},
+		{
+			name:   "semicolon ending a line inside a string",
+			script: "INSERT INTO a (note) VALUES ('first line;\n-- not a comment\nlast line');\nDELETE FROM b;",
+			want:   []string{"INSERT INTO a (note) VALUES ('first line;\n-- not a comment\nlast line')", "DELETE FROM b"},
+		},
+		{
+			name:   "escaped and doubled quotes",
+			script: `INSERT INTO a (note) VALUES ('it\'s; fine'), ('it''s; fine'), ("say \"hi;\"");`,
+			want:   []string{`INSERT INTO a (note) VALUES ('it\'s; fine'), ('it''s; fine'), ("say \"hi;\"")`},
+		},
+		{
+			name:   "quoted identifier",
+			script: "SELECT `odd;name` FROM a;",
+			want:   []string{"SELECT `odd;name` FROM a"},
+		},
+		{
+			name:   "empty script",
+			script: "\n-- nothing here\n;\n",
+			want:   nil,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := SplitStatements(tc.script); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("SplitStatements() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_email.up.sql":        {Data: []byte("ALTER TABLE a ADD email TEXT;")},
+		"0002_add_email.down.sql":      {Data: []byte("ALTER TABLE a DROP email;")},
+		"0001_initial_schema.up.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+		"0001_initial_schema.down.sql": {Data: []byte("DROP TABLE a;")},
+		"0003_backfill.up.sql":         {Data: []byte("")},
+		"README.md":                    {Data: []byte("not a migration")},
+		"seed/0001_sample.sql":         {Data: []byte("INSERT INTO a VALUES (1);")},
+	}
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Migration{
+		{Version: 1, Name: "initial_schema", Up: "CREATE TABLE a (id INT);", Down: "DROP TABLE a;"},
+		{Version: 2, Name: "add_email", Up: "ALTER TABLE a ADD email TEXT;", Down: "ALTER TABLE a DROP email;"},
+		{Version: 3, Name: "backfill"},
+	}
+	if !reflect.DeepEqual(migrations, want) {
+		t.Errorf("loadMigrations() = %+v, want %+v", migrations, want)
+	}
+}
+
+func TestLoadMigrationsRejects(t *testing.T) {
+	up := &fstest.MapFile{Data: []byte("SELECT 1;")}
+	cases := []struct {
+		name    string
+		files   fstest.MapFS
+		wantErr string
+	}{
+		{
+			name:    "invalid name",
+			files:   fstest.MapFS{"0001_Initial.up.sql": up},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name:    "version zero",
+			files:   fstest.MapFS{"0000_initial.up.sql": up},
+			wantErr: "invalid migration version",
+		},
+		{
+			name:    "conflicting names",
+			files:   fstest.MapFS{"0001_initial.up.sql": up, "0001_other.down.sql": up},
+			wantErr: "conflicting names",
+		},
+		{
+			name:    "duplicate version",
+			files:   fstest.MapFS{"0001_initial.up.sql": up, "1_initial.up.sql": up},
+			wantErr: "duplicate up files",
+		},
+		{
+			name:    "down without up",
+			files:   fstest.MapFS{"0001_initial.up.sql": up, "0002_extra.down.sql": up},
+			wantErr: "2_extra has no up file",
+		},
+		{
+			name:    "missing version",
+			files:   fstest.MapFS{"0001_initial.up.sql": up, "0003_extra.up.sql": up},
+			wantErr: "migration 2 is missing",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := loadMigrations(tc.files)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("loadMigrations() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpDown(t *testing.T) {
+	db, state := openFakeDB(t)
+	m, err := New(db, "test", fstest.MapFS{
+		"0001_a.up.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+		"0001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"0002_b.up.sql":   {Data: []byte("CREATE TABLE b (id INT);")},
+		"0002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	applied, err := m.Up(ctx)
+	if err != nil || !reflect.DeepEqual(applied, []int{1, 2}) {
+		t.Fatalf("Up() = %v, %v, want [1 2]", applied, err)
+	}
+	if applied, err := m.Up(ctx); err != nil || len(applied) != 0 {
+		t.Errorf("second Up() = %v, %v, want nothing to apply", applied, err)
+	}
+
+	reverted, err := m.Down(ctx, 1)
+	if err != nil || !reflect.DeepEqual(reverted, []int{2}) {
+		t.Fatalf("Down(1) = %v, %v, want [2]", reverted, err)
+	}
+	wantScripts := []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)", "DROP TABLE b"}
+	if !reflect.DeepEqual(state.executed, wantScripts) {
+		t.Errorf("executed %q, want %q", state.executed, wantScripts)
+	}
+	if !reflect.DeepEqual(state.versions, map[int]bool{1: false}) {
+		t.Errorf("versions = %v, want only 1 applied", state.versions)
+	}
+}
+
+func TestDirtyMigration(t *testing.T) {
+	db, state := openFakeDB(t)
+	m, err := New(db, "test", fstest.MapFS{
+		"0001_a.up.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+		"0002_b.up.sql": {Data: []byte("CREATE TABLE b (id INT);\nFAIL;")},
+		"0003_c.up.sql": {Data: []byte("CREATE TABLE c (id INT);")},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	applied, err := m.Up(ctx)
+	if err == nil || !reflect.DeepEqual(applied, []int{1}) {
+		t.Fatalf("Up() = %v, %v, want [1] and an error", applied, err)
+	}
+	if !reflect.DeepEqual(state.versions, map[int]bool{1: false, 2: true}) {
+		t.Errorf("versions = %v, want 2 left dirty", state.versions)
+	}
+
+	if _, err := m.Up(ctx); !errors.Is(err, ErrDirty) {
+		t.Errorf("Up() on a dirty schema error = %v, want ErrDirty", err)
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Status{
+		{Version: 1, Name: "a", Applied: true},
+		{Version: 2, Name: "b", Applied: true, Dirty: true},
+		{Version: 3, Name: "c"},
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("Status() = %+v, want %+v", statuses, want)
+	}
+
+	var out bytes.Buffer
+	if err := RunCommand(ctx, m, []string{"force", "2"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "Cleared dirty flag of migration 2\n" {
+		t.Errorf("force printed %q", got)
+	}
+	if applied, err := m.Up(ctx); err != nil || !reflect.DeepEqual(applied, []int{3}) {
+		t.Errorf("Up() after force = %v, %v, want [3]", applied, err)
+	}
+}
+
+func TestRunCommandArguments(t *testing.T) {
+	db, _ := openFakeDB(t)
+	m, err := New(db, "test", fstest.MapFS{"0001_a.up.sql": {Data: []byte("SELECT 1;")}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{nil, {"sideways"}, {"down", "0"}, {"down", "x"}, {"force"}, {"force", "x"}, {"force", "7"}} {
+		if err := RunCommand(context.Background(), m, args, io.Discard); err == nil {
+			t.Errorf("RunCommand(%q) succeeded, want an error", args)
+		}
+	}
+}
+
+// fakeState records what a fake connection did. Statements containing FAIL
+// return an error so a migration can be made to break halfway.
+type fakeState struct {
+	mu       sync.Mutex
+	versions map[int]bool
+	seeds    map[string]bool
+	executed []string
+}
+
+var (
+	fakeMu     sync.Mutex
+	fakeStates = map[string]*fakeState{}
+)
+
+func init() {
+	sql.Register("fakemigrate", fakeDriver{})
+}
+
+func openFakeDB(t *testing.T) (*sql.DB, *fakeState) {
+	state := &fakeState{versions: map[int]bool{}, seeds: map[string]bool{}}
+	fakeMu.Lock()
+	fakeStates[t.Name()] = state
+	fakeMu.Unlock()
+
+	db, err := sql.Open("fakemigrate", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, state
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	return &fakeConn{state: fakeStates[name]}, nil
+}
+
+type fakeConn struct {
+	state *fakeState
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare not supported: %s", query)
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("transactions not supported") }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query = strings.TrimSpace(query)
+	switch {
+	case strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS "+versionTable),
+		strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS "+seedTable),
+		strings.HasPrefix(query, "SELECT RELEASE_LOCK"):
+		return driver.RowsAffected(0), nil
+	case strings.HasPrefix(query, "INSERT INTO "+versionTable):
+		s.versions[int(args[0].Value.(int64))] = true
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(query, "UPDATE "+versionTable):
+		version := int(args[0].Value.(int64))
+		if _, ok := s.versions[version]; !ok {
+			return driver.RowsAffected(0), nil
+		}
+		s.versions[version] = strings.Contains(query, "dirty = TRUE")
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(query, "DELETE FROM "+versionTable):
+		delete(s.versions, int(args[0].Value.(int64)))
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(query, "INSERT INTO "+seedTable):
+		s.seeds[args[0].Value.(string)] = true
+		return driver.RowsAffected(1), nil
+	case strings.Contains(query, "FAIL"):
+		return nil, errors.New("statement failed")
+	}
+	s.executed = append(s.executed, query)
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "SELECT GET_LOCK"):
+		return &fakeRows{columns: []string{"locked"}, values: [][]driver.Value{{int64(1)}}}, nil
+	case strings.HasPrefix(query, "SELECT version, dirty FROM "+versionTable):
+		versions := make([]int, 0, len(s.versions))
+		for version := range s.versions {
+			versions = append(versions, version)
+		}
+		sort.Ints(versions)
+		rows := &fakeRows{columns: []string{"version", "dirty"}}
+		for _, version := range versions {
+			rows.values = append(rows.values, []driver.Value{int64(version), s.versions[version]})
+		}
+		return rows, nil
+	case strings.HasPrefix(query, "SELECT COUNT(*) FROM "+seedTable):
+		count := int64(0)
+		if s.seeds[args[0].Value.(string)] {
+			count = 1
+		}
+		return &fakeRows{columns: []string{"count"}, values: [][]driver.Value{{count}}}, nil
+	}
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
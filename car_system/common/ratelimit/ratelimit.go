@@ -0,0 +1,221 @@
+// Package ratelimit throttles requests per client and route with token
+// buckets.
+//
+// Every route wrapped by a Limiter has its own Policy and its own bucket per
+// client. A client is the session user when Options.Identify knows one, and
+// the client IP otherwise. Responses carry the RateLimit-Limit,
+// RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy headers of the
+// IETF RateLimit header fields draft. Rejected requests are answered with 429
+// RATE_LIMITED and a Retry-After header.
+package ratelimit
+
+import (
+	"car_system/common/apierror"
+	"car_system/common/logging"
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CodeRateLimited is the error code of rejected requests
+const CodeRateLimited = "RATE_LIMITED"
+
+var errRateLimited = apierror.New(http.StatusTooManyRequests, CodeRateLimited, "Too many requests. Please try again later.")
+
+// Policy allows Burst requests at once and refills the bucket at Burst
+// requests per Period. The zero Policy disables limiting.
+//
+// As text a policy is written "<burst>/<period>", e.g. "10/1m" or "5/h", or
+// "off".
+type Policy struct {
+	Burst  int
+	Period time.Duration
+}
+
+// ParsePolicy parses the text form of a policy
+func ParsePolicy(s string) (Policy, error) {
+	if s == "" || s == "off" {
+		return Policy{}, nil
+	}
+	burst, period, ok := strings.Cut(s, "/")
+	n, err := strconv.Atoi(burst)
+	if !ok || err != nil || n <= 0 {
+		return Policy{}, fmt.Errorf("expected <requests>/<period> such as 10/1m, got %q", s)
+	}
+	// Allow a bare unit such as "m" for one minute
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Policy{}, fmt.Errorf("expected a positive period such as 1m in %q", s)
+	}
+	return Policy{Burst: n, Period: d}, nil
+}
+
+// Disabled reports whether p lets every request through
+func (p Policy) Disabled() bool {
+	return p.Burst <= 0 || p.Period <= 0
+}
+
+func (p Policy) String() string {
+	if p.Disabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", p.Burst, shortDuration(p.Period))
+}
+
+// shortDuration formats d without zero trailing units: 1h instead of 1h0m0s
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// UnmarshalText lets policies be loaded by common/settings
+func (p *Policy) UnmarshalText(text []byte) error {
+	parsed, err := ParsePolicy(string(text))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// MarshalText is the inverse of UnmarshalText
+func (p Policy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// Decision is the outcome of taking a token from a bucket
+type Decision struct {
+	Allowed bool
+	// Remaining is the number of requests left in the bucket
+	Remaining int
+	// RetryAfter is how long a rejected client has to wait for a token
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// Store keeps the buckets. MemoryStore serves a single instance; a shared
+// backend lets several instances enforce one limit.
+type Store interface {
+	// Take removes a token from the bucket of key, which is refilled
+	// according to policy up to now
+	Take(ctx context.Context, key string, policy Policy, now time.Time) (Decision, error)
+}
+
+// Options configures a Limiter
+type Options struct {
+	// Store keeps the buckets; nil uses a new MemoryStore
+	Store Store
+	// Identify returns the user a request is counted against, or "" to count
+	// it against the client IP
+	Identify func(r *http.Request) string
+	// TrustForwardedFor takes the client IP from the last X-Forwarded-For
+	// entry, which is added by the proxy, for instances that are only
+	// reachable through one reverse proxy
+	TrustForwardedFor bool
+	// Registerer receives the http_rate_limited_requests_total counter
+	Registerer prometheus.Registerer
+}
+
+// Limiter applies per-route policies
+type Limiter struct {
+	opts     Options
+	rejected *prometheus.CounterVec
+	now      func() time.Time
+}
+
+// New creates a limiter and registers its metrics
+func New(opts Options) *Limiter {
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+	l := &Limiter{
+		opts: opts,
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_rate_limited_requests_total",
+			Help: "Requests rejected by the rate limiter, by route.",
+		}, []string{"route"}),
+		now: time.Now,
+	}
+	opts.Registerer.MustRegister(l.rejected)
+	return l
+}
+
+// Wrap limits next with policy. route names the buckets, so aliases of one
+// route share their limit by using the same name. A disabled policy returns
+// next unchanged.
+func (l *Limiter) Wrap(route string, policy Policy, next http.HandlerFunc) http.HandlerFunc {
+	if policy.Disabled() {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := route + "|" + l.client(r)
+		decision, err := l.opts.Store.Take(r.Context(), key, policy, l.now())
+		if err != nil {
+			// Fail open: an unavailable store must not take the API down
+			logging.FromContext(r.Context()).Error("Rate limit store failed", "route", route, "error", err)
+			next(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(policy.Burst))
+		header.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		header.Set("RateLimit-Reset", seconds(decision.Reset))
+		header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%s", policy.Burst, seconds(policy.Period)))
+		if !decision.Allowed {
+			l.rejected.WithLabelValues(route).Inc()
+			logging.FromContext(r.Context()).Warn("Rate limit exceeded", "route", route)
+			header.Set("Retry-After", seconds(decision.RetryAfter))
+			apierror.Write(w, r, errRateLimited)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// client identifies the sender of r: the user when known, the IP otherwise
+func (l *Limiter) client(r *http.Request) string {
+	if l.opts.Identify != nil {
+		if user := l.opts.Identify(r); user != "" {
+			return "user:" + user
+		}
+	}
+	return "ip:" + l.clientIP(r)
+}
+
+func (l *Limiter) clientIP(r *http.Request) string {
+	if l.opts.TrustForwardedFor {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			// Earlier entries are sent by the client and cannot be trusted
+			entries := strings.Split(forwarded, ",")
+			return strings.TrimSpace(entries[len(entries)-1])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// seconds renders d as whole seconds, rounded up
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,177 @@
+package ratelimit
+
+import (
+	"car_system/common/apierror"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestParsePolicy(t *testing.T) {
+	for text, want := range map[string]Policy{
+		"10/1m": {Burst: 10, Period: time.Minute},
+		"5/h":   {Burst: 5, Period: time.Hour},
+		"3/90s": {Burst: 3, Period: 90 * time.Second},
+		"off":   {},
+		"":      {},
+	} {
+		got, err := ParsePolicy(text)
+		if err != nil || got != want {
+			t.Errorf("ParsePolicy(%q) = %v, %v, want %v", text, got, err, want)
+		}
+	}
+	for _, text := range []string{"10", "0/1m", "ten/1m", "10/soon", "10/-1m"} {
+		if _, err := ParsePolicy(text); err == nil {
+			t.Errorf("ParsePolicy(%q) succeeded, want an error", text)
+		}
+	}
+	if text, _ := (Policy{Burst: 5, Period: time.Hour}).MarshalText(); string(text) != "5/1h" {
+		t.Errorf("MarshalText = %q", text)
+	}
+}
+
+// testLimiter returns a limiter whose clock is moved by advancing *now
+func testLimiter(opts Options) (*Limiter, *time.Time) {
+	opts.Registerer = prometheus.NewRegistry()
+	l := New(opts)
+	now := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func request(remoteAddr, user string) *http.Request {
+	r := httptest.NewRequest("POST", "/v1/sessions", nil)
+	r.RemoteAddr = remoteAddr
+	if user != "" {
+		r.Header.Set("X-Test-User", user)
+	}
+	return r
+}
+
+func ok(w http.ResponseWriter, r *http.Request) {}
+
+func TestWrap(t *testing.T) {
+	l, now := testLimiter(Options{})
+	h := l.Wrap("login", Policy{Burst: 2, Period: time.Minute}, ok)
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		rec := httptest.NewRecorder()
+		h(rec, request("10.0.0.1:5000", ""))
+		if rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Remaining") != wantRemaining {
+			t.Fatalf("request %d: got %d with remaining %q, want 200 with %s", i+1, rec.Code, rec.Header().Get("RateLimit-Remaining"), wantRemaining)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h(rec, request("10.0.0.1:5001", ""))
+	var apiErr apierror.Error
+	json.Unmarshal(rec.Body.Bytes(), &apiErr)
+	if rec.Code != http.StatusTooManyRequests || apiErr.Code != CodeRateLimited {
+		t.Fatalf("third request: got %d %s, want 429 %s", rec.Code, rec.Body, CodeRateLimited)
+	}
+	for header, want := range map[string]string{
+		"Retry-After":      "30",
+		"RateLimit-Limit":  "2",
+		"RateLimit-Reset":  "60",
+		"RateLimit-Policy": "2;w=60",
+	} {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+	if n := testutil.ToFloat64(l.rejected.WithLabelValues("login")); n != 1 {
+		t.Errorf("rejected counter = %v, want 1", n)
+	}
+
+	// Another client, and the same client on another route, have their own buckets
+	rec = httptest.NewRecorder()
+	h(rec, request("10.0.0.2:5000", ""))
+	if rec.Code != http.StatusOK {
+		t.Errorf("other IP: got %d, want 200", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	l.Wrap("register", Policy{Burst: 2, Period: time.Minute}, ok)(rec, request("10.0.0.1:5000", ""))
+	if rec.Code != http.StatusOK {
+		t.Errorf("other route: got %d, want 200", rec.Code)
+	}
+
+	// One token is back after Period/Burst
+	*now = now.Add(30 * time.Second)
+	rec = httptest.NewRecorder()
+	h(rec, request("10.0.0.1:5000", ""))
+	if rec.Code != http.StatusOK {
+		t.Errorf("after refill: got %d, want 200", rec.Code)
+	}
+}
+
+func TestClientIdentity(t *testing.T) {
+	l, _ := testLimiter(Options{
+		Identify:          func(r *http.Request) string { return r.Header.Get("X-Test-User") },
+		TrustForwardedFor: true,
+	})
+	h := l.Wrap("reservations", Policy{Burst: 1, Period: time.Minute}, ok)
+
+	send := func(r *http.Request) int {
+		rec := httptest.NewRecorder()
+		h(rec, r)
+		return rec.Code
+	}
+	// A user keeps their bucket across IPs
+	send(request("10.0.0.1:5000", "7"))
+	if code := send(request("10.0.0.9:5000", "7")); code != http.StatusTooManyRequests {
+		t.Errorf("same user from another IP: got %d, want 429", code)
+	}
+	// Anonymous clients behind the proxy are told apart by the entry the
+	// proxy appended to X-Forwarded-For, not by the spoofable ones before it
+	proxied := func(forwarded string) *http.Request {
+		r := request("192.168.0.1:80", "")
+		r.Header.Set("X-Forwarded-For", forwarded)
+		return r
+	}
+	send(proxied("198.51.100.1, 203.0.113.5"))
+	if code := send(proxied("203.0.113.6")); code != http.StatusOK {
+		t.Errorf("other forwarded client: got %d, want 200", code)
+	}
+	if code := send(proxied("203.0.113.5")); code != http.StatusTooManyRequests {
+		t.Errorf("same forwarded client: got %d, want 429", code)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Policy, time.Time) (Decision, error) {
+	return Decision{}, errors.New("store unavailable")
+}
+
+func TestFailOpenAndDisabled(t *testing.T) {
+	l, _ := testLimiter(Options{Store: failingStore{}})
+	rec := httptest.NewRecorder()
+	l.Wrap("login", Policy{Burst: 1, Period: time.Minute}, ok)(rec, request("10.0.0.1:5000", ""))
+	if rec.Code != http.StatusOK {
+		t.Errorf("store failure: got %d, want the request to pass", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	l.Wrap("login", Policy{}, ok)(rec, request("10.0.0.1:5000", ""))
+	if rec.Header().Get("RateLimit-Limit") != "" {
+		t.Error("disabled policy still sets rate limit headers")
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s := NewMemoryStore()
+	policy := Policy{Burst: 2, Period: time.Minute}
+	start := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	s.Take(context.Background(), "a", policy, start)
+	s.Take(context.Background(), "b", policy, start.Add(2*time.Minute))
+	if len(s.buckets) != 1 {
+		t.Errorf("got %d buckets after the sweep, want only the recent one", len(s.buckets))
+	}
+}
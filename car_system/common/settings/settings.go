@@ -0,0 +1,305 @@
+// Package settings loads typed service configuration from defaults, an
+// optional .env file, environment variables and command-line flags.
+//
+// Configuration is a struct whose fields carry the tags
+//
+//	env:"DB_HOST"      environment variable, also exposed as the -db-host flag
+//	default:"3306"     value used when nothing else sets the field
+//	usage:"..."        help text of the flag
+//	secret:"true"      value is redacted by Print and Values
+//
+// Nested structs without an env tag are loaded recursively, so shared pieces
+// such as database.Settings or httpserver.Config can be embedded as fields.
+// Supported field types are string, bool, int, float64, time.Duration,
+// time.Time (a 2006-01-02 date or an RFC 3339 timestamp), []string (comma
+// separated) and types that implement encoding.TextUnmarshaler, which are
+// printed with their MarshalText method when they have one.
+package settings
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Redacted replaces the value of secret settings in printed output
+const Redacted = "[REDACTED]"
+
+// EnvFileVariable names the environment variable that points at the .env file
+const EnvFileVariable = "ENV_FILE"
+
+// Validator is implemented by configuration structs that check their values
+// once everything has been loaded
+type Validator interface {
+	Validate() error
+}
+
+// Value is one resolved setting as reported by Values
+type Value struct {
+	Name  string
+	Value string
+}
+
+// field is a settable leaf of the configuration struct
+type field struct {
+	env    string
+	def    string
+	usage  string
+	secret bool
+	value  reflect.Value
+}
+
+// Load fills cfg, a pointer to a configuration struct, and returns the
+// arguments left after the flags (e.g. a "migrate up" subcommand).
+//
+// Sources are applied in increasing order of precedence: the default tag (or
+// the value already in cfg), the .env file, environment variables, flags. The
+// .env file is optional; its path is read from ENV_FILE and defaults to ".env".
+// It is only consulted, never copied into the process environment.
+func Load(name string, cfg interface{}, args []string) ([]string, error) {
+	fields, err := collect(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	envFile := os.Getenv(EnvFileVariable)
+	if envFile == "" {
+		envFile = ".env"
+	}
+	dotenv, err := godotenv.Read(envFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("loading %s: %w", envFile, err)
+	}
+
+	for _, f := range fields {
+		if f.def != "" && f.value.IsZero() {
+			if err := set(f.value, f.def); err != nil {
+				return nil, fmt.Errorf("default of %s: %w", f.env, err)
+			}
+		}
+		v := os.Getenv(f.env)
+		if v == "" {
+			v = dotenv[f.env]
+		}
+		if v != "" {
+			if err := set(f.value, v); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", f.env, err)
+			}
+		}
+	}
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	for _, f := range fields {
+		flags.Var(flagValue{f}, flagName(f.env), fmt.Sprintf("%s (env %s)", f.usage, f.env))
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if v, ok := cfg.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid configuration: %w", err)
+		}
+	}
+	return flags.Args(), nil
+}
+
+// Values returns every setting of cfg in declaration order with secrets redacted
+func Values(cfg interface{}) []Value {
+	fields, err := collect(cfg)
+	if err != nil {
+		return nil
+	}
+	values := make([]Value, 0, len(fields))
+	for _, f := range fields {
+		v := format(f.value)
+		if f.secret && v != "" {
+			v = Redacted
+		}
+		values = append(values, Value{Name: f.env, Value: v})
+	}
+	return values
+}
+
+// Map returns Values as a map, e.g. for a structured log record
+func Map(cfg interface{}) map[string]string {
+	out := make(map[string]string)
+	for _, v := range Values(cfg) {
+		out[v.Name] = v.Value
+	}
+	return out
+}
+
+// Print writes the resolved configuration to w as NAME=value lines with
+// secrets redacted
+func Print(w io.Writer, cfg interface{}) error {
+	for _, v := range Values(cfg) {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", v.Name, v.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collect walks the configuration struct and returns its tagged leaves
+func collect(cfg interface{}) ([]field, error) {
+	rv := reflect.ValueOf(cfg)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("settings: expected a pointer to a struct, got %T", cfg)
+	}
+	var fields []field
+	walk(rv.Elem(), &fields)
+	return fields, nil
+}
+
+func walk(v reflect.Value, fields *[]field) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, ok := sf.Tag.Lookup("env")
+		if !ok {
+			if sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(time.Time{}) {
+				walk(v.Field(i), fields)
+			}
+			continue
+		}
+		*fields = append(*fields, field{
+			env:    name,
+			def:    sf.Tag.Get("default"),
+			usage:  sf.Tag.Get("usage"),
+			secret: sf.Tag.Get("secret") == "true",
+			value:  v.Field(i),
+		})
+	}
+}
+
+// flagName derives the flag of an environment variable: DB_HOST becomes db-host
+func flagName(env string) string {
+	return strings.ReplaceAll(strings.ToLower(env), "_", "-")
+}
+
+var (
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshaler   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// set parses s into v according to the type of v
+func set(v reflect.Value, s string) error {
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Type() == reflect.TypeOf(time.Time{}):
+		t, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			if t, err = time.Parse(time.RFC3339, s); err != nil {
+				return fmt.Errorf("expected a date (2006-01-02) or an RFC 3339 timestamp, got %q", s)
+			}
+		}
+		v.Set(reflect.ValueOf(t))
+	case v.CanAddr() && v.Addr().Type().Implements(textUnmarshaler):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	case v.Kind() == reflect.String:
+		v.SetString(s)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+// format renders v the way set parses it
+func format(v reflect.Value) string {
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		return time.Duration(v.Int()).String()
+	case v.Type() == reflect.TypeOf(time.Time{}):
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		if t.Equal(t.Truncate(24 * time.Hour)) {
+			return t.Format(time.DateOnly)
+		}
+		return t.Format(time.RFC3339)
+	case v.Type().Implements(textMarshaler):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err.Error()
+		}
+		return string(text)
+	case v.Kind() == reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).String()
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// flagValue adapts a field to flag.Value
+type flagValue struct {
+	f field
+}
+
+func (fv flagValue) String() string {
+	if !fv.f.value.IsValid() {
+		return ""
+	}
+	if v := format(fv.f.value); !fv.f.secret || v == "" {
+		return v
+	}
+	return Redacted
+}
+
+func (fv flagValue) Set(s string) error {
+	return set(fv.f.value, s)
+}
+
+// IsBoolFlag lets boolean settings be passed as -flag without a value
+func (fv flagValue) IsBoolFlag() bool {
+	return fv.f.value.IsValid() && fv.f.value.Kind() == reflect.Bool
+}
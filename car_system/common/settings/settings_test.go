@@ -0,0 +1,164 @@
+package settings
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// level is a custom setting type parsed by UnmarshalText
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return fmt.Errorf("expected low or high, got %q", text)
+	}
+	return nil
+}
+
+func (l level) MarshalText() ([]byte, error) {
+	return []byte(map[level]string{1: "low", 2: "high"}[l]), nil
+}
+
+type nested struct {
+	Timeout time.Duration `env:"TEST_TIMEOUT" default:"5s"`
+	Until   time.Time     `env:"TEST_UNTIL" default:"2030-06-30"`
+	Level   level         `env:"TEST_LEVEL" default:"low"`
+}
+
+type testConfig struct {
+	Port    int      `env:"TEST_PORT" default:"8080"`
+	Host    string   `env:"TEST_HOST" default:"localhost"`
+	Debug   bool     `env:"TEST_DEBUG"`
+	Origins []string `env:"TEST_ORIGINS" default:"http://a.example,http://b.example"`
+	Secret  string   `env:"TEST_SECRET" secret:"true"`
+	Name    string   `env:"TEST_NAME"`
+	Nested  nested
+}
+
+func (c *testConfig) Validate() error {
+	if c.Secret == "" {
+		return errors.New("TEST_SECRET is required")
+	}
+	return nil
+}
+
+// useEnvFile points ENV_FILE at a temporary file with the given contents
+func useEnvFile(t *testing.T, contents string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".env")
+	if contents != "" {
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv(EnvFileVariable, path)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	useEnvFile(t, "TEST_HOST=from-dotenv\nTEST_PORT=7000\nTEST_SECRET=s3cret\n")
+	t.Setenv("TEST_PORT", "9000")
+	t.Setenv("TEST_LEVEL", "high")
+
+	cfg := testConfig{Name: "prefilled"}
+	args, err := Load("test", &cfg, []string{"-test-debug", "-test-timeout", "30s", "migrate", "up"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Host != "from-dotenv" {
+		t.Errorf("Host = %q, want the .env value", cfg.Host)
+	}
+	if cfg.Port != 9000 {
+		t.Errorf("Port = %d, want the environment to win over .env", cfg.Port)
+	}
+	if !cfg.Debug || cfg.Nested.Timeout != 30*time.Second {
+		t.Errorf("flags not applied: Debug=%v Timeout=%v", cfg.Debug, cfg.Nested.Timeout)
+	}
+	if len(cfg.Origins) != 2 {
+		t.Errorf("Origins = %v, want the two defaults", cfg.Origins)
+	}
+	if want := time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC); !cfg.Nested.Until.Equal(want) {
+		t.Errorf("Until = %v, want the default date %v", cfg.Nested.Until, want)
+	}
+	if cfg.Nested.Level != 2 {
+		t.Errorf("Level = %d, want the environment value high (2)", cfg.Nested.Level)
+	}
+	if cfg.Name != "prefilled" {
+		t.Errorf("Name = %q, want the prefilled value", cfg.Name)
+	}
+	if strings.Join(args, " ") != "migrate up" {
+		t.Errorf("remaining args = %v, want [migrate up]", args)
+	}
+}
+
+func TestLoadWithoutEnvFile(t *testing.T) {
+	useEnvFile(t, "")
+	t.Setenv("TEST_SECRET", "s3cret")
+
+	var cfg testConfig
+	if _, err := Load("test", &cfg, nil); err != nil {
+		t.Fatalf("a missing .env file must not be an error: %v", err)
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("Port = %d, want default 8080", cfg.Port)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	useEnvFile(t, "")
+
+	var cfg testConfig
+	if _, err := Load("test", &cfg, nil); err == nil || !strings.Contains(err.Error(), "TEST_SECRET") {
+		t.Errorf("missing secret: got %v, want a validation error", err)
+	}
+
+	t.Setenv("TEST_SECRET", "s3cret")
+	t.Setenv("TEST_PORT", "eighty")
+	cfg = testConfig{}
+	if _, err := Load("test", &cfg, nil); err == nil || !strings.Contains(err.Error(), "TEST_PORT") {
+		t.Errorf("invalid port: got %v, want an error naming TEST_PORT", err)
+	}
+
+	t.Setenv("TEST_PORT", "8080")
+	t.Setenv("TEST_UNTIL", "next year")
+	cfg = testConfig{}
+	if _, err := Load("test", &cfg, nil); err == nil || !strings.Contains(err.Error(), "TEST_UNTIL") {
+		t.Errorf("invalid date: got %v, want an error naming TEST_UNTIL", err)
+	}
+
+	t.Setenv("TEST_UNTIL", "2030-06-30")
+	t.Setenv("TEST_LEVEL", "medium")
+	cfg = testConfig{}
+	if _, err := Load("test", &cfg, nil); err == nil || !strings.Contains(err.Error(), "TEST_LEVEL") {
+		t.Errorf("invalid custom value: got %v, want an error naming TEST_LEVEL", err)
+	}
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	cfg := testConfig{Port: 8080, Secret: "s3cret", Origins: []string{"x", "y"}, Nested: nested{Level: 2}}
+
+	var buf bytes.Buffer
+	if err := Print(&buf, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "s3cret") || !strings.Contains(out, "TEST_SECRET="+Redacted) {
+		t.Errorf("secret not redacted:\n%s", out)
+	}
+	for _, want := range []string{"TEST_PORT=8080\n", "TEST_ORIGINS=x,y\n", "TEST_TIMEOUT=0s\n", "TEST_LEVEL=high\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+// Package integration boots user_service, vehicle_service and billing_service
+// in-process on ephemeral ports and runs scripted user journeys against them.
+// Run with: go test ./...
+package integration
@@ -0,0 +1,270 @@
+package integration
+
+import (
+	"bytes"
+	billingapi "car_system/billing_service/api"
+	billingmodels "car_system/billing_service/models"
+	billingserver "car_system/billing_service/server"
+	"car_system/common/apispec"
+	"car_system/common/deprecation"
+	"car_system/common/logging"
+	"car_system/common/metrics"
+	"car_system/common/ratelimit"
+	userapi "car_system/user_service/api"
+	usercontrollers "car_system/user_service/controllers"
+	usermodels "car_system/user_service/models"
+	userserver "car_system/user_service/server"
+	vehicleapi "car_system/vehicle_service/api"
+	"car_system/vehicle_service/booking"
+	"car_system/vehicle_service/charging"
+	"car_system/vehicle_service/geofence"
+	"car_system/vehicle_service/maintenance"
+	vehiclemodels "car_system/vehicle_service/models"
+	vehicleserver "car_system/vehicle_service/server"
+	"car_system/vehicle_service/telemetry"
+	"car_system/vehicle_service/trip"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// legacyPolicy are the dates announced by the legacy routes in the tests
+var legacyPolicy = deprecation.Policy{
+	Deprecated: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+	Sunset:     time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC),
+}
+
+// loginLimit is the login rate limit of user_service in the tests; the other
+// routes are not limited
+var loginLimit = ratelimit.Policy{Burst: 3, Period: time.Minute}
+
+// vipMaxDuration lets VIP members book for a week
+var vipMaxDuration = 7 * 24 * time.Hour
+
+// bookingPolicy are the reservation rules of vehicle_service in the tests. The
+// tests book fixed dates in 2030, so lead time and horizon are not limited.
+var bookingPolicy = booking.Policy{
+	Default:    booking.Rules{MinDuration: time.Hour, MaxDuration: 72 * time.Hour},
+	Tiers:      booking.TierRules{"VIP": {MaxDuration: &vipMaxDuration}},
+	Turnaround: booking.Turnaround{Default: 30 * time.Minute},
+	OneWay:     booking.OneWayFee{Base: 10, PerKM: 0.5},
+}
+
+// chargeForecast charges the sample fleet at 13 kW, e.g. the 65 kWh vehicle 3
+// by 20% an hour, and rejects reservations that would not get their charge
+var chargeForecast = charging.Forecaster{PowerKW: 13, ReturnLevel: 20, Shortfall: charging.ShortfallReject}
+
+// rangeEstimator uses the default consumption of the sample fleet and rejects
+// reservations for trips beyond the range
+var rangeEstimator = trip.Estimator{
+	KWhPerKM:     0.18,
+	Models:       trip.ModelConsumption{"Tesla Model 3": 0.15, "Nissan Leaf": 0.17, "Chevrolet Bolt": 0.16, "Hyundai Kona Electric": 0.15, "BMW i3": 0.16},
+	SafetyMargin: 0.15,
+	OutOfRange:   trip.OutOfRangeReject,
+}
+
+// telemetrySecret derives the telemetry tokens the tests report with
+const telemetrySecret = "integration-telemetry-secret"
+
+// telemetrySettings keep raw readings for a day, so the tests report recent ones
+var telemetrySettings = telemetry.Settings{Secret: telemetrySecret, RawRetention: 24 * time.Hour, DownsampleInterval: 15 * time.Minute, Retention: 48 * time.Hour}
+
+// zonePolicy charges returns outside the allowed return zone of the sample
+// geofences
+var zonePolicy = geofence.Policy{OutOfZone: geofence.OutOfZoneFee, Fee: 50, MaxPositionAge: 15 * time.Minute}
+
+// maintenanceSettings report the sample vehicles due within 3000 km of their
+// next service, which is vehicle 1 at 12000 km
+var maintenanceSettings = maintenance.Settings{ServiceIntervalKM: 15000, DueWithinKM: 3000}
+
+// adminToken is the token of the fleet operators in the tests
+const adminToken = "integration-admin-token"
+
+// serviceToken proves to vehicle_service that user_service sent a membership
+const serviceToken = "integration-service-token"
+
+// harness holds the three services and their in-memory stores
+type harness struct {
+	t        *testing.T
+	user     *httptest.Server
+	vehicle  *httptest.Server
+	billing  *httptest.Server
+	users    *usermodels.MemoryStore
+	vehicles *vehiclemodels.MemoryStore
+	bills    *billingmodels.MemoryStore
+
+	// now is the time of vehicle_service; zero means the system clock
+	now time.Time
+}
+
+// startHarness boots vehicle_service and billing_service first so that the
+// user_service proxies and readiness checks can be pointed at their URLs
+func startHarness(t *testing.T) *harness {
+	t.Helper()
+	h := &harness{
+		t:        t,
+		users:    usermodels.NewMemoryStore(),
+		vehicles: vehiclemodels.NewMemoryStore(),
+		bills:    billingmodels.NewMemoryStore(),
+	}
+	h.vehicles.SeedSampleFleet()
+
+	vehiclemodels.UseRepositories(h.vehicles.Repositories())
+	h.vehicle = httptest.NewServer(checkResponses(t, vehicleapi.Spec, vehicleserver.NewHandler(vehicleserver.Options{
+		Logger:           quietLogger("vehicle_service"),
+		Metrics:          metrics.New("vehicle_service"),
+		ValidateRequests: true,
+		Legacy:           legacyPolicy,
+		Booking:          bookingPolicy,
+		Charging:         &chargeForecast,
+		Range:            &rangeEstimator,
+		Telemetry:        telemetrySettings,
+		Geofence:         zonePolicy,
+		Maintenance:      maintenanceSettings,
+		ServiceToken:     serviceToken,
+		AdminToken:       adminToken,
+		Clock:            h.clock,
+	})))
+	t.Cleanup(h.vehicle.Close)
+
+	billingmodels.UseRepositories(h.bills.Repositories())
+	h.billing = httptest.NewServer(checkResponses(t, billingapi.Spec, billingserver.NewHandler(billingserver.Options{
+		Logger:           quietLogger("billing_service"),
+		Metrics:          metrics.New("billing_service"),
+		ValidateRequests: true,
+		Legacy:           legacyPolicy,
+	})))
+	t.Cleanup(h.billing.Close)
+
+	usermodels.UseRepositories(h.users.Repositories())
+	usercontrollers.UseSessionSecret("integration-test-secret")
+	usercontrollers.VehicleServiceURL = h.vehicle.URL
+	usercontrollers.BillingServiceURL = h.billing.URL
+	usercontrollers.UseServiceToken(serviceToken)
+	h.user = httptest.NewServer(checkResponses(t, userapi.Spec, userserver.NewHandler(userserver.Options{
+		Logger:           quietLogger("user_service"),
+		Metrics:          metrics.New("user_service"),
+		ValidateRequests: true,
+		Legacy:           legacyPolicy,
+		RateLimits:       userserver.RateLimits{Login: loginLimit},
+	})))
+	t.Cleanup(h.user.Close)
+
+	return h
+}
+
+// clock is the clock of vehicle_service. The tests book fixed dates in 2030
+// and set now to act on those reservations once they have begun.
+func (h *harness) clock() time.Time {
+	if h.now.IsZero() {
+		return time.Now()
+	}
+	return h.now
+}
+
+// checkResponses fails the test for every response of handler that does not
+// match its OpenAPI document. Together with ValidateRequests this catches drift
+// between the specs, the handlers and the generated clients.
+func checkResponses(t *testing.T, document []byte, handler http.Handler) http.Handler {
+	return apispec.MustLoad(document).ReportResponses(handler, func(r *http.Request, err error) {
+		t.Errorf("%s %s: response does not match the OpenAPI document: %v", r.Method, r.URL.Path, err)
+	})
+}
+
+func quietLogger(service string) *slog.Logger {
+	return logging.NewWithWriter(service, io.Discard, slog.LevelError)
+}
+
+// client is a browser-like HTTP client with its own cookie jar. Like the
+// static pages, it keeps the CSRF token of the last login or /v1/csrf-token
+// response and sends it with every state-changing request.
+type client struct {
+	h         *harness
+	http      *http.Client
+	csrfToken string
+}
+
+func (h *harness) newClient() *client {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	return &client{h: h, http: &http.Client{Jar: jar}}
+}
+
+// response is a decoded JSON response
+type response struct {
+	status int
+	header http.Header
+	body   map[string]interface{}
+	raw    string
+}
+
+// do sends a JSON request and decodes the JSON response
+func (c *client) do(method, url string, payload interface{}) response {
+	c.h.t.Helper()
+	return c.doWithHeader(method, url, nil, payload)
+}
+
+// doWithHeader is do with extra request headers
+func (c *client) doWithHeader(method, url string, header http.Header, payload interface{}) response {
+	c.h.t.Helper()
+	var reader io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			c.h.t.Fatal(err)
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		c.h.t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.csrfToken != "" && method != "GET" && req.Header.Get(usercontrollers.CSRFHeader) == "" {
+		req.Header.Set(usercontrollers.CSRFHeader, c.csrfToken)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		c.h.t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(resp.Body)
+	r := response{status: resp.StatusCode, header: resp.Header, raw: string(raw)}
+	json.Unmarshal(raw, &r.body)
+	if token, ok := r.body["csrf_token"].(string); ok {
+		c.csrfToken = token
+	}
+	return r
+}
+
+// expect fails the test when the response status differs from want
+func (r response) expect(t *testing.T, step string, want int) response {
+	t.Helper()
+	if r.status != want {
+		t.Fatalf("%s: got status %d, want %d: %s", step, r.status, want, r.raw)
+	}
+	return r
+}
+
+// data returns the "data" object of the response
+func (r response) data(t *testing.T) map[string]interface{} {
+	t.Helper()
+	data, ok := r.body["data"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("response has no data object: %s", r.raw)
+	}
+	return data
+}
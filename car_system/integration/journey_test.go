@@ -0,0 +1,712 @@
+package integration
+
+import (
+	"car_system/vehicle_service/telemetry"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var journeyUser = map[string]string{
+	"name":     "Alice Tan",
+	"email":    "alice@example.com",
+	"phone_no": "91234567",
+	"password": "s3cret-pass",
+	"dob":      "1995-04-01",
+}
+
+// signUp registers journeyUser and returns a client holding its session cookie
+func signUp(t *testing.T, h *harness) *client {
+	t.Helper()
+	c := h.newClient()
+	c.do("POST", h.user.URL+"/v1/users", journeyUser).expect(t, "register", http.StatusOK)
+	c.do("POST", h.user.URL+"/v1/sessions", map[string]string{
+		"email":    journeyUser["email"],
+		"password": journeyUser["password"],
+	}).expect(t, "login", http.StatusOK)
+	return c
+}
+
+// TestBookingJourney walks through sign up, search, reservation, fee
+// calculation and billing across all three services
+func TestBookingJourney(t *testing.T) {
+	h := startHarness(t)
+	c := signUp(t, h)
+
+	details := c.do("GET", h.user.URL+"/v1/me", nil).expect(t, "view-details", http.StatusOK).data(t)
+	if details["email"] != journeyUser["email"] {
+		t.Fatalf("view-details email = %v, want %s", details["email"], journeyUser["email"])
+	}
+	userID := details["user_id"]
+
+	resp := c.do("GET", h.user.URL+"/v1/vehicles", nil).expect(t, "available vehicles", http.StatusOK)
+	vehicles, _ := resp.body["vehicles"].([]interface{})
+	if len(vehicles) != 5 {
+		t.Fatalf("got %d available vehicles, want 5", len(vehicles))
+	}
+
+	reservation := map[string]interface{}{
+		"vehicle_id": 2,
+		"start_time": "2030-01-01T10:00:00Z",
+		"end_time":   "2030-01-01T13:00:00Z",
+	}
+	c.do("POST", h.user.URL+"/v1/reservations", reservation).expect(t, "create reservation", http.StatusOK)
+
+	stored := h.vehicles.Reservations()
+	if len(stored) != 1 {
+		t.Fatalf("vehicle_service stored %d reservations, want 1", len(stored))
+	}
+	if float64(stored[0].UserID) != userID {
+		t.Errorf("reservation user_id = %d, want the session user %v", stored[0].UserID, userID)
+	}
+
+	latest := c.do("GET", h.user.URL+"/v1/reservations/latest", nil).expect(t, "latest reservation", http.StatusOK).data(t)
+	if latest["vehicle_id"] != float64(2) {
+		t.Fatalf("latest reservation vehicle_id = %v, want 2", latest["vehicle_id"])
+	}
+
+	fee := c.do("POST", h.user.URL+"/v1/rental-fees", map[string]interface{}{
+		"reservation_id": latest["reservation_id"],
+		"vehicle_id":     latest["vehicle_id"],
+		"start_time":     "2030-01-01T10:00:00Z",
+		"end_time":       "2030-01-01T13:00:00Z",
+	}).expect(t, "calculate fee", http.StatusOK)
+	// Vehicle 2 of the sample fleet rents at 40 per hour
+	if fee.body["total_fee"] != float64(120) {
+		t.Fatalf("total_fee = %v, want 120", fee.body["total_fee"])
+	}
+
+	c.do("POST", h.billing.URL+"/v1/bills", map[string]interface{}{
+		"user_id":        userID,
+		"reservation_id": latest["reservation_id"],
+		"amount":         fee.body["total_fee"],
+		"status":         "Pending",
+	}).expect(t, "insert bill", http.StatusCreated)
+
+	bills := h.bills.Bills()
+	if len(bills) != 1 || bills[0].Amount != 120 {
+		t.Fatalf("billing_service stored %+v, want one bill of 120", bills)
+	}
+}
+
+func TestReservationConflict(t *testing.T) {
+	h := startHarness(t)
+	c := signUp(t, h)
+
+	reservation := map[string]interface{}{
+		"vehicle_id": 1,
+		"start_time": "2030-01-01T10:00:00Z",
+		"end_time":   "2030-01-01T12:00:00Z",
+	}
+	c.do("POST", h.user.URL+"/v1/reservations", reservation).expect(t, "first reservation", http.StatusOK)
+
+	overlapping := map[string]interface{}{
+		"vehicle_id": 1,
+		"start_time": "2030-01-01T11:00:00Z",
+		"end_time":   "2030-01-01T14:00:00Z",
+	}
+	resp := c.do("POST", h.user.URL+"/v1/reservations", overlapping).expect(t, "overlapping reservation", http.StatusConflict)
+	if resp.body["code"] != "VEHICLE_UNAVAILABLE" || resp.body["request_id"] == nil {
+		t.Errorf("overlapping reservation: got %s, want code VEHICLE_UNAVAILABLE with a request_id", resp.raw)
+	}
+
+	if n := len(h.vehicles.Reservations()); n != 1 {
+		t.Errorf("vehicle_service stored %d reservations, want 1", n)
+	}
+}
+
+func TestProxiesRequireSession(t *testing.T) {
+	h := startHarness(t)
+	c := h.newClient()
+
+	c.do("POST", h.user.URL+"/v1/reservations", map[string]interface{}{
+		"vehicle_id": 1,
+		"start_time": "2030-01-01T10:00:00Z",
+		"end_time":   "2030-01-01T12:00:00Z",
+	}).expect(t, "create reservation without session", http.StatusUnauthorized)
+	c.do("GET", h.user.URL+"/v1/reservations/latest", nil).expect(t, "latest reservation without session", http.StatusUnauthorized)
+
+	if n := len(h.vehicles.Reservations()); n != 0 {
+		t.Errorf("vehicle_service stored %d reservations, want 0", n)
+	}
+}
+
+func TestReadinessFollowsUpstreams(t *testing.T) {
+	h := startHarness(t)
+	c := h.newClient()
+
+	c.do("GET", h.user.URL+"/readyz", nil).expect(t, "readyz with upstreams up", http.StatusOK)
+
+	h.billing.Close()
+	resp := c.do("GET", h.user.URL+"/readyz", nil).expect(t, "readyz with billing_service down", http.StatusServiceUnavailable)
+	checks, _ := resp.body["checks"].(map[string]interface{})
+	billing, _ := checks["billing_service"].(map[string]interface{})
+	if billing["status"] == "ok" {
+		t.Errorf("billing_service check = %v, want a failure", billing)
+	}
+}
+
+func TestSpecsAreServedAndEnforced(t *testing.T) {
+	h := startHarness(t)
+	c := h.newClient()
+
+	for name, url := range map[string]string{"user_service": h.user.URL, "vehicle_service": h.vehicle.URL, "billing_service": h.billing.URL} {
+		doc := c.do("GET", url+"/openapi.json", nil).expect(t, name+" openapi.json", http.StatusOK)
+		if info, _ := doc.body["info"].(map[string]interface{}); info["title"] != name {
+			t.Errorf("%s serves the document of %v", name, info["title"])
+		}
+	}
+
+	// total_fee used to be sent by user_service and silently ignored
+	resp := c.do("POST", h.billing.URL+"/v1/rental-fees", map[string]interface{}{
+		"start_time":  "2030-01-01T10:00:00Z",
+		"end_time":    "2030-01-01T13:00:00Z",
+		"rental_rate": 40,
+		"total_fee":   120,
+	}).expect(t, "undocumented field", http.StatusBadRequest)
+	if resp.body["code"] != "VALIDATION_FAILED" {
+		t.Errorf("undocumented field: got %s, want VALIDATION_FAILED", resp.raw)
+	}
+}
+
+// TestLegacyRoutes checks that the unversioned routes used before /v1 still
+// work, announce their successor and are counted
+func TestLegacyRoutes(t *testing.T) {
+	h := startHarness(t)
+	c := h.newClient()
+
+	c.do("POST", h.user.URL+"/api/register", journeyUser).expect(t, "legacy register", http.StatusOK)
+	c.do("POST", h.user.URL+"/api/login", map[string]string{
+		"email":    journeyUser["email"],
+		"password": journeyUser["password"],
+	}).expect(t, "legacy login", http.StatusOK)
+	resp := c.do("POST", h.user.URL+"/api/proxy-create-reservation", map[string]interface{}{
+		"vehicle_id": 4,
+		"start_time": "2030-01-01T10:00:00Z",
+		"end_time":   "2030-01-01T12:00:00Z",
+	}).expect(t, "legacy create reservation", http.StatusOK)
+
+	for header, want := range map[string]string{
+		"Deprecation": "@1792368000",
+		"Sunset":      "Fri, 30 Apr 2027 00:00:00 GMT",
+		"Link":        `</v1/reservations>; rel="successor-version"`,
+	} {
+		if got := resp.header.Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+	if n := len(h.vehicles.Reservations()); n != 1 {
+		t.Errorf("vehicle_service stored %d reservations, want 1", n)
+	}
+
+	legacy := c.do("POST", h.billing.URL+"/billing", map[string]interface{}{
+		"user_id": 1, "reservation_id": 1, "amount": 80, "status": "Pending",
+	}).expect(t, "legacy billing", http.StatusCreated)
+	if legacy.header.Get("Deprecation") == "" {
+		t.Error("legacy billing route has no Deprecation header")
+	}
+
+	userMetrics := c.do("GET", h.user.URL+"/metrics", nil).raw
+	if !strings.Contains(userMetrics, `http_deprecated_requests_total{route="/api/proxy-create-reservation",service="user_service",successor="/v1/reservations"} 1`) {
+		t.Error("user_service did not count the legacy reservation request")
+	}
+	// user_service itself calls the versioned routes
+	vehicleMetrics := c.do("GET", h.vehicle.URL+"/metrics", nil).raw
+	if !strings.Contains(vehicleMetrics, `http_deprecated_requests_total{route="/create-reservation",service="vehicle_service",successor="/v1/reservations"} 0`) {
+		t.Error("user_service proxied the reservation to a legacy vehicle_service route")
+	}
+}
+
+// TestIdempotentRetries retries a reservation and a bill the way a mobile
+// client does after a timeout
+func TestIdempotentRetries(t *testing.T) {
+	h := startHarness(t)
+	c := signUp(t, h)
+	key := http.Header{"Idempotency-Key": {"3f1c2a9e-reservation"}}
+
+	reservation := map[string]interface{}{
+		"vehicle_id": 3,
+		"start_time": "2030-01-01T10:00:00Z",
+		"end_time":   "2030-01-01T12:00:00Z",
+	}
+	first := c.doWithHeader("POST", h.user.URL+"/v1/reservations", key, reservation).expect(t, "create reservation", http.StatusOK)
+	retry := c.doWithHeader("POST", h.user.URL+"/v1/reservations", key, reservation).expect(t, "retried reservation", http.StatusOK)
+	if retry.header.Get("Idempotent-Replayed") != "true" || retry.data(t)["reservation_id"] != first.data(t)["reservation_id"] {
+		t.Errorf("retry was not replayed: got %s, want %s", retry.raw, first.raw)
+	}
+	if n := len(h.vehicles.Reservations()); n != 1 {
+		t.Errorf("vehicle_service stored %d reservations, want 1", n)
+	}
+
+	reservation["end_time"] = "2030-01-01T15:00:00Z"
+	resp := c.doWithHeader("POST", h.user.URL+"/v1/reservations", key, reservation).expect(t, "reused key", http.StatusConflict)
+	if resp.body["code"] != "IDEMPOTENCY_KEY_REUSED" {
+		t.Errorf("reused key: got %s, want IDEMPOTENCY_KEY_REUSED", resp.raw)
+	}
+
+	bill := map[string]interface{}{
+		"user_id":        1,
+		"reservation_id": first.data(t)["reservation_id"],
+		"amount":         80,
+		"status":         "Pending",
+	}
+	billKey := http.Header{"Idempotency-Key": {"3f1c2a9e-bill"}}
+	c.doWithHeader("POST", h.billing.URL+"/v1/bills", billKey, bill).expect(t, "insert bill", http.StatusCreated)
+	c.doWithHeader("POST", h.billing.URL+"/v1/bills", billKey, bill).expect(t, "retried bill", http.StatusCreated)
+
+	// Without a key the second bill is stopped by the reservation_id constraint
+	resp = c.do("POST", h.billing.URL+"/v1/bills", bill).expect(t, "duplicate bill", http.StatusConflict)
+	if resp.body["code"] != "BILL_ALREADY_EXISTS" {
+		t.Errorf("duplicate bill: got %s, want BILL_ALREADY_EXISTS", resp.raw)
+	}
+	if n := len(h.bills.Bills()); n != 1 {
+		t.Errorf("billing_service stored %d bills, want 1", n)
+	}
+}
+
+// TestLoginRateLimit guesses passwords until user_service throttles the client
+func TestLoginRateLimit(t *testing.T) {
+	h := startHarness(t)
+	c := h.newClient()
+	c.do("POST", h.user.URL+"/v1/users", journeyUser).expect(t, "register", http.StatusOK)
+
+	guess := map[string]string{"email": journeyUser["email"], "password": "wrong"}
+	for i := 0; i < loginLimit.Burst; i++ {
+		c.do("POST", h.user.URL+"/v1/sessions", guess).expect(t, "wrong password", http.StatusUnauthorized)
+	}
+	resp := c.do("POST", h.user.URL+"/v1/sessions", guess).expect(t, "throttled login", http.StatusTooManyRequests)
+	if resp.body["code"] != "RATE_LIMITED" || resp.header.Get("Retry-After") == "" || resp.header.Get("RateLimit-Remaining") != "0" {
+		t.Errorf("throttled login: got %s with headers %v", resp.raw, resp.header)
+	}
+
+	// The legacy alias shares the bucket, and other routes are unaffected
+	c.do("POST", h.user.URL+"/api/login", guess).expect(t, "throttled legacy login", http.StatusTooManyRequests)
+	c.do("GET", h.user.URL+"/v1/vehicles", nil).expect(t, "available vehicles", http.StatusOK)
+}
+
+// TestCSRFProtection sends state-changing requests the way a cross-site page
+// would: with the session cookie but without the session's CSRF token
+func TestCSRFProtection(t *testing.T) {
+	h := startHarness(t)
+	c := signUp(t, h)
+
+	forged := http.Header{"X-Csrf-Token": {"forged"}, "Idempotency-Key": {"7d2e-reservation"}}
+	reservation := map[string]interface{}{
+		"vehicle_id": 1,
+		"start_time": "2030-01-01T10:00:00Z",
+		"end_time":   "2030-01-01T12:00:00Z",
+	}
+	resp := c.doWithHeader("POST", h.user.URL+"/v1/reservations", forged, reservation).expect(t, "forged reservation", http.StatusForbidden)
+	if resp.body["code"] != "CSRF_TOKEN_INVALID" {
+		t.Errorf("forged reservation: got %s, want CSRF_TOKEN_INVALID", resp.raw)
+	}
+	c.doWithHeader("PUT", h.user.URL+"/api/update-details", forged, map[string]string{"name": "Mallory"}).expect(t, "forged legacy update", http.StatusForbidden)
+	if n := len(h.vehicles.Reservations()); n != 0 {
+		t.Errorf("vehicle_service stored %d reservations, want none", n)
+	}
+
+	// A page that fetches the token can retry with the same Idempotency-Key,
+	// because the rejection was not stored
+	c.csrfToken = ""
+	c.do("GET", h.user.URL+"/v1/csrf-token", nil).expect(t, "csrf token", http.StatusOK)
+	retry := http.Header{"Idempotency-Key": forged["Idempotency-Key"]}
+	c.doWithHeader("POST", h.user.URL+"/v1/reservations", retry, reservation).expect(t, "reservation with token", http.StatusOK)
+
+	// Without a session the token endpoint has nothing to protect
+	h.newClient().do("GET", h.user.URL+"/v1/csrf-token", nil).expect(t, "csrf token without session", http.StatusUnauthorized)
+}
+
+// TestBookingPolicy checks that user_service passes the membership tier on and
+// vehicle_service applies the booking rules of that tier
+func TestBookingPolicy(t *testing.T) {
+	h := startHarness(t)
+	c := signUp(t, h)
+
+	fourDays := map[string]interface{}{
+		"vehicle_id": 1,
+		"start_time": "2030-01-01T10:00:00Z",
+		"end_time":   "2030-01-05T10:00:00Z",
+	}
+	resp := c.do("POST", h.user.URL+"/v1/reservations", fourDays).expect(t, "4 days as Basic", http.StatusUnprocessableEntity)
+	if resp.body["code"] != "BOOKING_TOO_LONG" {
+		t.Errorf("4 days as Basic: got %s, want BOOKING_TOO_LONG", resp.raw)
+	}
+
+	if err := h.users.SetTier(1, "VIP"); err != nil {
+		t.Fatal(err)
+	}
+	c.do("POST", h.user.URL+"/v1/reservations", fourDays).expect(t, "4 days as VIP", http.StatusOK)
+
+	short := map[string]interface{}{
+		"vehicle_id": 2,
+		"start_time": "2030-01-01T10:00:00Z",
+		"end_time":   "2030-01-01T10:30:00Z",
+	}
+	resp = c.do("POST", h.user.URL+"/v1/reservations", short).expect(t, "30 minutes", http.StatusUnprocessableEntity)
+	if resp.body["code"] != "BOOKING_TOO_SHORT" {
+		t.Errorf("30 minutes: got %s, want BOOKING_TOO_SHORT", resp.raw)
+	}
+}
+
+// TestTurnaround checks that the search, the calendar and new reservations
+// keep the turnaround after a reservation free
+func TestTurnaround(t *testing.T) {
+	h := startHarness(t)
+	c := signUp(t, h)
+
+	c.do("POST", h.user.URL+"/v1/reservations", map[string]interface{}{
+		"vehicle_id": 1,
+		"start_time": "2030-01-01T10:00:00Z",
+		"end_time":   "2030-01-01T12:00:00Z",
+	}).expect(t, "first reservation", http.StatusOK)
+
+	resp := c.do("GET", h.user.URL+"/v1/vehicles?start_time=2030-01-01T12:15:00Z&end_time=2030-01-01T14:00:00Z", nil).expect(t, "search", http.StatusOK)
+	vehicles, _ := resp.body["vehicles"].([]interface{})
+	for _, v := range vehicles {
+		if v.(map[string]interface{})["vehicle_id"] == float64(1) {
+			t.Error("search lists vehicle 1 during its turnaround")
+		}
+	}
+	if len(vehicles) != 4 {
+		t.Errorf("search found %d vehicles, want 4", len(vehicles))
+	}
+
+	calendar := c.do("GET", h.vehicle.URL+"/v1/vehicles/1/calendar?from=2030-01-01T00:00:00Z&to=2030-01-02T00:00:00Z", nil).expect(t, "calendar", http.StatusOK).data(t)
+	entries, _ := calendar["entries"].([]interface{})
+	if len(entries) != 2 || entries[1].(map[string]interface{})["end_time"] != "2030-01-01T12:30:00Z" {
+		t.Errorf("calendar entries = %v, want the reservation and its turnaround until 12:30", entries)
+	}
+
+	c.do("POST", h.user.URL+"/v1/reservations", map[string]interface{}{
+		"vehicle_id": 1,
+		"start_time": "2030-01-01T12:15:00Z",
+		"end_time":   "2030-01-01T14:00:00Z",
+	}).expect(t, "reservation during the turnaround", http.StatusConflict)
+	c.do("POST", h.user.URL+"/v1/reservations", map[string]interface{}{
+		"vehicle_id": 1,
+		"start_time": "2030-01-01T12:30:00Z",
+		"end_time":   "2030-01-01T14:00:00Z",
+	}).expect(t, "reservation after the turnaround", http.StatusOK)
+}
+
+// TestChargeForecast checks that a reservation must leave the vehicle time to
+// recharge for the next one, and that the search shows the forecast
+func TestChargeForecast(t *testing.T) {
+	h := startHarness(t)
+	c := signUp(t, h)
+
+	c.do("POST", h.user.URL+"/v1/reservations", map[string]interface{}{
+		"vehicle_id": 3,
+		"start_time": "2030-01-01T10:00:00Z",
+		"end_time":   "2030-01-01T12:00:00Z",
+	}).expect(t, "first reservation", http.StatusOK)
+
+	// Vehicle 3 is assumed back at 20% and charges to 40% by 13:00
+	resp := c.do("POST", h.user.URL+"/v1/reservations", map[string]interface{}{
+		"vehicle_id":            3,
+		"start_time":            "2030-01-01T13:00:00Z",
+		"end_time":              "2030-01-01T15:00:00Z",
+		"expected_charge_level": 80,
+	}).expect(t, "reservation expecting 80%", http.StatusConflict)
+	if resp.body["code"] != "CHARGE_LEVEL_UNREACHABLE" {
+		t.Errorf("reservation expecting 80%%: got %s, want CHARGE_LEVEL_UNREACHABLE", resp.raw)
+	}
+
+	resp = c.do("POST", h.user.URL+"/v1/reservations", map[string]interface{}{
+		"vehicle_id":            3,
+		"start_time":            "2030-01-01T16:00:00Z",
+		"end_time":              "2030-01-01T18:00:00Z",
+		"expected_charge_level": 80,
+	}).expect(t, "reservation after recharging", http.StatusOK)
+	if resp.body["predicted_charge_level"] != float64(100) {
+		t.Errorf("predicted_charge_level = %v, want 100", resp.body["predicted_charge_level"])
+	}
+
+	resp = c.do("GET", h.user.URL+"/v1/vehicles?start_time=2030-01-01T12:30:00Z&end_time=2030-01-01T15:00:00Z", nil).expect(t, "search", http.StatusOK)
+	vehicles, _ := resp.body["vehicles"].([]interface{})
+	for _, v := range vehicles {
+		vehicle := v.(map[string]interface{})
+		if vehicle["vehicle_id"] == float64(3) && vehicle["predicted_charge_level"] != float64(30) {
+			t.Errorf("vehicle 3 predicted at %v, want 30", vehicle["predicted_charge_level"])
+		}
+		if vehicle["predicted_charge_level"] == nil {
+			t.Errorf("vehicle %v has no predicted_charge_level", vehicle["vehicle_id"])
+		}
+	}
+}
+
+// TestTripRange checks the range search and the planned distance of a
+// reservation through the user_service proxies
+func TestTripRange(t *testing.T) {
+	h := startHarness(t)
+	c := signUp(t, h)
+
+	// Every vehicle is fully charged by 2030. Only the Tesla (500 km) covers
+	// 400 km and the 15% margin.
+	resp := c.do("GET", h.user.URL+"/v1/vehicles?start_time=2030-01-01T10:00:00Z&end_time=2030-01-01T18:00:00Z&distance_km=400", nil).expect(t, "range search", http.StatusOK)
+	vehicles, _ := resp.body["vehicles"].([]interface{})
+	if len(vehicles) != 1 || vehicles[0].(map[string]interface{})["estimated_range_km"] != float64(500) {
+		t.Errorf("range search found %v, want only vehicle 1 with 500 km", vehicles)
+	}
+
+	resp = c.do("POST", h.user.URL+"/v1/reservations", map[string]interface{}{
+		"vehicle_id":          5,
+		"start_time":          "2030-01-01T10:00:00Z",
+		"end_time":            "2030-01-01T18:00:00Z",
+		"planned_distance_km": 400,
+	}).expect(t, "400 km in the BMW i3", http.StatusConflict)
+	if resp.body["code"] != "TRIP_OUT_OF_RANGE" {
+		t.Errorf("400 km in the BMW i3: got %s, want TRIP_OUT_OF_RANGE", resp.raw)
+	}
+	c.do("POST", h.user.URL+"/v1/reservations", map[string]interface{}{
+		"vehicle_id":          1,
+		"start_time":          "2030-01-01T10:00:00Z",
+		"end_time":            "2030-01-01T18:00:00Z",
+		"planned_distance_km": 400,
+	}).expect(t, "400 km in the Tesla", http.StatusOK)
+}
+
+// TestTelemetry reports the state of a vehicle with its token and finds it in
+// the listing served through user_service
+func TestTelemetry(t *testing.T) {
+	h := startHarness(t)
+	c := signUp(t, h)
+	device := h.newClient()
+
+	url := h.vehicle.URL + "/v1/vehicles/2/telemetry"
+	recordedAt := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+	report := map[string]interface{}{"readings": []map[string]interface{}{{
+		"recorded_at":  recordedAt,
+		"latitude":     1.3644,
+		"longitude":    103.9915,
+		"charge_level": 64.5,
+		"odometer_km":  15042.7,
+		"locked":       false,
+		"speed_kmh":    42,
+	}}}
+	device.do("POST", url, report).expect(t, "report without a token", http.StatusUnauthorized)
+	device.doWithHeader("POST", url, http.Header{"Authorization": {"Bearer " + telemetry.TokenFor(telemetrySecret, 1)}}, report).
+		expect(t, "report with the token of vehicle 1", http.StatusUnauthorized)
+	device.doWithHeader("POST", url, http.Header{"Authorization": {"Bearer " + telemetry.TokenFor(telemetrySecret, 2)}}, report).
+		expect(t, "report", http.StatusAccepted)
+
+	var leaf map[string]interface{}
+	resp := c.do("GET", h.user.URL+"/v1/vehicles", nil).expect(t, "search", http.StatusOK)
+	vehicles, _ := resp.body["vehicles"].([]interface{})
+	for _, v := range vehicles {
+		if v := v.(map[string]interface{}); v["vehicle_id"] == float64(2) {
+			leaf = v
+		}
+	}
+	if leaf["charge_level"] != 64.5 || leaf["mileage"] != float64(15042) || leaf["latitude"] != 1.3644 || leaf["locked"] != false {
+		t.Errorf("vehicle 2 = %v, want the reported state", leaf)
+	}
+
+	history := device.do("GET", url, nil).expect(t, "telemetry history", http.StatusOK).data(t)
+	if readings, _ := history["readings"].([]interface{}); len(readings) != 1 {
+		t.Errorf("history = %v, want the reported reading", history)
+	}
+}
+
+// TestNearbyStations searches the stations and vehicles around a position
+// through the user_service proxy
+func TestNearbyStations(t *testing.T) {
+	h := startHarness(t)
+	c := signUp(t, h)
+
+	c.do("GET", h.user.URL+"/v1/stations/nearby?longitude=103.85", nil).expect(t, "search without a latitude", http.StatusBadRequest)
+	c.do("GET", h.user.URL+"/v1/stations/nearby?latitude=1.28&longitude=103.85&radius_km=500", nil).expect(t, "search too wide", http.StatusBadRequest)
+
+	// Near Raffles Place: Downtown Station is the nearest, then City Center
+	// and Train Station; the airport and the suburbs are further
+	nearby := c.do("GET", h.user.URL+"/v1/stations/nearby?latitude=1.2840&longitude=103.8514&radius_km=3", nil).expect(t, "nearby search", http.StatusOK).data(t)
+	stations, _ := nearby["stations"].([]interface{})
+	vehicles, _ := nearby["vehicles"].([]interface{})
+	var names []interface{}
+	for _, s := range stations {
+		names = append(names, s.(map[string]interface{})["name"])
+	}
+	if len(names) != 3 || names[0] != "Downtown Station" || names[2] != "Train Station" {
+		t.Errorf("stations = %v, want Downtown Station, City Center and Train Station", names)
+	}
+	if len(vehicles) != 3 || vehicles[0].(map[string]interface{})["location"] != "Downtown Station" || vehicles[0].(map[string]interface{})["distance_km"] == nil {
+		t.Errorf("vehicles = %v, want the vehicles of those 3 stations, nearest first", vehicles)
+	}
+
+	// A reservation of the Downtown vehicle leaves it out of a search of its
+	// window, while its station still shows up
+	c.do("POST", h.user.URL+"/v1/reservations", map[string]interface{}{
+		"vehicle_id": 1,
+		"start_time": "2030-01-01T10:00:00Z",
+		"end_time":   "2030-01-01T12:00:00Z",
+	}).expect(t, "reservation", http.StatusOK)
+	nearby = c.do("GET", h.user.URL+"/v1/stations/nearby?latitude=1.2840&longitude=103.8514&radius_km=1&start_time=2030-01-01T11:00:00Z&end_time=2030-01-01T13:00:00Z", nil).
+		expect(t, "nearby search of a window", http.StatusOK).data(t)
+	stations, _ = nearby["stations"].([]interface{})
+	vehicles, _ = nearby["vehicles"].([]interface{})
+	if len(stations) != 1 || stations[0].(map[string]interface{})["available_vehicles"] != float64(0) || len(vehicles) != 0 {
+		t.Errorf("nearby = %v, want Downtown Station without available vehicles", nearby)
+	}
+}
+
+// TestOneWayTrip books a vehicle from Downtown Station to Train Station, pays
+// the one-way fee with the rental and finds the vehicle at Train Station once
+// the trip is completed
+func TestOneWayTrip(t *testing.T) {
+	h := startHarness(t)
+	c := signUp(t, h)
+
+	reservation := map[string]interface{}{
+		"vehicle_id":        1,
+		"start_time":        "2030-01-01T10:00:00Z",
+		"end_time":          "2030-01-01T13:00:00Z",
+		"return_station_id": 5,
+	}
+	created := c.do("POST", h.user.URL+"/v1/reservations", reservation).expect(t, "one-way reservation", http.StatusOK).data(t)
+	// 10 plus 0.5 per km for the 2.78 km between the stations
+	if created["pickup_station_id"] != float64(1) || created["return_station_id"] != float64(5) || created["one_way_fee"] != 11.39 {
+		t.Fatalf("reservation = %v, want Downtown Station to Train Station for 11.39", created)
+	}
+
+	fee := c.do("POST", h.user.URL+"/v1/rental-fees", map[string]interface{}{
+		"reservation_id": created["reservation_id"],
+		"vehicle_id":     1,
+		"start_time":     "2030-01-01T10:00:00Z",
+		"end_time":       "2030-01-01T13:00:00Z",
+	}).expect(t, "calculate fee", http.StatusOK)
+	// Vehicle 1 of the sample fleet rents at 50 per hour
+	if total, _ := fee.body["total_fee"].(float64); fee.body["rental_fee"] != float64(150) || fee.body["one_way_fee"] != 11.39 || math.Abs(total-161.39) > 1e-9 {
+		t.Fatalf("fee = %s, want 150 plus the one-way fee of 11.39", fee.raw)
+	}
+
+	complete := h.user.URL + "/v1/reservations/" + strconv.Itoa(int(created["reservation_id"].(float64))) + "/complete"
+	resp := c.do("POST", complete, nil).expect(t, "complete before the start", http.StatusConflict)
+	if resp.body["code"] != "RESERVATION_NOT_STARTED" {
+		t.Errorf("complete before the start: got %s, want RESERVATION_NOT_STARTED", resp.raw)
+	}
+	h.now = time.Date(2030, 1, 1, 12, 45, 0, 0, time.UTC)
+	done := c.do("POST", complete, nil).expect(t, "complete", http.StatusOK).data(t)
+	if done["status"] != "Completed" || done["completed_at"] != "2030-01-01T12:45:00Z" {
+		t.Errorf("completed reservation = %v, want Completed at 12:45", done)
+	}
+	c.do("POST", complete, nil).expect(t, "complete twice", http.StatusConflict)
+
+	nearby := c.do("GET", h.user.URL+"/v1/stations/nearby?latitude=1.3008&longitude=103.8391&radius_km=0.5&start_time=2030-01-02T10:00:00Z&end_time=2030-01-02T12:00:00Z", nil).
+		expect(t, "search at Train Station", http.StatusOK).data(t)
+	if vehicles, _ := nearby["vehicles"].([]interface{}); len(vehicles) != 2 {
+		t.Errorf("vehicles at Train Station = %v, want vehicle 5 and the returned vehicle 1", vehicles)
+	}
+}
+
+// TestGeofencedReturn draws a no-parking zone, drives a vehicle out of the
+// service area and back, and pays the out-of-zone fee for parking it in the
+// zone instead of the lot of its station
+func TestGeofencedReturn(t *testing.T) {
+	h := startHarness(t)
+	c := signUp(t, h)
+	operator := h.newClient()
+
+	zone := map[string]interface{}{
+		"name": "Boat Quay",
+		"kind": "no_parking",
+		"geometry": map[string]interface{}{
+			"type":        "Polygon",
+			"coordinates": [][][]float64{{{103.8490, 1.2850}, {103.8510, 1.2850}, {103.8510, 1.2870}, {103.8490, 1.2870}, {103.8490, 1.2850}}},
+		},
+	}
+	operator.do("POST", h.vehicle.URL+"/v1/geofences", zone).expect(t, "draw without the admin token", http.StatusUnauthorized)
+	operator.doWithHeader("POST", h.vehicle.URL+"/v1/geofences", http.Header{"Authorization": {"Bearer " + adminToken}}, zone).
+		expect(t, "draw a no-parking zone", http.StatusCreated)
+	geofences := operator.do("GET", h.vehicle.URL+"/v1/geofences", nil).expect(t, "list geofences", http.StatusOK)
+	if list, _ := geofences.body["data"].([]interface{}); len(list) != 4 {
+		t.Fatalf("geofences = %s, want the 3 sample geofences and Boat Quay", geofences.raw)
+	}
+
+	created := c.do("POST", h.user.URL+"/v1/reservations", map[string]interface{}{
+		"vehicle_id": 1,
+		"start_time": "2030-01-01T10:00:00Z",
+		"end_time":   "2030-01-01T13:00:00Z",
+	}).expect(t, "reservation", http.StatusOK).data(t)
+	id := strconv.Itoa(int(created["reservation_id"].(float64)))
+
+	// Out of the Downtown Station lot, across the causeway and back to Boat
+	// Quay
+	h.now = time.Date(2030, 1, 1, 11, 0, 0, 0, time.UTC)
+	var readings []map[string]interface{}
+	for _, r := range []struct {
+		at       string
+		lat, lon float64
+	}{{"10:10", 1.2796, 103.8525}, {"10:20", 1.4300, 103.7700}, {"10:30", 1.4700, 103.7700}, {"10:40", 1.4300, 103.7700}, {"10:50", 1.2860, 103.8500}} {
+		readings = append(readings, map[string]interface{}{
+			"recorded_at": "2030-01-01T" + r.at + ":00Z", "latitude": r.lat, "longitude": r.lon,
+			"charge_level": 70, "odometer_km": 12060, "locked": false, "speed_kmh": 40,
+		})
+	}
+	operator.doWithHeader("POST", h.vehicle.URL+"/v1/vehicles/1/telemetry", http.Header{"Authorization": {"Bearer " + telemetry.TokenFor(telemetrySecret, 1)}},
+		map[string]interface{}{"readings": readings}).expect(t, "report the trip", http.StatusAccepted)
+
+	operator.do("GET", h.vehicle.URL+"/v1/reservations/"+id+"/zone-exits", nil).expect(t, "zone exits without the admin token", http.StatusUnauthorized)
+	exits := operator.doWithHeader("GET", h.vehicle.URL+"/v1/reservations/"+id+"/zone-exits", http.Header{"Authorization": {"Bearer " + adminToken}}, nil).
+		expect(t, "zone exits", http.StatusOK)
+	if list, _ := exits.body["data"].([]interface{}); len(list) != 1 || list[0].(map[string]interface{})["recorded_at"] != "2030-01-01T10:30:00Z" {
+		t.Errorf("zone exits = %s, want Singapore left at 10:30", exits.raw)
+	}
+
+	done := c.do("POST", h.user.URL+"/v1/reservations/"+id+"/complete", nil).expect(t, "complete in Boat Quay", http.StatusOK).data(t)
+	if done["out_of_zone_fee"] != float64(50) {
+		t.Errorf("completed reservation = %v, want an out-of-zone fee of 50", done)
+	}
+	fee := c.do("POST", h.user.URL+"/v1/rental-fees", map[string]interface{}{
+		"reservation_id": created["reservation_id"],
+		"vehicle_id":     1,
+		"start_time":     "2030-01-01T10:00:00Z",
+		"end_time":       "2030-01-01T13:00:00Z",
+	}).expect(t, "calculate fee", http.StatusOK)
+	if fee.body["out_of_zone_fee"] != float64(50) || fee.body["total_fee"] != float64(200) {
+		t.Errorf("fee = %s, want 150 plus the out-of-zone fee of 50", fee.raw)
+	}
+}
+
+// TestMaintenanceWindow schedules the service of a vehicle due for it, which
+// blocks its reservations until the work order is cancelled
+func TestMaintenanceWindow(t *testing.T) {
+	h := startHarness(t)
+	c := signUp(t, h)
+	operator := h.newClient()
+	admin := http.Header{"Authorization": {"Bearer " + adminToken}}
+	h.now = time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	due := operator.doWithHeader("GET", h.vehicle.URL+"/v1/maintenance/due", admin, nil).expect(t, "service due", http.StatusOK)
+	if list, _ := due.body["data"].([]interface{}); len(list) != 1 || list[0].(map[string]interface{})["due_at_km"] != float64(15000) {
+		t.Fatalf("service due = %s, want vehicle 1 at 15000 km", due.raw)
+	}
+
+	order := operator.doWithHeader("POST", h.vehicle.URL+"/v1/work-orders", admin, map[string]interface{}{
+		"vehicle_id":    1,
+		"type":          "service",
+		"planned_start": "2030-01-01T10:00:00Z",
+		"planned_end":   "2030-01-01T12:00:00Z",
+	}).expect(t, "schedule the service", http.StatusCreated).data(t)
+	id := strconv.Itoa(int(order["work_order_id"].(float64)))
+
+	reservation := map[string]interface{}{
+		"vehicle_id": 1,
+		"start_time": "2030-01-01T11:00:00Z",
+		"end_time":   "2030-01-01T13:00:00Z",
+	}
+	resp := c.do("POST", h.user.URL+"/v1/reservations", reservation).expect(t, "reservation during the service", http.StatusConflict)
+	if resp.body["code"] != "VEHICLE_UNAVAILABLE" {
+		t.Errorf("reservation during the service: got %s, want code VEHICLE_UNAVAILABLE", resp.raw)
+	}
+	calendar := operator.do("GET", h.vehicle.URL+"/v1/vehicles/1/calendar?from=2030-01-01T00:00:00Z&to=2030-01-02T00:00:00Z", nil).
+		expect(t, "calendar", http.StatusOK).data(t)
+	if entries, _ := calendar["entries"].([]interface{}); len(entries) != 2 || entries[0].(map[string]interface{})["kind"] != "maintenance" || entries[1].(map[string]interface{})["kind"] != "turnaround" {
+		t.Errorf("calendar = %v, want the maintenance window and its turnaround", calendar)
+	}
+
+	operator.doWithHeader("POST", h.vehicle.URL+"/v1/work-orders/"+id+"/cancel", admin, nil).expect(t, "cancel the service", http.StatusOK)
+	c.do("POST", h.user.URL+"/v1/reservations", reservation).expect(t, "reservation after the cancellation", http.StatusOK)
+}
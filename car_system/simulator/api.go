@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	usercontrollers "car_system/user_service/controllers"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+)
+
+// apiClient calls one of the services as a single client: it keeps the
+// session cookie, and the CSRF token of the last login for the writes
+type apiClient struct {
+	baseURL   string
+	http      *http.Client
+	csrfToken string
+}
+
+// newAPIClient returns a client of the service at baseURL reached through
+// transport
+func newAPIClient(baseURL string, transport http.RoundTripper) *apiClient {
+	jar, _ := cookiejar.New(nil)
+	return &apiClient{baseURL: baseURL, http: &http.Client{Transport: transport, Jar: jar}}
+}
+
+// apiResponse is the status and decoded body of a response
+type apiResponse struct {
+	status int
+	body   struct {
+		Message   string          `json:"message"`
+		Code      string          `json:"code"`
+		Data      json.RawMessage `json:"data"`
+		Vehicles  json.RawMessage `json:"vehicles"`
+		UserID    int             `json:"user_id"`
+		CSRFToken string          `json:"csrf_token"`
+	}
+	raw []byte
+}
+
+// ok reports whether the request succeeded
+func (r apiResponse) ok() bool {
+	return r.status >= 200 && r.status < 300
+}
+
+// errorCode returns the error code of a failed request, or its status when
+// the body carries none
+func (r apiResponse) errorCode() string {
+	if r.body.Code != "" {
+		return r.body.Code
+	}
+	return fmt.Sprintf("HTTP_%d", r.status)
+}
+
+// do sends payload, when not nil, as JSON to path and decodes the response
+func (c *apiClient) do(ctx context.Context, method, path string, header http.Header, payload interface{}) (apiResponse, error) {
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return apiResponse{}, err
+		}
+		body = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return apiResponse{}, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.csrfToken != "" && method != http.MethodGet {
+		req.Header.Set(usercontrollers.CSRFHeader, c.csrfToken)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return apiResponse{}, err
+	}
+	defer resp.Body.Close()
+	r := apiResponse{status: resp.StatusCode}
+	if r.raw, err = io.ReadAll(resp.Body); err != nil {
+		return apiResponse{}, err
+	}
+	if len(r.raw) > 0 {
+		if err := json.Unmarshal(r.raw, &r.body); err != nil {
+			return apiResponse{}, fmt.Errorf("%s %s: %d with an invalid body: %w", method, path, resp.StatusCode, err)
+		}
+	}
+	return r, nil
+}
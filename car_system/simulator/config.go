@@ -0,0 +1,85 @@
+package main
+
+import (
+	"car_system/common/settings"
+	"car_system/vehicle_service/booking"
+	"car_system/vehicle_service/charging"
+	"car_system/vehicle_service/telemetry"
+	"car_system/vehicle_service/trip"
+	"fmt"
+	"time"
+)
+
+// Config is the configuration of a simulation run
+type Config struct {
+	Seed     int           `env:"SIM_SEED" default:"1" usage:"Seed of every random choice; a run with the same seed and settings replays the same events"`
+	Start    time.Time     `env:"SIM_START" default:"2030-01-01T06:00:00Z" usage:"Virtual time the simulation starts at; ignored against running services, which use the system clock"`
+	Duration time.Duration `env:"SIM_DURATION" default:"72h" usage:"Simulated time to run for"`
+	Step     time.Duration `env:"SIM_STEP" default:"1m" usage:"Simulated time between two updates of the fleet"`
+	Vehicles int           `env:"SIM_VEHICLES" default:"5" usage:"Number of vehicles to drive; in-process, vehicles beyond the sample fleet are added at its locations"`
+	Users    int           `env:"SIM_USERS" default:"10" usage:"Number of virtual users"`
+
+	BookingsPerDay float64       `env:"SIM_BOOKINGS_PER_USER_DAY" default:"2" usage:"Average booking attempts of a user per simulated day"`
+	TelemetryEvery time.Duration `env:"SIM_TELEMETRY_EVERY" default:"5m" usage:"Simulated time between two telemetry reports of a vehicle"`
+	EventsFile     string        `env:"SIM_EVENTS_FILE" usage:"Write every event as a JSON line to this file, or to standard output with -"`
+	LogLevel       string        `env:"LOG_LEVEL" default:"warn" usage:"Minimum log level of the in-process services (debug, info, warn, error)"`
+
+	// UserServiceURL and VehicleServiceURL point the simulation at running
+	// services. Without them the services run in-process on the in-memory
+	// backend and the virtual clock, and nothing touches the network.
+	UserServiceURL    string `env:"SIM_USER_SERVICE_URL" usage:"Base URL of a running user_service; in-process when empty"`
+	VehicleServiceURL string `env:"SIM_VEHICLE_SERVICE_URL" usage:"Base URL of a running vehicle_service; in-process when empty"`
+
+	// The vehicle_service settings configure the in-process services. The
+	// simulated vehicles consume energy per Range and charge per Charging in
+	// both modes, and sign their telemetry with Telemetry.Secret.
+	Booking   booking.Settings
+	Charging  charging.Settings
+	Range     trip.Settings
+	Telemetry telemetry.Settings
+}
+
+// inProcess reports whether the services run in the simulator process
+func (c Config) inProcess() bool {
+	return c.UserServiceURL == "" && c.VehicleServiceURL == ""
+}
+
+// loadConfig resolves the configuration from defaults, the optional .env
+// file, the environment and the command-line flags in args
+func loadConfig(args []string) (Config, []string, error) {
+	var cfg Config
+	rest, err := settings.Load("simulator", &cfg, args)
+	return cfg, rest, err
+}
+
+// Validate checks the resolved configuration
+func (c *Config) Validate() error {
+	switch {
+	case c.Duration <= 0:
+		return fmt.Errorf("SIM_DURATION must be positive, got %s", c.Duration)
+	case c.Step <= 0 || c.Step > c.Duration:
+		return fmt.Errorf("SIM_STEP must be positive and at most SIM_DURATION, got %s", c.Step)
+	case c.TelemetryEvery < c.Step:
+		return fmt.Errorf("SIM_TELEMETRY_EVERY must be at least SIM_STEP, got %s", c.TelemetryEvery)
+	case c.Vehicles <= 0:
+		return fmt.Errorf("SIM_VEHICLES must be positive, got %d", c.Vehicles)
+	case c.Users < 0:
+		return fmt.Errorf("SIM_USERS must not be negative, got %d", c.Users)
+	case c.BookingsPerDay < 0:
+		return fmt.Errorf("SIM_BOOKINGS_PER_USER_DAY must not be negative, got %g", c.BookingsPerDay)
+	case (c.UserServiceURL == "") != (c.VehicleServiceURL == ""):
+		return fmt.Errorf("SIM_USER_SERVICE_URL and SIM_VEHICLE_SERVICE_URL must be set together")
+	case !c.inProcess() && c.Telemetry.Secret == "":
+		return fmt.Errorf("TELEMETRY_SECRET of the running vehicle_service is required with SIM_VEHICLE_SERVICE_URL")
+	}
+	if _, err := c.Booking.Policy(); err != nil {
+		return err
+	}
+	if _, err := c.Charging.Forecaster(); err != nil {
+		return err
+	}
+	if _, err := c.Range.Estimator(); err != nil {
+		return err
+	}
+	return c.Telemetry.Validate()
+}
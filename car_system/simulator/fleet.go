@@ -0,0 +1,165 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// point is a GPS position
+type point struct {
+	lat, lon float64
+}
+
+// location is a station vehicles start from and drive to
+type location struct {
+	name string
+	point
+}
+
+// roadFactor is how much longer the road between two locations is than the
+// straight line
+const roadFactor = 1.3
+
+// haversineKM returns the great-circle distance between a and b
+func haversineKM(a, b point) float64 {
+	const earthRadiusKM = 6371
+	rad := math.Pi / 180
+	dLat, dLon := (b.lat-a.lat)*rad, (b.lon-a.lon)*rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(a.lat*rad)*math.Cos(b.lat*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKM * math.Asin(math.Sqrt(h))
+}
+
+// roadKM returns the length of the road between a and b
+func roadKM(a, b point) float64 {
+	return haversineKM(a, b) * roadFactor
+}
+
+// leg is one drive of a trip, at constant speed along the straight line
+// between its ends
+type leg struct {
+	from, to       point
+	depart, arrive time.Time
+	km, doneKM     float64
+}
+
+// rental is a reservation of a simulated vehicle, driven from its home
+// location to dest and back
+type rental struct {
+	reservationID int
+	userID        int
+	start, end    time.Time
+	dest          location
+	speedKMH      float64
+}
+
+// journey is a rental being driven
+type journey struct {
+	rental
+	legs []leg
+}
+
+// vehicle is a simulated vehicle. Its state is what it reports as telemetry.
+type vehicle struct {
+	id         int
+	model      string
+	batteryKWH float64
+	// kwhPerKM is the consumption of the model, and chargeKW the charging
+	// power at its home location
+	kwhPerKM float64
+	chargeKW float64
+	token    string
+	home     location
+
+	pos      point
+	charge   float64
+	odometer float64
+	locked   bool
+	speed    float64
+
+	// bookings are the upcoming bookings ordered by start, and trip the one
+	// being driven
+	bookings []rental
+	trip     *journey
+}
+
+// book adds b to the upcoming bookings of v
+func (v *vehicle) book(b rental) {
+	i := len(v.bookings)
+	for i > 0 && v.bookings[i-1].start.After(b.start) {
+		i--
+	}
+	v.bookings = append(v.bookings, rental{})
+	copy(v.bookings[i+1:], v.bookings[i:])
+	v.bookings[i] = b
+}
+
+// plan lays out the legs of b: out to the destination at the start, and back
+// home to arrive five minutes before the end, or as soon as possible
+func (v *vehicle) plan(b rental) *journey {
+	km := roadKM(v.home.point, b.dest.point)
+	travel := time.Duration(km / b.speedKMH * float64(time.Hour)).Round(time.Second)
+	out := leg{from: v.home.point, to: b.dest.point, depart: b.start, arrive: b.start.Add(travel), km: km}
+	back := leg{from: b.dest.point, to: v.home.point, arrive: b.end.Add(-5 * time.Minute), km: km}
+	back.depart = back.arrive.Add(-travel)
+	if back.depart.Before(out.arrive) {
+		back.depart, back.arrive = out.arrive, out.arrive.Add(travel)
+	}
+	return &journey{rental: b, legs: []leg{out, back}}
+}
+
+// tripEvent reports a trip that started or ended during advance
+type tripEvent struct {
+	kind string
+	trip *journey
+}
+
+// advance moves v from now-step to now: it starts and ends trips, drives
+// along their legs and charges while parked at home
+func (v *vehicle) advance(now time.Time, step time.Duration) []tripEvent {
+	var events []tripEvent
+	if v.trip == nil && len(v.bookings) > 0 && !v.bookings[0].start.After(now) {
+		v.trip = v.plan(v.bookings[0])
+		v.bookings = v.bookings[1:]
+		events = append(events, tripEvent{"trip_started", v.trip})
+	}
+
+	if v.trip == nil {
+		v.speed, v.locked = 0, true
+		if v.batteryKWH > 0 {
+			v.charge = math.Min(100, v.charge+v.chargeKW*step.Hours()/v.batteryKWH*100)
+		}
+		return events
+	}
+
+	// Drive the part of each leg due by now; between legs the renter parks
+	// at the destination
+	v.speed, v.locked = 0, true
+	for i := range v.trip.legs {
+		l := &v.trip.legs[i]
+		if now.Before(l.depart) || l.doneKM >= l.km {
+			continue
+		}
+		progress := 1.0
+		if d := l.arrive.Sub(l.depart); d > 0 && now.Before(l.arrive) {
+			progress = float64(now.Sub(l.depart)) / float64(d)
+		}
+		done := progress * l.km
+		km := done - l.doneKM
+		l.doneKM = done
+		v.odometer += km
+		if v.batteryKWH > 0 {
+			v.charge = math.Max(0, v.charge-km*v.kwhPerKM/v.batteryKWH*100)
+		}
+		v.pos = point{l.from.lat + (l.to.lat-l.from.lat)*progress, l.from.lon + (l.to.lon-l.from.lon)*progress}
+		if progress < 1 {
+			v.speed, v.locked = l.km/l.arrive.Sub(l.depart).Hours(), false
+		}
+	}
+
+	last := v.trip.legs[len(v.trip.legs)-1]
+	if last.doneKM >= last.km && !now.Before(v.trip.end) {
+		events = append(events, tripEvent{"trip_ended", v.trip})
+		v.trip, v.pos = nil, v.home.point
+	}
+	return events
+}
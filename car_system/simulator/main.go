@@ -0,0 +1,70 @@
+// Command simulator drives a fleet of virtual vehicles and users against the
+// car-sharing services. The vehicles drain their battery on trips, charge at
+// their home location, add to their odometer and move between the sample
+// locations, reporting telemetry as they go; the users register, search and
+// reserve through the public APIs.
+//
+// By default the services run in-process on the in-memory backend and a
+// virtual clock, so a run needs no network and replays the same events for
+// the same SIM_SEED. With SIM_USER_SERVICE_URL and SIM_VEHICLE_SERVICE_URL it
+// drives running services in real time instead.
+package main
+
+import (
+	"car_system/common/settings"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	cfg, args, err := loadConfig(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// Print the resolved configuration with secrets redacted
+	if len(args) > 0 && args[0] == "config" {
+		settings.Print(os.Stdout, &cfg)
+		return
+	}
+
+	var events io.Writer = io.Discard
+	switch cfg.EventsFile {
+	case "":
+	case "-":
+		events = os.Stdout
+	default:
+		f, err := os.Create(cfg.EventsFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		events = f
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	summary, err := run(ctx, cfg, events)
+	// The summary goes to stderr when the events go to stdout
+	out := os.Stdout
+	if cfg.EventsFile == "-" {
+		out = os.Stderr
+	}
+	summary.Print(out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Simulation stopped:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	billingmodels "car_system/billing_service/models"
+	billingserver "car_system/billing_service/server"
+	"car_system/common/inprocess"
+	"car_system/common/logging"
+	"car_system/common/metrics"
+	usercontrollers "car_system/user_service/controllers"
+	usermodels "car_system/user_service/models"
+	userserver "car_system/user_service/server"
+	vehiclemodels "car_system/vehicle_service/models"
+	vehicleserver "car_system/vehicle_service/server"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultTelemetrySecret signs the telemetry of the in-process fleet when no
+// TELEMETRY_SECRET is configured
+const defaultTelemetrySecret = "simulator-telemetry-secret"
+
+// simulatorServiceToken lets the in-process user_service pass the membership
+// of the simulated users to vehicle_service
+const simulatorServiceToken = "simulator-service-token"
+
+// virtualClock is the time the in-process services run on. The simulation
+// moves it forward one step at a time.
+type virtualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now returns the current virtual time
+func (c *virtualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to t
+func (c *virtualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// endpoints are the transports the simulation reaches the services through
+type endpoints struct {
+	userURL, vehicleURL string
+	user, vehicle       http.RoundTripper
+}
+
+// startServices builds the three services in-process on the in-memory backend
+// and on clock, with vehicles vehicles: the sample fleet, and more added at its
+// locations. Nothing listens on the network; the returned endpoints carry the
+// requests straight to the handlers.
+func startServices(cfg Config, clock *virtualClock) (endpoints, error) {
+	bookingPolicy, err := cfg.Booking.Policy()
+	if err != nil {
+		return endpoints{}, fmt.Errorf("vehicle_service: %w", err)
+	}
+	forecaster, err := cfg.Charging.Forecaster()
+	if err != nil {
+		return endpoints{}, fmt.Errorf("vehicle_service: %w", err)
+	}
+	estimator, err := cfg.Range.Estimator()
+	if err != nil {
+		return endpoints{}, fmt.Errorf("vehicle_service: %w", err)
+	}
+
+	store := vehiclemodels.NewMemoryStore()
+	store.SeedSampleFleet()
+	sample, _ := store.Repositories().Vehicles.ListAvailable()
+	for i := len(sample); i < cfg.Vehicles; i++ {
+		v := sample[i%len(sample)]
+		v.LicensePlate = fmt.Sprintf("SIM%03d", i+1)
+		store.AddVehicle(v)
+	}
+	vehiclemodels.UseRepositories(store.Repositories())
+	vehicle := vehicleserver.NewHandler(vehicleserver.Options{
+		Logger:       logging.New("vehicle_service", cfg.LogLevel),
+		Metrics:      metrics.New("vehicle_service"),
+		Booking:      bookingPolicy,
+		Charging:     forecaster,
+		Range:        estimator,
+		Telemetry:    cfg.Telemetry,
+		ServiceToken: simulatorServiceToken,
+		Clock:        clock.Now,
+	})
+
+	billingmodels.UseRepositories(billingmodels.NewMemoryRepositories())
+	billing := billingserver.NewHandler(billingserver.Options{
+		Logger:  logging.New("billing_service", cfg.LogLevel),
+		Metrics: metrics.New("billing_service"),
+	})
+
+	// The upstream URLs only name the services; the in-process transports
+	// deliver the proxied calls
+	usermodels.UseRepositories(usermodels.NewMemoryRepositories())
+	usercontrollers.UseSessionSecret("simulator-session-secret")
+	usercontrollers.VehicleServiceURL = "http://vehicle_service"
+	usercontrollers.BillingServiceURL = "http://billing_service"
+	usercontrollers.UseServiceToken(simulatorServiceToken)
+	user := userserver.NewHandler(userserver.Options{
+		Logger:           logging.New("user_service", cfg.LogLevel),
+		Metrics:          metrics.New("user_service"),
+		VehicleTransport: inprocess.Transport(vehicle),
+		BillingTransport: inprocess.Transport(billing),
+	})
+
+	return endpoints{
+		userURL:    "http://user_service",
+		vehicleURL: "http://vehicle_service",
+		user:       inprocess.Transport(user),
+		vehicle:    inprocess.Transport(vehicle),
+	}, nil
+}
+
+// remoteServices returns the endpoints of running services
+func remoteServices(cfg Config) endpoints {
+	return endpoints{
+		userURL:    cfg.UserServiceURL,
+		vehicleURL: cfg.VehicleServiceURL,
+		user:       http.DefaultTransport,
+		vehicle:    http.DefaultTransport,
+	}
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"car_system/vehicle_service/models"
+	"car_system/vehicle_service/telemetry"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// event is a line of the event log
+type event struct {
+	Time        time.Time `json:"time"`
+	Kind        string    `json:"kind"`
+	User        int       `json:"user,omitempty"`
+	Vehicle     int       `json:"vehicle,omitempty"`
+	Reservation int       `json:"reservation,omitempty"`
+	Detail      string    `json:"detail,omitempty"`
+}
+
+// user is a virtual user with its session on user_service
+type user struct {
+	id     int
+	email  string
+	client *apiClient
+}
+
+// simulation is the state of a run
+type simulation struct {
+	cfg   Config
+	rng   *rand.Rand
+	ep    endpoints
+	clock *virtualClock
+	fleet []*vehicle
+	users []*user
+	// stations are the stations with a position, in ID order so that random
+	// choices among them are reproducible
+	stations []location
+	vehicle  *apiClient
+	events   *json.Encoder
+	summary  Summary
+}
+
+// run simulates cfg.Duration of fleet activity, writes every event to events
+// and returns the summary of the run. Without service URLs the services run
+// in-process on a virtual clock and the run takes as long as the computation;
+// against running services it follows the system clock.
+func run(ctx context.Context, cfg Config, events io.Writer) (Summary, error) {
+	s := &simulation{
+		cfg:    cfg,
+		rng:    rand.New(rand.NewPCG(uint64(cfg.Seed), uint64(cfg.Seed))),
+		events: json.NewEncoder(events),
+	}
+	start := time.Now().UTC().Truncate(time.Second)
+	if cfg.inProcess() {
+		if s.cfg.Telemetry.Secret == "" {
+			s.cfg.Telemetry.Secret = defaultTelemetrySecret
+		}
+		start = cfg.Start.UTC()
+		s.clock = &virtualClock{now: start}
+		ep, err := startServices(s.cfg, s.clock)
+		if err != nil {
+			return Summary{}, err
+		}
+		s.ep = ep
+	} else {
+		s.ep = remoteServices(cfg)
+	}
+	s.vehicle = newAPIClient(s.ep.vehicleURL, s.ep.vehicle)
+	s.summary = Summary{Start: start, End: start.Add(cfg.Duration), Rejections: map[string]int{}}
+
+	if err := s.signUpUsers(ctx, start); err != nil {
+		return s.summary, err
+	}
+	if err := s.loadStations(ctx); err != nil {
+		return s.summary, err
+	}
+	if err := s.loadFleet(ctx); err != nil {
+		return s.summary, err
+	}
+
+	lastReport, lastCompaction := start.Add(-cfg.TelemetryEvery), start
+	for now := start.Add(cfg.Step); !now.After(s.summary.End); now = now.Add(cfg.Step) {
+		if err := s.waitFor(ctx, now); err != nil {
+			return s.summary, err
+		}
+		for _, v := range s.fleet {
+			for _, e := range v.advance(now, cfg.Step) {
+				s.tripEvent(now, v, e)
+			}
+		}
+		if now.Sub(lastReport) >= cfg.TelemetryEvery {
+			if err := s.reportTelemetry(ctx, now); err != nil {
+				return s.summary, err
+			}
+			lastReport = now
+		}
+		for _, u := range s.users {
+			if err := s.act(ctx, u, now); err != nil {
+				return s.summary, err
+			}
+		}
+		// Running services compact their own telemetry
+		if s.clock != nil && s.cfg.Telemetry.CompactEvery > 0 && now.Sub(lastCompaction) >= s.cfg.Telemetry.CompactEvery {
+			if s.cfg.Telemetry.RawRetention > 0 && s.cfg.Telemetry.DownsampleInterval > 0 {
+				if _, _, err := s.cfg.Telemetry.Compact(now); err != nil {
+					return s.summary, fmt.Errorf("compacting telemetry: %w", err)
+				}
+			}
+			lastCompaction = now
+		}
+	}
+	return s.summary, nil
+}
+
+// waitFor moves the virtual clock to now, or waits until the system clock
+// reaches it
+func (s *simulation) waitFor(ctx context.Context, now time.Time) error {
+	if s.clock != nil {
+		s.clock.Set(now)
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Until(now)):
+		return nil
+	}
+}
+
+// emit writes e to the event log
+func (s *simulation) emit(e event) {
+	e.Time = e.Time.UTC()
+	s.events.Encode(e)
+}
+
+// signUpUsers registers the virtual users, or logs in those that already
+// exist on running services
+func (s *simulation) signUpUsers(ctx context.Context, now time.Time) error {
+	for i := 1; i <= s.cfg.Users; i++ {
+		u := &user{email: fmt.Sprintf("sim-user-%03d@example.com", i), client: newAPIClient(s.ep.userURL, s.ep.user)}
+		password := fmt.Sprintf("sim-pass-%03d", i)
+		resp, err := u.client.do(ctx, http.MethodPost, "/v1/users", nil, map[string]string{
+			"name":     fmt.Sprintf("Sim User %03d", i),
+			"email":    u.email,
+			"phone_no": fmt.Sprintf("9%07d", i),
+			"password": password,
+			"dob":      "1990-01-01",
+		})
+		if err != nil {
+			return fmt.Errorf("registering %s: %w", u.email, err)
+		}
+		if !resp.ok() && resp.status != http.StatusConflict {
+			return fmt.Errorf("registering %s: %d %s", u.email, resp.status, resp.errorCode())
+		}
+		resp, err = u.client.do(ctx, http.MethodPost, "/v1/sessions", nil, map[string]string{"email": u.email, "password": password})
+		if err != nil {
+			return fmt.Errorf("logging in %s: %w", u.email, err)
+		}
+		if !resp.ok() {
+			return fmt.Errorf("logging in %s: %d %s", u.email, resp.status, resp.errorCode())
+		}
+		u.id, u.client.csrfToken = resp.body.UserID, resp.body.CSRFToken
+		s.users = append(s.users, u)
+		s.emit(event{Time: now, Kind: "user_signed_up", User: u.id})
+	}
+	s.summary.Users = len(s.users)
+	return nil
+}
+
+// loadStations lists the stations vehicles can be driven to
+func (s *simulation) loadStations(ctx context.Context) error {
+	resp, err := s.vehicle.do(ctx, http.MethodGet, "/v1/stations", nil, nil)
+	if err != nil {
+		return fmt.Errorf("listing stations: %w", err)
+	}
+	var stations []models.Station
+	if !resp.ok() || json.Unmarshal(resp.body.Data, &stations) != nil {
+		return fmt.Errorf("listing stations: %d %s", resp.status, resp.errorCode())
+	}
+	for _, st := range stations {
+		if p, ok := st.Position(); ok {
+			s.stations = append(s.stations, location{st.Name, point{p.Lat, p.Lon}})
+		}
+	}
+	if len(s.stations) < 2 {
+		return fmt.Errorf("listing stations: found %d with a position, need at least 2", len(s.stations))
+	}
+	return nil
+}
+
+// stationNamed returns the station called name, or the first station for a
+// vehicle parked at a station without a position
+func (s *simulation) stationNamed(name string) location {
+	for _, l := range s.stations {
+		if l.name == name {
+			return l
+		}
+	}
+	return s.stations[0]
+}
+
+// loadFleet picks the vehicles to drive among the available ones, in ID order,
+// and starts them from their state in vehicle_service
+func (s *simulation) loadFleet(ctx context.Context) error {
+	resp, err := s.vehicle.do(ctx, http.MethodGet, "/v1/vehicles", nil, nil)
+	if err != nil {
+		return fmt.Errorf("listing vehicles: %w", err)
+	}
+	var available []models.Vehicle
+	if !resp.ok() || json.Unmarshal(resp.body.Vehicles, &available) != nil {
+		return fmt.Errorf("listing vehicles: %d %s", resp.status, resp.errorCode())
+	}
+	sort.Slice(available, func(i, j int) bool { return available[i].VehicleID < available[j].VehicleID })
+	if len(available) > s.cfg.Vehicles {
+		available = available[:s.cfg.Vehicles]
+	}
+
+	forecaster, _ := s.cfg.Charging.Forecaster()
+	estimator, _ := s.cfg.Range.Estimator()
+	for _, m := range available {
+		home := s.stationNamed(m.Location)
+		s.fleet = append(s.fleet, &vehicle{
+			id:         m.VehicleID,
+			model:      m.Model,
+			batteryKWH: m.BatteryCapacityKWH,
+			kwhPerKM:   estimator.ConsumptionOf(m.Model),
+			chargeKW:   forecaster.PowerAt(m.Location),
+			token:      telemetry.TokenFor(s.cfg.Telemetry.Secret, m.VehicleID),
+			home:       home,
+			pos:        home.point,
+			charge:     m.ChargeLevel,
+			odometer:   float64(m.Mileage),
+			locked:     true,
+		})
+	}
+	s.summary.Vehicles = len(s.fleet)
+	return nil
+}
+
+// tripEvent logs a trip that started or ended and counts it
+func (s *simulation) tripEvent(now time.Time, v *vehicle, e tripEvent) {
+	detail := fmt.Sprintf("%s to %s, %.0f%% charge", v.home.name, e.trip.dest.name, v.charge)
+	if e.kind == "trip_ended" {
+		km := 0.0
+		for _, l := range e.trip.legs {
+			km += l.km
+		}
+		s.summary.Trips++
+		s.summary.DrivenKM += km
+		detail = fmt.Sprintf("%.1f km, %.0f%% charge", km, v.charge)
+	}
+	s.emit(event{Time: now, Kind: e.kind, User: e.trip.userID, Vehicle: v.id, Reservation: e.trip.reservationID, Detail: detail})
+}
+
+// reportTelemetry sends the current state of every vehicle to vehicle_service,
+// authenticated with its token
+func (s *simulation) reportTelemetry(ctx context.Context, now time.Time) error {
+	for _, v := range s.fleet {
+		reading := map[string]interface{}{
+			"recorded_at":  now.UTC(),
+			"latitude":     math.Round(v.pos.lat*1e6) / 1e6,
+			"longitude":    math.Round(v.pos.lon*1e6) / 1e6,
+			"charge_level": math.Round(v.charge*10) / 10,
+			"odometer_km":  math.Round(v.odometer*10) / 10,
+			"locked":       v.locked,
+			"speed_kmh":    math.Round(v.speed*10) / 10,
+		}
+		header := http.Header{"Authorization": {"Bearer " + v.token}}
+		resp, err := s.vehicle.do(ctx, http.MethodPost, fmt.Sprintf("/v1/vehicles/%d/telemetry", v.id), header, map[string]interface{}{
+			"readings": []interface{}{reading},
+		})
+		if err != nil {
+			return fmt.Errorf("reporting telemetry of vehicle %d: %w", v.id, err)
+		}
+		if !resp.ok() {
+			s.summary.TelemetryRejected++
+			s.emit(event{Time: now, Kind: "telemetry_rejected", Vehicle: v.id, Detail: resp.errorCode()})
+			continue
+		}
+		s.summary.TelemetrySent++
+	}
+	return nil
+}
+
+// act lets u make a booking attempt with the probability of one in this step:
+// search the vehicles free in a random window, pick one of the fleet and
+// reserve it for a trip to another location
+func (s *simulation) act(ctx context.Context, u *user, now time.Time) error {
+	if s.rng.Float64() >= s.cfg.BookingsPerDay*s.cfg.Step.Hours()/24 {
+		return nil
+	}
+	start := now.Truncate(15 * time.Minute).Add(time.Duration(4+s.rng.IntN(93)) * 15 * time.Minute)
+	end := start.Add(time.Duration(2+s.rng.IntN(15)) * 30 * time.Minute)
+
+	s.summary.Searches++
+	path := fmt.Sprintf("/v1/vehicles?start_time=%s&end_time=%s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	resp, err := u.client.do(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return fmt.Errorf("searching vehicles: %w", err)
+	}
+	var found []models.Vehicle
+	if resp.ok() {
+		json.Unmarshal(resp.body.Vehicles, &found)
+	}
+	var candidates []*vehicle
+	for _, v := range s.fleet {
+		for _, f := range found {
+			if f.VehicleID == v.id {
+				candidates = append(candidates, v)
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		s.emit(event{Time: now, Kind: "search_empty", User: u.id, Detail: start.Format(time.RFC3339)})
+		return nil
+	}
+
+	v := candidates[s.rng.IntN(len(candidates))]
+	var destinations []location
+	for _, l := range s.stations {
+		if l.name != v.home.name {
+			destinations = append(destinations, l)
+		}
+	}
+	b := rental{
+		userID:   u.id,
+		start:    start,
+		end:      end,
+		dest:     destinations[s.rng.IntN(len(destinations))],
+		speedKMH: float64(20 + s.rng.IntN(41)),
+	}
+	// Drive fast enough to be back before the end
+	distance := 2 * roadKM(v.home.point, b.dest.point)
+	b.speedKMH = math.Max(b.speedKMH, distance/(end.Sub(start)-10*time.Minute).Hours())
+	reservation := map[string]interface{}{
+		"vehicle_id":          v.id,
+		"start_time":          start,
+		"end_time":            end,
+		"planned_distance_km": math.Ceil(distance),
+	}
+	if level := []float64{0, 0, 0, 40, 60, 80}[s.rng.IntN(6)]; level > 0 {
+		reservation["expected_charge_level"] = level
+	}
+
+	resp, err = u.client.do(ctx, http.MethodPost, "/v1/reservations", nil, reservation)
+	if err != nil {
+		return fmt.Errorf("reserving vehicle %d: %w", v.id, err)
+	}
+	if !resp.ok() {
+		s.summary.Rejections[resp.errorCode()]++
+		s.emit(event{Time: now, Kind: "reservation_rejected", User: u.id, Vehicle: v.id, Detail: resp.errorCode()})
+		return nil
+	}
+	var created models.Reservation
+	json.Unmarshal(resp.body.Data, &created)
+	b.reservationID = created.ReservationID
+	v.book(b)
+	s.summary.Reservations++
+	s.emit(event{Time: now, Kind: "reservation_created", User: u.id, Vehicle: v.id, Reservation: b.reservationID,
+		Detail: fmt.Sprintf("%s to %s, %s to %s", v.home.name, b.dest.name, start.Format(time.RFC3339), end.Format(time.RFC3339))})
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunIsDeterministic(t *testing.T) {
+	cfg, _, err := loadConfig([]string{"-sim-duration", "36h", "-sim-seed", "7", "-log-level", "error"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var first, second bytes.Buffer
+	summary, err := run(context.Background(), cfg, &first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run(context.Background(), cfg, &second); err != nil {
+		t.Fatal(err)
+	}
+	if first.String() != second.String() {
+		t.Error("two runs with the same seed logged different events")
+	}
+
+	if summary.Reservations == 0 || summary.Trips == 0 || summary.DrivenKM <= 0 {
+		t.Errorf("summary = %+v, want reservations and completed trips", summary)
+	}
+	if summary.TelemetrySent != 5*int(36*time.Hour/(5*time.Minute)) || summary.TelemetryRejected != 0 {
+		t.Errorf("telemetry sent %d, rejected %d; want a reading every 5 minutes from each of the 5 vehicles", summary.TelemetrySent, summary.TelemetryRejected)
+	}
+	if !strings.Contains(first.String(), `"kind":"trip_ended"`) {
+		t.Error("no trip ended in the event log")
+	}
+}
+
+func TestVehicleDrivesAndCharges(t *testing.T) {
+	start := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	home, dest := location{"Downtown Station", point{1.2796, 103.8525}}, location{"Airport Terminal", point{1.3644, 103.9915}}
+	v := &vehicle{id: 1, batteryKWH: 60, kwhPerKM: 0.15, chargeKW: 12, home: home, pos: home.point, charge: 50, odometer: 1000, locked: true}
+	v.book(rental{reservationID: 1, start: start, end: start.Add(3 * time.Hour), dest: dest, speedKMH: 40})
+
+	var kinds []string
+	for now := start; !now.After(start.Add(4 * time.Hour)); now = now.Add(time.Minute) {
+		for _, e := range v.advance(now, time.Minute) {
+			kinds = append(kinds, e.kind)
+		}
+		if now.Equal(start.Add(10 * time.Minute)) {
+			if v.locked || v.speed < 39.9 || v.speed > 40.1 || v.pos == home.point {
+				t.Errorf("10 minutes in: locked %v, %g km/h at %+v; want driving away", v.locked, v.speed, v.pos)
+			}
+		}
+	}
+
+	km := 2 * roadKM(home.point, dest.point)
+	if len(kinds) != 2 || kinds[0] != "trip_started" || kinds[1] != "trip_ended" {
+		t.Errorf("events = %v, want a started and an ended trip", kinds)
+	}
+	if v.odometer < 1000+km-0.01 || v.odometer > 1000+km+0.01 {
+		t.Errorf("odometer = %g, want %g", v.odometer, 1000+km)
+	}
+	// The trip uses km*0.15 kWh of the 60 kWh battery; the hour at home after
+	// its end charges 12 kWh, a fifth of it
+	want := 50 - km*0.15/60*100 + 20
+	if v.charge < want-0.01 || v.charge > want+0.01 || v.pos != home.point || !v.locked {
+		t.Errorf("charge %g at %+v, locked %v; want %g, parked at home", v.charge, v.pos, v.locked, want)
+	}
+}
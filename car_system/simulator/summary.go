@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Summary counts what happened during a run
+type Summary struct {
+	Start, End time.Time
+	Users      int
+	Vehicles   int
+
+	Searches     int
+	Reservations int
+	// Rejections counts the refused reservations by error code
+	Rejections map[string]int
+
+	Trips    int
+	DrivenKM float64
+
+	TelemetrySent     int
+	TelemetryRejected int
+}
+
+// Print writes the summary as an aligned table
+func (s Summary) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Simulated\t%s to %s\n", s.Start.Format(time.RFC3339), s.End.Format(time.RFC3339))
+	fmt.Fprintf(tw, "Users\t%d\n", s.Users)
+	fmt.Fprintf(tw, "Vehicles\t%d\n", s.Vehicles)
+	fmt.Fprintf(tw, "Searches\t%d\n", s.Searches)
+	fmt.Fprintf(tw, "Reservations created\t%d\n", s.Reservations)
+	codes := make([]string, 0, len(s.Rejections))
+	for code := range s.Rejections {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		fmt.Fprintf(tw, "Reservations rejected (%s)\t%d\n", code, s.Rejections[code])
+	}
+	fmt.Fprintf(tw, "Trips completed\t%d\n", s.Trips)
+	fmt.Fprintf(tw, "Distance driven\t%.1f km\n", s.DrivenKM)
+	fmt.Fprintf(tw, "Telemetry readings sent\t%d\n", s.TelemetrySent)
+	fmt.Fprintf(tw, "Telemetry readings rejected\t%d\n", s.TelemetryRejected)
+	return tw.Flush()
+}
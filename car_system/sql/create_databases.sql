@@ -0,0 +1,7 @@
+-- Creates the three service databases. Tables are created and upgraded by the
+-- migrations embedded in each service (go run . migrate up), and sample data
+-- is loaded separately with: go run . migrate seed
+
+CREATE DATABASE IF NOT EXISTS user_service;
+CREATE DATABASE IF NOT EXISTS vehicle_service;
+CREATE DATABASE IF NOT EXISTS billing_service;
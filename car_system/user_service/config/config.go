@@ -0,0 +1,79 @@
+package config
+
+import (
+	"car_system/common/database"
+	"car_system/common/deprecation"
+	"car_system/common/httpserver"
+	"car_system/common/idempotency"
+	"car_system/common/settings"
+	"car_system/user_service/controllers"
+	"car_system/user_service/server"
+	"fmt"
+	"net/url"
+)
+
+// Config is the resolved configuration of user_service
+type Config struct {
+	Port           int    `env:"PORT" default:"8080" usage:"HTTP listen port"`
+	LogLevel       string `env:"LOG_LEVEL" default:"info" usage:"Minimum log level (debug, info, warn, error)"`
+	StorageBackend string `env:"STORAGE_BACKEND" default:"mysql" usage:"Storage backend (mysql or memory)"`
+	AutoMigrate    bool   `env:"DB_AUTO_MIGRATE" default:"true" usage:"Apply pending migrations on startup"`
+	StaticDir      string `env:"STATIC_DIR" default:"./static/" usage:"Directory of the static pages"`
+
+	ValidateRequests bool `env:"OPENAPI_VALIDATE_REQUESTS" usage:"Reject requests that do not match the OpenAPI document"`
+
+	SessionSecret string `env:"SESSION_SECRET" secret:"true" usage:"Key used to sign session cookies"`
+	SessionCookie controllers.SessionCookie
+
+	VehicleServiceURL string `env:"VEHICLE_SERVICE_URL" default:"http://localhost:8081" usage:"Base URL of vehicle_service"`
+	BillingServiceURL string `env:"BILLING_SERVICE_URL" default:"http://localhost:8082" usage:"Base URL of billing_service"`
+	ServiceToken      string `env:"SERVICE_TOKEN" secret:"true" usage:"Token sent to vehicle_service with the membership of the users; the SERVICE_TOKEN of vehicle_service"`
+
+	DB          database.Settings
+	Pool        database.PoolConfig
+	HTTP        httpserver.Config
+	Legacy      deprecation.Policy
+	Idempotency idempotency.Settings
+	RateLimits  server.RateLimits
+}
+
+// Load resolves the configuration from defaults, the optional .env file, the
+// environment and the command-line flags in args. It returns the arguments
+// left after the flags.
+func Load(args []string) (Config, []string, error) {
+	cfg := Config{DB: database.Settings{Name: "user_service"}}
+	rest, err := settings.Load("user_service", &cfg, args)
+	return cfg, rest, err
+}
+
+// Validate checks the resolved configuration
+func (c *Config) Validate() error {
+	if c.Port <= 0 || c.Port > 65535 {
+		return fmt.Errorf("PORT must be between 1 and 65535, got %d", c.Port)
+	}
+	if c.SessionSecret == "" {
+		return fmt.Errorf("SESSION_SECRET is required")
+	}
+	if c.ServiceToken == "" {
+		return fmt.Errorf("SERVICE_TOKEN is required")
+	}
+	if err := c.SessionCookie.Validate(); err != nil {
+		return err
+	}
+	for name, raw := range map[string]string{
+		"VEHICLE_SERVICE_URL": c.VehicleServiceURL,
+		"BILLING_SERVICE_URL": c.BillingServiceURL,
+	} {
+		if u, err := url.Parse(raw); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%s must be an absolute URL, got %q", name, raw)
+		}
+	}
+	switch c.StorageBackend {
+	case "memory":
+		return nil
+	case "mysql":
+		return c.DB.Validate()
+	default:
+		return fmt.Errorf("STORAGE_BACKEND must be mysql or memory, got %q", c.StorageBackend)
+	}
+}
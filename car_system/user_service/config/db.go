@@ -1,47 +1,36 @@
 package config
 
 import (
+	"car_system/common/database"
 	"database/sql"
-	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
 	_ "github.com/go-sql-driver/mysql" // MySQL driver
-	"github.com/joho/godotenv"         // To load .env file
 )
 
 // DB is the global database connection pool
 var DB *sql.DB
 
 // ConnectDB initializes the connection to the MySQL database
-func ConnectDB() {
-	// Load environment variables from .env file
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatalf("Error loading .env file: %v", err)
-	}
-
-	// Get database credentials from environment variables
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbName := os.Getenv("DB_NAME")
-
-	// Build the DSN (Data Source Name)
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", dbUser, dbPassword, dbHost, dbPort, dbName)
-
+func ConnectDB(cfg Config) {
 	// Initialize the database connection
-	DB, err = sql.Open("mysql", dsn)
+	var err error
+	DB, err = sql.Open("mysql", cfg.DB.DSN())
 	if err != nil {
-		log.Fatalf("Error connecting to the database: %v", err)
+		slog.Error("Error connecting to the database", "error", err)
+		os.Exit(1)
 	}
 
+	// Apply connection pool limits
+	database.ConfigurePool(DB, cfg.Pool)
+
 	// Verify the connection
 	err = DB.Ping()
 	if err != nil {
-		log.Fatalf("Error verifying connection to the database: %v", err)
+		slog.Error("Error verifying connection to the database", "error", err)
+		os.Exit(1)
 	}
 
-	fmt.Println("Successfully connected to the database!")
+	slog.Info("Successfully connected to the database", "host", cfg.DB.Host, "database", cfg.DB.Name)
 }
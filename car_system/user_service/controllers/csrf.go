@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"car_system/common/apierror"
+	"car_system/common/logging"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+)
+
+// CSRFHeader carries the CSRF token of the session on state-changing requests
+const CSRFHeader = "X-CSRF-Token"
+
+// csrfTokenKey is the session value holding the CSRF token
+const csrfTokenKey = "csrf_token"
+
+// newCSRFToken returns a random URL-safe token
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// IssueCSRFToken returns the CSRF token of the logged-in user's session. A
+// session created before tokens were issued at login gets one here.
+func IssueCSRFToken(w http.ResponseWriter, r *http.Request) {
+	session, err := store.Get(r, "user-session")
+	if err != nil {
+		logging.FromContext(r.Context()).Warn("Error retrieving session", "error", err)
+		apierror.Write(w, r, errSessionInvalid)
+		return
+	}
+	if _, ok := session.Values["user_id"].(int); !ok {
+		apierror.Write(w, r, apierror.ErrUnauthorized)
+		return
+	}
+
+	token, _ := session.Values[csrfTokenKey].(string)
+	if token == "" {
+		if token, err = newCSRFToken(); err != nil {
+			logging.FromContext(r.Context()).Error("Error generating CSRF token", "error", err)
+			apierror.Write(w, r, apierror.ErrInternal)
+			return
+		}
+		session.Values[csrfTokenKey] = token
+		session.Options = sessionCookie.options()
+		if err := session.Save(r, w); err != nil {
+			logging.FromContext(r.Context()).Error("Error saving session", "error", err)
+			apierror.Write(w, r, apierror.ErrInternal)
+			return
+		}
+	}
+
+	// Pages read the token on every load, so it must not be cached
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"csrf_token": token})
+}
+
+// RequireCSRFToken guards a handler that changes state on behalf of the
+// session user. A request with a valid session must carry the session's token
+// in the X-CSRF-Token header, which a cross-site form or script cannot read
+// or set. Requests without a valid session are passed on, and rejected by
+// next as unauthenticated.
+func RequireCSRFToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		session, err := store.Get(r, "user-session")
+		if err != nil {
+			next(w, r)
+			return
+		}
+		if _, ok := session.Values["user_id"].(int); !ok {
+			next(w, r)
+			return
+		}
+
+		expected, _ := session.Values[csrfTokenKey].(string)
+		sent := r.Header.Get(CSRFHeader)
+		if expected == "" || subtle.ConstantTimeCompare([]byte(sent), []byte(expected)) != 1 {
+			logging.FromContext(r.Context()).Warn("Rejected request without a valid CSRF token", "path", r.URL.Path, "token_sent", sent != "")
+			apierror.Write(w, r, errCSRFTokenInvalid)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// loginWithToken logs testUser in and returns its session cookie and the CSRF
+// token of the login response
+func loginWithToken(t *testing.T) (*http.Cookie, string) {
+	t.Helper()
+	rec := doRequest(LoginUser, "POST", "/v1/sessions", map[string]string{
+		"email":    testUser["email"],
+		"password": testUser["password"],
+	})
+	token, _ := decodeBody(t, rec)["csrf_token"].(string)
+	if rec.Code != http.StatusOK || token == "" {
+		t.Fatalf("login: got %d %s, want 200 with a csrf_token", rec.Code, rec.Body.String())
+	}
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == "user-session" {
+			return c, token
+		}
+	}
+	t.Fatal("login did not set the user-session cookie")
+	return nil, ""
+}
+
+func TestIssueCSRFToken(t *testing.T) {
+	setupTest(t)
+	doRequest(RegisterUser, "POST", "/v1/users", testUser)
+	cookie, token := loginWithToken(t)
+
+	rec := doRequest(IssueCSRFToken, "GET", "/v1/csrf-token", nil, cookie)
+	if rec.Code != http.StatusOK || decodeBody(t, rec)["csrf_token"] != token {
+		t.Errorf("csrf-token: got %d %s, want 200 with the token of the login", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Cache-Control") != "no-store" {
+		t.Error("csrf-token response may be cached")
+	}
+	if rec := doRequest(IssueCSRFToken, "GET", "/v1/csrf-token", nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("without session: got %d, want 401", rec.Code)
+	}
+
+	// Every login rotates the token
+	if _, again := loginWithToken(t); again == token {
+		t.Error("a new login kept the previous CSRF token")
+	}
+}
+
+func TestRequireCSRFToken(t *testing.T) {
+	setupTest(t)
+	doRequest(RegisterUser, "POST", "/v1/users", testUser)
+	cookie, token := loginWithToken(t)
+	h := RequireCSRFToken(UpdateUserDetails)
+
+	send := func(header string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PUT", "/v1/me", nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		if header != "" {
+			req.Header.Set(CSRFHeader, header)
+		}
+		rec := httptest.NewRecorder()
+		h(rec, req)
+		return rec
+	}
+	for name, header := range map[string]string{"missing": "", "wrong": "not-the-token"} {
+		rec := send(header, cookie)
+		if rec.Code != http.StatusForbidden || decodeBody(t, rec)["code"] != CodeCSRFTokenInvalid {
+			t.Errorf("%s token: got %d %s, want 403 %s", name, rec.Code, rec.Body.String(), CodeCSRFTokenInvalid)
+		}
+	}
+	// The token passes the request on; the handler then rejects the empty body
+	if rec := send(token, cookie); rec.Code != http.StatusBadRequest {
+		t.Errorf("valid token: got %d %s, want the handler's 400", rec.Code, rec.Body.String())
+	}
+	if rec := send(""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("without session: got %d, want the handler's 401", rec.Code)
+	}
+}
+
+func TestSessionCookieAttributes(t *testing.T) {
+	setupTest(t)
+	UseSessionCookie(SessionCookie{SameSite: "strict", Secure: true})
+	defer UseSessionCookie(SessionCookie{SameSite: "lax"})
+	doRequest(RegisterUser, "POST", "/v1/users", testUser)
+
+	cookie, _ := loginWithToken(t)
+	if cookie.SameSite != http.SameSiteStrictMode || !cookie.Secure || !cookie.HttpOnly {
+		t.Errorf("cookie = SameSite %v, Secure %v, HttpOnly %v; want strict, secure and HttpOnly", cookie.SameSite, cookie.Secure, cookie.HttpOnly)
+	}
+
+	for cookie, valid := range map[SessionCookie]bool{
+		{SameSite: "lax"}:                  true,
+		{SameSite: "none", Secure: true}:   true,
+		{SameSite: "none"}:                 false,
+		{SameSite: "sometimes"}:            false,
+		{SameSite: "strict", Secure: true}: true,
+	} {
+		if err := cookie.Validate(); (err == nil) != valid {
+			t.Errorf("%+v.Validate() = %v, want valid %v", cookie, err, valid)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"car_system/common/apierror"
+	"net/http"
+)
+
+// Error codes returned by user_service in addition to the shared apierror codes
+const (
+	CodeEmailOrPhoneTaken  = "EMAIL_OR_PHONE_TAKEN"
+	CodeInvalidCredentials = "INVALID_CREDENTIALS"
+	CodeSessionInvalid     = "SESSION_INVALID"
+	CodeInvalidTimeRange   = "INVALID_TIME_RANGE"
+	CodeCSRFTokenInvalid   = "CSRF_TOKEN_INVALID"
+	// CodeReservationNotFound is also returned by vehicle_service
+	CodeReservationNotFound = "RESERVATION_NOT_FOUND"
+)
+
+var (
+	errEmailOrPhoneTaken  = apierror.New(http.StatusConflict, CodeEmailOrPhoneTaken, "Email or phone number already exists")
+	errInvalidCredentials = apierror.New(http.StatusUnauthorized, CodeInvalidCredentials, "Invalid email or password")
+	errSessionInvalid     = apierror.New(http.StatusUnauthorized, CodeSessionInvalid, "Session error. Please log in again.")
+	errInvalidTimeRange   = apierror.New(http.StatusBadRequest, CodeInvalidTimeRange, "End time must be after start time")
+	errCSRFTokenInvalid   = apierror.New(http.StatusForbidden, CodeCSRFTokenInvalid, "Missing or invalid X-CSRF-Token header. Fetch a token from /v1/csrf-token.")
+	errNoSuchReservation  = apierror.New(http.StatusNotFound, CodeReservationNotFound, "Reservation not found")
+	errUpstream           = apierror.New(http.StatusBadGateway, apierror.CodeUpstreamUnavailable, "Upstream service is unavailable")
+)
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	billingclient "car_system/billing_service/client"
+	"car_system/common/health"
+	"car_system/common/idempotency"
+	"car_system/common/logging"
+	"car_system/common/metrics"
+	vehicleclient "car_system/vehicle_service/client"
+	"context"
+	"net/http"
+	"time"
+)
+
+// Base URLs of the services proxied by user_service
+var (
+	VehicleServiceURL = "http://localhost:8081"
+	BillingServiceURL = "http://localhost:8082"
+)
+
+// serviceTokenHeader carries the token of user_service to vehicle_service,
+// which only then accepts the membership of a user
+const serviceTokenHeader = "X-Service-Token"
+
+// serviceToken proves to vehicle_service that a call comes from user_service
+var serviceToken string
+
+// UseServiceToken sets the token sent to vehicle_service, the SERVICE_TOKEN
+// of vehicle_service
+func UseServiceToken(token string) {
+	serviceToken = token
+}
+
+// HTTP clients used by the proxy handlers to reach the other services
+var (
+	vehicleClient = &http.Client{Timeout: 10 * time.Second}
+	billingClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// InitializeUpstreamClients records the outcome of every proxied call in m.
+// vehicle and billing replace the network transport of each client, e.g. to
+// call a handler mounted in the same process; nil keeps the default.
+func InitializeUpstreamClients(m *metrics.Metrics, vehicle, billing http.RoundTripper) {
+	vehicleClient.Transport = m.Transport("vehicle_service", vehicle)
+	billingClient.Transport = m.Transport("billing_service", billing)
+}
+
+// RegisterUpstreamChecks adds the reachability of vehicle_service and
+// billing_service to the readiness checks
+func RegisterUpstreamChecks(checker *health.Checker) {
+	checker.Add("vehicle_service", health.HTTPCheck(vehicleClient, VehicleServiceURL+"/healthz"))
+	checker.Add("billing_service", health.HTTPCheck(billingClient, BillingServiceURL+"/healthz"))
+}
+
+// vehicleAPI returns a typed vehicle_service client whose calls carry the
+// headers and the request ID of the incoming request r, and the service token
+func vehicleAPI(r *http.Request) (*vehicleclient.ClientWithResponses, error) {
+	return vehicleclient.NewClientWithResponses(VehicleServiceURL,
+		vehicleclient.WithHTTPClient(vehicleClient),
+		vehicleclient.WithRequestEditorFn(forwardHeaders(r)),
+		vehicleclient.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+			if serviceToken != "" {
+				req.Header.Set(serviceTokenHeader, serviceToken)
+			}
+			return nil
+		}),
+	)
+}
+
+// billingAPI returns a typed billing_service client whose calls carry the
+// headers and the request ID of the incoming request r
+func billingAPI(r *http.Request) (*billingclient.ClientWithResponses, error) {
+	return billingclient.NewClientWithResponses(BillingServiceURL,
+		billingclient.WithHTTPClient(billingClient),
+		billingclient.WithRequestEditorFn(forwardHeaders(r)),
+	)
+}
+
+// forwardHeaders copies the headers of r, including the session cookie, to an
+// upstream request. The body headers are left to the generated client, the
+// Idempotency-Key to upstreamIdempotencyKey, and the CSRF token and any
+// service token sent by the client stay here.
+func forwardHeaders(r *http.Request) func(context.Context, *http.Request) error {
+	return func(_ context.Context, req *http.Request) error {
+		for key, values := range r.Header {
+			if key == "Content-Length" || key == "Content-Type" || key == idempotency.KeyHeader || key == CSRFHeader || key == serviceTokenHeader {
+				continue
+			}
+			req.Header[key] = append([]string(nil), values...)
+		}
+		if id := logging.RequestID(r.Context()); id != "" {
+			req.Header.Set(logging.RequestIDHeader, id)
+		}
+		return nil
+	}
+}
+
+// upstreamIdempotencyKey derives the Idempotency-Key of a proxied call from the
+// key of r and the session user, so that the keys of different users never
+// collide upstream. It returns nil when r has no key.
+func upstreamIdempotencyKey(r *http.Request) *string {
+	key := r.Header.Get(idempotency.KeyHeader)
+	if key == "" {
+		return nil
+	}
+	scoped := idempotency.ScopedKey(SessionUser(r), key)
+	return &scoped
+}
@@ -1,37 +1,93 @@
 package controllers
 
 import (
-	"bytes"
+	billingclient "car_system/billing_service/client"
+	"car_system/common/apierror"
+	"car_system/common/logging"
 	"car_system/user_service/models"
+	vehicleclient "car_system/vehicle_service/client"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
-	"os"
+	"strconv"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/gorilla/sessions"
-	"github.com/joho/godotenv"
 )
 
 // Declare session store
 var store *sessions.CookieStore
 
-// InitializeSessionStore initializes the session store with a secret key from .env
-func InitializeSessionStore() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env file")
+// sessionCookie holds the attributes of the session cookie
+var sessionCookie = SessionCookie{SameSite: "lax"}
+
+// SessionCookie holds the attributes of the session cookie that differ
+// between environments: a local setup over plain HTTP cannot use Secure
+// cookies, and a frontend on another site needs SameSite=None.
+type SessionCookie struct {
+	SameSite string `env:"SESSION_COOKIE_SAMESITE" default:"lax" usage:"SameSite attribute of the session cookie (lax, strict or none)"`
+	Secure   bool   `env:"SESSION_COOKIE_SECURE" usage:"Send the session cookie over HTTPS only"`
+}
+
+// Validate checks the SameSite value. Browsers drop SameSite=None cookies
+// that are not Secure.
+func (c SessionCookie) Validate() error {
+	if _, ok := sameSiteModes[c.SameSite]; !ok {
+		return fmt.Errorf("SESSION_COOKIE_SAMESITE must be lax, strict or none, got %q", c.SameSite)
+	}
+	if c.SameSite == "none" && !c.Secure {
+		return fmt.Errorf("SESSION_COOKIE_SAMESITE=none requires SESSION_COOKIE_SECURE=true")
 	}
+	return nil
+}
+
+var sameSiteModes = map[string]http.SameSite{
+	"lax":    http.SameSiteLaxMode,
+	"strict": http.SameSiteStrictMode,
+	"none":   http.SameSiteNoneMode,
+}
 
-	secretKey := os.Getenv("SESSION_SECRET")
-	if secretKey == "" {
-		log.Fatal("SESSION_SECRET is not set in the environment")
+// options returns the options every session is saved with
+func (c SessionCookie) options() *sessions.Options {
+	return &sessions.Options{
+		Path:     "/",
+		MaxAge:   3600, // 1 hour
+		HttpOnly: true,
+		Secure:   c.Secure,
+		SameSite: sameSiteModes[c.SameSite],
 	}
+}
 
+// UseSessionSecret initializes the session store with the given secret key
+func UseSessionSecret(secretKey string) {
 	store = sessions.NewCookieStore([]byte(secretKey))
-	log.Println("Session store initialized successfully")
+	store.Options = sessionCookie.options()
+}
+
+// UseSessionCookie sets the attributes of the session cookies saved from now
+// on. It may be called before or after UseSessionSecret.
+func UseSessionCookie(cookie SessionCookie) {
+	sessionCookie = cookie
+	if store != nil {
+		store.Options = cookie.options()
+	}
+}
+
+// SessionUser returns the ID of the logged-in user of r, or "" when r has no
+// valid session. It keys the per-user state kept around the handlers: the
+// idempotency keys and the rate limits.
+func SessionUser(r *http.Request) string {
+	session, err := store.Get(r, "user-session")
+	if err != nil {
+		return ""
+	}
+	if userID, ok := session.Values["user_id"].(int); ok {
+		return strconv.Itoa(userID)
+	}
+	return ""
 }
 
 // Response structure for API responses
@@ -40,45 +96,45 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
-// sendErrorResponse is a helper function to send error messages
-func sendErrorResponse(w http.ResponseWriter, message string, statusCode int) {
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(Response{Message: message})
-}
-
 // RegisterUser handles user registration
 func RegisterUser(w http.ResponseWriter, r *http.Request) {
 	var user models.User
 
 	// Parse JSON request body
 	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		sendErrorResponse(w, "Invalid request payload", http.StatusBadRequest)
+		apierror.Write(w, r, apierror.ErrInvalidJSON)
 		return
 	}
 
 	// Validate input
-	if user.Name == "" || user.Email == "" || user.PhoneNo == "" || user.Password == "" || user.DOB == "" {
-		sendErrorResponse(w, "All fields (name, email, phone_no, password, dob) are required", http.StatusBadRequest)
+	var v apierror.Validation
+	v.Check(user.Name != "", "name", "is required")
+	v.Check(user.Email != "", "email", "is required")
+	v.Check(user.PhoneNo != "", "phone_no", "is required")
+	v.Check(user.Password != "", "password", "is required")
+	v.Check(user.DOB != "", "dob", "is required")
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
 	// Check for duplicate email or phone number
 	exists, err := models.IsUserExists(user.Email, user.PhoneNo)
 	if err != nil {
-		log.Printf("Error checking for duplicate user: %v", err)
-		sendErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Error checking for duplicate user", "error", err)
+		apierror.Write(w, r, apierror.ErrInternal)
 		return
 	}
 	if exists {
-		sendErrorResponse(w, "Email or phone number already exists", http.StatusConflict)
+		apierror.Write(w, r, errEmailOrPhoneTaken)
 		return
 	}
 
 	// Register the user
 	err = models.RegisterUser(&user)
 	if err != nil {
-		log.Printf("Error registering user: %v", err)
-		sendErrorResponse(w, "Failed to register user", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Error registering user", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to register user"))
 		return
 	}
 
@@ -96,77 +152,56 @@ func LoginUser(w http.ResponseWriter, r *http.Request) {
 
 	// Decode the request body
 	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
-		log.Println("Error decoding request body:", err)
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Invalid request payload",
-			"error":   err.Error(),
-		})
+		logging.FromContext(r.Context()).Warn("Error decoding request body", "error", err)
+		apierror.Write(w, r, apierror.ErrInvalidJSON)
 		return
 	}
 
 	// Authenticate user
 	user, err := models.LoginUser(credentials.Email, credentials.Password)
 	if err != nil || user == nil {
-		log.Printf("Invalid login attempt for email: %s\n", credentials.Email)
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Invalid email or password",
-		})
+		logging.FromContext(r.Context()).Warn("Invalid login attempt", "email", credentials.Email)
+		apierror.Write(w, r, errInvalidCredentials)
 		return
 	}
 
 	// Create a session
 	session, err := store.Get(r, "user-session")
 	if err != nil {
-		log.Println("Error creating session:", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Could not create session",
-			"error":   err.Error(),
-		})
+		logging.FromContext(r.Context()).Error("Error creating session", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Could not create session"))
 		return
 	}
 
-	// Set session values
-	session.Values["user_id"] = user.UserID
-	session.Options = &sessions.Options{
-		Path:     "/",
-		MaxAge:   3600, // 1 hour
-		HttpOnly: true,
+	// Set session values. A fresh CSRF token comes with every login so that
+	// a token seen before the login is worthless.
+	csrfToken, err := newCSRFToken()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error generating CSRF token", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Could not create session"))
+		return
 	}
+	session.Values["user_id"] = user.UserID
+	session.Values[csrfTokenKey] = csrfToken
+	session.Options = sessionCookie.options()
 
 	// Save session
 	if err := session.Save(r, w); err != nil {
-		log.Println("Error saving session:", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Could not save session",
-			"error":   err.Error(),
-		})
-		return
-	}
-
-	// Retrieve the session cookie
-	cookie, err := r.Cookie("user-session")
-	if err != nil {
-		log.Println("Error retrieving session cookie:", err)
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Session cookie is missing. Please log in again.",
-		})
+		logging.FromContext(r.Context()).Error("Error saving session", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Could not save session"))
 		return
 	}
 
 	// Log session details in the terminal
-	log.Printf("Login successful: User ID: %d, Session Cookie Value: %s\n", user.UserID, cookie.Value)
+	logging.FromContext(r.Context()).Info("Login successful", "user_id", user.UserID)
 
-	// Log session details in the app console
+	// The session itself travels in the HttpOnly cookie set by session.Save;
+	// the CSRF token is for the page to send back in the X-CSRF-Token header
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message":    "Login successful",
 		"user_id":    user.UserID,
-		"session_id": cookie.Value, // Pass the session cookie value to the frontend
+		"csrf_token": csrfToken,
 	})
 }
 
@@ -175,24 +210,24 @@ func DisplayRentalRecords(w http.ResponseWriter, r *http.Request) {
 	// Retrieve the session
 	session, err := store.Get(r, "user-session")
 	if err != nil {
-		log.Println("Error retrieving session:", err)
-		http.Error(w, "Session error. Please log in again.", http.StatusUnauthorized)
+		logging.FromContext(r.Context()).Warn("Error retrieving session", "error", err)
+		apierror.Write(w, r, errSessionInvalid)
 		return
 	}
 
 	// Retrieve the user ID from the session
 	userID, ok := session.Values["user_id"].(int)
 	if !ok {
-		log.Println("Invalid or missing user ID in session")
-		http.Error(w, "Unauthorized access. Please log in again.", http.StatusUnauthorized)
+		logging.FromContext(r.Context()).Warn("Invalid or missing user ID in session")
+		apierror.Write(w, r, apierror.ErrUnauthorized)
 		return
 	}
 
 	// Fetch rental records for the user
 	rentals, err := models.GetRentalsByUserID(userID)
 	if err != nil {
-		log.Printf("Error fetching rental records for user_id %d: %v\n", userID, err)
-		http.Error(w, "Failed to fetch rental records", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Error fetching rental records", "user_id", userID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch rental records"))
 		return
 	}
 
@@ -209,24 +244,24 @@ func DisplayUserMembership(w http.ResponseWriter, r *http.Request) {
 	// Retrieve session
 	session, err := store.Get(r, "user-session")
 	if err != nil {
-		log.Println("Error retrieving session:", err)
-		http.Error(w, "Session error. Please log in again.", http.StatusUnauthorized)
+		logging.FromContext(r.Context()).Warn("Error retrieving session", "error", err)
+		apierror.Write(w, r, errSessionInvalid)
 		return
 	}
 
 	// Retrieve user ID from session
 	userID, ok := session.Values["user_id"].(int)
 	if !ok {
-		log.Println("Invalid or missing user ID in session")
-		http.Error(w, "Unauthorized access. Please log in again.", http.StatusUnauthorized)
+		logging.FromContext(r.Context()).Warn("Invalid or missing user ID in session")
+		apierror.Write(w, r, apierror.ErrUnauthorized)
 		return
 	}
 
 	// Fetch membership tier details from the model
 	membership, err := models.GetUserMembershipDetails(userID)
 	if err != nil {
-		log.Printf("Error fetching membership details for user_id %d: %v\n", userID, err)
-		http.Error(w, "Failed to fetch membership details", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Error fetching membership details", "user_id", userID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch membership details"))
 		return
 	}
 
@@ -243,24 +278,24 @@ func DisplayUserDetails(w http.ResponseWriter, r *http.Request) {
 	// Retrieve session
 	session, err := store.Get(r, "user-session")
 	if err != nil {
-		log.Println("Error retrieving session:", err)
-		http.Error(w, "Session error. Please log in again.", http.StatusUnauthorized)
+		logging.FromContext(r.Context()).Warn("Error retrieving session", "error", err)
+		apierror.Write(w, r, errSessionInvalid)
 		return
 	}
 
 	// Retrieve user ID from session
 	userID, ok := session.Values["user_id"].(int)
 	if !ok {
-		log.Println("Invalid or missing user ID in session")
-		http.Error(w, "Unauthorized access. Please log in again.", http.StatusUnauthorized)
+		logging.FromContext(r.Context()).Warn("Invalid or missing user ID in session")
+		apierror.Write(w, r, apierror.ErrUnauthorized)
 		return
 	}
 
 	// Fetch user details from the model
 	user, err := models.GetUserDetailsByID(userID)
 	if err != nil {
-		log.Printf("Error fetching user details for user_id %d: %v\n", userID, err)
-		http.Error(w, "Failed to fetch user details", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Error fetching user details", "user_id", userID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch user details"))
 		return
 	}
 
@@ -276,29 +311,29 @@ func DisplayUserDetails(w http.ResponseWriter, r *http.Request) {
 func UpdateUserDetails(w http.ResponseWriter, r *http.Request) {
 	session, err := store.Get(r, "user-session")
 	if err != nil {
-		log.Println("Error retrieving session:", err)
-		http.Error(w, `{"message":"Session error. Please log in again."}`, http.StatusUnauthorized)
+		logging.FromContext(r.Context()).Warn("Error retrieving session", "error", err)
+		apierror.Write(w, r, errSessionInvalid)
 		return
 	}
 
 	userID, ok := session.Values["user_id"].(int)
 	if !ok {
-		log.Println("Invalid or missing user ID in session")
-		http.Error(w, `{"message":"Unauthorized access. Please log in again."}`, http.StatusUnauthorized)
+		logging.FromContext(r.Context()).Warn("Invalid or missing user ID in session")
+		apierror.Write(w, r, apierror.ErrUnauthorized)
 		return
 	}
 
 	var userDetails models.User
 	if err := json.NewDecoder(r.Body).Decode(&userDetails); err != nil {
-		log.Println("Error decoding request body:", err)
-		http.Error(w, `{"message":"Invalid request payload"}`, http.StatusBadRequest)
+		logging.FromContext(r.Context()).Warn("Error decoding request body", "error", err)
+		apierror.Write(w, r, apierror.ErrInvalidJSON)
 		return
 	}
 
 	err = models.UpdateUserDetails(userID, &userDetails)
 	if err != nil {
-		log.Printf("Error updating user details for user_id %d: %v\n", userID, err)
-		http.Error(w, `{"message":"Failed to update user details"}`, http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Error updating user details", "user_id", userID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to update user details"))
 		return
 	}
 
@@ -308,310 +343,416 @@ func UpdateUserDetails(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// ProxyAvailableVehicles fetches available vehicles from the vehicle_service
+// searchParams parses the start_time, end_time and distance_km query
+// parameters of a vehicle search
+func searchParams(r *http.Request, v *apierror.Validation) (start, end *time.Time, distance *float64) {
+	for _, p := range []struct {
+		name  string
+		field **time.Time
+	}{{"start_time", &start}, {"end_time", &end}} {
+		if value := r.URL.Query().Get(p.name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			v.Check(err == nil, p.name, "must be an RFC 3339 date-time")
+			*p.field = &t
+		}
+	}
+	if value := r.URL.Query().Get("distance_km"); value != "" {
+		km, err := strconv.ParseFloat(value, 64)
+		v.Check(err == nil && km > 0, "distance_km", "must be a positive number")
+		distance = &km
+	}
+	return start, end, distance
+}
+
+// ProxyAvailableVehicles fetches available vehicles from the vehicle_service,
+// optionally only those free between the start_time and end_time parameters
+// and able to drive distance_km
 func ProxyAvailableVehicles(w http.ResponseWriter, r *http.Request) {
-	vehicleServiceURL := "http://localhost:8081/available-vehicles"
+	var params vehicleclient.ListVehiclesParams
+	var v apierror.Validation
+	params.StartTime, params.EndTime, params.DistanceKm = searchParams(r, &v)
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
 
-	// Forward the request to the vehicle_service
-	resp, err := http.Get(vehicleServiceURL)
+	vehicles, err := vehicleAPI(r)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to fetch available vehicles: %v", err), http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.ErrInternal)
+		return
+	}
+
+	// Forward the request to the vehicle_service, which checks the window
+	resp, err := vehicles.ListVehicles(r.Context(), &params)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Failed to fetch available vehicles", "error", err)
+		apierror.Write(w, r, errUpstream)
 		return
 	}
 	defer resp.Body.Close()
 
 	// Forward the response body directly to the frontend
-	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
-	w.WriteHeader(resp.StatusCode)
-
-	if _, err := io.Copy(w, resp.Body); err != nil {
-		http.Error(w, "Failed to forward response", http.StatusInternalServerError)
-	}
+	forwardResponse(w, r, resp)
 }
 
 // ProxyCreateReservation proxies reservation creation requests to vehicle_service
 func ProxyCreateReservation(w http.ResponseWriter, r *http.Request) {
-	vehicleServiceURL := "http://localhost:8081/create-reservation"
-
 	// Retrieve session
 	session, err := store.Get(r, "user-session")
 	if err != nil || session.Values["user_id"] == nil {
-		log.Printf("Error retrieving session: %v", err)
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Unauthorized user. Please log in.",
-		})
+		logging.FromContext(r.Context()).Warn("Error retrieving session", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusUnauthorized, apierror.CodeUnauthorized, "Unauthorized user. Please log in."))
 		return
 	}
 
 	userID, ok := session.Values["user_id"].(int)
 	if !ok {
-		log.Printf("Invalid session data: %v", session.Values)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Session data is corrupted",
-		})
-		return
-	}
-
-	// Read the request body
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		logging.FromContext(r.Context()).Error("Invalid session data", "user_id_type", fmt.Sprintf("%T", session.Values["user_id"]))
+		apierror.Write(w, r, errSessionInvalid)
 		return
 	}
 
 	// Inject user_id into the request payload
-	var payload map[string]interface{}
-	if err := json.Unmarshal(body, &payload); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+	var payload vehicleclient.CreateReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		apierror.Write(w, r, apierror.ErrInvalidJSON)
 		return
 	}
-	payload["user_id"] = userID
+	payload.UserId = userID
 
-	// Forward the request to vehicle_service
-	proxyBody, err := json.Marshal(payload)
-	if err != nil {
-		http.Error(w, "Failed to marshal request payload", http.StatusInternalServerError)
+	// Pass the user's membership on, so that vehicle_service applies the
+	// booking rules of their tier. Values sent by the client are discarded.
+	payload.MembershipTier, payload.BookingLimit = nil, nil
+	membership, err := models.GetUserMembershipDetails(userID)
+	switch {
+	case err == nil:
+		payload.MembershipTier = &membership.Tier
+		payload.BookingLimit = &membership.BookingLimit
+	case errors.Is(err, models.ErrNotFound):
+		// Without a membership the default booking rules apply
+	default:
+		logging.FromContext(r.Context()).Error("Error fetching membership details", "user_id", userID, "error", err)
+		apierror.Write(w, r, apierror.ErrInternal)
 		return
 	}
 
-	req, err := http.NewRequest("POST", vehicleServiceURL, bytes.NewReader(proxyBody))
+	// Forward the request to vehicle_service
+	vehicles, err := vehicleAPI(r)
 	if err != nil {
-		http.Error(w, "Failed to create proxy request", http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.ErrInternal)
 		return
 	}
-	req.Header = r.Header // Forward headers
-	req.Header.Set("Content-Type", "application/json")
-
-	// Forward session cookies
-	if cookie, err := r.Cookie("user-session"); err == nil {
-		req.AddCookie(cookie)
-	}
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	params := &vehicleclient.CreateReservationParams{IdempotencyKey: upstreamIdempotencyKey(r)}
+	resp, err := vehicles.CreateReservation(r.Context(), params, payload)
 	if err != nil {
-		http.Error(w, "Failed to communicate with vehicle_service", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Failed to communicate with vehicle_service", "error", err)
+		apierror.Write(w, r, errUpstream)
 		return
 	}
 	defer resp.Body.Close()
 
-	// Log headers and response
-	log.Printf("Forwarding headers: %+v", req.Header)
-	log.Printf("Response from vehicle_service: %d", resp.StatusCode)
+	// Log the forwarded request and response
+	logging.FromContext(r.Context()).Info("Forwarded request to vehicle_service",
+		"upstream", resp.Request.URL.String(),
+		"upstream_status", resp.StatusCode,
+	)
 
 	// Forward the response from vehicle_service
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		http.Error(w, "Failed to read response from vehicle_service", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
-	w.WriteHeader(resp.StatusCode)
-	if _, err := w.Write(respBody); err != nil {
-		http.Error(w, "Failed to write response", http.StatusInternalServerError)
-	}
+	forwardResponse(w, r, resp)
 }
 
 // ProxyGetLatestReservation proxies the request to fetch the latest reservation for the logged-in user
 func ProxyGetLatestReservation(w http.ResponseWriter, r *http.Request) {
-	vehicleServiceURL := "http://localhost:8081/latest-reservation"
-
 	// Retrieve session
 	session, err := store.Get(r, "user-session")
 	if err != nil || session.Values["user_id"] == nil {
-		log.Printf("Error retrieving session: %v", err)
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Unauthorized user. Please log in.",
-		})
+		logging.FromContext(r.Context()).Warn("Error retrieving session", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusUnauthorized, apierror.CodeUnauthorized, "Unauthorized user. Please log in."))
 		return
 	}
 
 	// Get user_id from session
 	userID, ok := session.Values["user_id"].(int)
 	if !ok {
-		log.Printf("Invalid session data: %v", session.Values)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Session data is corrupted",
-		})
+		logging.FromContext(r.Context()).Error("Invalid session data", "user_id_type", fmt.Sprintf("%T", session.Values["user_id"]))
+		apierror.Write(w, r, errSessionInvalid)
 		return
 	}
 
 	// Log for debugging
-	log.Printf("ProxyGetLatestReservation: Retrieved User ID: %d", userID)
+	logging.FromContext(r.Context()).Debug("ProxyGetLatestReservation: retrieved user from session", "user_id", userID)
 
-	// Prepare the request to vehicle_service
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s?user_id=%d", vehicleServiceURL, userID), nil)
+	// Forward the request to vehicle_service
+	vehicles, err := vehicleAPI(r)
 	if err != nil {
-		http.Error(w, "Failed to create proxy request", http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.ErrInternal)
 		return
 	}
-	req.Header = r.Header // Forward headers
-
-	// Forward session cookies
-	if cookie, err := r.Cookie("user-session"); err == nil {
-		req.AddCookie(cookie)
-	}
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := vehicles.GetLatestReservation(r.Context(), &vehicleclient.GetLatestReservationParams{UserId: userID})
 	if err != nil {
-		http.Error(w, "Failed to communicate with vehicle_service", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Failed to communicate with vehicle_service", "error", err)
+		apierror.Write(w, r, errUpstream)
 		return
 	}
 	defer resp.Body.Close()
 
-	// Log headers and response status for debugging
-	log.Printf("Forwarding headers: %+v", req.Header)
-	log.Printf("Response from vehicle_service: %d", resp.StatusCode)
+	// Log the forwarded request and response
+	logging.FromContext(r.Context()).Info("Forwarded request to vehicle_service",
+		"upstream", resp.Request.URL.String(),
+		"upstream_status", resp.StatusCode,
+	)
 
 	// Forward the response from vehicle_service
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		http.Error(w, "Failed to read response from vehicle_service", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
-	w.WriteHeader(resp.StatusCode)
-	if _, err := w.Write(respBody); err != nil {
-		http.Error(w, "Failed to write response", http.StatusInternalServerError)
-	}
+	forwardResponse(w, r, resp)
 }
 
-// ProxyCalculateRentalFee calculates the total fee based on vehicle rental rate and reservation duration
-func ProxyCalculateRentalFee(w http.ResponseWriter, r *http.Request) {
-	billingServiceURL := "http://localhost:8082/calculate-rental-fee"
-	vehicleServiceURL := "http://localhost:8081/get-vehicle-details" // Assuming an endpoint to fetch vehicle details
-
-	// Read and parse the request body
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, `{"message":"Failed to read request body"}`, http.StatusBadRequest)
+// ProxyCompleteReservation completes the trip of the reservation {id} of the
+// logged-in user
+func ProxyCompleteReservation(w http.ResponseWriter, r *http.Request) {
+	// Retrieve session
+	session, err := store.Get(r, "user-session")
+	if err != nil || session.Values["user_id"] == nil {
+		logging.FromContext(r.Context()).Warn("Error retrieving session", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusUnauthorized, apierror.CodeUnauthorized, "Unauthorized user. Please log in."))
 		return
 	}
 
-	var payload struct {
-		ReservationID int    `json:"reservation_id"`
-		StartTime     string `json:"start_time"`
-		EndTime       string `json:"end_time"`
-		VehicleID     int    `json:"vehicle_id"`
-	}
-	if err := json.Unmarshal(body, &payload); err != nil {
-		http.Error(w, `{"message":"Invalid request payload"}`, http.StatusBadRequest)
+	userID, ok := session.Values["user_id"].(int)
+	if !ok {
+		logging.FromContext(r.Context()).Error("Invalid session data", "user_id_type", fmt.Sprintf("%T", session.Values["user_id"]))
+		apierror.Write(w, r, errSessionInvalid)
 		return
 	}
 
-	// Validate payload
-	if payload.VehicleID == 0 || payload.StartTime == "" || payload.EndTime == "" {
-		http.Error(w, `{"message":"Vehicle ID, start time, and end time are required"}`, http.StatusBadRequest)
+	reservationID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || reservationID <= 0 {
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Invalid Reservation ID").WithField("id", "must be a positive integer"))
 		return
 	}
 
-	// Fetch vehicle details to get the rental rate
-	vehicleDetails, err := fetchVehicleDetails(vehicleServiceURL, payload.VehicleID)
+	// Forward the request to vehicle_service, which only completes a
+	// reservation of this user
+	vehicles, err := vehicleAPI(r)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"message":"Failed to fetch vehicle details: %v"}`, err), http.StatusInternalServerError)
+		apierror.Write(w, r, apierror.ErrInternal)
 		return
 	}
-
-	// Parse the start and end times
-	startTime, err := time.Parse(time.RFC3339, payload.StartTime)
+	resp, err := vehicles.CompleteReservation(r.Context(), reservationID, vehicleclient.CompleteReservationRequest{UserId: userID})
 	if err != nil {
-		http.Error(w, `{"message":"Invalid start time format"}`, http.StatusBadRequest)
+		logging.FromContext(r.Context()).Error("Failed to communicate with vehicle_service", "error", err)
+		apierror.Write(w, r, errUpstream)
 		return
 	}
-	endTime, err := time.Parse(time.RFC3339, payload.EndTime)
-	if err != nil {
-		http.Error(w, `{"message":"Invalid end time format"}`, http.StatusBadRequest)
+	defer resp.Body.Close()
+
+	forwardResponse(w, r, resp)
+}
+
+// ProxyCalculateRentalFee calculates the fee of a reservation of the
+// logged-in user at the current rate of its vehicle. With a reservation_id
+// the times, rate and fees of that reservation are charged; without one the
+// fee of vehicle_id from start_time to end_time is quoted.
+func ProxyCalculateRentalFee(w http.ResponseWriter, r *http.Request) {
+	// Retrieve session
+	session, err := store.Get(r, "user-session")
+	if err != nil || session.Values["user_id"] == nil {
+		logging.FromContext(r.Context()).Warn("Error retrieving session", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusUnauthorized, apierror.CodeUnauthorized, "Unauthorized user. Please log in."))
 		return
 	}
 
-	// Calculate duration in hours
-	duration := endTime.Sub(startTime).Hours()
-	if duration <= 0 {
-		http.Error(w, `{"message":"Invalid time range"}`, http.StatusBadRequest)
+	userID, ok := session.Values["user_id"].(int)
+	if !ok {
+		logging.FromContext(r.Context()).Error("Invalid session data", "user_id_type", fmt.Sprintf("%T", session.Values["user_id"]))
+		apierror.Write(w, r, errSessionInvalid)
 		return
 	}
 
-	// Calculate total fee
-	totalFee := duration * vehicleDetails.RentalRate
-
-	// Prepare the final payload for billing service
-	billingPayload := map[string]interface{}{
-		"reservation_id": payload.ReservationID,
-		"start_time":     payload.StartTime,
-		"end_time":       payload.EndTime,
-		"rental_rate":    vehicleDetails.RentalRate,
-		"total_fee":      totalFee,
+	// Read and parse the request body
+	var payload struct {
+		ReservationID int    `json:"reservation_id"`
+		StartTime     string `json:"start_time"`
+		EndTime       string `json:"end_time"`
+		VehicleID     int    `json:"vehicle_id"`
 	}
-
-	// Forward to billing service
-	respBody, err := forwardToBillingService(billingServiceURL, billingPayload)
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		apierror.Write(w, r, apierror.ErrInvalidJSON)
+		return
+	}
+
+	var request billingclient.CalculateRentalFeeRequest
+	if payload.ReservationID != 0 {
+		// Charge the reservation as vehicle_service stored it, whatever the
+		// client sent
+		reservation, apiErr := fetchReservation(r, payload.ReservationID)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+		if reservation.UserId != userID {
+			logging.FromContext(r.Context()).Warn("Rejected the fee of a reservation of another user", "reservation_id", payload.ReservationID, "user_id", userID)
+			apierror.Write(w, r, errNoSuchReservation)
+			return
+		}
+		request = billingclient.CalculateRentalFeeRequest{
+			ReservationId: &reservation.ReservationId,
+			StartTime:     reservation.StartTime,
+			EndTime:       reservation.EndTime,
+			RentalRate:    reservation.VehicleRentalRate,
+			OneWayFee:     &reservation.OneWayFee,
+			OutOfZoneFee:  &reservation.OutOfZoneFee,
+		}
+	} else {
+		// Validate payload
+		var v apierror.Validation
+		v.Check(payload.VehicleID != 0, "vehicle_id", "is required")
+		startTime, err := time.Parse(time.RFC3339, payload.StartTime)
+		v.Check(err == nil, "start_time", "must be an RFC 3339 timestamp")
+		endTime, err := time.Parse(time.RFC3339, payload.EndTime)
+		v.Check(err == nil, "end_time", "must be an RFC 3339 timestamp")
+		if err := v.Err(); err != nil {
+			apierror.Write(w, r, err)
+			return
+		}
+		if !startTime.Before(endTime) {
+			apierror.Write(w, r, errInvalidTimeRange)
+			return
+		}
+
+		// Fetch vehicle details to get the rental rate
+		rentalRate, apiErr := fetchRentalRate(r, payload.VehicleID)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+		request = billingclient.CalculateRentalFeeRequest{
+			StartTime:  startTime,
+			EndTime:    endTime,
+			RentalRate: rentalRate,
+		}
+	}
+
+	billing, err := billingAPI(r)
+	if err != nil {
+		apierror.Write(w, r, apierror.ErrInternal)
+		return
+	}
+	params := &billingclient.CalculateRentalFeeParams{IdempotencyKey: upstreamIdempotencyKey(r)}
+	resp, err := billing.CalculateRentalFee(r.Context(), params, request)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"message":"Failed to communicate with billing service: %v"}`, err), http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Failed to communicate with billing_service", "error", err)
+		apierror.Write(w, r, errUpstream)
 		return
 	}
+	defer resp.Body.Close()
 
 	// Forward the response from billing service
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(respBody)
+	forwardResponse(w, r, resp)
 }
 
-// fetchVehicleDetails fetches the rental rate of the vehicle from vehicle_service
-func fetchVehicleDetails(vehicleServiceURL string, vehicleID int) (*struct {
-	RentalRate float64 `json:"rental_rate"`
-}, error) {
-	url := fmt.Sprintf("%s?vehicle_id=%d", vehicleServiceURL, vehicleID)
-	resp, err := http.Get(url)
+// fetchRentalRate fetches the rental rate of the vehicle from vehicle_service
+func fetchRentalRate(r *http.Request, vehicleID int) (float64, *apierror.Error) {
+	vehicles, err := vehicleAPI(r)
 	if err != nil {
-		return nil, err
+		return 0, apierror.ErrInternal
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch vehicle details, status: %d", resp.StatusCode)
+	resp, err := vehicles.GetVehicleWithResponse(r.Context(), vehicleID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Failed to fetch vehicle details", "vehicle_id", vehicleID, "error", err)
+		return 0, errUpstream
 	}
-
-	var vehicleDetails struct {
-		RentalRate float64 `json:"rental_rate"`
+	if resp.StatusCode() >= http.StatusBadRequest {
+		return 0, apierror.Parse(resp.StatusCode(), resp.Body)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&vehicleDetails); err != nil {
-		return nil, err
+	if resp.JSON200 == nil {
+		logging.FromContext(r.Context()).Error("Invalid vehicle details response", "vehicle_id", vehicleID, "status", resp.StatusCode())
+		return 0, errUpstream
 	}
 
-	return &vehicleDetails, nil
+	return resp.JSON200.Data.RentalRate, nil
 }
 
-// forwardToBillingService forwards the calculated payload to billing_service
-func forwardToBillingService(billingServiceURL string, payload map[string]interface{}) ([]byte, error) {
-	payloadBytes, err := json.Marshal(payload)
+// fetchReservation fetches a reservation from vehicle_service
+func fetchReservation(r *http.Request, reservationID int) (*vehicleclient.Reservation, *apierror.Error) {
+	vehicles, err := vehicleAPI(r)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal billing payload: %v", err)
+		return nil, apierror.ErrInternal
 	}
-
-	req, err := http.NewRequest("POST", billingServiceURL, bytes.NewReader(payloadBytes))
+	resp, err := vehicles.GetReservationWithResponse(r.Context(), reservationID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request to billing service: %v", err)
+		logging.FromContext(r.Context()).Error("Failed to fetch reservation", "reservation_id", reservationID, "error", err)
+		return nil, errUpstream
+	}
+	if resp.StatusCode() >= http.StatusBadRequest {
+		return nil, apierror.Parse(resp.StatusCode(), resp.Body)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	if resp.JSON200 == nil {
+		logging.FromContext(r.Context()).Error("Invalid reservation response", "reservation_id", reservationID, "status", resp.StatusCode())
+		return nil, errUpstream
+	}
+
+	return &resp.JSON200.Data, nil
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// forwardResponse copies a successful upstream response to w. Upstream errors
+// are passed on in the shared error envelope.
+func forwardResponse(w http.ResponseWriter, r *http.Request, resp *http.Response) {
+	if resp.StatusCode >= http.StatusBadRequest {
+		apierror.Write(w, r, apierror.Decode(resp))
+		return
+	}
+
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		logging.FromContext(r.Context()).Warn("Failed to forward upstream response", "error", err)
+	}
+}
+
+// ProxyNearbyStations fetches the stations and available vehicles within
+// radius_km of the latitude and longitude parameters from the vehicle_service,
+// with the same optional filters as ProxyAvailableVehicles
+func ProxyNearbyStations(w http.ResponseWriter, r *http.Request) {
+	var params vehicleclient.FindNearbyStationsParams
+	var v apierror.Validation
+	for _, p := range []struct {
+		name  string
+		field *float64
+	}{{"latitude", &params.Latitude}, {"longitude", &params.Longitude}} {
+		value := r.URL.Query().Get(p.name)
+		v.Check(value != "", p.name, "is required")
+		if value != "" {
+			var err error
+			*p.field, err = strconv.ParseFloat(value, 64)
+			v.Check(err == nil, p.name, "must be a number")
+		}
+	}
+	if value := r.URL.Query().Get("radius_km"); value != "" {
+		radius, err := strconv.ParseFloat(value, 64)
+		v.Check(err == nil, "radius_km", "must be a number")
+		params.RadiusKm = &radius
+	}
+	params.StartTime, params.EndTime, params.DistanceKm = searchParams(r, &v)
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	vehicles, err := vehicleAPI(r)
 	if err != nil {
-		return nil, fmt.Errorf("failed to communicate with billing service: %v", err)
+		apierror.Write(w, r, apierror.ErrInternal)
+		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("billing service error: %s", string(respBody))
+	// Forward the request to the vehicle_service, which checks the position
+	// and the radius
+	resp, err := vehicles.FindNearbyStations(r.Context(), &params)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Failed to fetch nearby stations", "error", err)
+		apierror.Write(w, r, errUpstream)
+		return
 	}
+	defer resp.Body.Close()
 
-	return io.ReadAll(resp.Body)
+	forwardResponse(w, r, resp)
 }
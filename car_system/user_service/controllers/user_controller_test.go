@@ -0,0 +1,310 @@
+package controllers
+
+import (
+	"bytes"
+	"car_system/user_service/models"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// setupTest wires the handlers to a fresh in-memory store and a test session store
+func setupTest(t *testing.T) *models.MemoryStore {
+	t.Helper()
+	memory := models.NewMemoryStore()
+	models.UseRepositories(memory.Repositories())
+	UseSessionSecret("test-session-secret")
+	return memory
+}
+
+func doRequest(handler http.HandlerFunc, method, target string, body interface{}, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		reader = bytes.NewReader(payload)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not JSON: %v (%q)", err, rec.Body.String())
+	}
+	return body
+}
+
+var testUser = map[string]string{
+	"name":     "Alice Tan",
+	"email":    "alice@example.com",
+	"phone_no": "91234567",
+	"password": "s3cret-pass",
+	"dob":      "1995-04-01",
+}
+
+// registerAndLogin creates testUser and returns its session cookie
+func registerAndLogin(t *testing.T) *http.Cookie {
+	t.Helper()
+	if rec := doRequest(RegisterUser, "POST", "/api/register", testUser); rec.Code != http.StatusOK {
+		t.Fatalf("register: got %d: %s", rec.Code, rec.Body.String())
+	}
+	return login(t)
+}
+
+// login logs testUser in and returns its session cookie
+func login(t *testing.T) *http.Cookie {
+	t.Helper()
+	rec := doRequest(LoginUser, "POST", "/api/login", map[string]string{
+		"email":    testUser["email"],
+		"password": testUser["password"],
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login: got %d: %s", rec.Code, rec.Body.String())
+	}
+	if _, ok := decodeBody(t, rec)["session_id"]; ok {
+		t.Error("login response must not expose the session cookie value")
+	}
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == "user-session" {
+			return c
+		}
+	}
+	t.Fatal("login did not set the user-session cookie")
+	return nil
+}
+
+func TestRegisterUser(t *testing.T) {
+	setupTest(t)
+
+	rec := doRequest(RegisterUser, "POST", "/api/register", testUser)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = doRequest(RegisterUser, "POST", "/api/register", testUser)
+	if rec.Code != http.StatusConflict || decodeBody(t, rec)["code"] != CodeEmailOrPhoneTaken {
+		t.Errorf("duplicate registration: got %d %s, want 409 %s", rec.Code, rec.Body.String(), CodeEmailOrPhoneTaken)
+	}
+
+	rec = doRequest(RegisterUser, "POST", "/api/register", map[string]string{"email": "bob@example.com"})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("missing fields: got %d, want 400", rec.Code)
+	}
+}
+
+func TestLoginUser(t *testing.T) {
+	setupTest(t)
+	doRequest(RegisterUser, "POST", "/api/register", testUser)
+
+	rec := doRequest(LoginUser, "POST", "/api/login", map[string]string{
+		"email":    testUser["email"],
+		"password": "wrong-password",
+	})
+	if rec.Code != http.StatusUnauthorized || decodeBody(t, rec)["code"] != CodeInvalidCredentials {
+		t.Errorf("wrong password: got %d %s, want 401 %s", rec.Code, rec.Body.String(), CodeInvalidCredentials)
+	}
+
+	login(t)
+}
+
+func TestUserDetailsRequireSession(t *testing.T) {
+	setupTest(t)
+
+	for name, handler := range map[string]http.HandlerFunc{
+		"view-details":       DisplayUserDetails,
+		"membership-details": DisplayUserMembership,
+		"rental-records":     DisplayRentalRecords,
+	} {
+		if rec := doRequest(handler, "GET", "/api/"+name, nil); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s without session: got %d, want 401", name, rec.Code)
+		}
+	}
+}
+
+func TestDisplayAndUpdateUserDetails(t *testing.T) {
+	memory := setupTest(t)
+	cookie := registerAndLogin(t)
+
+	rec := doRequest(DisplayUserDetails, "GET", "/api/view-details", nil, cookie)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("view-details: got %d: %s", rec.Code, rec.Body.String())
+	}
+	data := decodeBody(t, rec)["data"].(map[string]interface{})
+	if data["email"] != testUser["email"] {
+		t.Errorf("email = %v, want %s", data["email"], testUser["email"])
+	}
+	if _, ok := data["password"]; ok {
+		t.Error("view-details must not return the password")
+	}
+
+	update := map[string]string{
+		"name":     "Alice Lim",
+		"email":    testUser["email"],
+		"phone_no": testUser["phone_no"],
+		"dob":      testUser["dob"],
+	}
+	if rec := doRequest(UpdateUserDetails, "PUT", "/api/update-details", update, cookie); rec.Code != http.StatusOK {
+		t.Fatalf("update-details: got %d: %s", rec.Code, rec.Body.String())
+	}
+	user, err := memory.Repositories().Users.FindByID(1)
+	if err != nil || user.Name != "Alice Lim" {
+		t.Errorf("stored user = %+v, %v; want name Alice Lim", user, err)
+	}
+
+	// The password is unchanged because the update did not include one
+	login(t)
+}
+
+func TestMembershipAndRentalRecords(t *testing.T) {
+	memory := setupTest(t)
+	cookie := registerAndLogin(t)
+	if err := memory.SetTier(1, "VIP"); err != nil {
+		t.Fatal(err)
+	}
+	memory.AddRental(1, models.Rental{VehicleID: 2, StartTime: "2024-12-01 08:00:00", EndTime: "2024-12-01 12:00:00", Cost: 50, Status: "Completed"})
+
+	rec := doRequest(DisplayUserMembership, "GET", "/api/membership-details", nil, cookie)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("membership-details: got %d: %s", rec.Code, rec.Body.String())
+	}
+	if tier := decodeBody(t, rec)["data"].(map[string]interface{})["tier"]; tier != "VIP" {
+		t.Errorf("tier = %v, want VIP", tier)
+	}
+
+	rec = doRequest(DisplayRentalRecords, "GET", "/api/rental-records", nil, cookie)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("rental-records: got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rentals := decodeBody(t, rec)["data"].([]interface{}); len(rentals) != 1 {
+		t.Errorf("got %d rentals, want 1", len(rentals))
+	}
+}
+
+func TestProxyCreateReservationInjectsUserID(t *testing.T) {
+	memory := setupTest(t)
+	cookie := registerAndLogin(t)
+
+	UseServiceToken("user-service-token")
+	defer UseServiceToken("")
+
+	var forwarded map[string]interface{}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/reservations" {
+			t.Errorf("unexpected upstream path %s", r.URL.Path)
+		}
+		if token := r.Header.Get("X-Service-Token"); token != "user-service-token" {
+			t.Errorf("forwarded service token %q, want the one of user_service", token)
+		}
+		json.NewDecoder(r.Body).Decode(&forwarded)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"Reservation created successfully"}`))
+	}))
+	defer upstream.Close()
+
+	previous := VehicleServiceURL
+	VehicleServiceURL = upstream.URL
+	defer func() { VehicleServiceURL = previous }()
+
+	payload := map[string]interface{}{
+		"vehicle_id": 3,
+		"user_id":    999, // must be replaced by the session user
+		// must be replaced by the user's membership
+		"membership_tier": "VIP",
+		"booking_limit":   100,
+		"start_time":      "2030-01-01T10:00:00Z",
+		"end_time":        "2030-01-01T12:00:00Z",
+	}
+	req := httptest.NewRequest("POST", "/api/proxy-create-reservation", strings.NewReader(`{"vehicle_id": 3, "start_time": "2030-01-01T10:00:00Z", "end_time": "2030-01-01T12:00:00Z"}`))
+	req.AddCookie(cookie)
+	// A service token sent by the client is not forwarded
+	req.Header.Set("X-Service-Token", "forged-token")
+	rec := httptest.NewRecorder()
+	ProxyCreateReservation(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = doRequest(ProxyCreateReservation, "POST", "/api/proxy-create-reservation", payload, cookie)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	if forwarded["user_id"] != float64(1) {
+		t.Errorf("forwarded user_id = %v, want 1", forwarded["user_id"])
+	}
+	if forwarded["membership_tier"] != "Basic" || forwarded["booking_limit"] != float64(5) {
+		t.Errorf("forwarded membership = %v / %v, want the user's Basic / 5", forwarded["membership_tier"], forwarded["booking_limit"])
+	}
+
+	if err := memory.SetTier(1, "Premium"); err != nil {
+		t.Fatal(err)
+	}
+	doRequest(ProxyCreateReservation, "POST", "/api/proxy-create-reservation", payload, cookie)
+	if forwarded["membership_tier"] != "Premium" || forwarded["booking_limit"] != float64(10) {
+		t.Errorf("forwarded membership = %v / %v, want Premium / 10", forwarded["membership_tier"], forwarded["booking_limit"])
+	}
+
+	if rec := doRequest(ProxyCreateReservation, "POST", "/api/proxy-create-reservation", payload); rec.Code != http.StatusUnauthorized {
+		t.Errorf("without session: got %d, want 401", rec.Code)
+	}
+}
+
+func TestProxyCalculateRentalFee(t *testing.T) {
+	setupTest(t)
+	cookie := registerAndLogin(t)
+
+	// Reservation 5 is the user's one-way trip, 6 a trip of another user
+	vehicles := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := map[string]int{"/v1/reservations/5": 1, "/v1/reservations/6": 2}[r.URL.Path]
+		if userID == 0 {
+			t.Errorf("unexpected upstream path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"message": "Reservation fetched successfully", "data": map[string]interface{}{
+			"reservation_id": 5, "vehicle_id": 3, "user_id": userID, "status": "Completed",
+			"start_time": "2030-01-01T10:00:00Z", "end_time": "2030-01-01T14:00:00Z", "created_at": "2029-12-01T10:00:00Z",
+			"expected_charge_level": 0, "vehicle_rental_rate": 45, "pickup_station_id": 3, "return_station_id": 5,
+			"one_way_fee": 12.5, "out_of_zone_fee": 50,
+		}})
+	}))
+	defer vehicles.Close()
+	var forwarded map[string]interface{}
+	billing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&forwarded)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"Rental fee calculated successfully"}`))
+	}))
+	defer billing.Close()
+
+	previousVehicles, previousBilling := VehicleServiceURL, BillingServiceURL
+	VehicleServiceURL, BillingServiceURL = vehicles.URL, billing.URL
+	defer func() { VehicleServiceURL, BillingServiceURL = previousVehicles, previousBilling }()
+
+	// The times and vehicle sent by the client are ignored for a reservation
+	payload := map[string]interface{}{"reservation_id": 5, "vehicle_id": 1, "start_time": "2030-01-01T10:00:00Z", "end_time": "2030-01-01T11:00:00Z"}
+	if rec := doRequest(ProxyCalculateRentalFee, "POST", "/v1/rental-fees", payload, cookie); rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	if forwarded["end_time"] != "2030-01-01T14:00:00Z" || forwarded["rental_rate"] != float64(45) || forwarded["one_way_fee"] != 12.5 || forwarded["out_of_zone_fee"] != float64(50) {
+		t.Errorf("forwarded %v, want the times, rate and fees of reservation 5", forwarded)
+	}
+
+	forwarded = nil
+	payload["reservation_id"] = 6
+	rec := doRequest(ProxyCalculateRentalFee, "POST", "/v1/rental-fees", payload, cookie)
+	if rec.Code != http.StatusNotFound || decodeBody(t, rec)["code"] != CodeReservationNotFound || forwarded != nil {
+		t.Errorf("reservation of another user: got %d %s, want 404 %s", rec.Code, rec.Body.String(), CodeReservationNotFound)
+	}
+
+	if rec := doRequest(ProxyCalculateRentalFee, "POST", "/v1/rental-fees", payload); rec.Code != http.StatusUnauthorized {
+		t.Errorf("without session: got %d, want 401", rec.Code)
+	}
+}
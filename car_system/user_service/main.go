@@ -1,43 +1,129 @@
 package main
 
 import (
+	"car_system/common/httpserver"
+	"car_system/common/logging"
+	"car_system/common/metrics"
+	"car_system/common/migrate"
+	"car_system/common/settings"
 	"car_system/user_service/config"
 	"car_system/user_service/controllers"
-	"log"
-	"net/http"
-
-	"github.com/gorilla/mux"
+	"car_system/user_service/migrations"
+	"car_system/user_service/models"
+	"car_system/user_service/server"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
-	// Connect to the database
-	config.ConnectDB()
-	defer config.DB.Close()
-
-	// Initialize session store globally in controllers
-	controllers.InitializeSessionStore()
-
-	// Set up router
-	router := mux.NewRouter()
-
-	// API Routes
-	api := router.PathPrefix("/api").Subrouter()
-	api.HandleFunc("/register", controllers.RegisterUser).Methods("POST")
-	api.HandleFunc("/login", controllers.LoginUser).Methods("POST")
-	api.HandleFunc("/rental-records", controllers.DisplayRentalRecords).Methods("GET")
-	api.HandleFunc("/membership-details", controllers.DisplayUserMembership).Methods("GET")
-	api.HandleFunc("/view-details", controllers.DisplayUserDetails).Methods("GET")
-	api.HandleFunc("/update-details", controllers.UpdateUserDetails).Methods("PUT")
-	api.HandleFunc("/proxy-available-vehicles", controllers.ProxyAvailableVehicles).Methods("GET")
-	api.HandleFunc("/proxy-create-reservation", controllers.ProxyCreateReservation).Methods("POST")
-	api.HandleFunc("/proxy-get-latest-reservation", controllers.ProxyGetLatestReservation).Methods("GET")
-	api.HandleFunc("/proxy-calculate-rental-fee", controllers.ProxyCalculateRentalFee).Methods("POST")
-
-	// Serve static files
-	staticDir := "./static/"
-	router.PathPrefix("/").Handler(http.StripPrefix("/", http.FileServer(http.Dir(staticDir))))
-
-	// Start the server
-	log.Println("User-service running on port 8080...")
-	log.Fatal(http.ListenAndServe(":8080", router))
+	// Resolve the configuration from defaults, .env, the environment and flags
+	cfg, args, err := config.Load(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// Print the resolved configuration with secrets redacted
+	if len(args) > 0 && args[0] == "config" {
+		settings.Print(os.Stdout, &cfg)
+		return
+	}
+
+	// Set up structured logging
+	logger := logging.New("user_service", cfg.LogLevel)
+	slog.SetDefault(logger)
+	logger.Info("Configuration loaded", "config", settings.Map(&cfg))
+
+	// Select the storage backend: MySQL by default, in-memory with STORAGE_BACKEND=memory
+	if cfg.StorageBackend == "memory" {
+		logger.Info("Using in-memory storage")
+		models.UseRepositories(models.NewMemoryRepositories())
+	} else {
+		// Connect to the database
+		config.ConnectDB(cfg)
+		defer config.DB.Close()
+
+		if !prepareDatabase(logger, cfg, args) {
+			return
+		}
+		models.UseRepositories(models.NewMySQLRepositories(config.DB))
+	}
+
+	// Expose request, upstream and connection pool metrics
+	appMetrics := metrics.New("user_service")
+	if config.DB != nil {
+		appMetrics.RegisterDB(config.DB, cfg.DB.Name)
+	}
+
+	// Initialize session store and upstream URLs globally in controllers
+	controllers.UseSessionSecret(cfg.SessionSecret)
+	controllers.UseSessionCookie(cfg.SessionCookie)
+	controllers.VehicleServiceURL = cfg.VehicleServiceURL
+	controllers.BillingServiceURL = cfg.BillingServiceURL
+	controllers.UseServiceToken(cfg.ServiceToken)
+
+	// Set up the router and middleware
+	handler := server.NewHandler(server.Options{
+		Logger:           logger,
+		Metrics:          appMetrics,
+		DB:               config.DB,
+		StaticDir:        cfg.StaticDir,
+		ValidateRequests: cfg.ValidateRequests,
+		Legacy:           cfg.Legacy,
+		Idempotency:      cfg.Idempotency,
+		RateLimits:       cfg.RateLimits,
+	})
+
+	// Start the server and drain in-flight requests on SIGINT/SIGTERM
+	srv := httpserver.New(fmt.Sprintf(":%d", cfg.Port), handler, cfg.HTTP)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("User-service running", "port", cfg.Port)
+	if err := httpserver.Run(ctx, srv, cfg.HTTP.ShutdownTimeout); err != nil {
+		logger.Error("Server stopped", "error", err)
+		return
+	}
+	logger.Info("User-service shut down gracefully")
+}
+
+// prepareDatabase runs a migrate subcommand (up, down, status, seed, force) or
+// applies pending migrations. It returns false when the service should not
+// start serving requests.
+func prepareDatabase(logger *slog.Logger, cfg config.Config, args []string) bool {
+	migrator, err := migrations.New(config.DB)
+	if err != nil {
+		logger.Error("Failed to load migrations", "error", err)
+		return false
+	}
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := migrate.RunCommand(context.Background(), migrator, args[1:], os.Stdout); err != nil {
+			logger.Error("Migration command failed", "error", err)
+			config.DB.Close()
+			os.Exit(1)
+		}
+		return false
+	}
+
+	// Bring the schema up to date before serving requests
+	if cfg.AutoMigrate {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			logger.Error("Failed to apply migrations", "error", err)
+			return false
+		}
+		logger.Info("Database schema is up to date", "applied", applied)
+	}
+	return true
 }
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS Rental_History;
+DROP TABLE IF EXISTS User;
+DROP TABLE IF EXISTS Membership;
+DROP TABLE IF EXISTS DriverLicense;
@@ -0,0 +1,44 @@
+-- Baseline of the user_service schema. IF NOT EXISTS lets databases created
+-- from the old schema.sql adopt the migration history without data loss.
+
+-- Driver License
+CREATE TABLE IF NOT EXISTS DriverLicense (
+    license_id INT AUTO_INCREMENT PRIMARY KEY,
+    license_no VARCHAR(50) UNIQUE NOT NULL,
+    license_issue_date DATE NOT NULL,
+    license_expiry_date DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Membership Table
+CREATE TABLE IF NOT EXISTS Membership (
+    membership_tier VARCHAR(50) PRIMARY KEY,
+    hourly_rate_discount DECIMAL(5, 2) NOT NULL,
+    priority_access BOOLEAN DEFAULT FALSE,
+    booking_limit INT NOT NULL
+);
+
+-- User Table
+CREATE TABLE IF NOT EXISTS User (
+    user_id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    email VARCHAR(100) UNIQUE NOT NULL,
+    phone_no VARCHAR(15) UNIQUE NOT NULL,
+    password VARCHAR(500) NOT NULL,
+    dob DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    membership_tier VARCHAR(50) DEFAULT 'Basic',
+    FOREIGN KEY (membership_tier) REFERENCES Membership(membership_tier)
+);
+
+-- Rental History Table
+CREATE TABLE IF NOT EXISTS Rental_History (
+    history_id SERIAL PRIMARY KEY,
+    user_id INT UNSIGNED NOT NULL,  -- Matches User.user_id
+    vehicle_id INT NOT NULL,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    cost DECIMAL(10, 2) NOT NULL,
+    status ENUM('Completed', 'Refunded', 'Cancelled') DEFAULT 'Completed',
+    FOREIGN KEY (user_id) REFERENCES User(user_id)
+);
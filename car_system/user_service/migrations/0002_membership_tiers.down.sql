@@ -0,0 +1,4 @@
+-- Users keep a foreign key to their tier, so tiers still in use stay behind
+DELETE FROM Membership
+WHERE membership_tier IN ('Basic', 'Premium', 'VIP')
+  AND membership_tier NOT IN (SELECT membership_tier FROM User WHERE membership_tier IS NOT NULL);
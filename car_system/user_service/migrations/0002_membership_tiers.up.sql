@@ -0,0 +1,6 @@
+-- Membership tiers are reference data: every new user defaults to 'Basic'
+INSERT IGNORE INTO Membership (membership_tier, hourly_rate_discount, priority_access, booking_limit)
+VALUES
+('Basic', 0.00, FALSE, 5),
+('Premium', 10.00, TRUE, 10),
+('VIP', 20.00, TRUE, 20);
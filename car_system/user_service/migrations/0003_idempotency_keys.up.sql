@@ -0,0 +1,12 @@
+-- Responses stored for requests sent with an Idempotency-Key header, see
+-- common/idempotency. idempotency_key is a SHA-256 of the caller's scope and key.
+CREATE TABLE IF NOT EXISTS IdempotencyKey (
+    idempotency_key CHAR(64) PRIMARY KEY,
+    fingerprint CHAR(64) NOT NULL,
+    status_code INT DEFAULT NULL,
+    response_header TEXT DEFAULT NULL,
+    response_body MEDIUMBLOB DEFAULT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_idempotency_expires_at (expires_at)
+);
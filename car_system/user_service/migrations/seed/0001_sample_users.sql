@@ -0,0 +1,25 @@
+-- Sample data for local development. Apply with: go run . migrate seed
+
+-- User Data
+INSERT IGNORE INTO User (name, email, phone_no, password, dob, membership_tier)
+VALUES
+('John Doe', 'john.doe@example.com', '1234567890', 'hashed_password_1', '1990-01-15', 'Basic'),
+('Jane Smith', 'jane.smith@example.com', '0987654321', 'hashed_password_2', '1985-06-25', 'Premium'),
+('Robert Brown', 'robert.brown@example.com', '1122334455', 'hashed_password_3', '1995-11-10', 'VIP'),
+('Emily Davis', 'emily.davis@example.com', '5566778899', 'hashed_password_4', '1992-03-05', 'Basic');
+
+-- Rental History Data
+INSERT INTO Rental_History (user_id, vehicle_id, start_time, end_time, cost, status)
+VALUES
+(1, 101, '2024-12-01 08:00:00', '2024-12-01 12:00:00', 50.00, 'Completed'),
+(2, 102, '2024-12-02 10:00:00', '2024-12-02 14:00:00', 80.00, 'Completed'),
+(3, 103, '2024-12-03 09:00:00', '2024-12-03 17:00:00', 120.00, 'Refunded'),
+(1, 104, '2024-12-04 15:00:00', '2024-12-04 18:00:00', 75.00, 'Cancelled');
+
+-- Driver License Data
+INSERT IGNORE INTO DriverLicense (license_no, license_issue_date, license_expiry_date)
+VALUES
+('DL12345678', '2020-01-15', '2030-01-15'),
+('DL87654321', '2018-06-10', '2028-06-10'),
+('DL45678901', '2019-03-20', '2029-03-20'),
+('DL23456789', '2021-08-05', '2031-08-05');
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MemoryStore is an in-memory backend for local development and tests. It is
+// created with the same membership tiers as the 0002_membership_tiers migration.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	nextID    int
+	users     map[int]User
+	userTiers map[int]string
+	tiers     map[string]Membership
+	rentals   map[int][]Rental
+}
+
+// NewMemoryStore returns an empty store with the default membership tiers
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		nextID:    1,
+		users:     map[int]User{},
+		userTiers: map[int]string{},
+		tiers: map[string]Membership{
+			"Basic":   {Tier: "Basic", HourlyRateDiscount: 0, PriorityAccess: false, BookingLimit: 5},
+			"Premium": {Tier: "Premium", HourlyRateDiscount: 10, PriorityAccess: true, BookingLimit: 10},
+			"VIP":     {Tier: "VIP", HourlyRateDiscount: 20, PriorityAccess: true, BookingLimit: 20},
+		},
+		rentals: map[int][]Rental{},
+	}
+}
+
+// NewMemoryRepositories returns repositories backed by a new MemoryStore
+func NewMemoryRepositories() Repositories {
+	return NewMemoryStore().Repositories()
+}
+
+// Repositories returns the repository views over the store
+func (s *MemoryStore) Repositories() Repositories {
+	return Repositories{
+		Users:       memoryUserRepository{s},
+		Memberships: memoryMembershipRepository{s},
+		Rentals:     memoryRentalRepository{s},
+	}
+}
+
+// SetTier changes the membership tier of a user
+func (s *MemoryStore) SetTier(userID int, tier string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tiers[tier]; !ok {
+		return fmt.Errorf("unknown membership tier %q", tier)
+	}
+	if _, ok := s.users[userID]; !ok {
+		return ErrNotFound
+	}
+	s.userTiers[userID] = tier
+	return nil
+}
+
+// AddRental appends a record to the rental history of a user
+func (s *MemoryStore) AddRental(userID int, rental Rental) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rental.HistoryID = len(s.rentals[userID]) + 1
+	s.rentals[userID] = append(s.rentals[userID], rental)
+}
+
+type memoryUserRepository struct {
+	s *MemoryStore
+}
+
+func (r memoryUserRepository) Create(user *User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, existing := range r.s.users {
+		if strings.EqualFold(existing.Email, user.Email) || existing.PhoneNo == user.PhoneNo {
+			return fmt.Errorf("duplicate email or phone number")
+		}
+	}
+	user.UserID = r.s.nextID
+	r.s.nextID++
+	r.s.users[user.UserID] = *user
+	r.s.userTiers[user.UserID] = "Basic"
+	return nil
+}
+
+func (r memoryUserRepository) ExistsByEmailOrPhone(email, phoneNo string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	for _, existing := range r.s.users {
+		if strings.EqualFold(existing.Email, email) || existing.PhoneNo == phoneNo {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memoryUserRepository) FindByEmail(email string) (*User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	for _, existing := range r.s.users {
+		if strings.EqualFold(existing.Email, email) {
+			user := existing
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r memoryUserRepository) FindByID(userID int) (*User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	existing, ok := r.s.users[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	user := existing
+	user.Password = ""
+	return &user, nil
+}
+
+func (r memoryUserRepository) Update(userID int, user *User, passwordHash string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	existing, ok := r.s.users[userID]
+	if !ok {
+		return ErrNotFound
+	}
+	existing.Name = user.Name
+	existing.Email = user.Email
+	existing.PhoneNo = user.PhoneNo
+	existing.DOB = user.DOB
+	if passwordHash != "" {
+		existing.Password = passwordHash
+	}
+	r.s.users[userID] = existing
+	return nil
+}
+
+type memoryMembershipRepository struct {
+	s *MemoryStore
+}
+
+func (r memoryMembershipRepository) FindByUserID(userID int) (*Membership, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	tier, ok := r.s.userTiers[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	membership := r.s.tiers[tier]
+	return &membership, nil
+}
+
+type memoryRentalRepository struct {
+	s *MemoryStore
+}
+
+func (r memoryRentalRepository) ListByUserID(userID int) ([]Rental, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return append([]Rental(nil), r.s.rentals[userID]...), nil
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"database/sql"
+	"log/slog"
+)
+
+// NewMySQLRepositories returns repositories backed by the user_service database
+func NewMySQLRepositories(db *sql.DB) Repositories {
+	return Repositories{
+		Users:       &mysqlUserRepository{db: db},
+		Memberships: &mysqlMembershipRepository{db: db},
+		Rentals:     &mysqlRentalRepository{db: db},
+	}
+}
+
+type mysqlUserRepository struct {
+	db *sql.DB
+}
+
+func (r *mysqlUserRepository) Create(user *User) error {
+	query := "INSERT INTO User (name, email, phone_no, password, dob) VALUES (?, ?, ?, ?, ?)"
+	result, err := r.db.Exec(query, user.Name, user.Email, user.PhoneNo, user.Password, user.DOB)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	user.UserID = int(id)
+	return nil
+}
+
+func (r *mysqlUserRepository) ExistsByEmailOrPhone(email, phoneNo string) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM User WHERE email = ? OR phone_no = ?"
+	if err := r.db.QueryRow(query, email, phoneNo).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *mysqlUserRepository) FindByEmail(email string) (*User, error) {
+	var user User
+	query := "SELECT user_id, name, email, phone_no, password, dob FROM User WHERE email = ?"
+	err := r.db.QueryRow(query, email).Scan(&user.UserID, &user.Name, &user.Email, &user.PhoneNo, &user.Password, &user.DOB)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *mysqlUserRepository) FindByID(userID int) (*User, error) {
+	var user User
+	query := "SELECT user_id, name, email, phone_no, dob FROM User WHERE user_id = ?"
+	err := r.db.QueryRow(query, userID).Scan(&user.UserID, &user.Name, &user.Email, &user.PhoneNo, &user.DOB)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *mysqlUserRepository) Update(userID int, user *User, passwordHash string) error {
+	var updateFields []interface{}
+	query := "UPDATE User SET name = ?, email = ?, phone_no = ?, dob = ?"
+	updateFields = append(updateFields, user.Name, user.Email, user.PhoneNo, user.DOB)
+
+	if passwordHash != "" {
+		query += ", password = ?"
+		updateFields = append(updateFields, passwordHash)
+	}
+
+	query += " WHERE user_id = ?"
+	updateFields = append(updateFields, userID)
+
+	_, err := r.db.Exec(query, updateFields...)
+	return err
+}
+
+type mysqlMembershipRepository struct {
+	db *sql.DB
+}
+
+func (r *mysqlMembershipRepository) FindByUserID(userID int) (*Membership, error) {
+	var membership Membership
+
+	query := `
+        SELECT m.membership_tier, m.hourly_rate_discount, m.priority_access, m.booking_limit
+        FROM Membership m
+        INNER JOIN User u ON u.membership_tier = m.membership_tier
+        WHERE u.user_id = ?
+    `
+
+	err := r.db.QueryRow(query, userID).Scan(
+		&membership.Tier,
+		&membership.HourlyRateDiscount,
+		&membership.PriorityAccess,
+		&membership.BookingLimit,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &membership, nil
+}
+
+type mysqlRentalRepository struct {
+	db *sql.DB
+}
+
+func (r *mysqlRentalRepository) ListByUserID(userID int) ([]Rental, error) {
+	query := `
+		SELECT history_id, vehicle_id, start_time, end_time, cost, status
+		FROM Rental_History
+		WHERE user_id = ?
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		slog.Error("Error fetching rental records", "user_id", userID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rentals []Rental
+	for rows.Next() {
+		var rental Rental
+		if err := rows.Scan(&rental.HistoryID, &rental.VehicleID, &rental.StartTime, &rental.EndTime, &rental.Cost, &rental.Status); err != nil {
+			slog.Error("Error scanning rental record", "error", err)
+			return nil, err
+		}
+		rentals = append(rentals, rental)
+	}
+
+	return rentals, rows.Err()
+}
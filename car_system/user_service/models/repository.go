@@ -0,0 +1,43 @@
+package models
+
+import "errors"
+
+// ErrNotFound is returned by repositories when a record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// UserRepository stores user accounts
+type UserRepository interface {
+	// Create inserts user, whose Password already holds the bcrypt hash, and sets its UserID
+	Create(user *User) error
+	ExistsByEmailOrPhone(email, phoneNo string) (bool, error)
+	// FindByEmail returns the user including the password hash, or nil when no user matches
+	FindByEmail(email string) (*User, error)
+	FindByID(userID int) (*User, error)
+	// Update replaces the profile fields and, when passwordHash is not empty, the password
+	Update(userID int, user *User, passwordHash string) error
+}
+
+// MembershipRepository stores membership tiers
+type MembershipRepository interface {
+	FindByUserID(userID int) (*Membership, error)
+}
+
+// RentalRepository stores the rental history of users
+type RentalRepository interface {
+	ListByUserID(userID int) ([]Rental, error)
+}
+
+// Repositories groups the storage backends used by the model functions
+type Repositories struct {
+	Users       UserRepository
+	Memberships MembershipRepository
+	Rentals     RentalRepository
+}
+
+// repos is the storage backend selected at startup with UseRepositories
+var repos Repositories
+
+// UseRepositories selects the storage backend used by the model functions
+func UseRepositories(r Repositories) {
+	repos = r
+}
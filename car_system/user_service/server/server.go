@@ -0,0 +1,160 @@
+package server
+
+import (
+	"car_system/common/apispec"
+	"car_system/common/deprecation"
+	"car_system/common/health"
+	"car_system/common/idempotency"
+	"car_system/common/logging"
+	"car_system/common/metrics"
+	"car_system/common/ratelimit"
+	openapi "car_system/user_service/api"
+	"car_system/user_service/controllers"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// spec is the OpenAPI document of user_service, served at /openapi.json
+var spec = apispec.MustLoad(openapi.Spec)
+
+// Options holds the dependencies of a user_service instance
+type Options struct {
+	Logger  *slog.Logger
+	Metrics *metrics.Metrics
+	// DB is nil when the service runs on the in-memory backend
+	DB        *sql.DB
+	StaticDir string
+
+	// VehicleTransport and BillingTransport, when set, carry the proxied
+	// calls instead of the network (see common/inprocess)
+	VehicleTransport http.RoundTripper
+	BillingTransport http.RoundTripper
+
+	// ValidateRequests rejects requests that do not match api/openapi.json
+	ValidateRequests bool
+	// Legacy holds the dates announced by the unversioned legacy routes
+	Legacy deprecation.Policy
+	// Idempotency configures the replay of requests retried with the same
+	// Idempotency-Key; the keys are stored in DB when it is set
+	Idempotency idempotency.Settings
+	// RateLimits are the per-client limits of the API routes. The zero value
+	// disables them.
+	RateLimits RateLimits
+	// RateLimitStore keeps the rate limit buckets; nil keeps them in memory
+	RateLimitStore ratelimit.Store
+}
+
+// RateLimits are the token bucket policies of the API routes. Each route has
+// its own buckets, per session user or, without a session, per client IP.
+type RateLimits struct {
+	Register     ratelimit.Policy `env:"RATE_LIMIT_REGISTER" default:"5/1h" usage:"Registrations per client, as <requests>/<period> or off"`
+	Login        ratelimit.Policy `env:"RATE_LIMIT_LOGIN" default:"10/1m" usage:"Login attempts per client"`
+	Reservations ratelimit.Policy `env:"RATE_LIMIT_RESERVATIONS" default:"20/1m" usage:"Reservation requests per client"`
+	Default      ratelimit.Policy `env:"RATE_LIMIT_DEFAULT" default:"120/1m" usage:"Requests per client to each other API route"`
+	// TrustForwardedFor identifies anonymous clients by X-Forwarded-For
+	TrustForwardedFor bool `env:"RATE_LIMIT_TRUST_FORWARDED_FOR" usage:"Take the client IP from X-Forwarded-For (only behind a reverse proxy)"`
+}
+
+// NewHandler builds the user_service router wrapped in the logging and metrics
+// middleware. The session store and the upstream URLs in controllers must be
+// set before it is called.
+func NewHandler(opts Options) http.Handler {
+	controllers.InitializeUpstreamClients(opts.Metrics, opts.VehicleTransport, opts.BillingTransport)
+	router := newRouter(opts)
+
+	var handler http.Handler = router
+	if opts.ValidateRequests {
+		handler = spec.ValidateRequests(handler)
+	}
+	handler = idempotency.Middleware(idempotency.Options{
+		Store: idempotency.NewStore(opts.DB),
+		TTL:   opts.Idempotency.TTL,
+		Scope: controllers.SessionUser,
+	})(handler)
+
+	return logging.Middleware(opts.Logger)(opts.Metrics.InstrumentRouter(router, handler))
+}
+
+// newRouter registers the API, probe, metrics and static routes
+func newRouter(opts Options) *mux.Router {
+	// Set up router
+	router := mux.NewRouter()
+
+	// Rate limited handlers, shared by the /v1 routes and their legacy
+	// aliases so that both count against the same buckets
+	limits := opts.RateLimits
+	limiter := ratelimit.New(ratelimit.Options{
+		Store:             opts.RateLimitStore,
+		Identify:          controllers.SessionUser,
+		TrustForwardedFor: limits.TrustForwardedFor,
+		Registerer:        opts.Metrics.Registerer,
+	})
+	var (
+		registerUser          = limiter.Wrap("register", limits.Register, controllers.RegisterUser)
+		loginUser             = limiter.Wrap("login", limits.Login, controllers.LoginUser)
+		displayUserDetails    = limiter.Wrap("view-details", limits.Default, controllers.DisplayUserDetails)
+		updateUserDetails     = limiter.Wrap("update-details", limits.Default, controllers.RequireCSRFToken(controllers.UpdateUserDetails))
+		displayRentalRecords  = limiter.Wrap("rentals", limits.Default, controllers.DisplayRentalRecords)
+		displayUserMembership = limiter.Wrap("membership", limits.Default, controllers.DisplayUserMembership)
+		availableVehicles     = limiter.Wrap("vehicles", limits.Default, controllers.ProxyAvailableVehicles)
+		nearbyStations        = limiter.Wrap("nearby-stations", limits.Default, controllers.ProxyNearbyStations)
+		createReservation     = limiter.Wrap("reservations", limits.Reservations, controllers.RequireCSRFToken(controllers.ProxyCreateReservation))
+		latestReservation     = limiter.Wrap("latest-reservation", limits.Reservations, controllers.ProxyGetLatestReservation)
+		completeReservation   = limiter.Wrap("complete-reservation", limits.Reservations, controllers.RequireCSRFToken(controllers.ProxyCompleteReservation))
+		calculateRentalFee    = limiter.Wrap("rental-fees", limits.Default, controllers.ProxyCalculateRentalFee)
+		csrfToken             = limiter.Wrap("csrf-token", limits.Default, controllers.IssueCSRFToken)
+	)
+
+	// API Routes
+	v1 := router.PathPrefix("/v1").Subrouter()
+	v1.HandleFunc("/users", registerUser).Methods("POST")
+	v1.HandleFunc("/sessions", loginUser).Methods("POST")
+	v1.HandleFunc("/me", displayUserDetails).Methods("GET")
+	v1.HandleFunc("/me", updateUserDetails).Methods("PUT")
+	v1.HandleFunc("/me/rentals", displayRentalRecords).Methods("GET")
+	v1.HandleFunc("/me/membership", displayUserMembership).Methods("GET")
+	v1.HandleFunc("/vehicles", availableVehicles).Methods("GET")
+	v1.HandleFunc("/stations/nearby", nearbyStations).Methods("GET")
+	v1.HandleFunc("/reservations", createReservation).Methods("POST")
+	v1.HandleFunc("/reservations/latest", latestReservation).Methods("GET")
+	v1.HandleFunc("/reservations/{id}/complete", completeReservation).Methods("POST")
+	v1.HandleFunc("/rental-fees", calculateRentalFee).Methods("POST")
+	v1.HandleFunc("/csrf-token", csrfToken).Methods("GET")
+
+	// Legacy aliases of the routes above, kept until the sunset date
+	legacy := deprecation.New(opts.Metrics.Registerer, opts.Legacy)
+	legacy.Handle(router, "/api/register", "/v1/users", registerUser).Methods("POST")
+	legacy.Handle(router, "/api/login", "/v1/sessions", loginUser).Methods("POST")
+	legacy.Handle(router, "/api/rental-records", "/v1/me/rentals", displayRentalRecords).Methods("GET")
+	legacy.Handle(router, "/api/membership-details", "/v1/me/membership", displayUserMembership).Methods("GET")
+	legacy.Handle(router, "/api/view-details", "/v1/me", displayUserDetails).Methods("GET")
+	legacy.Handle(router, "/api/update-details", "/v1/me", updateUserDetails).Methods("PUT")
+	legacy.Handle(router, "/api/proxy-available-vehicles", "/v1/vehicles", availableVehicles).Methods("GET")
+	legacy.Handle(router, "/api/proxy-create-reservation", "/v1/reservations", createReservation).Methods("POST")
+	legacy.Handle(router, "/api/proxy-get-latest-reservation", "/v1/reservations/latest", latestReservation).Methods("GET")
+	legacy.Handle(router, "/api/proxy-calculate-rental-fee", "/v1/rental-fees", calculateRentalFee).Methods("POST")
+
+	// OpenAPI document
+	router.Handle("/openapi.json", spec.Handler()).Methods("GET")
+
+	// Prometheus metrics
+	router.Handle("/metrics", opts.Metrics.Handler()).Methods("GET")
+
+	// Liveness and readiness probes
+	checker := health.New("user_service", 2*time.Second)
+	if opts.DB != nil {
+		checker.Add("database", health.DBCheck(opts.DB))
+	}
+	controllers.RegisterUpstreamChecks(checker)
+	router.Handle("/healthz", checker.LiveHandler()).Methods("GET")
+	router.Handle("/readyz", checker.ReadyHandler()).Methods("GET")
+
+	// Serve static files
+	router.PathPrefix("/").Handler(http.StripPrefix("/", http.FileServer(http.Dir(opts.StaticDir))))
+
+	return router
+}
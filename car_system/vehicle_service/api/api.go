@@ -0,0 +1,9 @@
+// Package api embeds the OpenAPI description of vehicle_service.
+package api
+
+import _ "embed"
+
+// Spec is the OpenAPI 3 document served at /openapi.json
+//
+//go:embed openapi.json
+var Spec []byte
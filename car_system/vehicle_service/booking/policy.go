@@ -0,0 +1,419 @@
+// Package booking holds the rules a reservation must satisfy before
+// vehicle_service accepts it: its duration, how far ahead it is made, blackout
+// dates, the number of bookings a member may hold and the turnaround each
+// vehicle needs between two reservations. It also prices one-way trips.
+//
+// The rules of a Policy apply to everyone, and membership tiers may override
+// individual rules, e.g. to let VIP members book for a week. A violated rule
+// is reported as an apierror with a specific code.
+package booking
+
+import (
+	"car_system/common/apierror"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Error codes of violated rules. BOOKING_LIMIT_REACHED is a 409 because it
+// depends on the other reservations of the user; the others are 422.
+const (
+	CodeStartInPast  = "BOOKING_START_IN_PAST"
+	CodeLeadTime     = "BOOKING_LEAD_TIME_TOO_SHORT"
+	CodeTooFarAhead  = "BOOKING_TOO_FAR_AHEAD"
+	CodeTooShort     = "BOOKING_TOO_SHORT"
+	CodeTooLong      = "BOOKING_TOO_LONG"
+	CodeBlackout     = "BOOKING_BLACKOUT"
+	CodeLimitReached = "BOOKING_LIMIT_REACHED"
+)
+
+const violationStatus = http.StatusUnprocessableEntity
+
+// Rules bound the reservations of a tier. A zero field does not restrict
+// anything.
+type Rules struct {
+	// MinDuration and MaxDuration bound the length of a reservation
+	MinDuration time.Duration
+	MaxDuration time.Duration
+	// MinLeadTime is how long before its start a reservation must be made
+	MinLeadTime time.Duration
+	// MaxAdvance is how far ahead of now a reservation may start
+	MaxAdvance time.Duration
+}
+
+// Override replaces the rules of Rules that are set. A rule set to 0 lifts
+// the limit, e.g. a MaxAdvance of 0 lets a tier book any time ahead.
+type Override struct {
+	MinDuration *time.Duration
+	MaxDuration *time.Duration
+	MinLeadTime *time.Duration
+	MaxAdvance  *time.Duration
+}
+
+// override returns r with the rules set in o
+func (r Rules) override(o Override) Rules {
+	for _, f := range ruleFields {
+		if d := *f.override(&o); d != nil {
+			*f.rule(&r) = *d
+		}
+	}
+	return r
+}
+
+// Period is a half-open time interval [Start, End)
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Policy is the complete set of booking rules. The zero Policy has no rules of
+// its own and only enforces the booking limit of a Request.
+type Policy struct {
+	// Default applies to every reservation
+	Default Rules
+	// Tiers override individual rules of Default for a membership tier
+	Tiers TierRules
+	// Blackouts are the periods in which no reservation may run
+	Blackouts []Period
+	// BookingLimit is how many upcoming reservations a user may hold when
+	// the request does not carry the limit of their membership; 0 is
+	// unlimited
+	BookingLimit int
+	// Turnaround is the time kept free after every reservation of a vehicle
+	// for cleaning and charging
+	Turnaround Turnaround
+	// OneWay prices the reservations returned to another station
+	OneWay OneWayFee
+	// TimeZone is the zone of the blackout dates and of the opening hours of
+	// the stations; nil is UTC
+	TimeZone *time.Location
+}
+
+// Zone returns the time zone of the policy
+func (p Policy) Zone() *time.Location {
+	if p.TimeZone == nil {
+		return time.UTC
+	}
+	return p.TimeZone
+}
+
+// Turnaround is the time a vehicle needs between two reservations. Models
+// override Default for the vehicles of a model, e.g. one that charges slowly.
+type Turnaround struct {
+	Default time.Duration
+	Models  ModelDurations
+}
+
+// For returns the turnaround of the vehicles of model
+func (t Turnaround) For(model string) time.Duration {
+	if d, ok := t.Models[model]; ok {
+		return d
+	}
+	return t.Default
+}
+
+// OneWayFee is the fee of a reservation that returns the vehicle to another
+// station than the one it was picked up at: Base plus PerKM for every km
+// between the two stations
+type OneWayFee struct {
+	Base  float64
+	PerKM float64
+}
+
+// For returns the fee of a one-way trip between stations distanceKM apart,
+// rounded to cents
+func (f OneWayFee) For(distanceKM float64) float64 {
+	return math.Round((f.Base+f.PerKM*distanceKM)*100) / 100
+}
+
+// Request describes a reservation to check
+type Request struct {
+	// Tier is the membership tier of the user; "" applies the default rules
+	Tier       string
+	Start, End time.Time
+	// BookingLimit is how many upcoming reservations the user may hold, and
+	// Upcoming how many they hold now. A zero limit is not enforced.
+	BookingLimit int
+	Upcoming     int
+}
+
+// RulesFor returns the rules that apply to tier
+func (p Policy) RulesFor(tier string) Rules {
+	return p.Default.override(p.Tiers[tier])
+}
+
+// Check returns the first rule req violates at time now, or nil
+func (p Policy) Check(req Request, now time.Time) *apierror.Error {
+	rules := p.RulesFor(req.Tier)
+	duration := req.End.Sub(req.Start)
+	lead := req.Start.Sub(now)
+
+	switch {
+	case lead < 0:
+		return apierror.New(violationStatus, CodeStartInPast, "Reservations cannot start in the past").
+			WithField("start_time", "is in the past")
+	case lead < rules.MinLeadTime:
+		return apierror.New(violationStatus, CodeLeadTime, fmt.Sprintf("Reservations must be made at least %s before they start", describe(rules.MinLeadTime))).
+			WithField("start_time", "is too soon")
+	case rules.MaxAdvance > 0 && lead > rules.MaxAdvance:
+		return apierror.New(violationStatus, CodeTooFarAhead, fmt.Sprintf("Reservations can be made at most %s ahead", describe(rules.MaxAdvance))).
+			WithField("start_time", "is too far ahead")
+	case duration < rules.MinDuration:
+		return apierror.New(violationStatus, CodeTooShort, fmt.Sprintf("Reservations must last at least %s", describe(rules.MinDuration))).
+			WithField("end_time", "is too close to start_time")
+	case rules.MaxDuration > 0 && duration > rules.MaxDuration:
+		return apierror.New(violationStatus, CodeTooLong, fmt.Sprintf("Reservations must last at most %s", describe(rules.MaxDuration))).
+			WithField("end_time", "is too far from start_time")
+	}
+
+	for _, b := range p.Blackouts {
+		if req.Start.Before(b.End) && req.End.After(b.Start) {
+			return apierror.New(violationStatus, CodeBlackout, fmt.Sprintf("No reservations can run between %s and %s",
+				b.Start.Format(time.RFC3339), b.End.Format(time.RFC3339)))
+		}
+	}
+
+	if req.BookingLimit > 0 && req.Upcoming >= req.BookingLimit {
+		return apierror.New(http.StatusConflict, CodeLimitReached, fmt.Sprintf("Your membership allows %d upcoming reservations at a time", req.BookingLimit))
+	}
+	return nil
+}
+
+// describe renders d in the largest whole unit: "3 days", "1 hour", "90m0s"
+func describe(d time.Duration) string {
+	for _, unit := range []struct {
+		size time.Duration
+		name string
+	}{{24 * time.Hour, "day"}, {time.Hour, "hour"}, {time.Minute, "minute"}} {
+		if d >= unit.size && d%unit.size == 0 {
+			n := int(d / unit.size)
+			if n == 1 {
+				return "1 " + unit.name
+			}
+			return fmt.Sprintf("%d %ss", n, unit.name)
+		}
+	}
+	return d.String()
+}
+
+// TierRules maps membership tiers to their overrides. As text it is written
+// "<tier>:<rule>=<duration>[,<rule>=<duration>]" with tiers separated by ";",
+// e.g. "VIP:max_duration=168h;Premium:max_duration=120h,max_advance=4320h".
+// The rules are min_duration, max_duration, min_lead_time and max_advance;
+// a rule set to 0 is lifted for the tier.
+type TierRules map[string]Override
+
+// ruleFields names the fields of Rules and Override in their text form
+var ruleFields = []struct {
+	name     string
+	rule     func(*Rules) *time.Duration
+	override func(*Override) **time.Duration
+}{
+	{"min_duration", func(r *Rules) *time.Duration { return &r.MinDuration }, func(o *Override) **time.Duration { return &o.MinDuration }},
+	{"max_duration", func(r *Rules) *time.Duration { return &r.MaxDuration }, func(o *Override) **time.Duration { return &o.MaxDuration }},
+	{"min_lead_time", func(r *Rules) *time.Duration { return &r.MinLeadTime }, func(o *Override) **time.Duration { return &o.MinLeadTime }},
+	{"max_advance", func(r *Rules) *time.Duration { return &r.MaxAdvance }, func(o *Override) **time.Duration { return &o.MaxAdvance }},
+}
+
+// UnmarshalText lets tier rules be loaded by common/settings
+func (t *TierRules) UnmarshalText(text []byte) error {
+	parsed := TierRules{}
+	for _, entry := range strings.Split(string(text), ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		tier, list, ok := strings.Cut(entry, ":")
+		tier = strings.TrimSpace(tier)
+		if !ok || tier == "" {
+			return fmt.Errorf("expected <tier>:<rule>=<duration>, got %q", entry)
+		}
+		rules := parsed[tier]
+		for _, assignment := range strings.Split(list, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(assignment), "=")
+			d, err := time.ParseDuration(value)
+			if err != nil || d < 0 {
+				return fmt.Errorf("expected a duration such as 168h for %s of tier %s, got %q", name, tier, value)
+			}
+			field := ruleField(&rules, name)
+			if field == nil {
+				return fmt.Errorf("unknown booking rule %q of tier %s", name, tier)
+			}
+			*field = &d
+		}
+		parsed[tier] = rules
+	}
+	*t = parsed
+	return nil
+}
+
+// MarshalText is the inverse of UnmarshalText, with the tiers sorted
+func (t TierRules) MarshalText() ([]byte, error) {
+	tiers := make([]string, 0, len(t))
+	for tier := range t {
+		tiers = append(tiers, tier)
+	}
+	sort.Strings(tiers)
+
+	entries := make([]string, 0, len(tiers))
+	for _, tier := range tiers {
+		rules := t[tier]
+		var assignments []string
+		for _, f := range ruleFields {
+			if d := *f.override(&rules); d != nil {
+				assignments = append(assignments, f.name+"="+shortDuration(*d))
+			}
+		}
+		entries = append(entries, tier+":"+strings.Join(assignments, ","))
+	}
+	return []byte(strings.Join(entries, ";")), nil
+}
+
+// shortDuration formats d without zero trailing units: 168h instead of 168h0m0s
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+func ruleField(o *Override, name string) **time.Duration {
+	for _, f := range ruleFields {
+		if f.name == name {
+			return f.override(o)
+		}
+	}
+	return nil
+}
+
+// ModelDurations maps vehicle models to a duration. As text it is written
+// "<model>=<duration>" with models separated by ";", e.g.
+// "Nissan Leaf=45m;BMW i3=1h". Model names may contain spaces.
+type ModelDurations map[string]time.Duration
+
+// UnmarshalText lets model durations be loaded by common/settings
+func (m *ModelDurations) UnmarshalText(text []byte) error {
+	parsed := ModelDurations{}
+	for _, entry := range strings.Split(string(text), ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		model, value, ok := strings.Cut(entry, "=")
+		model = strings.TrimSpace(model)
+		if !ok || model == "" {
+			return fmt.Errorf("expected <model>=<duration>, got %q", entry)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d < 0 {
+			return fmt.Errorf("expected a duration such as 45m for model %s, got %q", model, value)
+		}
+		parsed[model] = d
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalText is the inverse of UnmarshalText, with the models sorted
+func (m ModelDurations) MarshalText() ([]byte, error) {
+	models := make([]string, 0, len(m))
+	for model := range m {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+
+	entries := make([]string, 0, len(models))
+	for _, model := range models {
+		entries = append(entries, model+"="+shortDuration(m[model]))
+	}
+	return []byte(strings.Join(entries, ";")), nil
+}
+
+// ParseBlackouts parses dates ("2026-12-25") and inclusive date ranges
+// ("2026-12-24..2026-12-26") into periods covering whole days in loc
+func ParseBlackouts(items []string, loc *time.Location) ([]Period, error) {
+	var periods []Period
+	for _, item := range items {
+		first, last, isRange := strings.Cut(item, "..")
+		if !isRange {
+			last = first
+		}
+		start, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(first), loc)
+		if err != nil {
+			return nil, fmt.Errorf("expected a date (2006-01-02) or a range (2006-01-02..2006-01-03), got %q", item)
+		}
+		end, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(last), loc)
+		if err != nil || end.Before(start) {
+			return nil, fmt.Errorf("expected a date range whose end is not before its start, got %q", item)
+		}
+		periods = append(periods, Period{Start: start, End: end.AddDate(0, 0, 1)})
+	}
+	return periods, nil
+}
+
+// Settings is the configuration of a Policy
+type Settings struct {
+	MinDuration  time.Duration `env:"BOOKING_MIN_DURATION" default:"1h" usage:"Shortest reservation (0 for no limit)"`
+	MaxDuration  time.Duration `env:"BOOKING_MAX_DURATION" default:"72h" usage:"Longest reservation (0 for no limit)"`
+	MinLeadTime  time.Duration `env:"BOOKING_MIN_LEAD_TIME" default:"15m" usage:"How long before its start a reservation must be made"`
+	MaxAdvance   time.Duration `env:"BOOKING_MAX_ADVANCE" default:"2160h" usage:"How far ahead a reservation may start (0 for no limit)"`
+	Tiers        TierRules     `env:"BOOKING_TIER_RULES" default:"VIP:max_duration=168h" usage:"Per-tier overrides, e.g. VIP:max_duration=168h;Premium:max_advance=4320h"`
+	Blackouts    []string      `env:"BOOKING_BLACKOUT_DATES" usage:"Comma-separated dates or ranges (2026-12-24..2026-12-26) on which no reservation may run"`
+	BookingLimit int           `env:"BOOKING_DEFAULT_LIMIT" default:"5" usage:"Upcoming reservations a user without a membership may hold (0 for no limit)"`
+	TimeZone     string        `env:"BOOKING_TIME_ZONE" default:"UTC" usage:"Time zone of the blackout dates and of the station opening hours"`
+
+	Turnaround      time.Duration  `env:"BOOKING_TURNAROUND" default:"30m" usage:"Time kept free after every reservation of a vehicle for cleaning and charging"`
+	ModelTurnaround ModelDurations `env:"BOOKING_TURNAROUND_BY_MODEL" usage:"Per-model turnaround, e.g. Nissan Leaf=45m;BMW i3=1h"`
+
+	OneWayFee      float64 `env:"BOOKING_ONE_WAY_FEE" default:"10" usage:"Flat fee of a reservation returned to another station"`
+	OneWayFeePerKM float64 `env:"BOOKING_ONE_WAY_FEE_PER_KM" default:"0.5" usage:"Fee per km between the pickup and return stations of a one-way reservation"`
+}
+
+// Policy builds the policy described by s
+func (s Settings) Policy() (Policy, error) {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return Policy{}, fmt.Errorf("BOOKING_TIME_ZONE: %v", err)
+	}
+	blackouts, err := ParseBlackouts(s.Blackouts, loc)
+	if err != nil {
+		return Policy{}, fmt.Errorf("BOOKING_BLACKOUT_DATES: %v", err)
+	}
+	policy := Policy{
+		Default: Rules{
+			MinDuration: s.MinDuration,
+			MaxDuration: s.MaxDuration,
+			MinLeadTime: s.MinLeadTime,
+			MaxAdvance:  s.MaxAdvance,
+		},
+		Tiers:        s.Tiers,
+		Blackouts:    blackouts,
+		BookingLimit: s.BookingLimit,
+		Turnaround:   Turnaround{Default: s.Turnaround, Models: s.ModelTurnaround},
+		OneWay:       OneWayFee{Base: s.OneWayFee, PerKM: s.OneWayFeePerKM},
+		TimeZone:     loc,
+	}
+	if s.OneWayFee < 0 || s.OneWayFeePerKM < 0 {
+		return Policy{}, fmt.Errorf("BOOKING_ONE_WAY_FEE and BOOKING_ONE_WAY_FEE_PER_KM must not be negative")
+	}
+	if s.BookingLimit < 0 {
+		return Policy{}, fmt.Errorf("BOOKING_DEFAULT_LIMIT must not be negative, got %d", s.BookingLimit)
+	}
+	if s.Turnaround < 0 {
+		return Policy{}, fmt.Errorf("BOOKING_TURNAROUND must not be negative, got %s", s.Turnaround)
+	}
+	for tier := range policy.Tiers {
+		if r := policy.RulesFor(tier); r.MaxDuration > 0 && r.MinDuration > r.MaxDuration {
+			return Policy{}, fmt.Errorf("booking rules of tier %s: min_duration %s exceeds max_duration %s", tier, r.MinDuration, r.MaxDuration)
+		}
+	}
+	if r := policy.Default; r.MaxDuration > 0 && r.MinDuration > r.MaxDuration {
+		return Policy{}, fmt.Errorf("BOOKING_MIN_DURATION %s exceeds BOOKING_MAX_DURATION %s", r.MinDuration, r.MaxDuration)
+	}
+	return policy, nil
+}
@@ -0,0 +1,144 @@
+package booking
+
+import (
+	"car_system/common/settings"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// duration returns a pointer to d, the value of a rule set in an Override
+func duration(d time.Duration) *time.Duration {
+	return &d
+}
+
+// request starts a reservation lead after now and lasts duration
+func request(tier string, lead, duration time.Duration) Request {
+	start := now.Add(lead)
+	return Request{Tier: tier, Start: start, End: start.Add(duration)}
+}
+
+func TestCheck(t *testing.T) {
+	blackouts, err := ParseBlackouts([]string{"2030-01-10", "2030-02-01..2030-02-03"}, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := Policy{
+		Default:   Rules{MinDuration: time.Hour, MaxDuration: 72 * time.Hour, MinLeadTime: 15 * time.Minute, MaxAdvance: 90 * 24 * time.Hour},
+		Tiers:     TierRules{"VIP": {MaxDuration: duration(7 * 24 * time.Hour)}, "Corporate": {MaxAdvance: duration(0), MinLeadTime: duration(0)}},
+		Blackouts: blackouts,
+	}
+
+	for name, tc := range map[string]struct {
+		req  Request
+		want string
+	}{
+		"valid":               {request("", time.Hour, 2*time.Hour), ""},
+		"in the past":         {request("", -time.Minute, 2*time.Hour), CodeStartInPast},
+		"too soon":            {request("", 10*time.Minute, 2*time.Hour), CodeLeadTime},
+		"too far ahead":       {request("", 91*24*time.Hour, 2*time.Hour), CodeTooFarAhead},
+		"too short":           {request("", time.Hour, 30*time.Minute), CodeTooShort},
+		"too long":            {request("", time.Hour, 4*24*time.Hour), CodeTooLong},
+		"VIP may book a week": {request("VIP", time.Hour, 7*24*time.Hour), ""},
+		"VIP keeps the rest":  {request("VIP", time.Hour, 30*time.Minute), CodeTooShort},
+		"unknown tier":        {request("Gold", time.Hour, 4*24*time.Hour), CodeTooLong},
+		"lifted max advance":  {request("Corporate", 365*24*time.Hour, 2*time.Hour), ""},
+		"lifted lead time":    {request("Corporate", time.Minute, 2*time.Hour), ""},
+		"others keep limits":  {request("VIP", 365*24*time.Hour, 2*time.Hour), CodeTooFarAhead},
+		"blackout day":        {request("", 9*24*time.Hour+14*time.Hour, 2*time.Hour), CodeBlackout},
+		"across a blackout":   {request("", 30*24*time.Hour, 72*time.Hour), CodeBlackout},
+		"after a blackout":    {request("", 34*24*time.Hour, 2*time.Hour), ""},
+		"limit reached":       {Request{Start: now.Add(time.Hour), End: now.Add(3 * time.Hour), BookingLimit: 2, Upcoming: 2}, CodeLimitReached},
+		"under the limit":     {Request{Start: now.Add(time.Hour), End: now.Add(3 * time.Hour), BookingLimit: 2, Upcoming: 1}, ""},
+	} {
+		got := ""
+		if err := policy.Check(tc.req, now); err != nil {
+			got = err.Code
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %q, want %q", name, got, tc.want)
+		}
+	}
+
+	if err := policy.Check(request("", time.Hour, 4*24*time.Hour), now); err.Message != "Reservations must last at most 3 days" {
+		t.Errorf("message = %q", err.Message)
+	}
+	if err := (Policy{}).Check(request("", time.Hour, 30*24*time.Hour), now); err != nil {
+		t.Errorf("zero policy rejected a reservation: %v", err.Code)
+	}
+}
+
+func TestTierRulesText(t *testing.T) {
+	var tiers TierRules
+	if err := tiers.UnmarshalText([]byte("VIP:max_duration=168h,max_advance=0; Premium:max_duration=120h,max_advance=4320h")); err != nil {
+		t.Fatal(err)
+	}
+	if vip, premium := tiers["VIP"], tiers["Premium"]; *vip.MaxDuration != 168*time.Hour || *vip.MaxAdvance != 0 || vip.MinDuration != nil || *premium.MaxAdvance != 4320*time.Hour {
+		t.Errorf("parsed %+v", tiers)
+	}
+	if text, _ := tiers.MarshalText(); string(text) != "Premium:max_duration=120h,max_advance=4320h;VIP:max_duration=168h,max_advance=0s" {
+		t.Errorf("MarshalText = %q", text)
+	}
+	for _, text := range []string{"VIP", "VIP:max_duration", "VIP:max_duration=soon", "VIP:fastest=1h"} {
+		if err := tiers.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func TestTurnaround(t *testing.T) {
+	var models ModelDurations
+	if err := models.UnmarshalText([]byte("Nissan Leaf=45m; BMW i3 = 1h")); err != nil {
+		t.Fatal(err)
+	}
+	turnaround := Turnaround{Default: 30 * time.Minute, Models: models}
+	if turnaround.For("Nissan Leaf") != 45*time.Minute || turnaround.For("BMW i3") != time.Hour || turnaround.For("Tesla Model 3") != 30*time.Minute {
+		t.Errorf("turnaround = %+v", turnaround)
+	}
+	if text, _ := models.MarshalText(); string(text) != "BMW i3=1h;Nissan Leaf=45m" {
+		t.Errorf("MarshalText = %q", text)
+	}
+	for _, text := range []string{"Nissan Leaf", "=45m", "Nissan Leaf=soon", "Nissan Leaf=-5m"} {
+		if err := models.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func TestSettings(t *testing.T) {
+	var s Settings
+	if _, err := settings.Load("test", &s, []string{"-booking-blackout-dates", "2030-12-25", "-booking-time-zone", "Asia/Singapore"}); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := s.Policy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.RulesFor("VIP").MaxDuration != 168*time.Hour || policy.RulesFor("Basic").MaxDuration != 72*time.Hour {
+		t.Errorf("default tier rules = %+v", policy.Tiers)
+	}
+	// The blackout day starts at midnight in Singapore, 16:00 UTC the day before
+	if len(policy.Blackouts) != 1 || !policy.Blackouts[0].Start.Equal(time.Date(2030, 12, 24, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("blackouts = %v", policy.Blackouts)
+	}
+	if policy.Turnaround.For("Tesla Model 3") != 30*time.Minute {
+		t.Errorf("default turnaround = %+v", policy.Turnaround)
+	}
+	if fee := policy.OneWay.For(12.345); fee != 16.17 {
+		t.Errorf("one-way fee for 12.345 km = %v, want 16.17", fee)
+	}
+
+	for _, bad := range []Settings{
+		{TimeZone: "Mars/Olympus"},
+		{Blackouts: []string{"2030-02-03..2030-02-01"}},
+		{MinDuration: 4 * time.Hour, MaxDuration: 2 * time.Hour},
+		{MaxDuration: 2 * time.Hour, Tiers: TierRules{"VIP": {MinDuration: duration(3 * time.Hour)}}},
+		{Turnaround: -time.Minute},
+		{OneWayFeePerKM: -0.5},
+	} {
+		if _, err := bad.Policy(); err == nil {
+			t.Errorf("%+v.Policy() succeeded, want an error", bad)
+		}
+	}
+}
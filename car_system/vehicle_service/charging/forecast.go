@@ -0,0 +1,218 @@
+// Package charging forecasts the state of charge of a vehicle at a future
+// time, so that vehicle_service can tell whether a reservation will get the
+// expected_charge_level it asks for.
+//
+// The forecast starts from the current charge_level of the vehicle. While the
+// vehicle is parked it charges at the power of its station, up to 100%. Every
+// reservation before the forecast time is assumed to bring the vehicle back
+// at ReturnLevel, or lower if it left with less.
+package charging
+
+import (
+	"car_system/vehicle_service/models"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CodeChargeUnreachable reports a reservation whose expected charge level is
+// not forecast to be reached. It is a 409 because it depends on the state of
+// the vehicle and its other reservations.
+const CodeChargeUnreachable = "CHARGE_LEVEL_UNREACHABLE"
+
+// What a Forecaster does with a reservation whose expected charge level is
+// not forecast to be reached
+const (
+	ShortfallReject = "reject"
+	ShortfallWarn   = "warn"
+	ShortfallIgnore = "off"
+)
+
+// Forecaster predicts charge levels
+type Forecaster struct {
+	// PowerKW is the effective charging power at a station without its own
+	// setting, and StationPowerKW the power of individual stations
+	PowerKW        float64
+	StationPowerKW StationPower
+	// ReturnLevel is the charge level, in percent, at which a reservation is
+	// assumed to bring the vehicle back
+	ReturnLevel float64
+	// Shortfall is ShortfallReject, ShortfallWarn or ShortfallIgnore
+	Shortfall string
+}
+
+// PowerAt returns the charging power at station
+func (f Forecaster) PowerAt(station string) float64 {
+	if kw, ok := f.StationPowerKW[station]; ok {
+		return kw
+	}
+	return f.PowerKW
+}
+
+// ratePerHour returns how many percent v gains per hour of charging
+func (f Forecaster) ratePerHour(v models.Vehicle) float64 {
+	if v.BatteryCapacityKWH <= 0 {
+		return 0
+	}
+	return f.PowerAt(v.Location) / v.BatteryCapacityKWH * 100
+}
+
+// LevelAt predicts the charge level of v at time at. reservations are the
+// reservations of v that have not ended by now, in any order; those that
+// start at or after at do not matter.
+func (f Forecaster) LevelAt(v models.Vehicle, reservations []models.Reservation, now, at time.Time) float64 {
+	sorted := append([]models.Reservation(nil), reservations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartTime.Before(sorted[j].StartTime) })
+
+	rate := f.ratePerHour(v)
+	charge := func(level float64, d time.Duration) float64 {
+		return math.Min(100, level+rate*d.Hours())
+	}
+
+	level, parked := v.ChargeLevel, now
+	for _, res := range sorted {
+		if !res.StartTime.Before(at) {
+			break
+		}
+		if !res.EndTime.After(parked) {
+			continue
+		}
+		if res.StartTime.After(parked) {
+			level = charge(level, res.StartTime.Sub(parked))
+		}
+		level = math.Min(level, f.ReturnLevel)
+		parked = res.EndTime
+	}
+	if at.After(parked) {
+		level = charge(level, at.Sub(parked))
+	}
+	return round(level)
+}
+
+// round keeps one decimal, which is all a forecast is good for
+func round(level float64) float64 {
+	return math.Round(level*10) / 10
+}
+
+// Shortfall is a reservation that is not forecast to get its expected charge
+type Shortfall struct {
+	// ReservationID is 0 for the reservation being made, or the next
+	// reservation of the vehicle that the new one would leave short
+	ReservationID int
+	Predicted     float64
+	Expected      float64
+}
+
+// Message describes the shortfall to the user
+func (s Shortfall) Message() string {
+	if s.ReservationID == 0 {
+		return fmt.Sprintf("The vehicle is forecast to hold %s%% at pickup, below the expected %s%%", percent(s.Predicted), percent(s.Expected))
+	}
+	return fmt.Sprintf("This reservation would leave the vehicle at %s%% for the next reservation, which expects %s%%", percent(s.Predicted), percent(s.Expected))
+}
+
+func percent(level float64) string {
+	return strconv.FormatFloat(level, 'f', -1, 64)
+}
+
+// Check forecasts the charge of v at the pickup of res, given its reservations
+// booked so far that have not ended by now. It returns that forecast and the
+// first shortfall: of res itself, or of the next booked reservation once res
+// brings the vehicle back.
+func (f Forecaster) Check(v models.Vehicle, booked []models.Reservation, res models.Reservation, now time.Time) (float64, *Shortfall) {
+	predicted := f.LevelAt(v, booked, now, res.StartTime)
+	if predicted < res.ExpectedChargeLevel {
+		return predicted, &Shortfall{Predicted: predicted, Expected: res.ExpectedChargeLevel}
+	}
+
+	var next *models.Reservation
+	for i, b := range booked {
+		if !b.StartTime.Before(res.EndTime) && (next == nil || b.StartTime.Before(next.StartTime)) {
+			next = &booked[i]
+		}
+	}
+	if next != nil {
+		withRes := append(append([]models.Reservation(nil), booked...), res)
+		level := f.LevelAt(v, withRes, now, next.StartTime)
+		if level < next.ExpectedChargeLevel && f.LevelAt(v, booked, now, next.StartTime) >= next.ExpectedChargeLevel {
+			return predicted, &Shortfall{ReservationID: next.ReservationID, Predicted: level, Expected: next.ExpectedChargeLevel}
+		}
+	}
+	return predicted, nil
+}
+
+// StationPower maps stations to their charging power in kW. As text it is
+// written "<station>=<kW>" with stations separated by ";", e.g.
+// "Downtown Station=50;Airport Terminal=22".
+type StationPower map[string]float64
+
+// UnmarshalText lets station power be loaded by common/settings
+func (p *StationPower) UnmarshalText(text []byte) error {
+	parsed := StationPower{}
+	for _, entry := range strings.Split(string(text), ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		station, value, ok := strings.Cut(entry, "=")
+		station = strings.TrimSpace(station)
+		if !ok || station == "" {
+			return fmt.Errorf("expected <station>=<kW>, got %q", entry)
+		}
+		kw, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || kw < 0 {
+			return fmt.Errorf("expected a power in kW such as 22 for station %s, got %q", station, value)
+		}
+		parsed[station] = kw
+	}
+	*p = parsed
+	return nil
+}
+
+// MarshalText is the inverse of UnmarshalText, with the stations sorted
+func (p StationPower) MarshalText() ([]byte, error) {
+	stations := make([]string, 0, len(p))
+	for station := range p {
+		stations = append(stations, station)
+	}
+	sort.Strings(stations)
+
+	entries := make([]string, 0, len(stations))
+	for _, station := range stations {
+		entries = append(entries, station+"="+strconv.FormatFloat(p[station], 'f', -1, 64))
+	}
+	return []byte(strings.Join(entries, ";")), nil
+}
+
+// Settings is the configuration of a Forecaster
+type Settings struct {
+	PowerKW        float64      `env:"CHARGER_POWER_KW" default:"11" usage:"Effective charging power in kW at stations without their own setting"`
+	StationPowerKW StationPower `env:"CHARGER_POWER_BY_STATION" usage:"Per-station charging power in kW, e.g. Downtown Station=50;Airport Terminal=22"`
+	ReturnLevel    float64      `env:"CHARGE_FORECAST_RETURN_LEVEL" default:"20" usage:"Charge level in percent at which a reservation is assumed to end"`
+	Shortfall      string       `env:"CHARGE_SHORTFALL" default:"reject" usage:"What to do with a reservation whose expected charge is not forecast to be reached (reject, warn or off)"`
+}
+
+// Forecaster builds the forecaster described by s
+func (s Settings) Forecaster() (*Forecaster, error) {
+	switch {
+	case s.PowerKW < 0:
+		return nil, fmt.Errorf("CHARGER_POWER_KW must not be negative, got %g", s.PowerKW)
+	case s.ReturnLevel < 0 || s.ReturnLevel > 100:
+		return nil, fmt.Errorf("CHARGE_FORECAST_RETURN_LEVEL must be between 0 and 100, got %g", s.ReturnLevel)
+	}
+	switch s.Shortfall {
+	case "":
+		s.Shortfall = ShortfallIgnore
+	case ShortfallReject, ShortfallWarn, ShortfallIgnore:
+	default:
+		return nil, fmt.Errorf("CHARGE_SHORTFALL must be reject, warn or off, got %q", s.Shortfall)
+	}
+	return &Forecaster{
+		PowerKW:        s.PowerKW,
+		StationPowerKW: s.StationPowerKW,
+		ReturnLevel:    s.ReturnLevel,
+		Shortfall:      s.Shortfall,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package charging
+
+import (
+	"car_system/common/settings"
+	"car_system/vehicle_service/models"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// at returns now plus hours
+func at(hours float64) time.Time {
+	return now.Add(time.Duration(hours * float64(time.Hour)))
+}
+
+// forecaster charges a 62 kWh Nissan Leaf by 20% an hour, or by 50% at the
+// Airport Terminal
+var forecaster = Forecaster{
+	PowerKW:        12.4,
+	StationPowerKW: StationPower{"Airport Terminal": 31},
+	ReturnLevel:    20,
+	Shortfall:      ShortfallReject,
+}
+
+var leaf = models.Vehicle{VehicleID: 2, Model: "Nissan Leaf", ChargeLevel: 30, Location: "Suburban Hub", BatteryCapacityKWH: 62}
+
+func TestLevelAt(t *testing.T) {
+	morning := models.Reservation{ReservationID: 1, StartTime: at(1), EndTime: at(3)}
+	airport := leaf
+	airport.Location = "Airport Terminal"
+	unknownBattery := leaf
+	unknownBattery.BatteryCapacityKWH = 0
+
+	for name, tc := range map[string]struct {
+		vehicle      models.Vehicle
+		reservations []models.Reservation
+		at           time.Time
+		want         float64
+	}{
+		"now":                      {leaf, nil, now, 30},
+		"parked for 2h":            {leaf, nil, at(2), 70},
+		"full after 3.5h":          {leaf, nil, at(5), 100},
+		"faster station":           {airport, nil, at(1), 80},
+		"after a reservation":      {leaf, []models.Reservation{morning}, at(4), 40},
+		"at the next pickup":       {leaf, []models.Reservation{morning}, at(3), 20},
+		"before the reservation":   {leaf, []models.Reservation{morning}, at(0.5), 40},
+		"already returned":         {leaf, []models.Reservation{{StartTime: at(-3), EndTime: at(-1)}}, at(1), 50},
+		"in use now":               {leaf, []models.Reservation{{StartTime: at(-1), EndTime: at(1)}}, at(2), 40},
+		"unknown battery capacity": {unknownBattery, nil, at(2), 30},
+	} {
+		if got := forecaster.LevelAt(tc.vehicle, tc.reservations, now, tc.at); got != tc.want {
+			t.Errorf("%s: got %g%%, want %g%%", name, got, tc.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	next := models.Reservation{ReservationID: 7, StartTime: at(4.5), EndTime: at(6), ExpectedChargeLevel: 60}
+	booked := []models.Reservation{next}
+
+	predicted, shortfall := forecaster.Check(leaf, booked, models.Reservation{StartTime: at(2), EndTime: at(3), ExpectedChargeLevel: 80}, now)
+	if predicted != 70 || shortfall == nil || shortfall.ReservationID != 0 {
+		t.Errorf("pickup at 70%% expecting 80%%: got %g%%, %+v", predicted, shortfall)
+	} else if shortfall.Message() != "The vehicle is forecast to hold 70% at pickup, below the expected 80%" {
+		t.Errorf("message = %q", shortfall.Message())
+	}
+
+	// Returning at 20% leaves 1.5h to charge to 50% for reservation 7
+	predicted, shortfall = forecaster.Check(leaf, booked, models.Reservation{StartTime: at(1), EndTime: at(3)}, now)
+	if predicted != 50 || shortfall == nil || shortfall.ReservationID != 7 || shortfall.Predicted != 50 {
+		t.Errorf("leaving the next reservation short: got %g%%, %+v", predicted, shortfall)
+	}
+
+	if _, shortfall := forecaster.Check(leaf, booked, models.Reservation{StartTime: at(1), EndTime: at(2), ExpectedChargeLevel: 50}, now); shortfall != nil {
+		t.Errorf("enough time to recharge: got %+v", shortfall)
+	}
+
+	// A next reservation that is short anyway is not blamed on the new one
+	hopeless := []models.Reservation{{ReservationID: 8, StartTime: at(2), EndTime: at(3), ExpectedChargeLevel: 80}}
+	if _, shortfall := forecaster.Check(leaf, hopeless, models.Reservation{StartTime: at(0.5), EndTime: at(1.5)}, now); shortfall != nil {
+		t.Errorf("next reservation already short: got %+v", shortfall)
+	}
+}
+
+func TestStationPowerText(t *testing.T) {
+	var power StationPower
+	if err := power.UnmarshalText([]byte("Downtown Station=50; Airport Terminal = 22.5")); err != nil {
+		t.Fatal(err)
+	}
+	if power["Downtown Station"] != 50 || power["Airport Terminal"] != 22.5 {
+		t.Errorf("parsed %+v", power)
+	}
+	if text, _ := power.MarshalText(); string(text) != "Airport Terminal=22.5;Downtown Station=50" {
+		t.Errorf("MarshalText = %q", text)
+	}
+	for _, text := range []string{"Downtown Station", "=50", "Downtown Station=fast", "Downtown Station=-1"} {
+		if err := power.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func TestSettings(t *testing.T) {
+	var s Settings
+	if _, err := settings.Load("test", &s, []string{"-charger-power-by-station", "Downtown Station=50"}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := s.Forecaster()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.PowerAt("Downtown Station") != 50 || f.PowerAt("Suburban Hub") != 11 || f.ReturnLevel != 20 || f.Shortfall != ShortfallReject {
+		t.Errorf("forecaster = %+v", f)
+	}
+
+	for _, bad := range []Settings{
+		{PowerKW: -1},
+		{ReturnLevel: 120},
+		{Shortfall: "maybe"},
+	} {
+		if _, err := bad.Forecaster(); err == nil {
+			t.Errorf("%+v.Forecaster() succeeded, want an error", bad)
+		}
+	}
+}
@@ -0,0 +1,4759 @@
+// Package client provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+const (
+	AdminTokenScopes   = "adminToken.Scopes"
+	ServiceTokenScopes = "serviceToken.Scopes"
+	VehicleTokenScopes = "vehicleToken.Scopes"
+)
+
+// Defines values for CalendarEntryKind.
+const (
+	CalendarEntryKindDecommissioned CalendarEntryKind = "decommissioned"
+	CalendarEntryKindMaintenance    CalendarEntryKind = "maintenance"
+	CalendarEntryKindReservation    CalendarEntryKind = "reservation"
+	CalendarEntryKindTurnaround     CalendarEntryKind = "turnaround"
+)
+
+// Defines values for GeoJSONGeometryType.
+const (
+	MultiPolygon GeoJSONGeometryType = "MultiPolygon"
+	Polygon      GeoJSONGeometryType = "Polygon"
+)
+
+// Defines values for GeofenceKind.
+const (
+	GeofenceKindNoParking   GeofenceKind = "no_parking"
+	GeofenceKindServiceArea GeofenceKind = "service_area"
+	GeofenceKindStationLot  GeofenceKind = "station_lot"
+)
+
+// Defines values for GeofenceRequestKind.
+const (
+	GeofenceRequestKindNoParking   GeofenceRequestKind = "no_parking"
+	GeofenceRequestKindServiceArea GeofenceRequestKind = "service_area"
+	GeofenceRequestKindStationLot  GeofenceRequestKind = "station_lot"
+)
+
+// Defines values for WorkOrderStatus.
+const (
+	WorkOrderStatusCancelled  WorkOrderStatus = "Cancelled"
+	WorkOrderStatusCompleted  WorkOrderStatus = "Completed"
+	WorkOrderStatusInProgress WorkOrderStatus = "In Progress"
+	WorkOrderStatusScheduled  WorkOrderStatus = "Scheduled"
+)
+
+// Defines values for WorkOrderType.
+const (
+	WorkOrderTypeBattery    WorkOrderType = "battery"
+	WorkOrderTypeInspection WorkOrderType = "inspection"
+	WorkOrderTypeRepair     WorkOrderType = "repair"
+	WorkOrderTypeService    WorkOrderType = "service"
+	WorkOrderTypeTires      WorkOrderType = "tires"
+)
+
+// Defines values for WorkOrderRequestType.
+const (
+	WorkOrderRequestTypeBattery    WorkOrderRequestType = "battery"
+	WorkOrderRequestTypeInspection WorkOrderRequestType = "inspection"
+	WorkOrderRequestTypeRepair     WorkOrderRequestType = "repair"
+	WorkOrderRequestTypeService    WorkOrderRequestType = "service"
+	WorkOrderRequestTypeTires      WorkOrderRequestType = "tires"
+)
+
+// Defines values for ListWorkOrdersParamsStatus.
+const (
+	ListWorkOrdersParamsStatusCancelled  ListWorkOrdersParamsStatus = "Cancelled"
+	ListWorkOrdersParamsStatusCompleted  ListWorkOrdersParamsStatus = "Completed"
+	ListWorkOrdersParamsStatusInProgress ListWorkOrdersParamsStatus = "In Progress"
+	ListWorkOrdersParamsStatusScheduled  ListWorkOrdersParamsStatus = "Scheduled"
+)
+
+// CalendarEntry defines model for CalendarEntry.
+type CalendarEntry struct {
+	EndTime time.Time         `json:"end_time"`
+	Kind    CalendarEntryKind `json:"kind"`
+
+	// ReservationId The reservation, or the reservation a turnaround follows; unset for maintenance
+	ReservationId *int      `json:"reservation_id,omitempty"`
+	StartTime     time.Time `json:"start_time"`
+
+	// WorkOrderId The work order of a maintenance entry, or the work order a turnaround follows; unset while a vehicle is Under Maintenance without work in progress
+	WorkOrderId *int `json:"work_order_id,omitempty"`
+}
+
+// CalendarEntryKind defines model for CalendarEntry.Kind.
+type CalendarEntryKind string
+
+// CompleteReservationRequest defines model for CompleteReservationRequest.
+type CompleteReservationRequest struct {
+	// UserId The user holding the reservation. A reservation of another user is 404
+	UserId int `json:"user_id"`
+}
+
+// CompleteWorkOrderRequest defines model for CompleteWorkOrderRequest.
+type CompleteWorkOrderRequest struct {
+	// Notes Notes on the work done
+	Notes *string `json:"notes,omitempty"`
+}
+
+// CreateReservationRequest defines model for CreateReservationRequest.
+type CreateReservationRequest struct {
+	// BookingLimit Upcoming reservations the user may hold at once, set by user_service; 0 is not enforced. A reservation without membership_tier and booking_limit gets the default limit of vehicle_service. Refused with 401 without the serviceToken.
+	BookingLimit *int      `json:"booking_limit,omitempty"`
+	EndTime      time.Time `json:"end_time"`
+
+	// ExpectedChargeLevel Charge level in percent the vehicle must hold at pickup
+	ExpectedChargeLevel *float64 `json:"expected_charge_level,omitempty"`
+
+	// MembershipTier Membership tier of the user, set by user_service. Selects the tier overrides of the booking rules. Refused with 401 without the serviceToken.
+	MembershipTier *string `json:"membership_tier,omitempty"`
+
+	// PlannedDistanceKm Distance in km the renter plans to drive, checked against the estimated range at pickup
+	PlannedDistanceKm *float64 `json:"planned_distance_km,omitempty"`
+
+	// ReturnStationId Station the vehicle will be returned to, for a one-way trip; the pickup station by default
+	ReturnStationId *int      `json:"return_station_id,omitempty"`
+	StartTime       time.Time `json:"start_time"`
+	UserId          int       `json:"user_id"`
+	VehicleId       int       `json:"vehicle_id"`
+}
+
+// Error defines model for Error.
+type Error struct {
+	Code      string        `json:"code"`
+	Fields    *[]FieldError `json:"fields,omitempty"`
+	Message   string        `json:"message"`
+	RequestId *string       `json:"request_id,omitempty"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// GeoJSONGeometry GeoJSON (RFC 7946) Polygon or MultiPolygon geometry. Positions are [longitude, latitude] and rings are closed.
+type GeoJSONGeometry struct {
+	Coordinates []interface{}       `json:"coordinates"`
+	Type        GeoJSONGeometryType `json:"type"`
+}
+
+// GeoJSONGeometryType defines model for GeoJSONGeometry.Type.
+type GeoJSONGeometryType string
+
+// Geofence defines model for Geofence.
+type Geofence struct {
+	GeofenceId int `json:"geofence_id"`
+
+	// Geometry GeoJSON (RFC 7946) Polygon or MultiPolygon geometry. Positions are [longitude, latitude] and rings are closed.
+	Geometry GeoJSONGeometry `json:"geometry"`
+
+	// Kind service_area: where vehicles may be driven and returned; no_parking: where they must not be returned; station_lot: the lot vehicles returned to station_id must be inside
+	Kind GeofenceKind `json:"kind"`
+	Name string       `json:"name"`
+
+	// StationId Station of a station_lot
+	StationId *int      `json:"station_id,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GeofenceKind service_area: where vehicles may be driven and returned; no_parking: where they must not be returned; station_lot: the lot vehicles returned to station_id must be inside
+type GeofenceKind string
+
+// GeofenceList defines model for GeofenceList.
+type GeofenceList struct {
+	Data    []Geofence `json:"data"`
+	Message string     `json:"message"`
+}
+
+// GeofenceRequest defines model for GeofenceRequest.
+type GeofenceRequest struct {
+	// Geometry GeoJSON (RFC 7946) Polygon or MultiPolygon geometry. Positions are [longitude, latitude] and rings are closed.
+	Geometry GeoJSONGeometry `json:"geometry"`
+
+	// Kind service_area: where vehicles may be driven and returned; no_parking: where they must not be returned; station_lot: the lot vehicles returned to station_id must be inside
+	Kind GeofenceRequestKind `json:"kind"`
+	Name string              `json:"name"`
+
+	// StationId Station of a station_lot; not allowed for the other kinds
+	StationId *int `json:"station_id,omitempty"`
+}
+
+// GeofenceRequestKind service_area: where vehicles may be driven and returned; no_parking: where they must not be returned; station_lot: the lot vehicles returned to station_id must be inside
+type GeofenceRequestKind string
+
+// GeofenceResponse defines model for GeofenceResponse.
+type GeofenceResponse struct {
+	Data    Geofence `json:"data"`
+	Message string   `json:"message"`
+}
+
+// NearbyStation defines model for NearbyStation.
+type NearbyStation struct {
+	Address string `json:"address"`
+
+	// AvailableVehicles Number of the vehicles of the search parked at the station
+	AvailableVehicles int `json:"available_vehicles"`
+
+	// Capacity Number of parking spaces; 0 is not limited
+	Capacity int `json:"capacity"`
+
+	// DistanceKm Great-circle distance from the searched position
+	DistanceKm float64 `json:"distance_km"`
+
+	// Latitude Unset for a station migrated from a location name whose position is not known yet
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	Name      string   `json:"name"`
+
+	// Open Whether the station is open at start_time, or now without a time window
+	Open bool `json:"open"`
+
+	// OpeningHours Daily opening hours as HH:MM-HH:MM in BOOKING_TIME_ZONE, past midnight when they close before they open; empty is open around the clock
+	OpeningHours string `json:"opening_hours"`
+	StationId    int    `json:"station_id"`
+}
+
+// NearbyStations defines model for NearbyStations.
+type NearbyStations struct {
+	Latitude  float64         `json:"latitude"`
+	Longitude float64         `json:"longitude"`
+	RadiusKm  float64         `json:"radius_km"`
+	Stations  []NearbyStation `json:"stations"`
+	Vehicles  []Vehicle       `json:"vehicles"`
+}
+
+// NearbyStationsResponse defines model for NearbyStationsResponse.
+type NearbyStationsResponse struct {
+	Data    NearbyStations `json:"data"`
+	Message string         `json:"message"`
+}
+
+// Reservation defines model for Reservation.
+type Reservation struct {
+	// CompletedAt When the trip was completed
+	CompletedAt         *time.Time `json:"completed_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	EndTime             time.Time  `json:"end_time"`
+	ExpectedChargeLevel float64    `json:"expected_charge_level"`
+
+	// OneWayFee Fee of a one-way trip, 0 for a round trip
+	OneWayFee float64 `json:"one_way_fee"`
+
+	// OutOfZoneFee Fee charged for returning the vehicle outside its return zone, 0 otherwise
+	OutOfZoneFee float64 `json:"out_of_zone_fee"`
+
+	// PickupStationId Station the vehicle is picked up at: its station at start_time
+	PickupStationId int `json:"pickup_station_id"`
+	ReservationId   int `json:"reservation_id"`
+
+	// ReturnStationId Station the vehicle is returned to; it differs from pickup_station_id on a one-way trip
+	ReturnStationId   int       `json:"return_station_id"`
+	StartTime         time.Time `json:"start_time"`
+	Status            string    `json:"status"`
+	UserId            int       `json:"user_id"`
+	VehicleId         int       `json:"vehicle_id"`
+	VehicleRentalRate float64   `json:"vehicle_rental_rate"`
+}
+
+// ReservationResponse defines model for ReservationResponse.
+type ReservationResponse struct {
+	Data Reservation `json:"data"`
+
+	// EstimatedRangeKm Estimated range in km at pickup
+	EstimatedRangeKm *float64 `json:"estimated_range_km,omitempty"`
+	Message          string   `json:"message"`
+
+	// PredictedChargeLevel Forecast charge level in percent at pickup
+	PredictedChargeLevel *float64 `json:"predicted_charge_level,omitempty"`
+
+	// Warnings Problems that did not prevent the reservation, e.g. an expected_charge_level that is not forecast to be reached
+	Warnings *[]string `json:"warnings,omitempty"`
+}
+
+// ServiceDue defines model for ServiceDue.
+type ServiceDue struct {
+	// DueAtKm Mileage the first maintenance falls due at, earlier than next_service_km when an open work order has a lower mileage_trigger
+	DueAtKm      int    `json:"due_at_km"`
+	LicensePlate string `json:"license_plate"`
+	Mileage      int    `json:"mileage"`
+
+	// NextServiceKm Mileage the periodic service falls due at
+	NextServiceKm int `json:"next_service_km"`
+
+	// RemainingKm Distance left until due_at_km; negative when overdue
+	RemainingKm int `json:"remaining_km"`
+	VehicleId   int `json:"vehicle_id"`
+
+	// WorkOrderId Open work order planned for the maintenance, if any
+	WorkOrderId *int `json:"work_order_id,omitempty"`
+}
+
+// ServiceDueList defines model for ServiceDueList.
+type ServiceDueList struct {
+	Data    []ServiceDue `json:"data"`
+	Message string       `json:"message"`
+}
+
+// Station defines model for Station.
+type Station struct {
+	Address string `json:"address"`
+
+	// Capacity Number of parking spaces; 0 is not limited
+	Capacity int `json:"capacity"`
+
+	// Latitude Unset for a station migrated from a location name whose position is not known yet
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	Name      string   `json:"name"`
+
+	// OpeningHours Daily opening hours as HH:MM-HH:MM in BOOKING_TIME_ZONE, past midnight when they close before they open; empty is open around the clock
+	OpeningHours string `json:"opening_hours"`
+	StationId    int    `json:"station_id"`
+}
+
+// StationList defines model for StationList.
+type StationList struct {
+	Data    []Station `json:"data"`
+	Message string    `json:"message"`
+}
+
+// TelemetryAccepted defines model for TelemetryAccepted.
+type TelemetryAccepted struct {
+	// Accepted Number of readings stored
+	Accepted int    `json:"accepted"`
+	Message  string `json:"message"`
+}
+
+// TelemetryReading defines model for TelemetryReading.
+type TelemetryReading struct {
+	ChargeLevel float64 `json:"charge_level"`
+	Latitude    float64 `json:"latitude"`
+	Locked      bool    `json:"locked"`
+	Longitude   float64 `json:"longitude"`
+	OdometerKm  float64 `json:"odometer_km"`
+
+	// RecordedAt When the reading was taken; at most five minutes in the future and not older than TELEMETRY_RAW_RETENTION
+	RecordedAt time.Time `json:"recorded_at"`
+	SpeedKmh   float64   `json:"speed_kmh"`
+
+	// VehicleId Set from the path; ignored in requests
+	VehicleId *int `json:"vehicle_id,omitempty"`
+}
+
+// TelemetryReport defines model for TelemetryReport.
+type TelemetryReport struct {
+	Readings []TelemetryReading `json:"readings"`
+}
+
+// TelemetryRollup Readings of one TELEMETRY_DOWNSAMPLE_INTERVAL after the raw readings expired: the last reported state, and the average and top speed
+type TelemetryRollup struct {
+	AvgSpeedKmh  float64   `json:"avg_speed_kmh"`
+	BucketEnd    time.Time `json:"bucket_end"`
+	BucketStart  time.Time `json:"bucket_start"`
+	ChargeLevel  float64   `json:"charge_level"`
+	Latitude     float64   `json:"latitude"`
+	Locked       bool      `json:"locked"`
+	Longitude    float64   `json:"longitude"`
+	MaxSpeedKmh  float64   `json:"max_speed_kmh"`
+	OdometerKm   float64   `json:"odometer_km"`
+	ReadingCount int       `json:"reading_count"`
+	VehicleId    int       `json:"vehicle_id"`
+}
+
+// Vehicle defines model for Vehicle.
+type Vehicle struct {
+	BatteryCapacityKwh *float64 `json:"battery_capacity_kwh,omitempty"`
+	ChargeLevel        float64  `json:"charge_level"`
+	Cleanliness        string   `json:"cleanliness"`
+
+	// DistanceKm Distance in km from the position of a nearby search, from the last reported position of the vehicle or else from its station
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+
+	// EstimatedRangeKm Estimated range in km on the current charge, or on the predicted charge of a search with a time window
+	EstimatedRangeKm *float64 `json:"estimated_range_km,omitempty"`
+
+	// Latitude Latitude of the last position reported by the vehicle, unset before its first report
+	Latitude     *float64 `json:"latitude,omitempty"`
+	LicensePlate string   `json:"license_plate"`
+
+	// Location Name of the station the vehicle is parked at
+	Location string `json:"location"`
+
+	// Locked Whether the vehicle was locked at its last report
+	Locked *bool `json:"locked,omitempty"`
+
+	// Longitude Longitude of the last position reported by the vehicle
+	Longitude *float64 `json:"longitude,omitempty"`
+	Mileage   int      `json:"mileage"`
+	Model     string   `json:"model"`
+
+	// PredictedChargeLevel Forecast charge level in percent at the start_time of a search. Only set when the search gives a time window.
+	PredictedChargeLevel *float64 `json:"predicted_charge_level,omitempty"`
+	RentalRate           float64  `json:"rental_rate"`
+	ReservationStatus    string   `json:"reservation_status"`
+
+	// StationId Station the vehicle is parked at
+	StationId int    `json:"station_id"`
+	Status    string `json:"status"`
+
+	// TelemetryAt Time of the last telemetry reading applied to the vehicle
+	TelemetryAt *time.Time `json:"telemetry_at,omitempty"`
+	VehicleId   int        `json:"vehicle_id"`
+}
+
+// VehicleCalendar defines model for VehicleCalendar.
+type VehicleCalendar struct {
+	Entries []CalendarEntry `json:"entries"`
+	From    time.Time       `json:"from"`
+	To      time.Time       `json:"to"`
+
+	// TurnaroundMinutes Time kept free after every reservation of the vehicle
+	TurnaroundMinutes int `json:"turnaround_minutes"`
+	VehicleId         int `json:"vehicle_id"`
+}
+
+// VehicleCalendarResponse defines model for VehicleCalendarResponse.
+type VehicleCalendarResponse struct {
+	Data    VehicleCalendar `json:"data"`
+	Message string          `json:"message"`
+}
+
+// VehicleList defines model for VehicleList.
+type VehicleList struct {
+	Message  string     `json:"message"`
+	Vehicles *[]Vehicle `json:"vehicles"`
+}
+
+// VehicleResponse defines model for VehicleResponse.
+type VehicleResponse struct {
+	Data    Vehicle `json:"data"`
+	Message string  `json:"message"`
+}
+
+// VehicleTelemetry defines model for VehicleTelemetry.
+type VehicleTelemetry struct {
+	From      time.Time          `json:"from"`
+	Readings  []TelemetryReading `json:"readings"`
+	Rollups   []TelemetryRollup  `json:"rollups"`
+	To        time.Time          `json:"to"`
+	VehicleId int                `json:"vehicle_id"`
+}
+
+// VehicleTelemetryResponse defines model for VehicleTelemetryResponse.
+type VehicleTelemetryResponse struct {
+	Data    VehicleTelemetry `json:"data"`
+	Message string           `json:"message"`
+}
+
+// WorkOrder defines model for WorkOrder.
+type WorkOrder struct {
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// CompletedMileage Mileage of the vehicle at completion, from which the next service is counted
+	CompletedMileage *int      `json:"completed_mileage,omitempty"`
+	CompletionNotes  *string   `json:"completion_notes,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+
+	// MileageTrigger Mileage the work falls due at, if it depends on the mileage
+	MileageTrigger *int       `json:"mileage_trigger,omitempty"`
+	Notes          string     `json:"notes"`
+	PlannedEnd     time.Time  `json:"planned_end"`
+	PlannedStart   time.Time  `json:"planned_start"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+
+	// Status Scheduled and In Progress work orders block reservations of the vehicle during their planned window; the vehicle is Under Maintenance while one is In Progress
+	Status      WorkOrderStatus `json:"status"`
+	Type        WorkOrderType   `json:"type"`
+	VehicleId   int             `json:"vehicle_id"`
+	WorkOrderId int             `json:"work_order_id"`
+}
+
+// WorkOrderStatus Scheduled and In Progress work orders block reservations of the vehicle during their planned window; the vehicle is Under Maintenance while one is In Progress
+type WorkOrderStatus string
+
+// WorkOrderType defines model for WorkOrder.Type.
+type WorkOrderType string
+
+// WorkOrderList defines model for WorkOrderList.
+type WorkOrderList struct {
+	Data    []WorkOrder `json:"data"`
+	Message string      `json:"message"`
+}
+
+// WorkOrderRequest defines model for WorkOrderRequest.
+type WorkOrderRequest struct {
+	// MileageTrigger Mileage the work falls due at. A service created without one falls due at the next service of the vehicle.
+	MileageTrigger *int                 `json:"mileage_trigger,omitempty"`
+	Notes          *string              `json:"notes,omitempty"`
+	PlannedEnd     time.Time            `json:"planned_end"`
+	PlannedStart   time.Time            `json:"planned_start"`
+	Type           WorkOrderRequestType `json:"type"`
+
+	// VehicleId Required on creation; it cannot be changed
+	VehicleId *int `json:"vehicle_id,omitempty"`
+}
+
+// WorkOrderRequestType defines model for WorkOrderRequest.Type.
+type WorkOrderRequestType string
+
+// WorkOrderResponse defines model for WorkOrderResponse.
+type WorkOrderResponse struct {
+	Data    WorkOrder `json:"data"`
+	Message string    `json:"message"`
+}
+
+// ZoneExit defines model for ZoneExit.
+type ZoneExit struct {
+	// GeofenceId Service area the vehicle left; it may have been deleted since
+	GeofenceId   int     `json:"geofence_id"`
+	GeofenceName string  `json:"geofence_name"`
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+
+	// RecordedAt Time of the first reading outside the service area
+	RecordedAt    time.Time `json:"recorded_at"`
+	ReservationId int       `json:"reservation_id"`
+	VehicleId     int       `json:"vehicle_id"`
+}
+
+// ZoneExitList defines model for ZoneExitList.
+type ZoneExitList struct {
+	Data    []ZoneExit `json:"data"`
+	Message string     `json:"message"`
+}
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// CreateGeofenceParams defines parameters for CreateGeofence.
+type CreateGeofenceParams struct {
+	// IdempotencyKey Unique key of the request. Retries with the same key and body replay the first response with an Idempotent-Replayed: true header; reusing the key for a different request returns 409.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// CreateReservationParams defines parameters for CreateReservation.
+type CreateReservationParams struct {
+	// IdempotencyKey Unique key of the request. Retries with the same key and body replay the first response with an Idempotent-Replayed: true header; reusing the key for a different request returns 409.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// GetLatestReservationParams defines parameters for GetLatestReservation.
+type GetLatestReservationParams struct {
+	UserId int `form:"user_id" json:"user_id"`
+}
+
+// FindNearbyStationsParams defines parameters for FindNearbyStations.
+type FindNearbyStationsParams struct {
+	Latitude  float64 `form:"latitude" json:"latitude"`
+	Longitude float64 `form:"longitude" json:"longitude"`
+
+	// RadiusKm Search radius in km, 5 by default
+	RadiusKm *float64 `form:"radius_km,omitempty" json:"radius_km,omitempty"`
+
+	// StartTime Start of the window to reserve; requires end_time
+	StartTime *time.Time `form:"start_time,omitempty" json:"start_time,omitempty"`
+
+	// EndTime End of the window to reserve; requires start_time
+	EndTime *time.Time `form:"end_time,omitempty" json:"end_time,omitempty"`
+
+	// DistanceKm Planned trip distance in km; only vehicles whose estimated range covers it with the safety margin are listed
+	DistanceKm *float64 `form:"distance_km,omitempty" json:"distance_km,omitempty"`
+}
+
+// ListVehiclesParams defines parameters for ListVehicles.
+type ListVehiclesParams struct {
+	// StartTime Start of the window to reserve; requires end_time
+	StartTime *time.Time `form:"start_time,omitempty" json:"start_time,omitempty"`
+
+	// EndTime End of the window to reserve; requires start_time
+	EndTime *time.Time `form:"end_time,omitempty" json:"end_time,omitempty"`
+
+	// DistanceKm Planned trip distance in km; only vehicles whose estimated range covers it with the safety margin are listed
+	DistanceKm *float64 `form:"distance_km,omitempty" json:"distance_km,omitempty"`
+}
+
+// GetVehicleCalendarParams defines parameters for GetVehicleCalendar.
+type GetVehicleCalendarParams struct {
+	// From Start of the calendar, now by default
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To End of the calendar, seven days after from by default
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// GetVehicleTelemetryParams defines parameters for GetVehicleTelemetry.
+type GetVehicleTelemetryParams struct {
+	// From Start of the period, 24 hours before to by default
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To End of the period, now by default
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// ReportVehicleTelemetryParams defines parameters for ReportVehicleTelemetry.
+type ReportVehicleTelemetryParams struct {
+	// IdempotencyKey Unique key of the request. Retries with the same key and body replay the first response with an Idempotent-Replayed: true header; reusing the key for a different request returns 409.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// ListWorkOrdersParams defines parameters for ListWorkOrders.
+type ListWorkOrdersParams struct {
+	VehicleId *int                        `form:"vehicle_id,omitempty" json:"vehicle_id,omitempty"`
+	Status    *ListWorkOrdersParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+}
+
+// ListWorkOrdersParamsStatus defines parameters for ListWorkOrders.
+type ListWorkOrdersParamsStatus string
+
+// CreateWorkOrderParams defines parameters for CreateWorkOrder.
+type CreateWorkOrderParams struct {
+	// IdempotencyKey Unique key of the request. Retries with the same key and body replay the first response with an Idempotent-Replayed: true header; reusing the key for a different request returns 409.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// CreateGeofenceJSONRequestBody defines body for CreateGeofence for application/json ContentType.
+type CreateGeofenceJSONRequestBody = GeofenceRequest
+
+// UpdateGeofenceJSONRequestBody defines body for UpdateGeofence for application/json ContentType.
+type UpdateGeofenceJSONRequestBody = GeofenceRequest
+
+// CreateReservationJSONRequestBody defines body for CreateReservation for application/json ContentType.
+type CreateReservationJSONRequestBody = CreateReservationRequest
+
+// CompleteReservationJSONRequestBody defines body for CompleteReservation for application/json ContentType.
+type CompleteReservationJSONRequestBody = CompleteReservationRequest
+
+// ReportVehicleTelemetryJSONRequestBody defines body for ReportVehicleTelemetry for application/json ContentType.
+type ReportVehicleTelemetryJSONRequestBody = TelemetryReport
+
+// CreateWorkOrderJSONRequestBody defines body for CreateWorkOrder for application/json ContentType.
+type CreateWorkOrderJSONRequestBody = WorkOrderRequest
+
+// UpdateWorkOrderJSONRequestBody defines body for UpdateWorkOrder for application/json ContentType.
+type UpdateWorkOrderJSONRequestBody = WorkOrderRequest
+
+// CompleteWorkOrderJSONRequestBody defines body for CompleteWorkOrder for application/json ContentType.
+type CompleteWorkOrderJSONRequestBody = CompleteWorkOrderRequest
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// ListGeofences request
+	ListGeofences(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CreateGeofenceWithBody request with any body
+	CreateGeofenceWithBody(ctx context.Context, params *CreateGeofenceParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CreateGeofence(ctx context.Context, params *CreateGeofenceParams, body CreateGeofenceJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteGeofence request
+	DeleteGeofence(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetGeofence request
+	GetGeofence(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// UpdateGeofenceWithBody request with any body
+	UpdateGeofenceWithBody(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	UpdateGeofence(ctx context.Context, id int, body UpdateGeofenceJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetServiceDue request
+	GetServiceDue(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CreateReservationWithBody request with any body
+	CreateReservationWithBody(ctx context.Context, params *CreateReservationParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CreateReservation(ctx context.Context, params *CreateReservationParams, body CreateReservationJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetLatestReservation request
+	GetLatestReservation(ctx context.Context, params *GetLatestReservationParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetReservation request
+	GetReservation(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CompleteReservationWithBody request with any body
+	CompleteReservationWithBody(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CompleteReservation(ctx context.Context, id int, body CompleteReservationJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetZoneExits request
+	GetZoneExits(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListStations request
+	ListStations(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// FindNearbyStations request
+	FindNearbyStations(ctx context.Context, params *FindNearbyStationsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListVehicles request
+	ListVehicles(ctx context.Context, params *ListVehiclesParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetVehicle request
+	GetVehicle(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetVehicleCalendar request
+	GetVehicleCalendar(ctx context.Context, id int, params *GetVehicleCalendarParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetVehicleTelemetry request
+	GetVehicleTelemetry(ctx context.Context, id int, params *GetVehicleTelemetryParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ReportVehicleTelemetryWithBody request with any body
+	ReportVehicleTelemetryWithBody(ctx context.Context, id int, params *ReportVehicleTelemetryParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	ReportVehicleTelemetry(ctx context.Context, id int, params *ReportVehicleTelemetryParams, body ReportVehicleTelemetryJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListWorkOrders request
+	ListWorkOrders(ctx context.Context, params *ListWorkOrdersParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CreateWorkOrderWithBody request with any body
+	CreateWorkOrderWithBody(ctx context.Context, params *CreateWorkOrderParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CreateWorkOrder(ctx context.Context, params *CreateWorkOrderParams, body CreateWorkOrderJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetWorkOrder request
+	GetWorkOrder(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// UpdateWorkOrderWithBody request with any body
+	UpdateWorkOrderWithBody(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	UpdateWorkOrder(ctx context.Context, id int, body UpdateWorkOrderJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CancelWorkOrder request
+	CancelWorkOrder(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CompleteWorkOrderWithBody request with any body
+	CompleteWorkOrderWithBody(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CompleteWorkOrder(ctx context.Context, id int, body CompleteWorkOrderJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// StartWorkOrder request
+	StartWorkOrder(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) ListGeofences(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListGeofencesRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateGeofenceWithBody(ctx context.Context, params *CreateGeofenceParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateGeofenceRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateGeofence(ctx context.Context, params *CreateGeofenceParams, body CreateGeofenceJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateGeofenceRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteGeofence(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteGeofenceRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetGeofence(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetGeofenceRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateGeofenceWithBody(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateGeofenceRequestWithBody(c.Server, id, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateGeofence(ctx context.Context, id int, body UpdateGeofenceJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateGeofenceRequest(c.Server, id, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetServiceDue(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetServiceDueRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateReservationWithBody(ctx context.Context, params *CreateReservationParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateReservationRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateReservation(ctx context.Context, params *CreateReservationParams, body CreateReservationJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateReservationRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetLatestReservation(ctx context.Context, params *GetLatestReservationParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetLatestReservationRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetReservation(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetReservationRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CompleteReservationWithBody(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCompleteReservationRequestWithBody(c.Server, id, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CompleteReservation(ctx context.Context, id int, body CompleteReservationJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCompleteReservationRequest(c.Server, id, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetZoneExits(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetZoneExitsRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListStations(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListStationsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) FindNearbyStations(ctx context.Context, params *FindNearbyStationsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewFindNearbyStationsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListVehicles(ctx context.Context, params *ListVehiclesParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListVehiclesRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetVehicle(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetVehicleRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetVehicleCalendar(ctx context.Context, id int, params *GetVehicleCalendarParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetVehicleCalendarRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetVehicleTelemetry(ctx context.Context, id int, params *GetVehicleTelemetryParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetVehicleTelemetryRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ReportVehicleTelemetryWithBody(ctx context.Context, id int, params *ReportVehicleTelemetryParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewReportVehicleTelemetryRequestWithBody(c.Server, id, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ReportVehicleTelemetry(ctx context.Context, id int, params *ReportVehicleTelemetryParams, body ReportVehicleTelemetryJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewReportVehicleTelemetryRequest(c.Server, id, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListWorkOrders(ctx context.Context, params *ListWorkOrdersParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListWorkOrdersRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateWorkOrderWithBody(ctx context.Context, params *CreateWorkOrderParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateWorkOrderRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateWorkOrder(ctx context.Context, params *CreateWorkOrderParams, body CreateWorkOrderJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateWorkOrderRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetWorkOrder(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetWorkOrderRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateWorkOrderWithBody(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateWorkOrderRequestWithBody(c.Server, id, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateWorkOrder(ctx context.Context, id int, body UpdateWorkOrderJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateWorkOrderRequest(c.Server, id, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CancelWorkOrder(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCancelWorkOrderRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CompleteWorkOrderWithBody(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCompleteWorkOrderRequestWithBody(c.Server, id, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CompleteWorkOrder(ctx context.Context, id int, body CompleteWorkOrderJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCompleteWorkOrderRequest(c.Server, id, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) StartWorkOrder(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewStartWorkOrderRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewListGeofencesRequest generates requests for ListGeofences
+func NewListGeofencesRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/geofences")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCreateGeofenceRequest calls the generic CreateGeofence builder with application/json body
+func NewCreateGeofenceRequest(server string, params *CreateGeofenceParams, body CreateGeofenceJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCreateGeofenceRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewCreateGeofenceRequestWithBody generates requests for CreateGeofence with any type of body
+func NewCreateGeofenceRequestWithBody(server string, params *CreateGeofenceParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/geofences")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.IdempotencyKey != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, *params.IdempotencyKey)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Idempotency-Key", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewDeleteGeofenceRequest generates requests for DeleteGeofence
+func NewDeleteGeofenceRequest(server string, id int) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/geofences/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetGeofenceRequest generates requests for GetGeofence
+func NewGetGeofenceRequest(server string, id int) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/geofences/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewUpdateGeofenceRequest calls the generic UpdateGeofence builder with application/json body
+func NewUpdateGeofenceRequest(server string, id int, body UpdateGeofenceJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewUpdateGeofenceRequestWithBody(server, id, "application/json", bodyReader)
+}
+
+// NewUpdateGeofenceRequestWithBody generates requests for UpdateGeofence with any type of body
+func NewUpdateGeofenceRequestWithBody(server string, id int, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/geofences/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetServiceDueRequest generates requests for GetServiceDue
+func NewGetServiceDueRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/maintenance/due")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCreateReservationRequest calls the generic CreateReservation builder with application/json body
+func NewCreateReservationRequest(server string, params *CreateReservationParams, body CreateReservationJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCreateReservationRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewCreateReservationRequestWithBody generates requests for CreateReservation with any type of body
+func NewCreateReservationRequestWithBody(server string, params *CreateReservationParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/reservations")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.IdempotencyKey != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, *params.IdempotencyKey)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Idempotency-Key", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetLatestReservationRequest generates requests for GetLatestReservation
+func NewGetLatestReservationRequest(server string, params *GetLatestReservationParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/reservations/latest")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "user_id", runtime.ParamLocationQuery, params.UserId); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetReservationRequest generates requests for GetReservation
+func NewGetReservationRequest(server string, id int) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/reservations/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCompleteReservationRequest calls the generic CompleteReservation builder with application/json body
+func NewCompleteReservationRequest(server string, id int, body CompleteReservationJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCompleteReservationRequestWithBody(server, id, "application/json", bodyReader)
+}
+
+// NewCompleteReservationRequestWithBody generates requests for CompleteReservation with any type of body
+func NewCompleteReservationRequestWithBody(server string, id int, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/reservations/%s/complete", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetZoneExitsRequest generates requests for GetZoneExits
+func NewGetZoneExitsRequest(server string, id int) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/reservations/%s/zone-exits", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListStationsRequest generates requests for ListStations
+func NewListStationsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/stations")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewFindNearbyStationsRequest generates requests for FindNearbyStations
+func NewFindNearbyStationsRequest(server string, params *FindNearbyStationsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/stations/nearby")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "latitude", runtime.ParamLocationQuery, params.Latitude); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "longitude", runtime.ParamLocationQuery, params.Longitude); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if params.RadiusKm != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "radius_km", runtime.ParamLocationQuery, *params.RadiusKm); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.StartTime != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "start_time", runtime.ParamLocationQuery, *params.StartTime); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.EndTime != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "end_time", runtime.ParamLocationQuery, *params.EndTime); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.DistanceKm != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "distance_km", runtime.ParamLocationQuery, *params.DistanceKm); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListVehiclesRequest generates requests for ListVehicles
+func NewListVehiclesRequest(server string, params *ListVehiclesParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/vehicles")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.StartTime != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "start_time", runtime.ParamLocationQuery, *params.StartTime); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.EndTime != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "end_time", runtime.ParamLocationQuery, *params.EndTime); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.DistanceKm != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "distance_km", runtime.ParamLocationQuery, *params.DistanceKm); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetVehicleRequest generates requests for GetVehicle
+func NewGetVehicleRequest(server string, id int) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/vehicles/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetVehicleCalendarRequest generates requests for GetVehicleCalendar
+func NewGetVehicleCalendarRequest(server string, id int, params *GetVehicleCalendarParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/vehicles/%s/calendar", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.From != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "from", runtime.ParamLocationQuery, *params.From); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.To != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "to", runtime.ParamLocationQuery, *params.To); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetVehicleTelemetryRequest generates requests for GetVehicleTelemetry
+func NewGetVehicleTelemetryRequest(server string, id int, params *GetVehicleTelemetryParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/vehicles/%s/telemetry", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.From != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "from", runtime.ParamLocationQuery, *params.From); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.To != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "to", runtime.ParamLocationQuery, *params.To); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewReportVehicleTelemetryRequest calls the generic ReportVehicleTelemetry builder with application/json body
+func NewReportVehicleTelemetryRequest(server string, id int, params *ReportVehicleTelemetryParams, body ReportVehicleTelemetryJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewReportVehicleTelemetryRequestWithBody(server, id, params, "application/json", bodyReader)
+}
+
+// NewReportVehicleTelemetryRequestWithBody generates requests for ReportVehicleTelemetry with any type of body
+func NewReportVehicleTelemetryRequestWithBody(server string, id int, params *ReportVehicleTelemetryParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/vehicles/%s/telemetry", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.IdempotencyKey != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, *params.IdempotencyKey)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Idempotency-Key", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewListWorkOrdersRequest generates requests for ListWorkOrders
+func NewListWorkOrdersRequest(server string, params *ListWorkOrdersParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/work-orders")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.VehicleId != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "vehicle_id", runtime.ParamLocationQuery, *params.VehicleId); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Status != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "status", runtime.ParamLocationQuery, *params.Status); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCreateWorkOrderRequest calls the generic CreateWorkOrder builder with application/json body
+func NewCreateWorkOrderRequest(server string, params *CreateWorkOrderParams, body CreateWorkOrderJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCreateWorkOrderRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewCreateWorkOrderRequestWithBody generates requests for CreateWorkOrder with any type of body
+func NewCreateWorkOrderRequestWithBody(server string, params *CreateWorkOrderParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/work-orders")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.IdempotencyKey != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, *params.IdempotencyKey)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Idempotency-Key", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetWorkOrderRequest generates requests for GetWorkOrder
+func NewGetWorkOrderRequest(server string, id int) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/work-orders/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewUpdateWorkOrderRequest calls the generic UpdateWorkOrder builder with application/json body
+func NewUpdateWorkOrderRequest(server string, id int, body UpdateWorkOrderJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewUpdateWorkOrderRequestWithBody(server, id, "application/json", bodyReader)
+}
+
+// NewUpdateWorkOrderRequestWithBody generates requests for UpdateWorkOrder with any type of body
+func NewUpdateWorkOrderRequestWithBody(server string, id int, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/work-orders/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewCancelWorkOrderRequest generates requests for CancelWorkOrder
+func NewCancelWorkOrderRequest(server string, id int) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/work-orders/%s/cancel", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCompleteWorkOrderRequest calls the generic CompleteWorkOrder builder with application/json body
+func NewCompleteWorkOrderRequest(server string, id int, body CompleteWorkOrderJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCompleteWorkOrderRequestWithBody(server, id, "application/json", bodyReader)
+}
+
+// NewCompleteWorkOrderRequestWithBody generates requests for CompleteWorkOrder with any type of body
+func NewCompleteWorkOrderRequestWithBody(server string, id int, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/work-orders/%s/complete", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewStartWorkOrderRequest generates requests for StartWorkOrder
+func NewStartWorkOrderRequest(server string, id int) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/work-orders/%s/start", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// ListGeofencesWithResponse request
+	ListGeofencesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListGeofencesResponse, error)
+
+	// CreateGeofenceWithBodyWithResponse request with any body
+	CreateGeofenceWithBodyWithResponse(ctx context.Context, params *CreateGeofenceParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateGeofenceResponse, error)
+
+	CreateGeofenceWithResponse(ctx context.Context, params *CreateGeofenceParams, body CreateGeofenceJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateGeofenceResponse, error)
+
+	// DeleteGeofenceWithResponse request
+	DeleteGeofenceWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*DeleteGeofenceResponse, error)
+
+	// GetGeofenceWithResponse request
+	GetGeofenceWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*GetGeofenceResponse, error)
+
+	// UpdateGeofenceWithBodyWithResponse request with any body
+	UpdateGeofenceWithBodyWithResponse(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateGeofenceResponse, error)
+
+	UpdateGeofenceWithResponse(ctx context.Context, id int, body UpdateGeofenceJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateGeofenceResponse, error)
+
+	// GetServiceDueWithResponse request
+	GetServiceDueWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetServiceDueResponse, error)
+
+	// CreateReservationWithBodyWithResponse request with any body
+	CreateReservationWithBodyWithResponse(ctx context.Context, params *CreateReservationParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateReservationResponse, error)
+
+	CreateReservationWithResponse(ctx context.Context, params *CreateReservationParams, body CreateReservationJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateReservationResponse, error)
+
+	// GetLatestReservationWithResponse request
+	GetLatestReservationWithResponse(ctx context.Context, params *GetLatestReservationParams, reqEditors ...RequestEditorFn) (*GetLatestReservationResponse, error)
+
+	// GetReservationWithResponse request
+	GetReservationWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*GetReservationResponse, error)
+
+	// CompleteReservationWithBodyWithResponse request with any body
+	CompleteReservationWithBodyWithResponse(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CompleteReservationResponse, error)
+
+	CompleteReservationWithResponse(ctx context.Context, id int, body CompleteReservationJSONRequestBody, reqEditors ...RequestEditorFn) (*CompleteReservationResponse, error)
+
+	// GetZoneExitsWithResponse request
+	GetZoneExitsWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*GetZoneExitsResponse, error)
+
+	// ListStationsWithResponse request
+	ListStationsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListStationsResponse, error)
+
+	// FindNearbyStationsWithResponse request
+	FindNearbyStationsWithResponse(ctx context.Context, params *FindNearbyStationsParams, reqEditors ...RequestEditorFn) (*FindNearbyStationsResponse, error)
+
+	// ListVehiclesWithResponse request
+	ListVehiclesWithResponse(ctx context.Context, params *ListVehiclesParams, reqEditors ...RequestEditorFn) (*ListVehiclesResponse, error)
+
+	// GetVehicleWithResponse request
+	GetVehicleWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*GetVehicleResponse, error)
+
+	// GetVehicleCalendarWithResponse request
+	GetVehicleCalendarWithResponse(ctx context.Context, id int, params *GetVehicleCalendarParams, reqEditors ...RequestEditorFn) (*GetVehicleCalendarResponse, error)
+
+	// GetVehicleTelemetryWithResponse request
+	GetVehicleTelemetryWithResponse(ctx context.Context, id int, params *GetVehicleTelemetryParams, reqEditors ...RequestEditorFn) (*GetVehicleTelemetryResponse, error)
+
+	// ReportVehicleTelemetryWithBodyWithResponse request with any body
+	ReportVehicleTelemetryWithBodyWithResponse(ctx context.Context, id int, params *ReportVehicleTelemetryParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ReportVehicleTelemetryResponse, error)
+
+	ReportVehicleTelemetryWithResponse(ctx context.Context, id int, params *ReportVehicleTelemetryParams, body ReportVehicleTelemetryJSONRequestBody, reqEditors ...RequestEditorFn) (*ReportVehicleTelemetryResponse, error)
+
+	// ListWorkOrdersWithResponse request
+	ListWorkOrdersWithResponse(ctx context.Context, params *ListWorkOrdersParams, reqEditors ...RequestEditorFn) (*ListWorkOrdersResponse, error)
+
+	// CreateWorkOrderWithBodyWithResponse request with any body
+	CreateWorkOrderWithBodyWithResponse(ctx context.Context, params *CreateWorkOrderParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateWorkOrderResponse, error)
+
+	CreateWorkOrderWithResponse(ctx context.Context, params *CreateWorkOrderParams, body CreateWorkOrderJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateWorkOrderResponse, error)
+
+	// GetWorkOrderWithResponse request
+	GetWorkOrderWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*GetWorkOrderResponse, error)
+
+	// UpdateWorkOrderWithBodyWithResponse request with any body
+	UpdateWorkOrderWithBodyWithResponse(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateWorkOrderResponse, error)
+
+	UpdateWorkOrderWithResponse(ctx context.Context, id int, body UpdateWorkOrderJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateWorkOrderResponse, error)
+
+	// CancelWorkOrderWithResponse request
+	CancelWorkOrderWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*CancelWorkOrderResponse, error)
+
+	// CompleteWorkOrderWithBodyWithResponse request with any body
+	CompleteWorkOrderWithBodyWithResponse(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CompleteWorkOrderResponse, error)
+
+	CompleteWorkOrderWithResponse(ctx context.Context, id int, body CompleteWorkOrderJSONRequestBody, reqEditors ...RequestEditorFn) (*CompleteWorkOrderResponse, error)
+
+	// StartWorkOrderWithResponse request
+	StartWorkOrderWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*StartWorkOrderResponse, error)
+}
+
+type ListGeofencesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GeofenceList
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ListGeofencesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListGeofencesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CreateGeofenceResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *GeofenceResponse
+	JSON400      *Error
+	JSON401      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r CreateGeofenceResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CreateGeofenceResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteGeofenceResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
+	JSON401      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteGeofenceResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteGeofenceResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetGeofenceResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GeofenceResponse
+	JSON400      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetGeofenceResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetGeofenceResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UpdateGeofenceResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GeofenceResponse
+	JSON400      *Error
+	JSON401      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r UpdateGeofenceResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UpdateGeofenceResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetServiceDueResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ServiceDueList
+	JSON401      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetServiceDueResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetServiceDueResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CreateReservationResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ReservationResponse
+	JSON400      *Error
+	JSON401      *Error
+	JSON404      *Error
+	JSON409      *Error
+	JSON422      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r CreateReservationResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CreateReservationResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetLatestReservationResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ReservationResponse
+	JSON400      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetLatestReservationResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetLatestReservationResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetReservationResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ReservationResponse
+	JSON400      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetReservationResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetReservationResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CompleteReservationResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ReservationResponse
+	JSON400      *Error
+	JSON404      *Error
+	JSON409      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r CompleteReservationResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CompleteReservationResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetZoneExitsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ZoneExitList
+	JSON400      *Error
+	JSON401      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetZoneExitsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetZoneExitsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListStationsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *StationList
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ListStationsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListStationsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type FindNearbyStationsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *NearbyStationsResponse
+	JSON400      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r FindNearbyStationsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r FindNearbyStationsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListVehiclesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *VehicleList
+	JSON400      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ListVehiclesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListVehiclesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetVehicleResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *VehicleResponse
+	JSON400      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetVehicleResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetVehicleResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetVehicleCalendarResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *VehicleCalendarResponse
+	JSON400      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetVehicleCalendarResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetVehicleCalendarResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetVehicleTelemetryResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *VehicleTelemetryResponse
+	JSON400      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetVehicleTelemetryResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetVehicleTelemetryResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ReportVehicleTelemetryResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *TelemetryAccepted
+	JSON400      *Error
+	JSON401      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ReportVehicleTelemetryResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ReportVehicleTelemetryResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListWorkOrdersResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *WorkOrderList
+	JSON400      *Error
+	JSON401      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ListWorkOrdersResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListWorkOrdersResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CreateWorkOrderResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *WorkOrderResponse
+	JSON400      *Error
+	JSON401      *Error
+	JSON404      *Error
+	JSON409      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r CreateWorkOrderResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CreateWorkOrderResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetWorkOrderResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *WorkOrderResponse
+	JSON400      *Error
+	JSON401      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetWorkOrderResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetWorkOrderResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UpdateWorkOrderResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *WorkOrderResponse
+	JSON400      *Error
+	JSON401      *Error
+	JSON404      *Error
+	JSON409      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r UpdateWorkOrderResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UpdateWorkOrderResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CancelWorkOrderResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *WorkOrderResponse
+	JSON400      *Error
+	JSON401      *Error
+	JSON404      *Error
+	JSON409      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r CancelWorkOrderResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CancelWorkOrderResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CompleteWorkOrderResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *WorkOrderResponse
+	JSON400      *Error
+	JSON401      *Error
+	JSON404      *Error
+	JSON409      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r CompleteWorkOrderResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CompleteWorkOrderResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type StartWorkOrderResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *WorkOrderResponse
+	JSON400      *Error
+	JSON401      *Error
+	JSON404      *Error
+	JSON409      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r StartWorkOrderResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r StartWorkOrderResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// ListGeofencesWithResponse request returning *ListGeofencesResponse
+func (c *ClientWithResponses) ListGeofencesWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListGeofencesResponse, error) {
+	rsp, err := c.ListGeofences(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListGeofencesResponse(rsp)
+}
+
+// CreateGeofenceWithBodyWithResponse request with arbitrary body returning *CreateGeofenceResponse
+func (c *ClientWithResponses) CreateGeofenceWithBodyWithResponse(ctx context.Context, params *CreateGeofenceParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateGeofenceResponse, error) {
+	rsp, err := c.CreateGeofenceWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateGeofenceResponse(rsp)
+}
+
+func (c *ClientWithResponses) CreateGeofenceWithResponse(ctx context.Context, params *CreateGeofenceParams, body CreateGeofenceJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateGeofenceResponse, error) {
+	rsp, err := c.CreateGeofence(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateGeofenceResponse(rsp)
+}
+
+// DeleteGeofenceWithResponse request returning *DeleteGeofenceResponse
+func (c *ClientWithResponses) DeleteGeofenceWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*DeleteGeofenceResponse, error) {
+	rsp, err := c.DeleteGeofence(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteGeofenceResponse(rsp)
+}
+
+// GetGeofenceWithResponse request returning *GetGeofenceResponse
+func (c *ClientWithResponses) GetGeofenceWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*GetGeofenceResponse, error) {
+	rsp, err := c.GetGeofence(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetGeofenceResponse(rsp)
+}
+
+// UpdateGeofenceWithBodyWithResponse request with arbitrary body returning *UpdateGeofenceResponse
+func (c *ClientWithResponses) UpdateGeofenceWithBodyWithResponse(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateGeofenceResponse, error) {
+	rsp, err := c.UpdateGeofenceWithBody(ctx, id, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateGeofenceResponse(rsp)
+}
+
+func (c *ClientWithResponses) UpdateGeofenceWithResponse(ctx context.Context, id int, body UpdateGeofenceJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateGeofenceResponse, error) {
+	rsp, err := c.UpdateGeofence(ctx, id, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateGeofenceResponse(rsp)
+}
+
+// GetServiceDueWithResponse request returning *GetServiceDueResponse
+func (c *ClientWithResponses) GetServiceDueWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetServiceDueResponse, error) {
+	rsp, err := c.GetServiceDue(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetServiceDueResponse(rsp)
+}
+
+// CreateReservationWithBodyWithResponse request with arbitrary body returning *CreateReservationResponse
+func (c *ClientWithResponses) CreateReservationWithBodyWithResponse(ctx context.Context, params *CreateReservationParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateReservationResponse, error) {
+	rsp, err := c.CreateReservationWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateReservationResponse(rsp)
+}
+
+func (c *ClientWithResponses) CreateReservationWithResponse(ctx context.Context, params *CreateReservationParams, body CreateReservationJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateReservationResponse, error) {
+	rsp, err := c.CreateReservation(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateReservationResponse(rsp)
+}
+
+// GetLatestReservationWithResponse request returning *GetLatestReservationResponse
+func (c *ClientWithResponses) GetLatestReservationWithResponse(ctx context.Context, params *GetLatestReservationParams, reqEditors ...RequestEditorFn) (*GetLatestReservationResponse, error) {
+	rsp, err := c.GetLatestReservation(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetLatestReservationResponse(rsp)
+}
+
+// GetReservationWithResponse request returning *GetReservationResponse
+func (c *ClientWithResponses) GetReservationWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*GetReservationResponse, error) {
+	rsp, err := c.GetReservation(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetReservationResponse(rsp)
+}
+
+// CompleteReservationWithBodyWithResponse request with arbitrary body returning *CompleteReservationResponse
+func (c *ClientWithResponses) CompleteReservationWithBodyWithResponse(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CompleteReservationResponse, error) {
+	rsp, err := c.CompleteReservationWithBody(ctx, id, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCompleteReservationResponse(rsp)
+}
+
+func (c *ClientWithResponses) CompleteReservationWithResponse(ctx context.Context, id int, body CompleteReservationJSONRequestBody, reqEditors ...RequestEditorFn) (*CompleteReservationResponse, error) {
+	rsp, err := c.CompleteReservation(ctx, id, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCompleteReservationResponse(rsp)
+}
+
+// GetZoneExitsWithResponse request returning *GetZoneExitsResponse
+func (c *ClientWithResponses) GetZoneExitsWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*GetZoneExitsResponse, error) {
+	rsp, err := c.GetZoneExits(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetZoneExitsResponse(rsp)
+}
+
+// ListStationsWithResponse request returning *ListStationsResponse
+func (c *ClientWithResponses) ListStationsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListStationsResponse, error) {
+	rsp, err := c.ListStations(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListStationsResponse(rsp)
+}
+
+// FindNearbyStationsWithResponse request returning *FindNearbyStationsResponse
+func (c *ClientWithResponses) FindNearbyStationsWithResponse(ctx context.Context, params *FindNearbyStationsParams, reqEditors ...RequestEditorFn) (*FindNearbyStationsResponse, error) {
+	rsp, err := c.FindNearbyStations(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFindNearbyStationsResponse(rsp)
+}
+
+// ListVehiclesWithResponse request returning *ListVehiclesResponse
+func (c *ClientWithResponses) ListVehiclesWithResponse(ctx context.Context, params *ListVehiclesParams, reqEditors ...RequestEditorFn) (*ListVehiclesResponse, error) {
+	rsp, err := c.ListVehicles(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListVehiclesResponse(rsp)
+}
+
+// GetVehicleWithResponse request returning *GetVehicleResponse
+func (c *ClientWithResponses) GetVehicleWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*GetVehicleResponse, error) {
+	rsp, err := c.GetVehicle(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetVehicleResponse(rsp)
+}
+
+// GetVehicleCalendarWithResponse request returning *GetVehicleCalendarResponse
+func (c *ClientWithResponses) GetVehicleCalendarWithResponse(ctx context.Context, id int, params *GetVehicleCalendarParams, reqEditors ...RequestEditorFn) (*GetVehicleCalendarResponse, error) {
+	rsp, err := c.GetVehicleCalendar(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetVehicleCalendarResponse(rsp)
+}
+
+// GetVehicleTelemetryWithResponse request returning *GetVehicleTelemetryResponse
+func (c *ClientWithResponses) GetVehicleTelemetryWithResponse(ctx context.Context, id int, params *GetVehicleTelemetryParams, reqEditors ...RequestEditorFn) (*GetVehicleTelemetryResponse, error) {
+	rsp, err := c.GetVehicleTelemetry(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetVehicleTelemetryResponse(rsp)
+}
+
+// ReportVehicleTelemetryWithBodyWithResponse request with arbitrary body returning *ReportVehicleTelemetryResponse
+func (c *ClientWithResponses) ReportVehicleTelemetryWithBodyWithResponse(ctx context.Context, id int, params *ReportVehicleTelemetryParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ReportVehicleTelemetryResponse, error) {
+	rsp, err := c.ReportVehicleTelemetryWithBody(ctx, id, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseReportVehicleTelemetryResponse(rsp)
+}
+
+func (c *ClientWithResponses) ReportVehicleTelemetryWithResponse(ctx context.Context, id int, params *ReportVehicleTelemetryParams, body ReportVehicleTelemetryJSONRequestBody, reqEditors ...RequestEditorFn) (*ReportVehicleTelemetryResponse, error) {
+	rsp, err := c.ReportVehicleTelemetry(ctx, id, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseReportVehicleTelemetryResponse(rsp)
+}
+
+// ListWorkOrdersWithResponse request returning *ListWorkOrdersResponse
+func (c *ClientWithResponses) ListWorkOrdersWithResponse(ctx context.Context, params *ListWorkOrdersParams, reqEditors ...RequestEditorFn) (*ListWorkOrdersResponse, error) {
+	rsp, err := c.ListWorkOrders(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListWorkOrdersResponse(rsp)
+}
+
+// CreateWorkOrderWithBodyWithResponse request with arbitrary body returning *CreateWorkOrderResponse
+func (c *ClientWithResponses) CreateWorkOrderWithBodyWithResponse(ctx context.Context, params *CreateWorkOrderParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateWorkOrderResponse, error) {
+	rsp, err := c.CreateWorkOrderWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateWorkOrderResponse(rsp)
+}
+
+func (c *ClientWithResponses) CreateWorkOrderWithResponse(ctx context.Context, params *CreateWorkOrderParams, body CreateWorkOrderJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateWorkOrderResponse, error) {
+	rsp, err := c.CreateWorkOrder(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateWorkOrderResponse(rsp)
+}
+
+// GetWorkOrderWithResponse request returning *GetWorkOrderResponse
+func (c *ClientWithResponses) GetWorkOrderWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*GetWorkOrderResponse, error) {
+	rsp, err := c.GetWorkOrder(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetWorkOrderResponse(rsp)
+}
+
+// UpdateWorkOrderWithBodyWithResponse request with arbitrary body returning *UpdateWorkOrderResponse
+func (c *ClientWithResponses) UpdateWorkOrderWithBodyWithResponse(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateWorkOrderResponse, error) {
+	rsp, err := c.UpdateWorkOrderWithBody(ctx, id, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateWorkOrderResponse(rsp)
+}
+
+func (c *ClientWithResponses) UpdateWorkOrderWithResponse(ctx context.Context, id int, body UpdateWorkOrderJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateWorkOrderResponse, error) {
+	rsp, err := c.UpdateWorkOrder(ctx, id, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateWorkOrderResponse(rsp)
+}
+
+// CancelWorkOrderWithResponse request returning *CancelWorkOrderResponse
+func (c *ClientWithResponses) CancelWorkOrderWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*CancelWorkOrderResponse, error) {
+	rsp, err := c.CancelWorkOrder(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCancelWorkOrderResponse(rsp)
+}
+
+// CompleteWorkOrderWithBodyWithResponse request with arbitrary body returning *CompleteWorkOrderResponse
+func (c *ClientWithResponses) CompleteWorkOrderWithBodyWithResponse(ctx context.Context, id int, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CompleteWorkOrderResponse, error) {
+	rsp, err := c.CompleteWorkOrderWithBody(ctx, id, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCompleteWorkOrderResponse(rsp)
+}
+
+func (c *ClientWithResponses) CompleteWorkOrderWithResponse(ctx context.Context, id int, body CompleteWorkOrderJSONRequestBody, reqEditors ...RequestEditorFn) (*CompleteWorkOrderResponse, error) {
+	rsp, err := c.CompleteWorkOrder(ctx, id, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCompleteWorkOrderResponse(rsp)
+}
+
+// StartWorkOrderWithResponse request returning *StartWorkOrderResponse
+func (c *ClientWithResponses) StartWorkOrderWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*StartWorkOrderResponse, error) {
+	rsp, err := c.StartWorkOrder(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStartWorkOrderResponse(rsp)
+}
+
+// ParseListGeofencesResponse parses an HTTP response from a ListGeofencesWithResponse call
+func ParseListGeofencesResponse(rsp *http.Response) (*ListGeofencesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListGeofencesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GeofenceList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCreateGeofenceResponse parses an HTTP response from a CreateGeofenceWithResponse call
+func ParseCreateGeofenceResponse(rsp *http.Response) (*CreateGeofenceResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CreateGeofenceResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest GeofenceResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDeleteGeofenceResponse parses an HTTP response from a DeleteGeofenceWithResponse call
+func ParseDeleteGeofenceResponse(rsp *http.Response) (*DeleteGeofenceResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeleteGeofenceResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetGeofenceResponse parses an HTTP response from a GetGeofenceWithResponse call
+func ParseGetGeofenceResponse(rsp *http.Response) (*GetGeofenceResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetGeofenceResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GeofenceResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseUpdateGeofenceResponse parses an HTTP response from a UpdateGeofenceWithResponse call
+func ParseUpdateGeofenceResponse(rsp *http.Response) (*UpdateGeofenceResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UpdateGeofenceResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GeofenceResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetServiceDueResponse parses an HTTP response from a GetServiceDueWithResponse call
+func ParseGetServiceDueResponse(rsp *http.Response) (*GetServiceDueResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetServiceDueResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ServiceDueList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCreateReservationResponse parses an HTTP response from a CreateReservationWithResponse call
+func ParseCreateReservationResponse(rsp *http.Response) (*CreateReservationResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CreateReservationResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ReservationResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 422:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON422 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetLatestReservationResponse parses an HTTP response from a GetLatestReservationWithResponse call
+func ParseGetLatestReservationResponse(rsp *http.Response) (*GetLatestReservationResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetLatestReservationResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ReservationResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetReservationResponse parses an HTTP response from a GetReservationWithResponse call
+func ParseGetReservationResponse(rsp *http.Response) (*GetReservationResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetReservationResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ReservationResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCompleteReservationResponse parses an HTTP response from a CompleteReservationWithResponse call
+func ParseCompleteReservationResponse(rsp *http.Response) (*CompleteReservationResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CompleteReservationResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ReservationResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetZoneExitsResponse parses an HTTP response from a GetZoneExitsWithResponse call
+func ParseGetZoneExitsResponse(rsp *http.Response) (*GetZoneExitsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetZoneExitsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ZoneExitList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListStationsResponse parses an HTTP response from a ListStationsWithResponse call
+func ParseListStationsResponse(rsp *http.Response) (*ListStationsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListStationsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest StationList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseFindNearbyStationsResponse parses an HTTP response from a FindNearbyStationsWithResponse call
+func ParseFindNearbyStationsResponse(rsp *http.Response) (*FindNearbyStationsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &FindNearbyStationsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest NearbyStationsResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListVehiclesResponse parses an HTTP response from a ListVehiclesWithResponse call
+func ParseListVehiclesResponse(rsp *http.Response) (*ListVehiclesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListVehiclesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest VehicleList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetVehicleResponse parses an HTTP response from a GetVehicleWithResponse call
+func ParseGetVehicleResponse(rsp *http.Response) (*GetVehicleResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetVehicleResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest VehicleResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetVehicleCalendarResponse parses an HTTP response from a GetVehicleCalendarWithResponse call
+func ParseGetVehicleCalendarResponse(rsp *http.Response) (*GetVehicleCalendarResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetVehicleCalendarResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest VehicleCalendarResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetVehicleTelemetryResponse parses an HTTP response from a GetVehicleTelemetryWithResponse call
+func ParseGetVehicleTelemetryResponse(rsp *http.Response) (*GetVehicleTelemetryResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetVehicleTelemetryResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest VehicleTelemetryResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseReportVehicleTelemetryResponse parses an HTTP response from a ReportVehicleTelemetryWithResponse call
+func ParseReportVehicleTelemetryResponse(rsp *http.Response) (*ReportVehicleTelemetryResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportVehicleTelemetryResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest TelemetryAccepted
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListWorkOrdersResponse parses an HTTP response from a ListWorkOrdersWithResponse call
+func ParseListWorkOrdersResponse(rsp *http.Response) (*ListWorkOrdersResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListWorkOrdersResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest WorkOrderList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCreateWorkOrderResponse parses an HTTP response from a CreateWorkOrderWithResponse call
+func ParseCreateWorkOrderResponse(rsp *http.Response) (*CreateWorkOrderResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CreateWorkOrderResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest WorkOrderResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetWorkOrderResponse parses an HTTP response from a GetWorkOrderWithResponse call
+func ParseGetWorkOrderResponse(rsp *http.Response) (*GetWorkOrderResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetWorkOrderResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest WorkOrderResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseUpdateWorkOrderResponse parses an HTTP response from a UpdateWorkOrderWithResponse call
+func ParseUpdateWorkOrderResponse(rsp *http.Response) (*UpdateWorkOrderResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UpdateWorkOrderResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest WorkOrderResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCancelWorkOrderResponse parses an HTTP response from a CancelWorkOrderWithResponse call
+func ParseCancelWorkOrderResponse(rsp *http.Response) (*CancelWorkOrderResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CancelWorkOrderResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest WorkOrderResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCompleteWorkOrderResponse parses an HTTP response from a CompleteWorkOrderWithResponse call
+func ParseCompleteWorkOrderResponse(rsp *http.Response) (*CompleteWorkOrderResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CompleteWorkOrderResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest WorkOrderResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseStartWorkOrderResponse parses an HTTP response from a StartWorkOrderWithResponse call
+func ParseStartWorkOrderResponse(rsp *http.Response) (*StartWorkOrderResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &StartWorkOrderResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest WorkOrderResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
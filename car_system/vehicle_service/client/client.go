@@ -0,0 +1,5 @@
+package client
+
+// The client is generated from the vehicle_service OpenAPI document; run
+// go generate after changing api/openapi.json.
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.5.1 -config oapi-codegen.yaml ../api/openapi.json
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"car_system/common/database"
+	"car_system/common/deprecation"
+	"car_system/common/httpserver"
+	"car_system/common/idempotency"
+	"car_system/common/settings"
+	"car_system/vehicle_service/booking"
+	"car_system/vehicle_service/charging"
+	"car_system/vehicle_service/geofence"
+	"car_system/vehicle_service/maintenance"
+	"car_system/vehicle_service/telemetry"
+	"car_system/vehicle_service/trip"
+	"fmt"
+)
+
+// Config is the resolved configuration of vehicle_service
+type Config struct {
+	Port           int    `env:"PORT" default:"8081" usage:"HTTP listen port"`
+	LogLevel       string `env:"LOG_LEVEL" default:"info" usage:"Minimum log level (debug, info, warn, error)"`
+	StorageBackend string `env:"STORAGE_BACKEND" default:"mysql" usage:"Storage backend (mysql or memory)"`
+	AutoMigrate    bool   `env:"DB_AUTO_MIGRATE" default:"true" usage:"Apply pending migrations on startup"`
+	StaticDir      string `env:"STATIC_DIR" default:"./static/" usage:"Directory of the static pages"`
+
+	ValidateRequests bool `env:"OPENAPI_VALIDATE_REQUESTS" usage:"Reject requests that do not match the OpenAPI document"`
+
+	CORSAllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS" default:"http://localhost:8080" usage:"Comma-separated origins allowed to call the API from a browser"`
+
+	ServiceToken string `env:"SERVICE_TOKEN" secret:"true" usage:"Token user_service sends with the membership of its users; memberships are refused while it is empty"`
+
+	AdminToken string `env:"ADMIN_TOKEN" secret:"true" usage:"Bearer token of the admin routes that manage the geofences and the work orders; they are refused while it is empty"`
+
+	DB          database.Settings
+	Pool        database.PoolConfig
+	HTTP        httpserver.Config
+	Legacy      deprecation.Policy
+	Idempotency idempotency.Settings
+	Booking     booking.Settings
+	Charging    charging.Settings
+	Range       trip.Settings
+	Telemetry   telemetry.Settings
+	Geofence    geofence.Settings
+	Maintenance maintenance.Settings
+}
+
+// Load resolves the configuration from defaults, the optional .env file, the
+// environment and the command-line flags in args. It returns the arguments
+// left after the flags.
+func Load(args []string) (Config, []string, error) {
+	cfg := Config{DB: database.Settings{Name: "vehicle_service"}}
+	rest, err := settings.Load("vehicle_service", &cfg, args)
+	return cfg, rest, err
+}
+
+// Validate checks the resolved configuration
+func (c *Config) Validate() error {
+	if c.Port <= 0 || c.Port > 65535 {
+		return fmt.Errorf("PORT must be between 1 and 65535, got %d", c.Port)
+	}
+	if _, err := c.Booking.Policy(); err != nil {
+		return err
+	}
+	if _, err := c.Charging.Forecaster(); err != nil {
+		return err
+	}
+	if _, err := c.Range.Estimator(); err != nil {
+		return err
+	}
+	if err := c.Telemetry.Validate(); err != nil {
+		return err
+	}
+	if _, err := c.Geofence.Policy(); err != nil {
+		return err
+	}
+	if err := c.Maintenance.Validate(); err != nil {
+		return err
+	}
+	if len(c.CORSAllowedOrigins) == 0 {
+		return fmt.Errorf("CORS_ALLOWED_ORIGINS must list at least one origin")
+	}
+	switch c.StorageBackend {
+	case "memory":
+		return nil
+	case "mysql":
+		return c.DB.Validate()
+	default:
+		return fmt.Errorf("STORAGE_BACKEND must be mysql or memory, got %q", c.StorageBackend)
+	}
+}
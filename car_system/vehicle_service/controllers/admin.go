@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"car_system/common/apierror"
+	"car_system/common/logging"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// adminToken authenticates the fleet operators on the admin routes; no
+// admin request is accepted without one
+var adminToken string
+
+// UseAdminToken sets the token the admin routes require in an
+// Authorization: Bearer header
+func UseAdminToken(token string) {
+	adminToken = token
+}
+
+var errAdminToken = apierror.New(http.StatusUnauthorized, apierror.CodeUnauthorized, "Missing or invalid admin token")
+
+// requireAdmin writes a 401 and returns false unless the request carries the
+// admin token
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+		logging.FromContext(r.Context()).Warn("Rejected admin request without a valid token", "method", r.Method, "path", r.URL.Path, "token_sent", token != "")
+		w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+		apierror.Write(w, r, errAdminToken)
+		return false
+	}
+	return true
+}
+
+// ServiceTokenHeader carries the token of user_service on the requests it
+// forwards
+const ServiceTokenHeader = "X-Service-Token"
+
+// serviceToken authenticates user_service; without one no request is taken
+// to come from it
+var serviceToken string
+
+// UseServiceToken sets the token user_service sends in ServiceTokenHeader
+func UseServiceToken(token string) {
+	serviceToken = token
+}
+
+// fromUserService reports whether the request carries the token of
+// user_service, which vouches for the membership of the user
+func fromUserService(r *http.Request) bool {
+	token := r.Header.Get(ServiceTokenHeader)
+	return serviceToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(serviceToken)) == 1
+}
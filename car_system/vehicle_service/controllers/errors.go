@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"car_system/common/apierror"
+	"net/http"
+)
+
+// Error codes returned by vehicle_service in addition to the shared apierror codes
+const (
+	CodeVehicleNotFound     = "VEHICLE_NOT_FOUND"
+	CodeVehicleUnavailable  = "VEHICLE_UNAVAILABLE"
+	CodeReservationNotFound = "RESERVATION_NOT_FOUND"
+	CodeInvalidTimeRange    = "INVALID_TIME_RANGE"
+	CodeStationNotFound     = "STATION_NOT_FOUND"
+	CodeReturnStationFull   = "RETURN_STATION_FULL"
+	CodeReservationInactive = "RESERVATION_NOT_ACTIVE"
+	CodeReservationNotBegun = "RESERVATION_NOT_STARTED"
+	CodeGeofenceNotFound    = "GEOFENCE_NOT_FOUND"
+	CodeWorkOrderNotFound   = "WORK_ORDER_NOT_FOUND"
+	CodeWorkOrderStatus     = "WORK_ORDER_STATUS_CONFLICT"
+)
+
+var (
+	errVehicleNotFound     = apierror.New(http.StatusNotFound, CodeVehicleNotFound, "Vehicle not found")
+	errVehicleUnavailable  = apierror.New(http.StatusConflict, CodeVehicleUnavailable, "Vehicle not available for the selected time range")
+	errReservationNotFound = apierror.New(http.StatusNotFound, CodeReservationNotFound, "No reservations found for the user")
+	errInvalidTimeRange    = apierror.New(http.StatusBadRequest, CodeInvalidTimeRange, "End time must be after start time")
+	errStationNotFound     = apierror.New(http.StatusNotFound, CodeStationNotFound, "Station not found")
+	errNoSuchReservation   = apierror.New(http.StatusNotFound, CodeReservationNotFound, "Reservation not found")
+	errGeofenceNotFound    = apierror.New(http.StatusNotFound, CodeGeofenceNotFound, "Geofence not found")
+	errWorkOrderNotFound   = apierror.New(http.StatusNotFound, CodeWorkOrderNotFound, "Work order not found")
+	errMembershipUntrusted = apierror.New(http.StatusUnauthorized, apierror.CodeUnauthorized, "membership_tier and booking_limit are only accepted from user_service")
+)
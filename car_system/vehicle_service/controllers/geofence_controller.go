@@ -0,0 +1,356 @@
+package controllers
+
+import (
+	"car_system/common/apierror"
+	"car_system/common/logging"
+	"car_system/vehicle_service/geo"
+	"car_system/vehicle_service/geofence"
+	"car_system/vehicle_service/models"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// zonePolicy decides what happens to vehicles returned outside the allowed
+// return zone
+var zonePolicy geofence.Policy
+
+// UseGeofencePolicy sets the policy checked by CompleteReservation. The zero
+// value does not check returns.
+func UseGeofencePolicy(p geofence.Policy) {
+	zonePolicy = p
+}
+
+// maxGeofenceName is the longest geofence name stored
+const maxGeofenceName = 100
+
+// geofenceIDVar parses the {id} path variable
+func geofenceIDVar(w http.ResponseWriter, r *http.Request) (int, bool) {
+	geofenceID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || geofenceID <= 0 {
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Invalid Geofence ID").WithField("id", "must be a positive integer"))
+		return 0, false
+	}
+	return geofenceID, true
+}
+
+// ListGeofences returns every geofence
+func ListGeofences(w http.ResponseWriter, r *http.Request) {
+	geofences, err := models.GetGeofences()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching geofences", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch geofences"))
+		return
+	}
+	if geofences == nil {
+		geofences = []models.Geofence{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Geofences fetched successfully",
+		"data":    geofences,
+	})
+}
+
+// GetGeofence returns the geofence {id}
+func GetGeofence(w http.ResponseWriter, r *http.Request) {
+	geofenceID, ok := geofenceIDVar(w, r)
+	if !ok {
+		return
+	}
+	g, err := models.GetGeofenceByID(geofenceID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching geofence", "geofence_id", geofenceID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch geofence"))
+		return
+	}
+	if g == nil {
+		apierror.Write(w, r, errGeofenceNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Geofence fetched successfully",
+		"data":    g,
+	})
+}
+
+// decodeGeofence reads and validates the geofence in the request body. It
+// writes the error and returns false when the geofence cannot be stored.
+func decodeGeofence(w http.ResponseWriter, r *http.Request) (models.Geofence, bool) {
+	var request struct {
+		Name      string          `json:"name"`
+		Kind      string          `json:"kind"`
+		StationID *int            `json:"station_id"`
+		Geometry  json.RawMessage `json:"geometry"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierror.Write(w, r, apierror.ErrInvalidJSON)
+		return models.Geofence{}, false
+	}
+
+	var v apierror.Validation
+	v.Check(request.Name != "", "name", "is required")
+	v.Check(len(request.Name) <= maxGeofenceName, "name", "must be at most 100 characters")
+	switch request.Kind {
+	case models.ZoneServiceArea, models.ZoneNoParking:
+		v.Check(request.StationID == nil, "station_id", "is only allowed for a station_lot")
+	case models.ZoneStationLot:
+		v.Check(request.StationID != nil, "station_id", "is required for a station_lot")
+	default:
+		v.Add("kind", "must be service_area, no_parking or station_lot")
+	}
+	if len(request.Geometry) == 0 || string(request.Geometry) == "null" {
+		v.Add("geometry", "is required")
+	} else if _, err := geo.ParseGeoJSON(request.Geometry); err != nil {
+		v.Add("geometry", err.Error())
+	}
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
+		return models.Geofence{}, false
+	}
+
+	if request.StationID != nil {
+		station, err := models.GetStationByID(*request.StationID)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error fetching station", "station_id", *request.StationID, "error", err)
+			apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to store geofence"))
+			return models.Geofence{}, false
+		}
+		if station == nil {
+			apierror.Write(w, r, errStationNotFound.WithField("station_id", "does not exist"))
+			return models.Geofence{}, false
+		}
+	}
+	return models.Geofence{Name: request.Name, Kind: request.Kind, StationID: request.StationID, Geometry: request.Geometry}, true
+}
+
+// CreateGeofence stores a new geofence. It is an admin route.
+func CreateGeofence(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	g, ok := decodeGeofence(w, r)
+	if !ok {
+		return
+	}
+	if err := models.CreateGeofence(&g); err != nil {
+		logging.FromContext(r.Context()).Error("Error creating geofence", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to store geofence"))
+		return
+	}
+	logging.FromContext(r.Context()).Info("Geofence created", "geofence_id", g.GeofenceID, "kind", g.Kind, "name", g.Name)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Geofence created successfully",
+		"data":    g,
+	})
+}
+
+// UpdateGeofence replaces the geofence {id}. It is an admin route.
+func UpdateGeofence(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	geofenceID, ok := geofenceIDVar(w, r)
+	if !ok {
+		return
+	}
+	g, ok := decodeGeofence(w, r)
+	if !ok {
+		return
+	}
+	g.GeofenceID = geofenceID
+	found, err := models.UpdateGeofence(&g)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error updating geofence", "geofence_id", geofenceID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to store geofence"))
+		return
+	}
+	if !found {
+		apierror.Write(w, r, errGeofenceNotFound)
+		return
+	}
+	logging.FromContext(r.Context()).Info("Geofence updated", "geofence_id", geofenceID, "kind", g.Kind, "name", g.Name)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Geofence updated successfully",
+		"data":    g,
+	})
+}
+
+// DeleteGeofence removes the geofence {id}. It is an admin route.
+func DeleteGeofence(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	geofenceID, ok := geofenceIDVar(w, r)
+	if !ok {
+		return
+	}
+	found, err := models.DeleteGeofence(geofenceID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error deleting geofence", "geofence_id", geofenceID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to delete geofence"))
+		return
+	}
+	if !found {
+		apierror.Write(w, r, errGeofenceNotFound)
+		return
+	}
+	logging.FromContext(r.Context()).Info("Geofence deleted", "geofence_id", geofenceID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetZoneExits returns the service areas the vehicle of the reservation {id}
+// left during it. Only the fleet operators may track a trip.
+func GetZoneExits(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	reservationID, ok := reservationIDVar(w, r)
+	if !ok {
+		return
+	}
+	errFetch := apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch zone exits")
+	reservation, err := models.GetReservationByID(reservationID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching reservation", "reservation_id", reservationID, "error", err)
+		apierror.Write(w, r, errFetch)
+		return
+	}
+	if reservation == nil {
+		apierror.Write(w, r, errNoSuchReservation)
+		return
+	}
+	exits, err := models.GetZoneExits(reservationID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching zone exits", "reservation_id", reservationID, "error", err)
+		apierror.Write(w, r, errFetch)
+		return
+	}
+	if exits == nil {
+		exits = []models.ZoneExit{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Zone exits fetched successfully",
+		"data":    exits,
+	})
+}
+
+// detectZoneExits records the service areas vehicle left during a
+// reservation, comparing every new reading with the position before it.
+// Readings older than the last reported state are skipped. Failures are
+// only logged, as the readings are stored already.
+func detectZoneExits(r *http.Request, vehicle models.Vehicle, readings []models.TelemetryReading) {
+	logger := logging.FromContext(r.Context())
+	zones, err := geofence.Load()
+	if err != nil {
+		logger.Error("Error loading geofences", "error", err)
+		return
+	}
+	if !zones.HasServiceArea() {
+		return
+	}
+
+	sorted := make([]models.TelemetryReading, 0, len(readings))
+	for _, reading := range readings {
+		if vehicle.TelemetryAt == nil || reading.RecordedAt.After(*vehicle.TelemetryAt) {
+			sorted = append(sorted, reading)
+		}
+	}
+	if len(sorted) == 0 {
+		return
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].RecordedAt.Before(sorted[j].RecordedAt) })
+	first, last := sorted[0].RecordedAt, sorted[len(sorted)-1].RecordedAt
+	reservations, err := models.GetReservationsBetween(vehicle.VehicleID, first, last.Add(1))
+	if err != nil {
+		logger.Error("Error fetching reservations", "vehicle_id", vehicle.VehicleID, "error", err)
+		return
+	}
+
+	var exits []models.ZoneExit
+	var previous *geo.Point
+	if vehicle.Latitude != nil && vehicle.Longitude != nil {
+		previous = &geo.Point{Lat: *vehicle.Latitude, Lon: *vehicle.Longitude}
+	}
+	for _, reading := range sorted {
+		p := geo.Point{Lat: reading.Latitude, Lon: reading.Longitude}
+		if previous != nil {
+			for _, res := range reservations {
+				if res.Status != "Active" || reading.RecordedAt.Before(res.StartTime) || !reading.RecordedAt.Before(res.EndTime) {
+					continue
+				}
+				for _, g := range zones.Exits(*previous, p) {
+					exits = append(exits, models.ZoneExit{
+						GeofenceID: g.GeofenceID, GeofenceName: g.Name, VehicleID: vehicle.VehicleID, ReservationID: res.ReservationID,
+						RecordedAt: reading.RecordedAt, Latitude: p.Lat, Longitude: p.Lon,
+					})
+				}
+			}
+		}
+		previous = &p
+	}
+	if err := models.RecordZoneExits(exits); err != nil {
+		logger.Error("Error storing zone exits", "vehicle_id", vehicle.VehicleID, "error", err)
+		return
+	}
+	for _, exit := range exits {
+		zoneExitsTotal.Inc()
+		logger.Warn("Vehicle left a service area", "vehicle_id", exit.VehicleID, "reservation_id", exit.ReservationID, "geofence", exit.GeofenceName, "recorded_at", exit.RecordedAt)
+	}
+}
+
+// returnZoneFee checks the last reported position of the vehicle of
+// reservation against its return zone. It returns the out-of-zone fee to
+// charge, or the error refusing the return. A vehicle that never reported
+// its position, or not recently enough, is taken to be where it should be.
+func returnZoneFee(r *http.Request, reservation models.Reservation) (float64, *apierror.Error) {
+	if zonePolicy.OutOfZone == "" || zonePolicy.OutOfZone == geofence.OutOfZoneIgnore {
+		return 0, nil
+	}
+	errCheck := apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to complete reservation")
+	vehicle, err := models.GetVehicleByID(reservation.VehicleID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching vehicle", "vehicle_id", reservation.VehicleID, "error", err)
+		return 0, errCheck
+	}
+	if vehicle == nil {
+		return 0, nil
+	}
+	if vehicle.Latitude == nil || vehicle.Longitude == nil || vehicle.TelemetryAt == nil {
+		logging.FromContext(r.Context()).Warn("Return zone not checked without a reported position", "reservation_id", reservation.ReservationID, "vehicle_id", reservation.VehicleID)
+		return 0, nil
+	}
+	if zonePolicy.Stale(*vehicle.TelemetryAt, clock()) {
+		logging.FromContext(r.Context()).Warn("Return zone not checked with a stale position", "reservation_id", reservation.ReservationID, "vehicle_id", reservation.VehicleID,
+			"telemetry_at", *vehicle.TelemetryAt, "max_position_age", zonePolicy.MaxPositionAge)
+		return 0, nil
+	}
+	zones, err := geofence.Load()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error loading geofences", "error", err)
+		return 0, errCheck
+	}
+	reason := zones.CheckReturn(geo.Point{Lat: *vehicle.Latitude, Lon: *vehicle.Longitude}, reservation.ReturnStationID)
+	if reason == "" {
+		return 0, nil
+	}
+	if zonePolicy.OutOfZone == geofence.OutOfZoneReject {
+		return 0, apierror.New(http.StatusConflict, geofence.CodeOutsideZone, reason)
+	}
+	logging.FromContext(r.Context()).Warn("Vehicle returned outside the return zone", "reservation_id", reservation.ReservationID, "vehicle_id", reservation.VehicleID,
+		"reason", reason, "out_of_zone_fee", zonePolicy.Fee)
+	return zonePolicy.Fee, nil
+}
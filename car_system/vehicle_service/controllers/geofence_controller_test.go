@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"bytes"
+	"car_system/common/apierror"
+	"car_system/vehicle_service/geofence"
+	"car_system/vehicle_service/models"
+	"car_system/vehicle_service/telemetry"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func TestGeofenceAdmin(t *testing.T) {
+	setupTest(t)
+	UseAdminToken("ops-token")
+	defer UseAdminToken("")
+
+	send := func(handler http.HandlerFunc, method, id, token, body string) *httptest.ResponseRecorder {
+		req := mux.SetURLVars(httptest.NewRequest(method, "/v1/geofences/"+id, bytes.NewBufferString(body)), map[string]string{"id": id})
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+	zone := `{"name": "Sentosa", "kind": "no_parking", "geometry": {"type": "Polygon", "coordinates": [[[103.81, 1.24], [103.84, 1.24], [103.84, 1.26], [103.81, 1.26], [103.81, 1.24]]]}}`
+
+	for _, token := range []string{"", "wrong-token"} {
+		if rec := send(CreateGeofence, "POST", "", token, zone); rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("token %q: got %d, want 401 with a challenge", token, rec.Code)
+		}
+	}
+	for _, body := range []string{
+		`{"name": "Sentosa", "kind": "parking", "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}}`,
+		`{"name": "Lot", "kind": "station_lot", "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}}`,
+		`{"name": "Open ring", "kind": "no_parking", "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1]]]}}`,
+		`{"name": "", "kind": "service_area"}`,
+	} {
+		if rec := send(CreateGeofence, "POST", "", "ops-token", body); rec.Code != http.StatusBadRequest || errorCode(t, rec) != apierror.CodeValidationFailed {
+			t.Errorf("%s: got %d %s, want 400", body, rec.Code, rec.Body.String())
+		}
+	}
+	lot := `{"name": "Lot", "kind": "station_lot", "station_id": 99, "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}}`
+	if rec := send(CreateGeofence, "POST", "", "ops-token", lot); rec.Code != http.StatusNotFound || errorCode(t, rec) != CodeStationNotFound {
+		t.Errorf("lot of an unknown station: got %d %s, want 404", rec.Code, rec.Body.String())
+	}
+
+	rec := send(CreateGeofence, "POST", "", "ops-token", zone)
+	var created struct {
+		Data models.Geofence `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil || rec.Code != http.StatusCreated || created.Data.GeofenceID != 4 {
+		t.Fatalf("create: got %d %s, want 201 after the 3 sample geofences", rec.Code, rec.Body.String())
+	}
+	id := strconv.Itoa(created.Data.GeofenceID)
+
+	renamed := `{"name": "Sentosa island", "kind": "no_parking", "geometry": {"type": "Polygon", "coordinates": [[[103.81, 1.24], [103.84, 1.24], [103.84, 1.26], [103.81, 1.26], [103.81, 1.24]]]}}`
+	if rec := send(UpdateGeofence, "PUT", id, "ops-token", renamed); rec.Code != http.StatusOK {
+		t.Errorf("update: got %d %s, want 200", rec.Code, rec.Body.String())
+	}
+	if rec := send(UpdateGeofence, "PUT", "99", "ops-token", renamed); rec.Code != http.StatusNotFound || errorCode(t, rec) != CodeGeofenceNotFound {
+		t.Errorf("update an unknown geofence: got %d %s, want 404", rec.Code, rec.Body.String())
+	}
+	if g, _ := models.GetGeofenceByID(created.Data.GeofenceID); g == nil || g.Name != "Sentosa island" {
+		t.Errorf("stored geofence = %+v, want it renamed", g)
+	}
+
+	if rec := send(DeleteGeofence, "DELETE", id, "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("delete without a token: got %d, want 401", rec.Code)
+	}
+	if rec := send(DeleteGeofence, "DELETE", id, "ops-token", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("delete: got %d %s, want 204", rec.Code, rec.Body.String())
+	}
+	if rec := send(GetGeofence, "GET", id, "", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("get a deleted geofence: got %d, want 404", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	ListGeofences(rec, httptest.NewRequest("GET", "/v1/geofences", nil))
+	var list struct {
+		Data []models.Geofence `json:"data"`
+	}
+	if json.Unmarshal(rec.Body.Bytes(), &list); len(list.Data) != 3 || list.Data[2].Kind != models.ZoneStationLot || *list.Data[2].StationID != 1 {
+		t.Errorf("list = %s, want the 3 sample geofences", rec.Body.String())
+	}
+}
+
+func TestZoneExitsAndReturns(t *testing.T) {
+	setupTest(t)
+	UseTelemetry(telemetry.Settings{Secret: "fleet-secret", RawRetention: 24 * time.Hour})
+	defer UseTelemetry(telemetry.Settings{})
+	UseClock(func() time.Time { return time.Date(2030, 1, 1, 10, 30, 0, 0, time.UTC) })
+	defer UseClock(nil)
+	defer UseGeofencePolicy(geofence.Policy{})
+	UseAdminToken("ops-token")
+	defer UseAdminToken("")
+
+	// A round trip of the airport vehicle
+	res := models.Reservation{VehicleID: 2, UserID: 7, StartTime: time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC),
+		PickupStationID: 2, ReturnStationID: 2}
+	if err := models.CreateReservation(&res); err != nil {
+		t.Fatal(err)
+	}
+	id := strconv.Itoa(res.ReservationID)
+
+	report := func(at string, lat, lon float64) {
+		t.Helper()
+		recordedAt, _ := time.Parse(time.RFC3339, "2030-01-01T"+at+":00Z")
+		body, _ := json.Marshal(map[string]interface{}{"readings": []map[string]interface{}{
+			{"recorded_at": recordedAt, "latitude": lat, "longitude": lon, "charge_level": 80, "odometer_km": 15010, "locked": false, "speed_kmh": 50},
+		}})
+		req := mux.SetURLVars(httptest.NewRequest("POST", "/v1/vehicles/2/telemetry", bytes.NewReader(body)), map[string]string{"id": "2"})
+		req.Header.Set("Authorization", "Bearer "+telemetry.TokenFor("fleet-secret", 2))
+		rec := httptest.NewRecorder()
+		IngestTelemetry(rec, req)
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("report at %s: got %d %s, want 202", at, rec.Code, rec.Body.String())
+		}
+	}
+	send := func(handler http.HandlerFunc, method, path, token, body string) *httptest.ResponseRecorder {
+		req := mux.SetURLVars(httptest.NewRequest(method, path, bytes.NewBufferString(body)), map[string]string{"id": id})
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	// Out of Singapore across the causeway and back, then parked on the
+	// Marina Bay waterfront
+	report("10:10", 1.3644, 103.9915)
+	report("10:15", 1.4300, 103.7700)
+	report("10:20", 1.4700, 103.7700)
+	report("10:25", 1.4300, 103.7700)
+	report("10:28", 1.2830, 103.8600)
+
+	if rec := send(GetZoneExits, "GET", "/v1/reservations/"+id+"/zone-exits", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("zone exits without the admin token: got %d, want 401", rec.Code)
+	}
+	rec := send(GetZoneExits, "GET", "/v1/reservations/"+id+"/zone-exits", "ops-token", "")
+	var exits struct {
+		Data []models.ZoneExit `json:"data"`
+	}
+	if json.Unmarshal(rec.Body.Bytes(), &exits); rec.Code != http.StatusOK || len(exits.Data) != 1 {
+		t.Fatalf("zone exits: got %d %s, want one", rec.Code, rec.Body.String())
+	}
+	if e := exits.Data[0]; e.GeofenceName != "Singapore" || e.ReservationID != res.ReservationID || e.RecordedAt.Format("15:04") != "10:20" {
+		t.Errorf("zone exit = %+v, want Singapore left at 10:20", e)
+	}
+
+	UseGeofencePolicy(geofence.Policy{OutOfZone: geofence.OutOfZoneReject, Fee: 50})
+	rec = send(CompleteReservation, "POST", "/v1/reservations/"+id+"/complete", "", `{"user_id":7}`)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != geofence.CodeOutsideZone {
+		t.Errorf("return in a no-parking zone: got %d %s, want 409 %s", rec.Code, rec.Body.String(), geofence.CodeOutsideZone)
+	}
+
+	UseGeofencePolicy(geofence.Policy{OutOfZone: geofence.OutOfZoneFee, Fee: 50})
+	rec = send(CompleteReservation, "POST", "/v1/reservations/"+id+"/complete", "", `{"user_id":7}`)
+	var completed struct {
+		Data models.Reservation `json:"data"`
+	}
+	if json.Unmarshal(rec.Body.Bytes(), &completed); rec.Code != http.StatusOK || completed.Data.OutOfZoneFee != 50 {
+		t.Errorf("return in a no-parking zone with a fee: got %d %s, want 200 with a fee of 50", rec.Code, rec.Body.String())
+	}
+	if stored, _ := models.GetReservationByID(res.ReservationID); stored.OutOfZoneFee != 50 {
+		t.Errorf("stored reservation = %+v, want the out-of-zone fee", stored)
+	}
+
+	// Readings after the trip record no exits
+	UseClock(func() time.Time { return time.Date(2030, 1, 1, 12, 30, 0, 0, time.UTC) })
+	report("12:20", 1.4300, 103.7700)
+	report("12:25", 1.4700, 103.7700)
+	if exits, _ := models.GetZoneExits(res.ReservationID); len(exits) != 1 {
+		t.Errorf("zone exits after the trip = %+v, want still one", exits)
+	}
+
+	// The vehicle was last seen across the causeway at 12:25, which only
+	// refuses a return while that position is recent
+	next := models.Reservation{VehicleID: 2, UserID: 7, StartTime: time.Date(2030, 1, 1, 12, 30, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 1, 14, 0, 0, 0, time.UTC),
+		PickupStationID: 2, ReturnStationID: 2}
+	if err := models.CreateReservation(&next); err != nil {
+		t.Fatal(err)
+	}
+	id = strconv.Itoa(next.ReservationID)
+	UseGeofencePolicy(geofence.Policy{OutOfZone: geofence.OutOfZoneReject, Fee: 50, MaxPositionAge: 15 * time.Minute})
+	UseClock(func() time.Time { return time.Date(2030, 1, 1, 12, 40, 0, 0, time.UTC) })
+	if rec := send(CompleteReservation, "POST", "/v1/reservations/"+id+"/complete", "", `{"user_id":7}`); rec.Code != http.StatusConflict {
+		t.Errorf("return with a recent position outside the zone: got %d %s, want 409", rec.Code, rec.Body.String())
+	}
+	UseClock(func() time.Time { return time.Date(2030, 1, 1, 13, 0, 0, 0, time.UTC) })
+	rec = send(CompleteReservation, "POST", "/v1/reservations/"+id+"/complete", "", `{"user_id":7}`)
+	if json.Unmarshal(rec.Body.Bytes(), &completed); rec.Code != http.StatusOK || completed.Data.OutOfZoneFee != 0 {
+		t.Errorf("return with a stale position: got %d %s, want 200 without a fee", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,435 @@
+package controllers
+
+import (
+	"car_system/common/apierror"
+	"car_system/common/logging"
+	"car_system/vehicle_service/maintenance"
+	"car_system/vehicle_service/models"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// maintenanceSettings holds the service interval of the fleet
+var maintenanceSettings maintenance.Settings
+
+// UseMaintenance sets the service interval used by GetServiceDue and by the
+// service work orders created without a mileage trigger. The zero value
+// reports no service due.
+func UseMaintenance(s maintenance.Settings) {
+	maintenanceSettings = s
+}
+
+// maxWorkOrderNotes is the longest notes stored on a work order
+const maxWorkOrderNotes = 1000
+
+// workOrderIDVar parses the {id} path variable
+func workOrderIDVar(w http.ResponseWriter, r *http.Request) (int, bool) {
+	workOrderID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || workOrderID <= 0 {
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Invalid Work Order ID").WithField("id", "must be a positive integer"))
+		return 0, false
+	}
+	return workOrderID, true
+}
+
+// errWorkOrderStatus reports a transition a work order in status cannot make
+func errWorkOrderStatus(want, action, status string) *apierror.Error {
+	return apierror.New(http.StatusConflict, CodeWorkOrderStatus, "Only "+want+" work orders can be "+action+"; this one is "+status)
+}
+
+// writeWorkOrder responds with order and the message
+func writeWorkOrder(w http.ResponseWriter, status int, message string, order *models.WorkOrder) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": message,
+		"data":    order,
+	})
+}
+
+// findWorkOrder fetches the work order {id}. It writes the error and returns
+// nil when there is none.
+func findWorkOrder(w http.ResponseWriter, r *http.Request) *models.WorkOrder {
+	workOrderID, ok := workOrderIDVar(w, r)
+	if !ok {
+		return nil
+	}
+	order, err := models.GetWorkOrderByID(workOrderID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching work order", "work_order_id", workOrderID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch work order"))
+		return nil
+	}
+	if order == nil {
+		apierror.Write(w, r, errWorkOrderNotFound)
+	}
+	return order
+}
+
+// ListWorkOrders returns the work orders, optionally of the vehicle_id and in
+// the status query parameters, ordered by planned start. It is an admin route.
+func ListWorkOrders(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	var v apierror.Validation
+	vehicleID := 0
+	if value := r.URL.Query().Get("vehicle_id"); value != "" {
+		var err error
+		vehicleID, err = strconv.Atoi(value)
+		v.Check(err == nil && vehicleID > 0, "vehicle_id", "must be a positive integer")
+	}
+	status := r.URL.Query().Get("status")
+	v.Check(status == "" || slices.Contains([]string{models.WorkOrderScheduled, models.WorkOrderInProgress, models.WorkOrderCompleted, models.WorkOrderCancelled}, status),
+		"status", "must be Scheduled, In Progress, Completed or Cancelled")
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	orders, err := models.GetWorkOrders(vehicleID, status)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching work orders", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch work orders"))
+		return
+	}
+	if orders == nil {
+		orders = []models.WorkOrder{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Work orders fetched successfully",
+		"data":    orders,
+	})
+}
+
+// GetWorkOrder returns the work order {id}. It is an admin route.
+func GetWorkOrder(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	if order := findWorkOrder(w, r); order != nil {
+		writeWorkOrder(w, http.StatusOK, "Work order fetched successfully", order)
+	}
+}
+
+// workOrderRequest is the plan of a work order in a request body
+type workOrderRequest struct {
+	VehicleID      int       `json:"vehicle_id"`
+	Type           string    `json:"type"`
+	PlannedStart   time.Time `json:"planned_start"`
+	PlannedEnd     time.Time `json:"planned_end"`
+	MileageTrigger *int      `json:"mileage_trigger"`
+	Notes          string    `json:"notes"`
+}
+
+// decodeWorkOrder reads and validates the plan of a work order in the
+// request body. It writes the error and returns false when the plan is
+// invalid.
+func decodeWorkOrder(w http.ResponseWriter, r *http.Request) (workOrderRequest, bool) {
+	var request workOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierror.Write(w, r, apierror.ErrInvalidJSON)
+		return request, false
+	}
+
+	var v apierror.Validation
+	v.Check(slices.Contains(models.WorkTypes, request.Type), "type", "must be "+strings.Join(models.WorkTypes[:len(models.WorkTypes)-1], ", ")+" or "+models.WorkTypes[len(models.WorkTypes)-1])
+	v.Check(!request.PlannedStart.IsZero(), "planned_start", "is required")
+	v.Check(!request.PlannedEnd.IsZero(), "planned_end", "is required")
+	v.Check(request.MileageTrigger == nil || *request.MileageTrigger >= 0, "mileage_trigger", "must not be negative")
+	v.Check(len(request.Notes) <= maxWorkOrderNotes, "notes", "must be at most 1000 characters")
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
+		return request, false
+	}
+	if !request.PlannedStart.Before(request.PlannedEnd) {
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, CodeInvalidTimeRange, "planned_end must be after planned_start").WithField("planned_end", "is not after planned_start"))
+		return request, false
+	}
+	return request, true
+}
+
+// checkWorkWindow checks that the vehicle of order is in the fleet, and
+// neither reserved nor in other maintenance during the planned window of order
+func checkWorkWindow(r *http.Request, order models.WorkOrder) *apierror.Error {
+	errCheck := apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Error checking vehicle availability")
+	vehicle, err := models.GetVehicleByID(order.VehicleID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching vehicle", "vehicle_id", order.VehicleID, "error", err)
+		return errCheck
+	}
+	if vehicle != nil && vehicle.Status == models.VehicleDecommissioned {
+		return apierror.New(http.StatusConflict, CodeVehicleUnavailable, "The vehicle is decommissioned").
+			WithField("vehicle_id", "is decommissioned")
+	}
+	reservations, err := models.GetReservationsBetween(order.VehicleID, order.PlannedStart, order.PlannedEnd)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching reservations", "vehicle_id", order.VehicleID, "error", err)
+		return errCheck
+	}
+	for _, res := range reservations {
+		if res.Status == "Active" {
+			return apierror.New(http.StatusConflict, CodeVehicleUnavailable, "The vehicle is reserved during the planned window").
+				WithField("planned_start", "overlaps reservation "+strconv.Itoa(res.ReservationID))
+		}
+	}
+	orders, err := models.GetOpenWorkOrdersBetween(order.VehicleID, order.PlannedStart, order.PlannedEnd)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching work orders", "vehicle_id", order.VehicleID, "error", err)
+		return errCheck
+	}
+	for _, other := range orders {
+		if other.WorkOrderID != order.WorkOrderID {
+			return apierror.New(http.StatusConflict, CodeVehicleUnavailable, "The vehicle already has maintenance planned during the window").
+				WithField("planned_start", "overlaps work order "+strconv.Itoa(other.WorkOrderID))
+		}
+	}
+	return nil
+}
+
+// CreateWorkOrder schedules maintenance on a vehicle. The vehicle must be
+// free during the planned window, which it cannot be reserved for from then
+// on. A service without a mileage_trigger falls due at the next service of
+// the vehicle. It is an admin route.
+func CreateWorkOrder(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	request, ok := decodeWorkOrder(w, r)
+	if !ok {
+		return
+	}
+	var v apierror.Validation
+	v.Check(request.VehicleID > 0, "vehicle_id", "is required")
+	v.Check(request.PlannedEnd.After(clock()), "planned_end", "must be in the future")
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	errCreate := apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to create work order")
+	vehicle, err := models.GetVehicleByID(request.VehicleID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching vehicle", "vehicle_id", request.VehicleID, "error", err)
+		apierror.Write(w, r, errCreate)
+		return
+	}
+	if vehicle == nil {
+		apierror.Write(w, r, errVehicleNotFound.WithField("vehicle_id", "does not exist"))
+		return
+	}
+
+	order := models.WorkOrder{VehicleID: request.VehicleID, Type: request.Type, PlannedStart: request.PlannedStart.UTC(), PlannedEnd: request.PlannedEnd.UTC(),
+		MileageTrigger: request.MileageTrigger, Notes: request.Notes}
+	if order.Type == models.WorkService && order.MileageTrigger == nil && maintenanceSettings.ServiceIntervalKM > 0 {
+		lastService, err := maintenance.LastServiceKM(vehicle.VehicleID)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error fetching last service", "vehicle_id", vehicle.VehicleID, "error", err)
+			apierror.Write(w, r, errCreate)
+			return
+		}
+		next := maintenanceSettings.NextServiceKM(vehicle.Mileage, lastService)
+		order.MileageTrigger = &next
+	}
+	if apiErr := checkWorkWindow(r, order); apiErr != nil {
+		apierror.Write(w, r, apiErr)
+		return
+	}
+	if err := models.CreateWorkOrder(&order); err != nil {
+		logging.FromContext(r.Context()).Error("Error creating work order", "vehicle_id", order.VehicleID, "error", err)
+		apierror.Write(w, r, errCreate)
+		return
+	}
+	logging.FromContext(r.Context()).Info("Work order scheduled", "work_order_id", order.WorkOrderID, "vehicle_id", order.VehicleID, "type", order.Type,
+		"planned_start", order.PlannedStart, "planned_end", order.PlannedEnd)
+	writeWorkOrder(w, http.StatusCreated, "Work order created successfully", &order)
+}
+
+// UpdateWorkOrder replaces the plan of the open work order {id}. A work order
+// In Progress keeps its planned start, but its end may be moved, e.g. when
+// the work overruns. It is an admin route.
+func UpdateWorkOrder(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	order := findWorkOrder(w, r)
+	if order == nil {
+		return
+	}
+	request, ok := decodeWorkOrder(w, r)
+	if !ok {
+		return
+	}
+	if !order.Open() {
+		apierror.Write(w, r, errWorkOrderStatus("Scheduled or In Progress", "updated", order.Status))
+		return
+	}
+	var v apierror.Validation
+	v.Check(request.VehicleID == 0 || request.VehicleID == order.VehicleID, "vehicle_id", "cannot be changed")
+	v.Check(order.Status == models.WorkOrderScheduled || request.PlannedStart.Equal(order.PlannedStart), "planned_start", "cannot be changed once the work order has started")
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	order.Type, order.PlannedStart, order.PlannedEnd, order.MileageTrigger, order.Notes = request.Type, request.PlannedStart.UTC(), request.PlannedEnd.UTC(), request.MileageTrigger, request.Notes
+	if apiErr := checkWorkWindow(r, *order); apiErr != nil {
+		apierror.Write(w, r, apiErr)
+		return
+	}
+	open, err := models.UpdateWorkOrder(order)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error updating work order", "work_order_id", order.WorkOrderID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to update work order"))
+		return
+	}
+	if !open {
+		apierror.Write(w, r, apierror.New(http.StatusConflict, CodeWorkOrderStatus, "The work order was closed in the meantime"))
+		return
+	}
+	logging.FromContext(r.Context()).Info("Work order updated", "work_order_id", order.WorkOrderID, "planned_start", order.PlannedStart, "planned_end", order.PlannedEnd)
+	writeWorkOrder(w, http.StatusOK, "Work order updated successfully", order)
+}
+
+// StartWorkOrder starts the Scheduled work order {id} now and puts its
+// vehicle Under Maintenance. Started early, the work order moves its planned
+// start to now, which the vehicle must be free for. Work orders also start by
+// themselves when their planned window opens. It is an admin route.
+func StartWorkOrder(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	order := findWorkOrder(w, r)
+	if order == nil {
+		return
+	}
+	if order.Status != models.WorkOrderScheduled {
+		apierror.Write(w, r, errWorkOrderStatus("Scheduled", "started", order.Status))
+		return
+	}
+	now := clock().UTC()
+	if now.Before(order.PlannedStart) {
+		early := *order
+		early.PlannedStart = now
+		if apiErr := checkWorkWindow(r, early); apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+	}
+	transitionWorkOrder(w, r, order.WorkOrderID, "started", func() (bool, error) { return models.StartWorkOrder(order.WorkOrderID, now) })
+}
+
+// CompleteWorkOrder completes the In Progress work order {id} with the
+// optional notes of the body and puts its vehicle back in operation. The
+// mileage of the vehicle is recorded, and a completed service starts the next
+// service interval. It is an admin route.
+func CompleteWorkOrder(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	var request struct {
+		Notes string `json:"notes"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			apierror.Write(w, r, apierror.ErrInvalidJSON)
+			return
+		}
+	}
+	if len(request.Notes) > maxWorkOrderNotes {
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "One or more fields are invalid").WithField("notes", "must be at most 1000 characters"))
+		return
+	}
+	order := findWorkOrder(w, r)
+	if order == nil {
+		return
+	}
+	if order.Status != models.WorkOrderInProgress {
+		apierror.Write(w, r, errWorkOrderStatus("In Progress", "completed", order.Status))
+		return
+	}
+	now := clock().UTC()
+	transitionWorkOrder(w, r, order.WorkOrderID, "completed", func() (bool, error) { return models.CompleteWorkOrder(order.WorkOrderID, now, request.Notes) })
+}
+
+// CancelWorkOrder cancels the Scheduled work order {id}, which frees its
+// planned window. It is an admin route.
+func CancelWorkOrder(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	order := findWorkOrder(w, r)
+	if order == nil {
+		return
+	}
+	if order.Status != models.WorkOrderScheduled {
+		apierror.Write(w, r, errWorkOrderStatus("Scheduled", "cancelled", order.Status))
+		return
+	}
+	transitionWorkOrder(w, r, order.WorkOrderID, "cancelled", func() (bool, error) { return models.CancelWorkOrder(order.WorkOrderID) })
+}
+
+// transitionWorkOrder applies a status transition and responds with the
+// updated work order. transition reports false when the work order changed
+// status in the meantime.
+func transitionWorkOrder(w http.ResponseWriter, r *http.Request, workOrderID int, action string, transition func() (bool, error)) {
+	errTransition := apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to update work order")
+	ok, err := transition()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error updating work order", "work_order_id", workOrderID, "action", action, "error", err)
+		apierror.Write(w, r, errTransition)
+		return
+	}
+	if !ok {
+		apierror.Write(w, r, apierror.New(http.StatusConflict, CodeWorkOrderStatus, "The work order changed status in the meantime"))
+		return
+	}
+	order, err := models.GetWorkOrderByID(workOrderID)
+	if err != nil || order == nil {
+		logging.FromContext(r.Context()).Error("Error fetching work order", "work_order_id", workOrderID, "error", err)
+		apierror.Write(w, r, errTransition)
+		return
+	}
+	logging.FromContext(r.Context()).Info("Work order "+action, "work_order_id", workOrderID, "vehicle_id", order.VehicleID, "status", order.Status)
+	writeWorkOrder(w, http.StatusOK, "Work order "+action+" successfully", order)
+}
+
+// GetServiceDue lists the vehicles whose maintenance falls due within the
+// configured distance, most urgent first, with the work order planned for it
+// if any. It is an admin route.
+func GetServiceDue(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	errFetch := apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch the vehicles due for service")
+	vehicles, err := models.GetFleet()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching vehicles", "error", err)
+		apierror.Write(w, r, errFetch)
+		return
+	}
+	orders, err := models.GetWorkOrders(0, "")
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching work orders", "error", err)
+		apierror.Write(w, r, errFetch)
+		return
+	}
+	due := maintenanceSettings.DueList(vehicles, orders)
+	if due == nil {
+		due = []maintenance.Due{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Vehicles due for service fetched successfully",
+		"data":    due,
+	})
+}
@@ -0,0 +1,318 @@
+package controllers
+
+import (
+	"bytes"
+	"car_system/common/apierror"
+	"car_system/vehicle_service/booking"
+	"car_system/vehicle_service/maintenance"
+	"car_system/vehicle_service/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func TestWorkOrders(t *testing.T) {
+	setupTest(t)
+	UseAdminToken("ops-token")
+	defer UseAdminToken("")
+	UseMaintenance(maintenance.Settings{ServiceIntervalKM: 15000, DueWithinKM: 3000})
+	defer UseMaintenance(maintenance.Settings{})
+	now := time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC)
+	UseClock(func() time.Time { return now })
+	defer UseClock(nil)
+
+	send := func(handler http.HandlerFunc, method, url, id, token, body string) *httptest.ResponseRecorder {
+		req := mux.SetURLVars(httptest.NewRequest(method, url, bytes.NewBufferString(body)), map[string]string{"id": id})
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+	workOrder := func(rec *httptest.ResponseRecorder) models.WorkOrder {
+		t.Helper()
+		var body struct {
+			Data models.WorkOrder `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("response is not JSON: %v (%q)", err, rec.Body.String())
+		}
+		return body.Data
+	}
+	due := func() []maintenance.Due {
+		t.Helper()
+		rec := send(GetServiceDue, "GET", "/v1/maintenance/due", "", "ops-token", "")
+		var body struct {
+			Data []maintenance.Due `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("service due: got %d %s", rec.Code, rec.Body.String())
+		}
+		return body.Data
+	}
+	service := `{"vehicle_id": 1, "type": "service", "planned_start": "2030-01-01T13:00:00Z", "planned_end": "2030-01-01T15:00:00Z", "notes": "Annual service"}`
+
+	for _, token := range []string{"", "wrong-token"} {
+		if rec := send(CreateWorkOrder, "POST", "/v1/work-orders", "", token, service); rec.Code != http.StatusUnauthorized {
+			t.Errorf("token %q: got %d, want 401", token, rec.Code)
+		}
+	}
+	if rec := send(GetServiceDue, "GET", "/v1/maintenance/due", "", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("service due without a token: got %d, want 401", rec.Code)
+	}
+	for _, tc := range []struct {
+		body     string
+		wantCode string
+	}{
+		{`{"vehicle_id": 1, "type": "wash", "planned_start": "2030-01-01T13:00:00Z", "planned_end": "2030-01-01T15:00:00Z"}`, apierror.CodeValidationFailed},
+		{`{"vehicle_id": 1, "type": "repair", "planned_start": "2030-01-01T13:00:00Z", "planned_end": "2030-01-01T15:00:00Z", "mileage_trigger": -1}`, apierror.CodeValidationFailed},
+		{`{"type": "repair", "planned_start": "2030-01-01T13:00:00Z", "planned_end": "2030-01-01T15:00:00Z"}`, apierror.CodeValidationFailed},
+		{`{"vehicle_id": 1, "type": "repair", "planned_start": "2029-12-31T13:00:00Z", "planned_end": "2029-12-31T15:00:00Z"}`, apierror.CodeValidationFailed},
+		{`{"vehicle_id": 1, "type": "repair", "planned_start": "2030-01-01T15:00:00Z", "planned_end": "2030-01-01T13:00:00Z"}`, CodeInvalidTimeRange},
+	} {
+		if rec := send(CreateWorkOrder, "POST", "/v1/work-orders", "", "ops-token", tc.body); rec.Code != http.StatusBadRequest || errorCode(t, rec) != tc.wantCode {
+			t.Errorf("%s: got %d %s, want 400 %s", tc.body, rec.Code, rec.Body.String(), tc.wantCode)
+		}
+	}
+	unknown := `{"vehicle_id": 99, "type": "repair", "planned_start": "2030-01-01T13:00:00Z", "planned_end": "2030-01-01T15:00:00Z"}`
+	if rec := send(CreateWorkOrder, "POST", "/v1/work-orders", "", "ops-token", unknown); rec.Code != http.StatusNotFound || errorCode(t, rec) != CodeVehicleNotFound {
+		t.Errorf("unknown vehicle: got %d %s, want 404", rec.Code, rec.Body.String())
+	}
+
+	// Maintenance cannot be planned over a reservation
+	postReservation(t, `{"vehicle_id":1,"user_id":7,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z"}`)
+	overlap := `{"vehicle_id": 1, "type": "repair", "planned_start": "2030-01-01T11:00:00Z", "planned_end": "2030-01-01T13:00:00Z"}`
+	if rec := send(CreateWorkOrder, "POST", "/v1/work-orders", "", "ops-token", overlap); rec.Code != http.StatusConflict || errorCode(t, rec) != CodeVehicleUnavailable {
+		t.Errorf("over a reservation: got %d %s, want 409", rec.Code, rec.Body.String())
+	}
+
+	// The service of vehicle 1 falls due at its first interval
+	rec := send(CreateWorkOrder, "POST", "/v1/work-orders", "", "ops-token", service)
+	created := workOrder(rec)
+	if rec.Code != http.StatusCreated || created.WorkOrderID != 1 || created.Status != models.WorkOrderScheduled || created.MileageTrigger == nil || *created.MileageTrigger != 15000 {
+		t.Fatalf("create: got %d %s, want a Scheduled service due at 15000 km", rec.Code, rec.Body.String())
+	}
+	if rec := send(CreateWorkOrder, "POST", "/v1/work-orders", "", "ops-token", service); rec.Code != http.StatusConflict || errorCode(t, rec) != CodeVehicleUnavailable {
+		t.Errorf("over another work order: got %d %s, want 409", rec.Code, rec.Body.String())
+	}
+	if d := due(); len(d) != 1 || d[0].VehicleID != 1 || d[0].RemainingKM != 3000 || d[0].WorkOrderID == nil || *d[0].WorkOrderID != 1 {
+		t.Errorf("service due = %+v, want vehicle 1 with its work order", d)
+	}
+
+	// The planned window blocks reservations and shows in the calendar
+	if rec := postReservation(t, `{"vehicle_id":1,"user_id":8,"start_time":"2030-01-01T14:00:00Z","end_time":"2030-01-01T16:00:00Z"}`); rec.Code != http.StatusConflict {
+		t.Errorf("reservation during the maintenance: got %d %s, want 409", rec.Code, rec.Body.String())
+	}
+	if rec := postReservation(t, `{"vehicle_id":1,"user_id":8,"start_time":"2030-01-01T16:00:00Z","end_time":"2030-01-01T17:00:00Z"}`); rec.Code != http.StatusOK {
+		t.Errorf("reservation after the maintenance: got %d %s, want 200", rec.Code, rec.Body.String())
+	}
+	rec = send(GetVehicleCalendar, "GET", "/v1/vehicles/1/calendar?from=2030-01-01T00:00:00Z&to=2030-01-02T00:00:00Z", "1", "", "")
+	var calendar struct {
+		Data struct {
+			Entries []models.CalendarEntry `json:"entries"`
+		} `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &calendar)
+	var kinds []string
+	for _, e := range calendar.Data.Entries {
+		kinds = append(kinds, fmt.Sprintf("%s %s-%s", e.Kind, e.StartTime.Format("15:04"), e.EndTime.Format("15:04")))
+	}
+	if want := "[reservation 10:00-12:00 maintenance 13:00-15:00 reservation 16:00-17:00]"; fmt.Sprint(kinds) != want || calendar.Data.Entries[1].WorkOrderID != 1 {
+		t.Errorf("calendar = %v, want %s", kinds, want)
+	}
+
+	// Started early, the work would overlap the reservation at 10:00
+	if rec := send(StartWorkOrder, "POST", "/v1/work-orders/1/start", "1", "ops-token", ""); rec.Code != http.StatusConflict || errorCode(t, rec) != CodeVehicleUnavailable {
+		t.Errorf("early start: got %d %s, want 409", rec.Code, rec.Body.String())
+	}
+	now = time.Date(2030, 1, 1, 12, 30, 0, 0, time.UTC)
+	rec = send(StartWorkOrder, "POST", "/v1/work-orders/1/start", "1", "ops-token", "")
+	if started := workOrder(rec); rec.Code != http.StatusOK || started.Status != models.WorkOrderInProgress || !started.PlannedStart.Equal(now) || started.StartedAt == nil {
+		t.Errorf("start: got %d %s, want it In Progress from 12:30", rec.Code, rec.Body.String())
+	}
+	if v, _ := models.GetVehicleByID(1); v.Status != models.VehicleUnderMaintenance {
+		t.Errorf("vehicle 1 is %q, want it Under Maintenance", v.Status)
+	}
+	rec = send(GetAvailableVehicles, "GET", "/v1/vehicles", "", "", "")
+	var list struct {
+		Vehicles []models.Vehicle `json:"vehicles"`
+	}
+	if json.Unmarshal(rec.Body.Bytes(), &list); len(list.Vehicles) != 4 || list.Vehicles[0].VehicleID != 2 {
+		t.Errorf("available vehicles = %d, want 4 without vehicle 1", len(list.Vehicles))
+	}
+	if rec := send(CancelWorkOrder, "POST", "/v1/work-orders/1/cancel", "1", "ops-token", ""); rec.Code != http.StatusConflict || errorCode(t, rec) != CodeWorkOrderStatus {
+		t.Errorf("cancel In Progress: got %d %s, want 409", rec.Code, rec.Body.String())
+	}
+
+	// The work overruns: its end moves, but not its start
+	overrun := `{"type": "service", "planned_start": "2030-01-01T12:30:00Z", "planned_end": "2030-01-01T15:30:00Z", "mileage_trigger": 15000, "notes": "Brake pads on back order"}`
+	if rec := send(UpdateWorkOrder, "PUT", "/v1/work-orders/1", "1", "ops-token", overrun); rec.Code != http.StatusOK || !workOrder(rec).PlannedEnd.Equal(time.Date(2030, 1, 1, 15, 30, 0, 0, time.UTC)) {
+		t.Errorf("update: got %d %s, want the window extended", rec.Code, rec.Body.String())
+	}
+	moved := `{"type": "service", "planned_start": "2030-01-01T13:00:00Z", "planned_end": "2030-01-01T15:30:00Z"}`
+	if rec := send(UpdateWorkOrder, "PUT", "/v1/work-orders/1", "1", "ops-token", moved); rec.Code != http.StatusBadRequest {
+		t.Errorf("move the start of started work: got %d %s, want 400", rec.Code, rec.Body.String())
+	}
+	tooLong := `{"type": "service", "planned_start": "2030-01-01T12:30:00Z", "planned_end": "2030-01-01T16:30:00Z"}`
+	if rec := send(UpdateWorkOrder, "PUT", "/v1/work-orders/1", "1", "ops-token", tooLong); rec.Code != http.StatusConflict || errorCode(t, rec) != CodeVehicleUnavailable {
+		t.Errorf("overrun into a reservation: got %d %s, want 409", rec.Code, rec.Body.String())
+	}
+
+	now = time.Date(2030, 1, 1, 15, 0, 0, 0, time.UTC)
+	rec = send(CompleteWorkOrder, "POST", "/v1/work-orders/1/complete", "1", "ops-token", `{"notes": "Brake pads replaced"}`)
+	completed := workOrder(rec)
+	if rec.Code != http.StatusOK || completed.Status != models.WorkOrderCompleted || completed.CompletedMileage == nil || *completed.CompletedMileage != 12000 ||
+		completed.CompletionNotes != "Brake pads replaced" || !completed.CompletedAt.Equal(now) {
+		t.Errorf("complete: got %d %s, want it Completed at 12000 km", rec.Code, rec.Body.String())
+	}
+	if v, _ := models.GetVehicleByID(1); v.Status != models.VehicleOperational {
+		t.Errorf("vehicle 1 is %q, want it Operational", v.Status)
+	}
+	if rec := send(CompleteWorkOrder, "POST", "/v1/work-orders/1/complete", "1", "ops-token", ""); rec.Code != http.StatusConflict || errorCode(t, rec) != CodeWorkOrderStatus {
+		t.Errorf("complete twice: got %d %s, want 409", rec.Code, rec.Body.String())
+	}
+	if rec := send(UpdateWorkOrder, "PUT", "/v1/work-orders/1", "1", "ops-token", overrun); rec.Code != http.StatusConflict || errorCode(t, rec) != CodeWorkOrderStatus {
+		t.Errorf("update a completed work order: got %d %s, want 409", rec.Code, rec.Body.String())
+	}
+	// The next service of vehicle 1 is counted from the one just done
+	if d := due(); len(d) != 0 {
+		t.Errorf("service due = %+v, want none", d)
+	}
+
+	// A cancelled work order frees its window
+	tires := `{"vehicle_id": 2, "type": "tires", "planned_start": "2030-01-02T09:00:00Z", "planned_end": "2030-01-02T10:00:00Z"}`
+	cancelled := workOrder(send(CreateWorkOrder, "POST", "/v1/work-orders", "", "ops-token", tires))
+	if cancelled.MileageTrigger != nil {
+		t.Errorf("tires = %+v, want no mileage trigger", cancelled)
+	}
+	if rec := send(CancelWorkOrder, "POST", "/v1/work-orders/2/cancel", "2", "ops-token", ""); rec.Code != http.StatusOK || workOrder(rec).Status != models.WorkOrderCancelled {
+		t.Errorf("cancel: got %d %s, want it Cancelled", rec.Code, rec.Body.String())
+	}
+	if rec := postReservation(t, `{"vehicle_id":2,"user_id":8,"start_time":"2030-01-02T09:00:00Z","end_time":"2030-01-02T10:00:00Z"}`); rec.Code != http.StatusOK {
+		t.Errorf("reservation in the cancelled window: got %d %s, want 200", rec.Code, rec.Body.String())
+	}
+	if rec := send(StartWorkOrder, "POST", "/v1/work-orders/2/start", "2", "ops-token", ""); rec.Code != http.StatusConflict || errorCode(t, rec) != CodeWorkOrderStatus {
+		t.Errorf("start a cancelled work order: got %d %s, want 409", rec.Code, rec.Body.String())
+	}
+
+	rec = send(ListWorkOrders, "GET", "/v1/work-orders?vehicle_id=1&status=Completed", "", "ops-token", "")
+	var orders struct {
+		Data []models.WorkOrder `json:"data"`
+	}
+	if json.Unmarshal(rec.Body.Bytes(), &orders); rec.Code != http.StatusOK || len(orders.Data) != 1 || orders.Data[0].WorkOrderID != 1 {
+		t.Errorf("list: got %d %s, want work order 1", rec.Code, rec.Body.String())
+	}
+	if rec := send(ListWorkOrders, "GET", "/v1/work-orders?status=Done", "", "ops-token", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("list in an unknown status: got %d, want 400", rec.Code)
+	}
+	if rec := send(GetWorkOrder, "GET", "/v1/work-orders/99", "99", "ops-token", ""); rec.Code != http.StatusNotFound || errorCode(t, rec) != CodeWorkOrderNotFound {
+		t.Errorf("unknown work order: got %d %s, want 404", rec.Code, rec.Body.String())
+	}
+}
+
+func TestWorkOrderOnDecommissionedVehicle(t *testing.T) {
+	memory := setupTest(t)
+	UseAdminToken("ops-token")
+	defer UseAdminToken("")
+	UseClock(func() time.Time { return time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC) })
+	defer UseClock(nil)
+	retired := memory.AddVehicle(models.Vehicle{LicensePlate: "OLD001", Model: "Nissan Leaf", Location: "Suburban Hub", Status: models.VehicleDecommissioned})
+
+	body := fmt.Sprintf(`{"vehicle_id": %d, "type": "repair", "planned_start": "2030-01-01T13:00:00Z", "planned_end": "2030-01-01T15:00:00Z"}`, retired)
+	req := httptest.NewRequest("POST", "/v1/work-orders", bytes.NewBufferString(body))
+	req.Header.Set("Authorization", "Bearer ops-token")
+	rec := httptest.NewRecorder()
+	CreateWorkOrder(rec, req)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != CodeVehicleUnavailable {
+		t.Errorf("work order on a decommissioned vehicle: got %d %s, want 409 %s", rec.Code, rec.Body.String(), CodeVehicleUnavailable)
+	}
+	if orders, _ := models.GetWorkOrders(retired, ""); len(orders) != 0 {
+		t.Errorf("work orders = %+v, want none", orders)
+	}
+}
+
+func TestAvailabilityAroundMaintenance(t *testing.T) {
+	memory := setupTest(t)
+	UseBookingPolicy(booking.Policy{Turnaround: booking.Turnaround{Default: 30 * time.Minute}})
+	defer UseBookingPolicy(booking.Policy{})
+	now := time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC)
+	UseClock(func() time.Time { return now })
+	defer UseClock(nil)
+
+	order := models.WorkOrder{VehicleID: 1, Type: models.WorkRepair, PlannedStart: time.Date(2030, 1, 1, 13, 0, 0, 0, time.UTC), PlannedEnd: time.Date(2030, 1, 1, 15, 0, 0, 0, time.UTC)}
+	if err := models.CreateWorkOrder(&order); err != nil {
+		t.Fatal(err)
+	}
+	// The vehicle needs its turnaround before and after the maintenance too
+	for _, tc := range []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{"ending within the turnaround before the maintenance", `{"vehicle_id":1,"user_id":7,"start_time":"2030-01-01T11:00:00Z","end_time":"2030-01-01T12:45:00Z"}`, http.StatusConflict},
+		{"inside the turnaround after the maintenance", `{"vehicle_id":1,"user_id":7,"start_time":"2030-01-01T15:15:00Z","end_time":"2030-01-01T16:00:00Z"}`, http.StatusConflict},
+		{"after the turnaround", `{"vehicle_id":1,"user_id":7,"start_time":"2030-01-01T15:30:00Z","end_time":"2030-01-01T16:30:00Z"}`, http.StatusOK},
+	} {
+		if rec := postReservation(t, tc.body); rec.Code != tc.wantCode {
+			t.Errorf("%s: got %d %s, want %d", tc.name, rec.Code, rec.Body.String(), tc.wantCode)
+		}
+	}
+	rec := httptest.NewRecorder()
+	GetVehicleCalendar(rec, mux.SetURLVars(httptest.NewRequest("GET", "/v1/vehicles/1/calendar?from=2030-01-01T00:00:00Z&to=2030-01-02T00:00:00Z", nil), map[string]string{"id": "1"}))
+	var calendar struct {
+		Data struct {
+			Entries []models.CalendarEntry `json:"entries"`
+		} `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &calendar)
+	var kinds []string
+	for _, e := range calendar.Data.Entries {
+		kinds = append(kinds, fmt.Sprintf("%s %s-%s", e.Kind, e.StartTime.Format("15:04"), e.EndTime.Format("15:04")))
+	}
+	if want := "[maintenance 13:00-15:00 turnaround 15:00-15:30 reservation 15:30-16:30 turnaround 16:30-17:00]"; fmt.Sprint(kinds) != want {
+		t.Errorf("calendar = %v, want %s", kinds, want)
+	}
+
+	// Vehicles under maintenance without work in progress, and those
+	// decommissioned, cannot be reserved at all, and their calendar says so
+	for status, kind := range map[string]string{models.VehicleUnderMaintenance: "maintenance", models.VehicleDecommissioned: "decommissioned"} {
+		id := memory.AddVehicle(models.Vehicle{LicensePlate: "OUT001", Model: "Nissan Leaf", Location: "Suburban Hub", Status: status})
+		body := fmt.Sprintf(`{"vehicle_id":%d,"user_id":7,"start_time":"2030-02-01T10:00:00Z","end_time":"2030-02-01T12:00:00Z"}`, id)
+		if rec := postReservation(t, body); rec.Code != http.StatusConflict || errorCode(t, rec) != CodeVehicleUnavailable {
+			t.Errorf("%s vehicle: got %d %s, want 409 %s", status, rec.Code, rec.Body.String(), CodeVehicleUnavailable)
+		}
+		rec := httptest.NewRecorder()
+		GetVehicleCalendar(rec, mux.SetURLVars(httptest.NewRequest("GET", "/v1/vehicles/"+fmt.Sprint(id)+"/calendar?from=2030-02-01T00:00:00Z&to=2030-02-02T00:00:00Z", nil), map[string]string{"id": fmt.Sprint(id)}))
+		calendar.Data.Entries = nil
+		json.Unmarshal(rec.Body.Bytes(), &calendar)
+		if entries := calendar.Data.Entries; len(entries) != 1 || entries[0].Kind != kind || entries[0].StartTime.Day() != 1 || entries[0].EndTime.Day() != 2 {
+			t.Errorf("calendar of a %s vehicle = %+v, want one %s entry for the whole day", status, entries, kind)
+		}
+	}
+
+	// Overrunning work keeps the vehicle until its planned end is moved
+	overrun := models.WorkOrder{VehicleID: 2, Type: models.WorkRepair, PlannedStart: time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC), PlannedEnd: time.Date(2030, 1, 1, 13, 0, 0, 0, time.UTC)}
+	if err := models.CreateWorkOrder(&overrun); err != nil {
+		t.Fatal(err)
+	}
+	now = time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	if ok, err := models.StartWorkOrder(overrun.WorkOrderID, now); !ok || err != nil {
+		t.Fatalf("StartWorkOrder = %v, %v", ok, err)
+	}
+	overrun.PlannedEnd = time.Date(2030, 1, 1, 18, 0, 0, 0, time.UTC)
+	if ok, err := models.UpdateWorkOrder(&overrun); !ok || err != nil {
+		t.Fatalf("UpdateWorkOrder = %v, %v", ok, err)
+	}
+	if rec := postReservation(t, `{"vehicle_id":2,"user_id":8,"start_time":"2030-01-01T18:15:00Z","end_time":"2030-01-01T19:00:00Z"}`); rec.Code != http.StatusConflict {
+		t.Errorf("within the turnaround of the work in progress: got %d %s, want 409", rec.Code, rec.Body.String())
+	}
+	if rec := postReservation(t, `{"vehicle_id":2,"user_id":8,"start_time":"2030-01-01T18:30:00Z","end_time":"2030-01-01T19:00:00Z"}`); rec.Code != http.StatusOK {
+		t.Errorf("after the work in progress: got %d %s, want 200", rec.Code, rec.Body.String())
+	}
+}
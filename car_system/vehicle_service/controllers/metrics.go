@@ -0,0 +1,27 @@
+package controllers
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// reservationsTotal counts reservation attempts by their result
+var reservationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "vehicle_reservations_total",
+	Help: "Reservation attempts by result (created, conflict, rejected, invalid, error).",
+}, []string{"result"})
+
+// telemetryReadingsTotal counts the reported telemetry readings by their result
+var telemetryReadingsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "vehicle_telemetry_readings_total",
+	Help: "Telemetry readings by result (accepted, invalid, unauthorized, error).",
+}, []string{"result"})
+
+// zoneExitsTotal counts the service areas left by vehicles during a
+// reservation
+var zoneExitsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "vehicle_zone_exits_total",
+	Help: "Service areas left by vehicles during a reservation.",
+})
+
+// RegisterMetrics registers the vehicle_service domain metrics
+func RegisterMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(reservationsTotal, telemetryReadingsTotal, zoneExitsTotal)
+}
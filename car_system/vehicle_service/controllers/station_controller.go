@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"car_system/common/apierror"
+	"car_system/common/logging"
+	"car_system/vehicle_service/geo"
+	"car_system/vehicle_service/models"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// defaultRadiusKM is the radius of a nearby search without radius_km, and
+// maxRadiusKM the largest one accepted
+const (
+	defaultRadiusKM = 5.0
+	maxRadiusKM     = 50.0
+)
+
+// ListStations returns every station
+func ListStations(w http.ResponseWriter, r *http.Request) {
+	stations, err := models.GetStations()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching stations", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch stations"))
+		return
+	}
+	if stations == nil {
+		stations = []models.Station{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Stations fetched successfully",
+		"data":    stations,
+	})
+}
+
+// floatParam parses the query parameter name as a number, or returns def
+// when it is not given
+func floatParam(r *http.Request, name string, def float64, v *apierror.Validation) float64 {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	v.Check(err == nil, name, "must be a number")
+	return f
+}
+
+// FindNearbyStations returns the stations within radius_km of the latitude
+// and longitude query parameters and the available vehicles around them,
+// nearest first. The start_time, end_time and distance_km parameters filter
+// the vehicles as on GetAvailableVehicles, and start_time is when the
+// stations must be open.
+func FindNearbyStations(w http.ResponseWriter, r *http.Request) {
+	var v apierror.Validation
+	query := r.URL.Query()
+	v.Check(query.Get("latitude") != "", "latitude", "is required")
+	v.Check(query.Get("longitude") != "", "longitude", "is required")
+	p := geo.Point{Lat: floatParam(r, "latitude", 0, &v), Lon: floatParam(r, "longitude", 0, &v)}
+	v.Check(p.Lat >= -90 && p.Lat <= 90, "latitude", "must be between -90 and 90")
+	v.Check(p.Lon >= -180 && p.Lon <= 180, "longitude", "must be between -180 and 180")
+	radius := floatParam(r, "radius_km", defaultRadiusKM, &v)
+	v.Check(radius > 0 && radius <= maxRadiusKM, "radius_km", "must be greater than 0 and at most 50")
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	vehicles, start, ok := searchVehicles(w, r)
+	if !ok {
+		return
+	}
+	stations, err := models.GetStations()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching stations", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch stations"))
+		return
+	}
+
+	if start.IsZero() {
+		start = clock()
+	}
+	nearbyVehicles := models.VehiclesNear(vehicles, stations, p, radius)
+	parked := make(map[int]int)
+	for _, vehicle := range vehicles {
+		parked[vehicle.StationID]++
+	}
+	nearbyStations := models.StationsNear(stations, p, radius)
+	for i := range nearbyStations {
+		nearbyStations[i].Open = nearbyStations[i].OpenAt(start, bookingPolicy.Zone())
+		nearbyStations[i].AvailableVehicles = parked[nearbyStations[i].StationID]
+	}
+	if nearbyStations == nil {
+		nearbyStations = []models.NearbyStation{}
+	}
+	if nearbyVehicles == nil {
+		nearbyVehicles = []models.Vehicle{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Nearby stations fetched successfully",
+		"data": map[string]interface{}{
+			"latitude":  p.Lat,
+			"longitude": p.Lon,
+			"radius_km": radius,
+			"stations":  nearbyStations,
+			"vehicles":  nearbyVehicles,
+		},
+	})
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"car_system/common/apierror"
+	"car_system/vehicle_service/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFindNearbyStations(t *testing.T) {
+	setupTest(t)
+
+	search := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		FindNearbyStations(rec, httptest.NewRequest("GET", "/v1/stations/nearby?"+query, nil))
+		return rec
+	}
+
+	for _, query := range []string{"", "latitude=1.28", "latitude=north&longitude=103.85", "latitude=1.28&longitude=200", "latitude=1.28&longitude=103.85&radius_km=80"} {
+		if rec := search(query); rec.Code != http.StatusBadRequest || errorCode(t, rec) != apierror.CodeValidationFailed {
+			t.Errorf("%q: got %d %s, want 400", query, rec.Code, rec.Body.String())
+		}
+	}
+
+	// Next to Downtown Station, late in the evening: City Center is
+	// 1.5 km away but closed, Train Station 2.8 km away and open
+	rec := search("latitude=1.2800&longitude=103.8525&start_time=2030-01-07T23:30:00Z&end_time=2030-01-08T08:00:00Z")
+	var body struct {
+		Data struct {
+			Stations []models.NearbyStation `json:"stations"`
+			Vehicles []models.Vehicle       `json:"vehicles"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("got %d %s, want 200", rec.Code, rec.Body.String())
+	}
+	var names []string
+	for _, s := range body.Data.Stations {
+		names = append(names, s.Name)
+	}
+	if len(names) != 3 || names[0] != "Downtown Station" || names[1] != "City Center" || names[2] != "Train Station" {
+		t.Fatalf("stations = %v, want Downtown Station, City Center and Train Station", names)
+	}
+	if s := body.Data.Stations; !s[0].Open || s[1].Open || !s[2].Open || s[0].AvailableVehicles != 1 || s[0].DistanceKM > 0.1 {
+		t.Errorf("stations = %+v, want City Center closed and one vehicle at Downtown Station", s)
+	}
+	if v := body.Data.Vehicles; len(v) != 3 || v[0].Location != "Downtown Station" || v[0].DistanceKM == nil || *v[2].DistanceKM < *v[1].DistanceKM {
+		t.Errorf("vehicles = %+v, want the 3 vehicles of those stations, nearest first", v)
+	}
+
+	rec = search("latitude=48.8566&longitude=2.3522&radius_km=50")
+	body.Data.Stations, body.Data.Vehicles = nil, nil
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("far away: got %d %s, want 200", rec.Code, rec.Body.String())
+	}
+	if body.Data.Stations == nil || len(body.Data.Stations) != 0 || len(body.Data.Vehicles) != 0 {
+		t.Errorf("far away: got %s, want empty lists", rec.Body.String())
+	}
+}
+
+func TestStationOpenAt(t *testing.T) {
+	setupTest(t)
+	stations, err := models.GetStations()
+	if err != nil || len(stations) != 5 {
+		t.Fatalf("GetStations = %d stations, %v; want the 5 sample stations", len(stations), err)
+	}
+	train := stations[4]
+	for hour, want := range map[string]bool{"05:00": false, "05:30": true, "23:59": true, "00:15": true, "00:30": false} {
+		at, _ := time.Parse("2006-01-02 15:04", "2030-01-07 "+hour)
+		if got := train.OpenAt(at, time.UTC); got != want {
+			t.Errorf("%s open at %s = %v, want %v", train.Name, hour, got, want)
+		}
+	}
+	if _, _, err := models.ParseOpeningHours("7am-11pm"); err == nil {
+		t.Error("ParseOpeningHours accepted 7am-11pm")
+	}
+}
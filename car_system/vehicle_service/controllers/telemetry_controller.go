@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"car_system/common/apierror"
+	"car_system/common/logging"
+	"car_system/vehicle_service/models"
+	"car_system/vehicle_service/telemetry"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// telemetrySettings authenticates vehicle reports and bounds their age
+var telemetrySettings telemetry.Settings
+
+// UseTelemetry sets the secret the vehicle tokens are derived from and the
+// retention checked by IngestTelemetry
+func UseTelemetry(s telemetry.Settings) {
+	telemetrySettings = s
+}
+
+// maxTelemetryBatch is the most readings accepted in one report, and
+// maxClockSkew how far in the future a reading may be recorded
+const (
+	maxTelemetryBatch = 500
+	maxClockSkew      = 5 * time.Minute
+)
+
+// telemetrySpan is the length of the history returned without a from
+// parameter
+const telemetrySpan = 24 * time.Hour
+
+var errTelemetryToken = apierror.New(http.StatusUnauthorized, apierror.CodeUnauthorized, "Missing or invalid telemetry token")
+
+// vehicleIDVar parses the {id} path variable
+func vehicleIDVar(w http.ResponseWriter, r *http.Request) (int, bool) {
+	vehicleID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || vehicleID <= 0 {
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Invalid Vehicle ID").WithField("id", "must be a positive integer"))
+		return 0, false
+	}
+	return vehicleID, true
+}
+
+// IngestTelemetry stores a batch of readings reported by the vehicle {id}
+// and updates the vehicle from the latest one. Readings that leave a service
+// area during a reservation are recorded as zone exits. The vehicle
+// authenticates with its token in an Authorization: Bearer header.
+func IngestTelemetry(w http.ResponseWriter, r *http.Request) {
+	vehicleID, ok := vehicleIDVar(w, r)
+	if !ok {
+		return
+	}
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !telemetrySettings.Verify(vehicleID, token) {
+		telemetryReadingsTotal.WithLabelValues("unauthorized").Inc()
+		logging.FromContext(r.Context()).Warn("Rejected telemetry without a valid token", "vehicle_id", vehicleID, "token_sent", token != "")
+		w.Header().Set("WWW-Authenticate", `Bearer realm="telemetry"`)
+		apierror.Write(w, r, errTelemetryToken)
+		return
+	}
+
+	var request struct {
+		Readings []models.TelemetryReading `json:"readings"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		telemetryReadingsTotal.WithLabelValues("invalid").Inc()
+		apierror.Write(w, r, apierror.ErrInvalidJSON)
+		return
+	}
+
+	var v apierror.Validation
+	v.Check(len(request.Readings) > 0, "readings", "must hold at least one reading")
+	v.Check(len(request.Readings) <= maxTelemetryBatch, "readings", fmt.Sprintf("must hold at most %d readings", maxTelemetryBatch))
+	now := clock()
+	for i := range request.Readings {
+		reading := &request.Readings[i]
+		reading.VehicleID = vehicleID
+		field := func(name string) string { return fmt.Sprintf("readings[%d].%s", i, name) }
+		v.Check(!reading.RecordedAt.IsZero(), field("recorded_at"), "is required")
+		v.Check(!reading.RecordedAt.After(now.Add(maxClockSkew)), field("recorded_at"), "must not be in the future")
+		if telemetrySettings.RawRetention > 0 {
+			v.Check(!reading.RecordedAt.Before(now.Add(-telemetrySettings.RawRetention)), field("recorded_at"), "is older than the raw retention")
+		}
+		v.Check(reading.Latitude >= -90 && reading.Latitude <= 90, field("latitude"), "must be between -90 and 90")
+		v.Check(reading.Longitude >= -180 && reading.Longitude <= 180, field("longitude"), "must be between -180 and 180")
+		v.Check(reading.ChargeLevel >= 0 && reading.ChargeLevel <= 100, field("charge_level"), "must be between 0 and 100")
+		v.Check(reading.OdometerKM >= 0, field("odometer_km"), "must not be negative")
+		v.Check(reading.SpeedKMH >= 0, field("speed_kmh"), "must not be negative")
+	}
+	if err := v.Err(); err != nil {
+		telemetryReadingsTotal.WithLabelValues("invalid").Add(float64(max(len(request.Readings), 1)))
+		apierror.Write(w, r, err)
+		return
+	}
+
+	vehicle, err := models.GetVehicleByID(vehicleID)
+	if err != nil {
+		telemetryReadingsTotal.WithLabelValues("error").Add(float64(len(request.Readings)))
+		logging.FromContext(r.Context()).Error("Error fetching vehicle", "vehicle_id", vehicleID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to store telemetry"))
+		return
+	}
+	if vehicle == nil {
+		telemetryReadingsTotal.WithLabelValues("invalid").Add(float64(len(request.Readings)))
+		apierror.Write(w, r, errVehicleNotFound)
+		return
+	}
+
+	if err := models.RecordTelemetry(request.Readings); err != nil {
+		telemetryReadingsTotal.WithLabelValues("error").Add(float64(len(request.Readings)))
+		logging.FromContext(r.Context()).Error("Error storing telemetry", "vehicle_id", vehicleID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to store telemetry"))
+		return
+	}
+
+	detectZoneExits(r, *vehicle, request.Readings)
+
+	telemetryReadingsTotal.WithLabelValues("accepted").Add(float64(len(request.Readings)))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Telemetry accepted",
+		"accepted": len(request.Readings),
+	})
+}
+
+// GetTelemetry returns the telemetry of the vehicle {id} between the from
+// and to query parameters, by default the last 24 hours: the raw readings,
+// and the rollups of the part that has been downsampled
+func GetTelemetry(w http.ResponseWriter, r *http.Request) {
+	vehicleID, ok := vehicleIDVar(w, r)
+	if !ok {
+		return
+	}
+	var v apierror.Validation
+	from, to := timeParam(r, "from", &v), timeParam(r, "to", &v)
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+	if to.IsZero() {
+		to = clock().UTC()
+	}
+	if from.IsZero() {
+		from = to.Add(-telemetrySpan)
+	}
+	if !from.Before(to) {
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, CodeInvalidTimeRange, "to must be after from").WithField("to", "is not after from"))
+		return
+	}
+
+	vehicle, err := models.GetVehicleByID(vehicleID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching vehicle", "vehicle_id", vehicleID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch telemetry"))
+		return
+	}
+	if vehicle == nil {
+		apierror.Write(w, r, errVehicleNotFound)
+		return
+	}
+
+	readings, rollups, err := models.GetTelemetry(vehicleID, from, to)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching telemetry", "vehicle_id", vehicleID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch telemetry"))
+		return
+	}
+	if readings == nil {
+		readings = []models.TelemetryReading{}
+	}
+	if rollups == nil {
+		rollups = []models.TelemetryRollup{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Vehicle telemetry fetched successfully",
+		"data": map[string]interface{}{
+			"vehicle_id": vehicleID,
+			"from":       from,
+			"to":         to,
+			"readings":   readings,
+			"rollups":    rollups,
+		},
+	})
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"bytes"
+	"car_system/common/apierror"
+	"car_system/vehicle_service/models"
+	"car_system/vehicle_service/telemetry"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func TestTelemetry(t *testing.T) {
+	setupTest(t)
+	UseTelemetry(telemetry.Settings{Secret: "fleet-secret", RawRetention: 24 * time.Hour})
+	defer UseTelemetry(telemetry.Settings{})
+
+	report := func(vehicleID, token string, readings ...map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{"readings": readings})
+		req := mux.SetURLVars(httptest.NewRequest("POST", "/v1/vehicles/"+vehicleID+"/telemetry", bytes.NewReader(body)), map[string]string{"id": vehicleID})
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		IngestTelemetry(rec, req)
+		return rec
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	reading := func(at time.Time, charge float64) map[string]interface{} {
+		return map[string]interface{}{"recorded_at": at, "latitude": 1.3, "longitude": 103.8, "charge_level": charge, "odometer_km": 12034.6, "locked": true, "speed_kmh": 0}
+	}
+	token := telemetry.TokenFor("fleet-secret", 1)
+
+	if rec := report("1", "", reading(now, 75)); rec.Code != http.StatusUnauthorized {
+		t.Errorf("without a token: got %d, want 401", rec.Code)
+	}
+	if rec := report("2", token, reading(now, 75)); rec.Code != http.StatusUnauthorized {
+		t.Errorf("with the token of another vehicle: got %d, want 401", rec.Code)
+	}
+	rec := report("1", token, reading(now.Add(time.Hour), 75), reading(now.Add(-48*time.Hour), 75), map[string]interface{}{"latitude": 91})
+	if rec.Code != http.StatusBadRequest || errorCode(t, rec) != apierror.CodeValidationFailed {
+		t.Errorf("invalid readings: got %d %s, want 400", rec.Code, rec.Body.String())
+	}
+
+	rec = report("1", token, reading(now.Add(-time.Minute), 75), reading(now, 74), reading(now.Add(-2*time.Minute), 76))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("valid readings: got %d %s, want 202", rec.Code, rec.Body.String())
+	}
+	// An older reading arriving late does not roll the vehicle back
+	report("1", token, reading(now.Add(-10*time.Minute), 90))
+
+	vehicle, _ := models.GetVehicleByID(1)
+	if vehicle.ChargeLevel != 74 || vehicle.Mileage != 12034 || vehicle.Locked == nil || !*vehicle.Locked || vehicle.Latitude == nil || *vehicle.Latitude != 1.3 {
+		t.Errorf("vehicle = %+v, want the state of the latest reading", vehicle)
+	}
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/v1/vehicles/1/telemetry", nil), map[string]string{"id": "1"})
+	rec = httptest.NewRecorder()
+	GetTelemetry(rec, req)
+	var body struct {
+		Data struct {
+			Readings []models.TelemetryReading `json:"readings"`
+			Rollups  []models.TelemetryRollup  `json:"rollups"`
+		} `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusOK || len(body.Data.Readings) != 4 || body.Data.Rollups == nil {
+		t.Errorf("history: got %d %s, want 4 readings and no rollups", rec.Code, rec.Body.String())
+	}
+}
@@ -1,19 +1,166 @@
 package controllers
 
 import (
+	"car_system/common/apierror"
+	"car_system/common/logging"
+	"car_system/vehicle_service/booking"
+	"car_system/vehicle_service/charging"
+	"car_system/vehicle_service/geo"
 	"car_system/vehicle_service/models"
+	"car_system/vehicle_service/trip"
 	"encoding/json"
-	"log"
 	"net/http"
 	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
 )
 
-// GetAvailableVehicles retrieves all vehicles from the database
-func GetAvailableVehicles(w http.ResponseWriter, r *http.Request) {
+// clock returns the current time. It is only replaced to run the service on
+// a virtual clock, as the fleet simulator does.
+var clock = time.Now
+
+// UseClock sets the clock the handlers read the current time from; nil
+// restores the system clock
+func UseClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	clock = now
+}
+
+// bookingPolicy holds the rules every new reservation must satisfy
+var bookingPolicy booking.Policy
+
+// UseBookingPolicy sets the rules checked by CreateReservation
+func UseBookingPolicy(policy booking.Policy) {
+	bookingPolicy = policy
+}
+
+// chargeForecast predicts charge levels at pickup; nil disables forecasts
+var chargeForecast *charging.Forecaster
+
+// UseChargeForecast sets the forecaster used by the search and by
+// CreateReservation to check expected charge levels
+func UseChargeForecast(f *charging.Forecaster) {
+	chargeForecast = f
+}
+
+// rangeEstimator estimates driving ranges; nil disables range estimates
+var rangeEstimator *trip.Estimator
+
+// UseRangeEstimator sets the estimator used by the search and by
+// CreateReservation to check planned distances
+func UseRangeEstimator(e *trip.Estimator) {
+	rangeEstimator = e
+}
+
+// predictCharge sets the PredictedChargeLevel of each vehicle at pickup
+func predictCharge(vehicles []models.Vehicle, pickup time.Time) error {
+	now := clock()
+	for i := range vehicles {
+		reservations, err := models.GetUpcomingReservations(vehicles[i].VehicleID, now)
+		if err != nil {
+			return err
+		}
+		level := chargeForecast.LevelAt(vehicles[i], reservations, now, pickup)
+		vehicles[i].PredictedChargeLevel = &level
+	}
+	return nil
+}
+
+// calendarSpan is the length of the calendar returned without a to parameter,
+// and maxCalendarSpan the longest one that may be requested
+const (
+	calendarSpan    = 7 * 24 * time.Hour
+	maxCalendarSpan = 92 * 24 * time.Hour
+)
+
+// timeParam parses the RFC 3339 query parameter name of r. It returns the
+// zero time when the parameter is absent.
+func timeParam(r *http.Request, name string, v *apierror.Validation) time.Time {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	v.Check(err == nil, name, "must be an RFC 3339 date-time")
+	return t
+}
+
+// estimateRange sets the EstimatedRangeKM of each vehicle, from its predicted
+// charge level when it has one. With a distance it drops the vehicles that
+// cannot drive it.
+func estimateRange(vehicles []models.Vehicle, distance float64) []models.Vehicle {
+	feasible := vehicles[:0]
+	for _, v := range vehicles {
+		level := v.ChargeLevel
+		if v.PredictedChargeLevel != nil {
+			level = *v.PredictedChargeLevel
+		}
+		rangeKM := rangeEstimator.RangeKM(v, level)
+		v.EstimatedRangeKM = &rangeKM
+		if distance == 0 || rangeEstimator.CanDrive(v, level, distance) {
+			feasible = append(feasible, v)
+		}
+	}
+	return feasible
+}
+
+// searchVehicles lists the vehicles that match the start_time, end_time and
+// distance_km query parameters of r, as described on GetAvailableVehicles,
+// with the start of the window. It writes the error response and returns
+// false when the search fails.
+func searchVehicles(w http.ResponseWriter, r *http.Request) ([]models.Vehicle, time.Time, bool) {
+	var v apierror.Validation
+	start, end := timeParam(r, "start_time", &v), timeParam(r, "end_time", &v)
+	v.Check(start.IsZero() == end.IsZero(), "end_time", "must be given together with start_time")
+	var distance float64
+	if value := r.URL.Query().Get("distance_km"); value != "" {
+		var err error
+		distance, err = strconv.ParseFloat(value, 64)
+		v.Check(err == nil && distance > 0, "distance_km", "must be a positive number")
+		v.Check(rangeEstimator != nil, "distance_km", "is not supported without range estimation")
+	}
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
+		return nil, start, false
+	}
+	if !start.IsZero() && !start.Before(end) {
+		apierror.Write(w, r, errInvalidTimeRange)
+		return nil, start, false
+	}
+
 	// Fetch available vehicles from the database
-	vehicles, err := models.GetAvailableVehicles()
+	var vehicles []models.Vehicle
+	var err error
+	if start.IsZero() {
+		vehicles, err = models.GetAvailableVehicles()
+	} else {
+		vehicles, err = models.GetVehiclesAvailableBetween(start, end, bookingPolicy.Turnaround.For)
+		if err == nil && chargeForecast != nil {
+			err = predictCharge(vehicles, start)
+		}
+	}
 	if err != nil {
-		http.Error(w, "Failed to fetch available vehicles", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Error fetching available vehicles", "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch available vehicles"))
+		return nil, start, false
+	}
+	if rangeEstimator != nil {
+		vehicles = estimateRange(vehicles, distance)
+	}
+	return vehicles, start, true
+}
+
+// GetAvailableVehicles retrieves all vehicles from the database. With the
+// start_time and end_time query parameters it only returns the vehicles that
+// can be reserved for that window, turnaround included, with their predicted
+// charge level at start_time. With distance_km it only returns the vehicles
+// whose estimated range covers that trip and its safety margin.
+func GetAvailableVehicles(w http.ResponseWriter, r *http.Request) {
+	vehicles, _, ok := searchVehicles(w, r)
+	if !ok {
 		return
 	}
 
@@ -25,61 +172,448 @@ func GetAvailableVehicles(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// GetVehicleDetails fetches a single vehicle by the {id} path variable of
+// /v1/vehicles/{id}, or the vehicle_id query parameter of the legacy route
+func GetVehicleDetails(w http.ResponseWriter, r *http.Request) {
+	field, value := "vehicle_id", r.URL.Query().Get("vehicle_id")
+	if id, ok := mux.Vars(r)["id"]; ok {
+		field, value = "id", id
+	}
+	vehicleID, err := strconv.Atoi(value)
+	if err != nil || vehicleID <= 0 {
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Invalid Vehicle ID").WithField(field, "must be a positive integer"))
+		return
+	}
+
+	vehicle, err := models.GetVehicleByID(vehicleID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching vehicle details", "vehicle_id", vehicleID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch vehicle details"))
+		return
+	}
+	if vehicle == nil {
+		apierror.Write(w, r, errVehicleNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Vehicle details fetched successfully",
+		"data":    vehicle,
+	})
+}
+
+// GetVehicleCalendar lists the reservations of a vehicle and the turnaround
+// after each between the from and to query parameters, by default the next
+// seven days
+func GetVehicleCalendar(w http.ResponseWriter, r *http.Request) {
+	vehicleID, ok := vehicleIDVar(w, r)
+	if !ok {
+		return
+	}
+	var v apierror.Validation
+	from, to := timeParam(r, "from", &v), timeParam(r, "to", &v)
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+	if from.IsZero() {
+		from = clock().UTC().Truncate(time.Minute)
+	}
+	if to.IsZero() {
+		to = from.Add(calendarSpan)
+	}
+	if !from.Before(to) {
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, CodeInvalidTimeRange, "to must be after from").WithField("to", "is not after from"))
+		return
+	}
+	if to.Sub(from) > maxCalendarSpan {
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "The calendar can span at most 92 days").WithField("to", "is too far from from"))
+		return
+	}
+
+	vehicle, err := models.GetVehicleByID(vehicleID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching vehicle", "vehicle_id", vehicleID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch the vehicle calendar"))
+		return
+	}
+	if vehicle == nil {
+		apierror.Write(w, r, errVehicleNotFound)
+		return
+	}
+
+	turnaround := bookingPolicy.Turnaround.For(vehicle.Model)
+	entries, err := models.GetVehicleCalendar(*vehicle, from, to, turnaround)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching vehicle calendar", "vehicle_id", vehicleID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to fetch the vehicle calendar"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Vehicle calendar fetched successfully",
+		"data": map[string]interface{}{
+			"vehicle_id":         vehicleID,
+			"from":               from,
+			"to":                 to,
+			"turnaround_minutes": int(turnaround / time.Minute),
+			"entries":            entries,
+		},
+	})
+}
+
 // Reserve Vehicle
 func CreateReservation(w http.ResponseWriter, r *http.Request) {
-	var reservation models.Reservation
+	// membership_tier and booking_limit are set by user_service from the
+	// user's membership and select the booking rules; they are refused from
+	// any other caller. planned_distance_km is
+	// checked against the range of the vehicle. None of them is stored. An
+	// optional return_station_id makes a one-way trip.
+	var request struct {
+		models.Reservation
+		MembershipTier    string  `json:"membership_tier"`
+		BookingLimit      int     `json:"booking_limit"`
+		PlannedDistanceKM float64 `json:"planned_distance_km"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		reservationsTotal.WithLabelValues("invalid").Inc()
+		apierror.Write(w, r, apierror.ErrInvalidJSON)
+		return
+	}
+	reservation := request.Reservation
 
-	if err := json.NewDecoder(r.Body).Decode(&reservation); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Invalid request payload",
-			"error":   err.Error(),
-		})
+	// Validate the request fields
+	var v apierror.Validation
+	v.Check(reservation.UserID > 0, "user_id", "is missing or invalid")
+	v.Check(reservation.VehicleID > 0, "vehicle_id", "is required")
+	v.Check(!reservation.StartTime.IsZero(), "start_time", "is required")
+	v.Check(!reservation.EndTime.IsZero(), "end_time", "is required")
+	v.Check(reservation.ExpectedChargeLevel >= 0 && reservation.ExpectedChargeLevel <= 100, "expected_charge_level", "must be between 0 and 100")
+	v.Check(request.PlannedDistanceKM >= 0, "planned_distance_km", "must not be negative")
+	v.Check(reservation.ReturnStationID >= 0, "return_station_id", "must be a positive integer")
+	if err := v.Err(); err != nil {
+		reservationsTotal.WithLabelValues("invalid").Inc()
+		apierror.Write(w, r, err)
+		return
+	}
+	if !reservation.StartTime.Before(reservation.EndTime) {
+		reservationsTotal.WithLabelValues("invalid").Inc()
+		apierror.Write(w, r, errInvalidTimeRange)
+		return
+	}
+	if (request.MembershipTier != "" || request.BookingLimit != 0) && !fromUserService(r) {
+		reservationsTotal.WithLabelValues("invalid").Inc()
+		logging.FromContext(r.Context()).Warn("Rejected a membership not sent by user_service", "user_id", reservation.UserID, "tier", request.MembershipTier)
+		apierror.Write(w, r, errMembershipUntrusted)
 		return
 	}
 
-	// Validate user_id
-	if reservation.UserID == 0 {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "User ID is missing or invalid",
-		})
+	logging.FromContext(r.Context()).Info("Reservation attempt", "user_id", reservation.UserID, "vehicle_id", reservation.VehicleID, "tier", request.MembershipTier)
+
+	// Check the booking rules of the user's tier
+	check := booking.Request{
+		Tier:         request.MembershipTier,
+		Start:        reservation.StartTime,
+		End:          reservation.EndTime,
+		BookingLimit: request.BookingLimit,
+	}
+	if request.MembershipTier == "" && request.BookingLimit == 0 {
+		// Without a membership, e.g. on a direct call, the default limit applies
+		check.BookingLimit = bookingPolicy.BookingLimit
+	}
+	now := clock()
+	if check.BookingLimit > 0 {
+		upcoming, err := models.CountUpcomingReservations(reservation.UserID, now)
+		if err != nil {
+			reservationsTotal.WithLabelValues("error").Inc()
+			logging.FromContext(r.Context()).Error("Error counting upcoming reservations", "user_id", reservation.UserID, "error", err)
+			apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Error checking the booking limit"))
+			return
+		}
+		check.Upcoming = upcoming
+	}
+	if violation := bookingPolicy.Check(check, now); violation != nil {
+		reservationsTotal.WithLabelValues("rejected").Inc()
+		logging.FromContext(r.Context()).Info("Reservation rejected by booking policy", "user_id", reservation.UserID, "code", violation.Code)
+		apierror.Write(w, r, violation)
 		return
 	}
 
-	log.Printf("Reservation attempt by User ID: %d", reservation.UserID)
+	// Check that the vehicle exists
+	vehicle, err := models.GetVehicleByID(reservation.VehicleID)
+	if err != nil {
+		reservationsTotal.WithLabelValues("error").Inc()
+		logging.FromContext(r.Context()).Error("Error fetching vehicle", "vehicle_id", reservation.VehicleID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Error checking vehicle availability"))
+		return
+	}
+	if vehicle == nil {
+		reservationsTotal.WithLabelValues("invalid").Inc()
+		apierror.Write(w, r, errVehicleNotFound)
+		return
+	}
 
-	// Check vehicle availability
-	available, err := models.IsVehicleAvailable(reservation.VehicleID, reservation.StartTime, reservation.EndTime)
+	// Check vehicle availability, keeping the turnaround of the model free
+	// around the reservation
+	available, err := models.IsVehicleAvailable(*vehicle, reservation.StartTime, reservation.EndTime, bookingPolicy.Turnaround.For(vehicle.Model))
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Error checking vehicle availability",
-			"error":   err.Error(),
-		})
+		reservationsTotal.WithLabelValues("error").Inc()
+		logging.FromContext(r.Context()).Error("Error checking vehicle availability", "vehicle_id", reservation.VehicleID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Error checking vehicle availability"))
 		return
 	}
 	if !available {
-		w.WriteHeader(http.StatusConflict)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Vehicle not available for the selected time range",
-		})
+		reservationsTotal.WithLabelValues("conflict").Inc()
+		apierror.Write(w, r, errVehicleUnavailable)
 		return
 	}
 
+	// Check where the vehicle is picked up and returned, and price a one-way
+	// trip
+	if apiErr := planStations(r, *vehicle, &reservation); apiErr != nil {
+		switch apiErr.Status {
+		case http.StatusInternalServerError:
+			reservationsTotal.WithLabelValues("error").Inc()
+		case http.StatusNotFound:
+			reservationsTotal.WithLabelValues("invalid").Inc()
+		default:
+			reservationsTotal.WithLabelValues("conflict").Inc()
+		}
+		apierror.Write(w, r, apiErr)
+		return
+	}
+
+	// Forecast the charge at pickup and whether the reservation leaves enough
+	// time to reach the expected level, for itself and the next reservation
+	response := map[string]interface{}{"message": "Reservation created successfully"}
+	var warnings []string
+	pickupLevel := vehicle.ChargeLevel
+	if chargeForecast != nil {
+		booked, err := models.GetUpcomingReservations(reservation.VehicleID, now)
+		if err != nil {
+			reservationsTotal.WithLabelValues("error").Inc()
+			logging.FromContext(r.Context()).Error("Error fetching upcoming reservations", "vehicle_id", reservation.VehicleID, "error", err)
+			apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Error forecasting the charge level"))
+			return
+		}
+		predicted, shortfall := chargeForecast.Check(*vehicle, booked, reservation, now)
+		response["predicted_charge_level"] = predicted
+		pickupLevel = predicted
+		if shortfall != nil && chargeForecast.Shortfall == charging.ShortfallReject {
+			reservationsTotal.WithLabelValues("rejected").Inc()
+			logging.FromContext(r.Context()).Info("Reservation rejected by charge forecast", "vehicle_id", reservation.VehicleID, "predicted", shortfall.Predicted, "expected", shortfall.Expected)
+			apierror.Write(w, r, apierror.New(http.StatusConflict, charging.CodeChargeUnreachable, shortfall.Message()).
+				WithField("expected_charge_level", "is not forecast to be reached"))
+			return
+		}
+		if shortfall != nil && chargeForecast.Shortfall == charging.ShortfallWarn {
+			warnings = append(warnings, shortfall.Message())
+		}
+	}
+
+	// Check that the vehicle can drive the planned distance from its charge
+	// at pickup
+	if rangeEstimator != nil {
+		rangeKM := rangeEstimator.RangeKM(*vehicle, pickupLevel)
+		response["estimated_range_km"] = rangeKM
+		if distance := request.PlannedDistanceKM; distance > 0 && !rangeEstimator.CanDrive(*vehicle, pickupLevel, distance) {
+			switch rangeEstimator.OutOfRange {
+			case trip.OutOfRangeReject:
+				reservationsTotal.WithLabelValues("rejected").Inc()
+				logging.FromContext(r.Context()).Info("Reservation rejected by range estimate", "vehicle_id", reservation.VehicleID, "range_km", rangeKM, "distance_km", distance)
+				apierror.Write(w, r, apierror.New(http.StatusConflict, trip.CodeOutOfRange, rangeEstimator.Shortfall(rangeKM, distance)).
+					WithField("planned_distance_km", "exceeds the estimated range"))
+				return
+			case trip.OutOfRangeWarn:
+				warnings = append(warnings, rangeEstimator.Shortfall(rangeKM, distance))
+			}
+		}
+	}
+	if len(warnings) > 0 {
+		response["warnings"] = warnings
+	}
+
 	// Save reservation
 	if err := models.CreateReservation(&reservation); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Failed to create reservation",
-			"error":   err.Error(),
-		})
+		reservationsTotal.WithLabelValues("error").Inc()
+		logging.FromContext(r.Context()).Error("Error creating reservation", "vehicle_id", reservation.VehicleID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to create reservation"))
 		return
 	}
 
+	reservationsTotal.WithLabelValues("created").Inc()
+	response["data"] = reservation
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// planStations sets the pickup station of reservation, where the vehicle is
+// at its start, and its return station, the pickup station unless the
+// request names another. A one-way trip must leave the vehicle where its next
+// reservation picks it up, needs a free parking space at the return station
+// from its end on, and is charged the one-way fee of the distance between
+// the stations.
+func planStations(r *http.Request, vehicle models.Vehicle, reservation *models.Reservation) *apierror.Error {
+	errStations := apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Error checking the return station")
+	log := logging.FromContext(r.Context())
+	pickup, err := models.StationAt(vehicle, reservation.StartTime)
+	if err != nil {
+		log.Error("Error locating vehicle", "vehicle_id", vehicle.VehicleID, "error", err)
+		return errStations
+	}
+	reservation.PickupStationID, reservation.OneWayFee, reservation.CompletedAt = pickup, 0, nil
+	if reservation.ReturnStationID == 0 {
+		reservation.ReturnStationID = pickup
+	}
+
+	returnStation, err := models.GetStationByID(reservation.ReturnStationID)
+	if err != nil {
+		log.Error("Error fetching station", "station_id", reservation.ReturnStationID, "error", err)
+		return errStations
+	}
+	if returnStation == nil {
+		return errStationNotFound.WithField("return_station_id", "does not exist")
+	}
+	next, err := models.NextReservation(vehicle.VehicleID, reservation.EndTime)
+	if err != nil {
+		log.Error("Error fetching next reservation", "vehicle_id", vehicle.VehicleID, "error", err)
+		return errStations
+	}
+	if next != nil && next.PickupStationID != reservation.ReturnStationID {
+		return apierror.New(http.StatusConflict, CodeVehicleUnavailable, "The vehicle must be returned to the station of its next reservation").
+			WithField("return_station_id", "differs from the pickup station of the next reservation")
+	}
+	if !reservation.OneWay() {
+		return nil
+	}
+
+	free, err := models.FreeSpacesFrom(*returnStation, reservation.EndTime)
+	if err != nil {
+		log.Error("Error counting free parking spaces", "station_id", returnStation.StationID, "error", err)
+		return errStations
+	}
+	if free == 0 {
+		log.Info("Reservation rejected by return station capacity", "station_id", returnStation.StationID, "capacity", returnStation.Capacity)
+		return apierror.New(http.StatusConflict, CodeReturnStationFull, returnStation.Name+" has no free parking space at the return time").
+			WithField("return_station_id", "has no free parking space at end_time")
+	}
+
+	pickupStation, err := models.GetStationByID(pickup)
+	if err != nil || pickupStation == nil {
+		log.Error("Error fetching station", "station_id", pickup, "error", err)
+		return errStations
+	}
+	var distance float64
+	from, okFrom := pickupStation.Position()
+	to, okTo := returnStation.Position()
+	if okFrom && okTo {
+		distance = geo.DistanceKM(from, to)
+	}
+	reservation.OneWayFee = bookingPolicy.OneWay.For(distance)
+	return nil
+}
+
+// reservationIDVar parses the {id} path variable of a reservation route
+func reservationIDVar(w http.ResponseWriter, r *http.Request) (int, bool) {
+	reservationID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || reservationID <= 0 {
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Invalid Reservation ID").WithField("id", "must be a positive integer"))
+		return 0, false
+	}
+	return reservationID, true
+}
+
+// GetReservation fetches a single reservation by the {id} path variable
+func GetReservation(w http.ResponseWriter, r *http.Request) {
+	reservationID, ok := reservationIDVar(w, r)
+	if !ok {
+		return
+	}
+	reservation, err := models.GetReservationByID(reservationID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching reservation", "reservation_id", reservationID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to retrieve reservation"))
+		return
+	}
+	if reservation == nil {
+		apierror.Write(w, r, errNoSuchReservation)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Reservation fetched successfully",
+		"data":    reservation,
+	})
+}
+
+// CompleteReservation ends the trip of the reservation {id} and parks its
+// vehicle at the return station, where searches find it from then on. Only
+// the user_id in the body that holds the reservation can complete it.
+// A vehicle reported outside its return zone is refused or charged the
+// out-of-zone fee, as the geofence policy says.
+func CompleteReservation(w http.ResponseWriter, r *http.Request) {
+	reservationID, ok := reservationIDVar(w, r)
+	if !ok {
+		return
+	}
+	var request struct {
+		UserID int `json:"user_id"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			apierror.Write(w, r, apierror.ErrInvalidJSON)
+			return
+		}
+	}
+	var v apierror.Validation
+	v.Check(request.UserID > 0, "user_id", "is missing or invalid")
+	if err := v.Err(); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	errComplete := apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to complete reservation")
+	reservation, err := models.GetReservationByID(reservationID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error fetching reservation", "reservation_id", reservationID, "error", err)
+		apierror.Write(w, r, errComplete)
+		return
+	}
+	if reservation == nil || reservation.UserID != request.UserID {
+		apierror.Write(w, r, errNoSuchReservation)
+		return
+	}
+	now := clock()
+	if reservation.Status != "Active" {
+		apierror.Write(w, r, apierror.New(http.StatusConflict, CodeReservationInactive, "Only Active reservations can be completed; this one is "+reservation.Status))
+		return
+	}
+	if now.Before(reservation.StartTime) {
+		apierror.Write(w, r, apierror.New(http.StatusConflict, CodeReservationNotBegun, "The reservation has not started yet"))
+		return
+	}
+
+	outOfZoneFee, apiErr := returnZoneFee(r, *reservation)
+	if apiErr != nil {
+		apierror.Write(w, r, apiErr)
+		return
+	}
+
+	if err := models.CompleteReservation(reservation, now.UTC(), outOfZoneFee); err != nil {
+		logging.FromContext(r.Context()).Error("Error completing reservation", "reservation_id", reservationID, "error", err)
+		apierror.Write(w, r, errComplete)
+		return
+	}
+	logging.FromContext(r.Context()).Info("Reservation completed", "reservation_id", reservationID, "vehicle_id", reservation.VehicleID, "return_station_id", reservation.ReturnStationID,
+		"out_of_zone_fee", outOfZoneFee)
+
+	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message": "Reservation created successfully",
+		"message": "Reservation completed successfully",
 		"data":    reservation,
 	})
 }
@@ -88,28 +622,25 @@ func CreateReservation(w http.ResponseWriter, r *http.Request) {
 func GetLatestReservation(w http.ResponseWriter, r *http.Request) {
 	userIDParam := r.URL.Query().Get("user_id")
 	if userIDParam == "" {
-		http.Error(w, `{"message":"User ID is required"}`, http.StatusBadRequest)
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "User ID is required").WithField("user_id", "is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDParam)
 	if err != nil {
-		http.Error(w, `{"message":"Invalid User ID"}`, http.StatusBadRequest)
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeValidationFailed, "Invalid User ID").WithField("user_id", "must be an integer"))
 		return
 	}
 
 	reservation, err := models.GetLatestReservationByUserID(userID)
 	if err != nil {
-		log.Printf("Error retrieving latest reservation for user ID %d: %v", userID, err)
-		http.Error(w, `{"message":"Failed to retrieve reservation"}`, http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Error retrieving latest reservation", "user_id", userID, "error", err)
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "Failed to retrieve reservation"))
 		return
 	}
 
 	if reservation == nil {
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "No reservations found for the user",
-		})
+		apierror.Write(w, r, errReservationNotFound)
 		return
 	}
 
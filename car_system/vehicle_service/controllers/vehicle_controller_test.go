@@ -0,0 +1,541 @@
+package controllers
+
+import (
+	"bytes"
+	"car_system/common/apierror"
+	"car_system/vehicle_service/booking"
+	"car_system/vehicle_service/charging"
+	"car_system/vehicle_service/models"
+	"car_system/vehicle_service/trip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// setupTest wires the handlers to an in-memory store holding the sample fleet
+func setupTest(t *testing.T) *models.MemoryStore {
+	t.Helper()
+	memory := models.NewMemoryStore()
+	memory.SeedSampleFleet()
+	models.UseRepositories(memory.Repositories())
+	return memory
+}
+
+func postReservation(t *testing.T, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/create-reservation", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+	CreateReservation(rec, req)
+	return rec
+}
+
+// errorCode returns the code of an error envelope response
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not JSON: %v (%q)", err, rec.Body.String())
+	}
+	return body.Code
+}
+
+func TestGetAvailableVehicles(t *testing.T) {
+	setupTest(t)
+
+	rec := httptest.NewRecorder()
+	GetAvailableVehicles(rec, httptest.NewRequest("GET", "/available-vehicles", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		Vehicles []models.Vehicle `json:"vehicles"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Vehicles) != 5 {
+		t.Errorf("got %d vehicles, want 5", len(body.Vehicles))
+	}
+}
+
+func TestCreateReservation(t *testing.T) {
+	setupTest(t)
+
+	rec := postReservation(t, `{"vehicle_id":1,"user_id":7,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z","expected_charge_level":80}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Data models.Reservation `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if body.Data.ReservationID == 0 || body.Data.Status != "Active" {
+		t.Errorf("reservation = %+v, want an ID and status Active", body.Data)
+	}
+
+	// Overlapping window on the same vehicle
+	rec = postReservation(t, `{"vehicle_id":1,"user_id":8,"start_time":"2030-01-01T11:00:00Z","end_time":"2030-01-01T13:00:00Z"}`)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != CodeVehicleUnavailable {
+		t.Errorf("overlap: got %d %s, want 409 %s", rec.Code, rec.Body.String(), CodeVehicleUnavailable)
+	}
+
+	// Back-to-back window on the same vehicle does not overlap
+	rec = postReservation(t, `{"vehicle_id":1,"user_id":8,"start_time":"2030-01-01T12:00:00Z","end_time":"2030-01-01T14:00:00Z"}`)
+	if rec.Code != http.StatusOK {
+		t.Errorf("adjacent: got %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = postReservation(t, `{"vehicle_id":1,"start_time":"2030-01-02T10:00:00Z","end_time":"2030-01-02T12:00:00Z"}`)
+	if rec.Code != http.StatusBadRequest || errorCode(t, rec) != apierror.CodeValidationFailed {
+		t.Errorf("missing user_id: got %d %s, want 400 %s", rec.Code, rec.Body.String(), apierror.CodeValidationFailed)
+	}
+
+	rec = postReservation(t, `{"vehicle_id":99,"user_id":8,"start_time":"2030-01-02T10:00:00Z","end_time":"2030-01-02T12:00:00Z"}`)
+	if rec.Code != http.StatusNotFound || errorCode(t, rec) != CodeVehicleNotFound {
+		t.Errorf("unknown vehicle: got %d %s, want 404 %s", rec.Code, rec.Body.String(), CodeVehicleNotFound)
+	}
+
+	rec = postReservation(t, `{"vehicle_id":2,"user_id":8,"start_time":"2030-01-02T12:00:00Z","end_time":"2030-01-02T10:00:00Z"}`)
+	if rec.Code != http.StatusBadRequest || errorCode(t, rec) != CodeInvalidTimeRange {
+		t.Errorf("reversed range: got %d %s, want 400 %s", rec.Code, rec.Body.String(), CodeInvalidTimeRange)
+	}
+}
+
+func TestGetLatestReservation(t *testing.T) {
+	setupTest(t)
+
+	rec := httptest.NewRecorder()
+	GetLatestReservation(rec, httptest.NewRequest("GET", "/latest-reservation?user_id=7", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("no reservations: got %d, want 404", rec.Code)
+	}
+
+	postReservation(t, `{"vehicle_id":2,"user_id":7,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z"}`)
+	postReservation(t, `{"vehicle_id":3,"user_id":7,"start_time":"2030-01-03T10:00:00Z","end_time":"2030-01-03T12:00:00Z"}`)
+
+	rec = httptest.NewRecorder()
+	GetLatestReservation(rec, httptest.NewRequest("GET", "/latest-reservation?user_id=7", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Data models.Reservation `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if body.Data.VehicleID != 3 || body.Data.RentalRate != 45 {
+		t.Errorf("latest reservation = %+v, want vehicle 3 at rate 45", body.Data)
+	}
+
+	rec = httptest.NewRecorder()
+	GetLatestReservation(rec, httptest.NewRequest("GET", "/latest-reservation?user_id=abc", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid user_id: got %d, want 400", rec.Code)
+	}
+}
+
+func TestCreateReservationBookingPolicy(t *testing.T) {
+	setupTest(t)
+	week := 7 * 24 * time.Hour
+	UseBookingPolicy(booking.Policy{
+		Default:      booking.Rules{MinDuration: time.Hour, MaxDuration: 72 * time.Hour},
+		Tiers:        booking.TierRules{"VIP": {MaxDuration: &week}},
+		BookingLimit: 1,
+	})
+	defer UseBookingPolicy(booking.Policy{})
+	UseServiceToken("user-service-token")
+	defer UseServiceToken("")
+
+	tomorrow := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	userID := 7
+	send := func(token string, vehicleID int, tier string, limit int, duration time.Duration) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{
+			"vehicle_id":      vehicleID,
+			"user_id":         userID,
+			"start_time":      tomorrow,
+			"end_time":        tomorrow.Add(duration),
+			"membership_tier": tier,
+			"booking_limit":   limit,
+		})
+		req := httptest.NewRequest("POST", "/v1/reservations", bytes.NewReader(body))
+		if token != "" {
+			req.Header.Set(ServiceTokenHeader, token)
+		}
+		rec := httptest.NewRecorder()
+		CreateReservation(rec, req)
+		return rec
+	}
+	reserve := func(vehicleID int, tier string, limit int, duration time.Duration) *httptest.ResponseRecorder {
+		return send("user-service-token", vehicleID, tier, limit, duration)
+	}
+
+	// Only user_service vouches for the membership of a user
+	for _, token := range []string{"", "forged-token"} {
+		if rec := send(token, 1, "VIP", 0, 4*24*time.Hour); rec.Code != http.StatusUnauthorized || errorCode(t, rec) != apierror.CodeUnauthorized {
+			t.Errorf("VIP claimed with token %q: got %d %s, want 401", token, rec.Code, rec.Body.String())
+		}
+	}
+	if rec := reserve(1, "Basic", 0, 4*24*time.Hour); rec.Code != http.StatusUnprocessableEntity || errorCode(t, rec) != booking.CodeTooLong {
+		t.Errorf("4 days as Basic: got %d %s, want 422 %s", rec.Code, rec.Body.String(), booking.CodeTooLong)
+	}
+	if rec := reserve(1, "VIP", 0, 4*24*time.Hour); rec.Code != http.StatusOK {
+		t.Errorf("4 days as VIP: got %d %s, want 200", rec.Code, rec.Body.String())
+	}
+	if rec := reserve(2, "Basic", 2, 2*time.Hour); rec.Code != http.StatusOK {
+		t.Errorf("second booking: got %d %s, want 200", rec.Code, rec.Body.String())
+	}
+	// User 7 now holds two upcoming reservations
+	if rec := reserve(3, "Basic", 2, 2*time.Hour); rec.Code != http.StatusConflict || errorCode(t, rec) != booking.CodeLimitReached {
+		t.Errorf("third booking: got %d %s, want 409 %s", rec.Code, rec.Body.String(), booking.CodeLimitReached)
+	}
+
+	// A direct call without a membership gets the default limit of one
+	userID = 8
+	if rec := send("", 4, "", 0, 2*time.Hour); rec.Code != http.StatusOK {
+		t.Errorf("direct booking: got %d %s, want 200", rec.Code, rec.Body.String())
+	}
+	if rec := send("", 5, "", 0, 2*time.Hour); rec.Code != http.StatusConflict || errorCode(t, rec) != booking.CodeLimitReached {
+		t.Errorf("second direct booking: got %d %s, want 409 %s", rec.Code, rec.Body.String(), booking.CodeLimitReached)
+	}
+
+	rec := postReservation(t, `{"vehicle_id":4,"user_id":7,"start_time":"2020-01-01T10:00:00Z","end_time":"2020-01-01T12:00:00Z"}`)
+	if rec.Code != http.StatusUnprocessableEntity || errorCode(t, rec) != booking.CodeStartInPast {
+		t.Errorf("past booking: got %d %s, want 422 %s", rec.Code, rec.Body.String(), booking.CodeStartInPast)
+	}
+}
+
+func TestTurnaround(t *testing.T) {
+	memory := setupTest(t)
+	UseBookingPolicy(booking.Policy{Turnaround: booking.Turnaround{
+		Default: 30 * time.Minute,
+		Models:  booking.ModelDurations{"Nissan Leaf": time.Hour},
+	}})
+	defer UseBookingPolicy(booking.Policy{})
+
+	postReservation(t, `{"vehicle_id":1,"user_id":7,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z"}`)
+	postReservation(t, `{"vehicle_id":2,"user_id":7,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z"}`)
+
+	for _, tc := range []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{"inside the turnaround", `{"vehicle_id":1,"user_id":8,"start_time":"2030-01-01T12:15:00Z","end_time":"2030-01-01T14:00:00Z"}`, http.StatusConflict},
+		{"ending within the turnaround before the next", `{"vehicle_id":1,"user_id":8,"start_time":"2030-01-01T08:00:00Z","end_time":"2030-01-01T09:45:00Z"}`, http.StatusConflict},
+		{"after the turnaround", `{"vehicle_id":1,"user_id":8,"start_time":"2030-01-01T12:30:00Z","end_time":"2030-01-01T14:00:00Z"}`, http.StatusOK},
+		{"inside the model's longer turnaround", `{"vehicle_id":2,"user_id":8,"start_time":"2030-01-01T12:30:00Z","end_time":"2030-01-01T14:00:00Z"}`, http.StatusConflict},
+	} {
+		if rec := postReservation(t, tc.body); rec.Code != tc.wantCode {
+			t.Errorf("%s: got %d %s, want %d", tc.name, rec.Code, rec.Body.String(), tc.wantCode)
+		}
+	}
+
+	// Vehicle 1 is now booked until 14:00 and vehicle 2 until 12:00
+	rec := httptest.NewRecorder()
+	GetAvailableVehicles(rec, httptest.NewRequest("GET", "/v1/vehicles?start_time=2030-01-01T12:45:00Z&end_time=2030-01-01T13:45:00Z", nil))
+	var list struct {
+		Vehicles []models.Vehicle `json:"vehicles"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &list)
+	var ids []int
+	for _, v := range list.Vehicles {
+		ids = append(ids, v.VehicleID)
+	}
+	if rec.Code != http.StatusOK || len(ids) != 3 || ids[0] != 3 {
+		t.Errorf("search: got %d with vehicles %v, want 3, 4 and 5", rec.Code, ids)
+	}
+	for _, query := range []string{"start_time=2030-01-01T12:00:00Z", "start_time=tomorrow&end_time=2030-01-01T12:00:00Z"} {
+		rec := httptest.NewRecorder()
+		GetAvailableVehicles(rec, httptest.NewRequest("GET", "/v1/vehicles?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("search %s: got %d, want 400", query, rec.Code)
+		}
+	}
+
+	calendar := func(query string) *httptest.ResponseRecorder {
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/v1/vehicles/1/calendar?"+query, nil), map[string]string{"id": "1"})
+		rec := httptest.NewRecorder()
+		GetVehicleCalendar(rec, req)
+		return rec
+	}
+	rec = calendar("from=2030-01-01T00:00:00Z&to=2030-01-02T00:00:00Z")
+	var body struct {
+		Data struct {
+			TurnaroundMinutes int                    `json:"turnaround_minutes"`
+			Entries           []models.CalendarEntry `json:"entries"`
+		} `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	var kinds []string
+	for _, e := range body.Data.Entries {
+		kinds = append(kinds, e.Kind+" "+e.StartTime.Format("15:04")+"-"+e.EndTime.Format("15:04"))
+	}
+	want := "[reservation 10:00-12:00 turnaround 12:00-12:30 reservation 12:30-14:00 turnaround 14:00-14:30]"
+	if rec.Code != http.StatusOK || body.Data.TurnaroundMinutes != 30 || fmt.Sprint(kinds) != want {
+		t.Errorf("calendar: got %d, %d minutes, %v; want 30 minutes, %s", rec.Code, body.Data.TurnaroundMinutes, kinds, want)
+	}
+	// The turnaround of a reservation that ended before from is still listed
+	rec = calendar("from=2030-01-01T14:15:00Z&to=2030-01-01T16:00:00Z")
+	body.Data.Entries = nil
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if len(body.Data.Entries) != 1 || body.Data.Entries[0].Kind != "turnaround" {
+		t.Errorf("calendar after the last reservation: %+v, want its turnaround", body.Data.Entries)
+	}
+	if rec := calendar("from=2030-01-02T00:00:00Z&to=2030-01-01T00:00:00Z"); rec.Code != http.StatusBadRequest {
+		t.Errorf("reversed calendar: got %d, want 400", rec.Code)
+	}
+
+	// A completed reservation and its turnaround leave the calendar
+	first, _ := models.GetReservationByID(1)
+	if err := models.CompleteReservation(first, time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC), 0); err != nil {
+		t.Fatal(err)
+	}
+	rec = calendar("from=2030-01-01T00:00:00Z&to=2030-01-02T00:00:00Z")
+	body.Data.Entries, kinds = nil, nil
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	for _, e := range body.Data.Entries {
+		kinds = append(kinds, e.Kind+" "+e.StartTime.Format("15:04")+"-"+e.EndTime.Format("15:04"))
+	}
+	if want := "[reservation 12:30-14:00 turnaround 14:00-14:30]"; fmt.Sprint(kinds) != want {
+		t.Errorf("calendar after completing the first reservation: %v, want %s", kinds, want)
+	}
+
+	// A slot the calendar shows free can be booked and any other cannot: the
+	// slot and the turnaround after it must miss every entry
+	cancelled := models.Reservation{VehicleID: 1, UserID: 9, Status: "Cancelled",
+		StartTime: time.Date(2030, 1, 1, 16, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 1, 17, 0, 0, 0, time.UTC)}
+	if err := memory.Repositories().Reservations.Create(&cancelled); err != nil {
+		t.Fatal(err)
+	}
+	for _, slot := range []struct {
+		from, to string
+		wantFree bool
+	}{
+		{"10:00", "12:15", false},
+		{"14:15", "15:00", false},
+		{"10:00", "12:00", true},
+		{"14:30", "15:30", true},
+		{"16:00", "17:00", true},
+	} {
+		start, _ := time.Parse(time.RFC3339, "2030-01-01T"+slot.from+":00Z")
+		end, _ := time.Parse(time.RFC3339, "2030-01-01T"+slot.to+":00Z")
+		body.Data.Entries = nil
+		json.Unmarshal(calendar("from=2030-01-01T00:00:00Z&to=2030-01-02T00:00:00Z").Body.Bytes(), &body)
+		free := true
+		for _, e := range body.Data.Entries {
+			free = free && !(e.StartTime.Before(end.Add(30*time.Minute)) && e.EndTime.After(start))
+		}
+		rec := postReservation(t, fmt.Sprintf(`{"vehicle_id":1,"user_id":8,"start_time":%q,"end_time":%q}`, start.Format(time.RFC3339), end.Format(time.RFC3339)))
+		if booked := rec.Code == http.StatusOK; free != slot.wantFree || booked != slot.wantFree {
+			t.Errorf("slot %s-%s: free in the calendar %v and booking got %d %s, want free %v", slot.from, slot.to, free, rec.Code, rec.Body.String(), slot.wantFree)
+		}
+	}
+}
+
+func TestChargeForecast(t *testing.T) {
+	memory := setupTest(t)
+	// The 65 kWh Chevrolet Bolt (vehicle 3) charges by 20% an hour
+	forecast := &charging.Forecaster{PowerKW: 13, ReturnLevel: 20, Shortfall: charging.ShortfallReject}
+	UseChargeForecast(forecast)
+	defer UseChargeForecast(nil)
+
+	postReservation(t, `{"vehicle_id":3,"user_id":7,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z"}`)
+	// A cancelled reservation does not drain the battery
+	cancelled := models.Reservation{VehicleID: 3, UserID: 9, Status: "Cancelled",
+		StartTime: time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 1, 13, 0, 0, 0, time.UTC)}
+	if err := memory.Repositories().Reservations.Create(&cancelled); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := postReservation(t, `{"vehicle_id":3,"user_id":8,"start_time":"2030-01-01T13:00:00Z","end_time":"2030-01-01T15:00:00Z","expected_charge_level":80}`)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != charging.CodeChargeUnreachable {
+		t.Errorf("80%% one hour after a return: got %d %s, want 409 %s", rec.Code, rec.Body.String(), charging.CodeChargeUnreachable)
+	}
+
+	forecast.Shortfall = charging.ShortfallWarn
+	rec = postReservation(t, `{"vehicle_id":3,"user_id":8,"start_time":"2030-01-01T13:00:00Z","end_time":"2030-01-01T15:00:00Z","expected_charge_level":80}`)
+	var body struct {
+		PredictedChargeLevel float64  `json:"predicted_charge_level"`
+		Warnings             []string `json:"warnings"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusOK || body.PredictedChargeLevel != 40 || len(body.Warnings) != 1 {
+		t.Errorf("with warnings: got %d %s, want 200 with a 40%% forecast and a warning", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	GetAvailableVehicles(rec, httptest.NewRequest("GET", "/v1/vehicles?start_time=2030-01-01T16:00:00Z&end_time=2030-01-01T18:00:00Z", nil))
+	var list struct {
+		Vehicles []models.Vehicle `json:"vehicles"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &list)
+	predicted := map[int]float64{}
+	for _, v := range list.Vehicles {
+		if v.PredictedChargeLevel != nil {
+			predicted[v.VehicleID] = *v.PredictedChargeLevel
+		}
+	}
+	if len(predicted) != 5 || predicted[3] != 40 || predicted[1] != 100 {
+		t.Errorf("search forecasts = %v, want 40%% for vehicle 3 and 100%% for vehicle 1", predicted)
+	}
+
+	rec = postReservation(t, `{"vehicle_id":4,"user_id":8,"start_time":"2030-01-02T10:00:00Z","end_time":"2030-01-02T12:00:00Z","expected_charge_level":120}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("120%%: got %d, want 400", rec.Code)
+	}
+}
+
+func TestRangeEstimate(t *testing.T) {
+	setupTest(t)
+	estimator := &trip.Estimator{
+		KWhPerKM:     0.16,
+		Models:       trip.ModelConsumption{"Tesla Model 3": 0.15, "Nissan Leaf": 0.17},
+		SafetyMargin: 0.15,
+		OutOfRange:   trip.OutOfRangeReject,
+	}
+	UseRangeEstimator(estimator)
+	defer UseRangeEstimator(nil)
+
+	// The Tesla (vehicle 1) drives 400 km and the Leaf (vehicle 2) 328 km;
+	// a 250 km trip needs 288 km with the margin
+	rec := httptest.NewRecorder()
+	GetAvailableVehicles(rec, httptest.NewRequest("GET", "/v1/vehicles?distance_km=250", nil))
+	var list struct {
+		Vehicles []models.Vehicle `json:"vehicles"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &list)
+	var found []string
+	for _, v := range list.Vehicles {
+		found = append(found, fmt.Sprintf("%d:%g", v.VehicleID, *v.EstimatedRangeKM))
+	}
+	if rec.Code != http.StatusOK || fmt.Sprint(found) != "[1:400 2:328]" {
+		t.Errorf("250 km search: got %d %v, want vehicles 1 and 2 with 400 and 328 km", rec.Code, found)
+	}
+	rec = httptest.NewRecorder()
+	GetAvailableVehicles(rec, httptest.NewRequest("GET", "/v1/vehicles?distance_km=-5", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("negative distance: got %d, want 400", rec.Code)
+	}
+
+	rec = postReservation(t, `{"vehicle_id":3,"user_id":7,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z","planned_distance_km":250}`)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != trip.CodeOutOfRange {
+		t.Errorf("250 km in the Bolt: got %d %s, want 409 %s", rec.Code, rec.Body.String(), trip.CodeOutOfRange)
+	}
+
+	estimator.OutOfRange = trip.OutOfRangeWarn
+	rec = postReservation(t, `{"vehicle_id":3,"user_id":7,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z","planned_distance_km":250}`)
+	var body struct {
+		EstimatedRangeKM float64  `json:"estimated_range_km"`
+		Warnings         []string `json:"warnings"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusOK || body.EstimatedRangeKM != 243 || len(body.Warnings) != 1 {
+		t.Errorf("with warnings: got %d %s, want 200 with 243 km and a warning", rec.Code, rec.Body.String())
+	}
+
+	if rec := postReservation(t, `{"vehicle_id":1,"user_id":7,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z","planned_distance_km":250}`); rec.Code != http.StatusOK {
+		t.Errorf("250 km in the Tesla: got %d %s, want 200", rec.Code, rec.Body.String())
+	}
+}
+
+func TestOneWayReservation(t *testing.T) {
+	memory := setupTest(t)
+	UseBookingPolicy(booking.Policy{OneWay: booking.OneWayFee{Base: 10, PerKM: 0.5}})
+	defer UseBookingPolicy(booking.Policy{})
+	tiny := memory.AddStation(models.Station{Name: "Tiny Lot", Capacity: 1})
+
+	created := func(rec *httptest.ResponseRecorder) models.Reservation {
+		t.Helper()
+		var body struct {
+			Data models.Reservation `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("got %d %s, want 200", rec.Code, rec.Body.String())
+		}
+		return body.Data
+	}
+
+	// Downtown Station to Train Station, 2.78 km apart
+	oneWay := created(postReservation(t, `{"vehicle_id":1,"user_id":7,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z","return_station_id":5}`))
+	if oneWay.PickupStationID != 1 || oneWay.ReturnStationID != 5 || oneWay.OneWayFee != 11.39 {
+		t.Errorf("one-way reservation = %+v, want from station 1 to 5 for 11.39", oneWay)
+	}
+	// The next reservation of the vehicle starts where the last one ends
+	next := created(postReservation(t, `{"vehicle_id":1,"user_id":7,"start_time":"2030-01-02T10:00:00Z","end_time":"2030-01-02T12:00:00Z","one_way_fee":0.01}`))
+	if next.PickupStationID != 5 || next.ReturnStationID != 5 || next.OneWayFee != 0 {
+		t.Errorf("round trip after it = %+v, want at station 5 without a fee", next)
+	}
+	rec := postReservation(t, `{"vehicle_id":1,"user_id":7,"start_time":"2030-01-01T20:00:00Z","end_time":"2030-01-01T22:00:00Z","return_station_id":2}`)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != CodeVehicleUnavailable {
+		t.Errorf("stranding the next reservation: got %d %s, want 409 %s", rec.Code, rec.Body.String(), CodeVehicleUnavailable)
+	}
+
+	rec = postReservation(t, `{"vehicle_id":2,"user_id":7,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z","return_station_id":99}`)
+	if rec.Code != http.StatusNotFound || errorCode(t, rec) != CodeStationNotFound {
+		t.Errorf("unknown station: got %d %s, want 404 %s", rec.Code, rec.Body.String(), CodeStationNotFound)
+	}
+	// Tiny Lot has a single space: a second car cannot arrive after the first
+	created(postReservation(t, fmt.Sprintf(`{"vehicle_id":2,"user_id":7,"start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z","return_station_id":%d}`, tiny)))
+	rec = postReservation(t, fmt.Sprintf(`{"vehicle_id":3,"user_id":7,"start_time":"2030-01-01T06:00:00Z","end_time":"2030-01-01T08:00:00Z","return_station_id":%d}`, tiny))
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != CodeReturnStationFull {
+		t.Errorf("full station: got %d %s, want 409 %s", rec.Code, rec.Body.String(), CodeReturnStationFull)
+	}
+
+	complete := func(reservationID int, body string) *httptest.ResponseRecorder {
+		id := strconv.Itoa(reservationID)
+		req := mux.SetURLVars(httptest.NewRequest("POST", "/v1/reservations/"+id+"/complete", strings.NewReader(body)), map[string]string{"id": id})
+		rec := httptest.NewRecorder()
+		CompleteReservation(rec, req)
+		return rec
+	}
+	UseClock(func() time.Time { return time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC) })
+	defer UseClock(nil)
+	for _, body := range []string{"", `{}`, `{"user_id":0}`} {
+		if rec := complete(oneWay.ReservationID, body); rec.Code != http.StatusBadRequest || errorCode(t, rec) != apierror.CodeValidationFailed {
+			t.Errorf("complete with %q: got %d %s, want 400 %s", body, rec.Code, rec.Body.String(), apierror.CodeValidationFailed)
+		}
+	}
+	if rec := complete(oneWay.ReservationID, `{"user_id":7}`); rec.Code != http.StatusConflict || errorCode(t, rec) != CodeReservationNotBegun {
+		t.Errorf("before the start: got %d %s, want 409 %s", rec.Code, rec.Body.String(), CodeReservationNotBegun)
+	}
+	UseClock(func() time.Time { return time.Date(2030, 1, 1, 11, 45, 0, 0, time.UTC) })
+	if rec := complete(oneWay.ReservationID, `{"user_id":8}`); rec.Code != http.StatusNotFound {
+		t.Errorf("reservation of another user: got %d, want 404", rec.Code)
+	}
+	if rec := complete(oneWay.ReservationID, `{"user_id":7}`); rec.Code != http.StatusOK {
+		t.Fatalf("complete: got %d %s, want 200", rec.Code, rec.Body.String())
+	}
+	vehicle, _ := models.GetVehicleByID(1)
+	if vehicle.StationID != 5 || vehicle.Location != "Train Station" {
+		t.Errorf("vehicle = %+v, want parked at Train Station", vehicle)
+	}
+	// Returned 15 minutes early, the vehicle can be rented again at once
+	rec = httptest.NewRecorder()
+	GetAvailableVehicles(rec, httptest.NewRequest("GET", "/v1/vehicles?start_time=2030-01-01T11:50:00Z&end_time=2030-01-01T13:00:00Z", nil))
+	var list struct {
+		Vehicles []models.Vehicle `json:"vehicles"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &list)
+	found := false
+	for _, v := range list.Vehicles {
+		found = found || v.VehicleID == 1 && v.StationID == 5
+	}
+	if !found {
+		t.Errorf("search after an early return: got %d %s, want vehicle 1 at Train Station", rec.Code, rec.Body.String())
+	}
+	if rec := postReservation(t, `{"vehicle_id":1,"user_id":8,"start_time":"2030-01-01T11:50:00Z","end_time":"2030-01-01T13:00:00Z"}`); rec.Code != http.StatusOK {
+		t.Errorf("booking after an early return: got %d %s, want 200", rec.Code, rec.Body.String())
+	}
+	if rec := complete(oneWay.ReservationID, `{"user_id":7}`); rec.Code != http.StatusConflict || errorCode(t, rec) != CodeReservationInactive {
+		t.Errorf("completed twice: got %d %s, want 409 %s", rec.Code, rec.Body.String(), CodeReservationInactive)
+	}
+}
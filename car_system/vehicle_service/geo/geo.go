@@ -0,0 +1,32 @@
+// Package geo measures distances between GPS positions, so that
+// vehicle_service can find the stations and vehicles near a renter, and
+// tells whether positions lie in the GeoJSON polygons of its geofences.
+//
+// Distances are great-circle distances on a spherical Earth (the haversine
+// formula). Within a city the error against the ellipsoid is well below the
+// accuracy of a phone's GPS fix.
+package geo
+
+import "math"
+
+// earthRadiusKM is the mean radius of the Earth
+const earthRadiusKM = 6371.0
+
+// Point is a position in decimal degrees
+type Point struct {
+	Lat float64 `json:"latitude"`
+	Lon float64 `json:"longitude"`
+}
+
+// Valid reports whether p lies within the range of latitudes and longitudes
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+// DistanceKM returns the great-circle distance between a and b in km
+func DistanceKM(a, b Point) float64 {
+	rad := math.Pi / 180
+	dLat, dLon := (b.Lat-a.Lat)*rad, (b.Lon-a.Lon)*rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(a.Lat*rad)*math.Cos(b.Lat*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKM * math.Asin(math.Min(1, math.Sqrt(h)))
+}
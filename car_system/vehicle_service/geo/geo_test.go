@@ -0,0 +1,78 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceKM(t *testing.T) {
+	downtown, airport := Point{1.2796, 103.8525}, Point{1.3644, 103.9915}
+	for _, tc := range []struct {
+		name string
+		a, b Point
+		want float64
+	}{
+		{"same point", downtown, downtown, 0},
+		{"across the city", downtown, airport, 18.1},
+		{"one degree of longitude at the equator", Point{0, 0}, Point{0, 1}, 111.2},
+		{"antipodes", Point{0, 0}, Point{0, 180}, math.Pi * earthRadiusKM},
+	} {
+		if got := DistanceKM(tc.a, tc.b); math.Abs(got-tc.want) > 0.1 {
+			t.Errorf("%s: DistanceKM = %.2f, want %.1f", tc.name, got, tc.want)
+		}
+		if DistanceKM(tc.a, tc.b) != DistanceKM(tc.b, tc.a) {
+			t.Errorf("%s: the distance depends on the direction", tc.name)
+		}
+	}
+
+	if (Point{91, 0}).Valid() || (Point{0, -181}).Valid() || !downtown.Valid() {
+		t.Error("Valid accepted an out-of-range point or refused a valid one")
+	}
+}
+
+func TestParseGeoJSON(t *testing.T) {
+	// A square around Raffles Place with a hole around the Downtown Station
+	area, err := ParseGeoJSON([]byte(`{"type": "Polygon", "coordinates": [
+		[[103.84, 1.27], [103.86, 1.27], [103.86, 1.29], [103.84, 1.29], [103.84, 1.27]],
+		[[103.852, 1.279], [103.853, 1.279], [103.853, 1.280], [103.852, 1.280], [103.852, 1.279]]
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name string
+		p    Point
+		want bool
+	}{
+		{"inside", Point{1.285, 103.845}, true},
+		{"in the hole", Point{1.2796, 103.8525}, false},
+		{"north of the square", Point{1.295, 103.85}, false},
+		{"east of the square", Point{1.28, 103.87}, false},
+	} {
+		if got := area.Contains(tc.p); got != tc.want {
+			t.Errorf("%s: Contains = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	multi, err := ParseGeoJSON([]byte(`{"type": "MultiPolygon", "coordinates": [
+		[[[0, 0], [1, 0], [1, 1], [0, 0]]],
+		[[[10, 10], [11, 10], [11, 11], [10, 10]]]
+	]}`))
+	if err != nil || len(multi) != 2 || !multi.Contains(Point{10.2, 10.8}) {
+		t.Errorf("MultiPolygon = %v, %v; want 2 polygons containing (10.2, 10.8)", multi, err)
+	}
+
+	for _, bad := range []string{
+		`[]`,
+		`{"type": "Point", "coordinates": [103.85, 1.28]}`,
+		`{"type": "Polygon", "coordinates": []}`,
+		`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [0, 0]]]}`,
+		`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1]]]}`,
+		`{"type": "Polygon", "coordinates": [[[0, 0], [1, 95], [1, 1], [0, 0]]]}`,
+		`{"type": "MultiPolygon", "coordinates": [[]]}`,
+	} {
+		if _, err := ParseGeoJSON([]byte(bad)); err == nil {
+			t.Errorf("ParseGeoJSON(%s) succeeded, want an error", bad)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Polygon is an area bounded by its first ring, less the holes bounded by
+// the others. Every ring is closed: its last point repeats the first.
+type Polygon [][]Point
+
+// Contains reports whether p lies inside the polygon and outside its holes
+func (poly Polygon) Contains(p Point) bool {
+	if len(poly) == 0 || !inRing(poly[0], p) {
+		return false
+	}
+	for _, hole := range poly[1:] {
+		if inRing(hole, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// inRing reports whether p lies inside ring by casting a ray towards the
+// east and counting the edges it crosses. Latitudes and longitudes are
+// treated as plane coordinates, which is exact enough for zones within a
+// city that do not cross the antimeridian.
+func inRing(ring []Point, p Point) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) && p.Lon < (b.Lon-a.Lon)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Area is a set of polygons, as described by a GeoJSON MultiPolygon
+type Area []Polygon
+
+// Contains reports whether p lies inside one of the polygons
+func (a Area) Contains(p Point) bool {
+	for _, poly := range a {
+		if poly.Contains(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseGeoJSON parses a GeoJSON (RFC 7946) Polygon or MultiPolygon geometry.
+// Positions are [longitude, latitude]; a third value, the altitude, is
+// ignored.
+func ParseGeoJSON(data []byte) (Area, error) {
+	var geometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+	if err := json.Unmarshal(data, &geometry); err != nil {
+		return nil, fmt.Errorf("expected a GeoJSON geometry: %w", err)
+	}
+
+	var polygons [][][][]float64
+	switch geometry.Type {
+	case "Polygon":
+		var polygon [][][]float64
+		if err := json.Unmarshal(geometry.Coordinates, &polygon); err != nil {
+			return nil, fmt.Errorf("expected the coordinates of a Polygon as rings of positions: %w", err)
+		}
+		polygons = [][][][]float64{polygon}
+	case "MultiPolygon":
+		if err := json.Unmarshal(geometry.Coordinates, &polygons); err != nil {
+			return nil, fmt.Errorf("expected the coordinates of a MultiPolygon as polygons of rings of positions: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("expected a Polygon or MultiPolygon geometry, got type %q", geometry.Type)
+	}
+	if len(polygons) == 0 {
+		return nil, fmt.Errorf("expected at least one polygon")
+	}
+
+	area := make(Area, 0, len(polygons))
+	for _, rings := range polygons {
+		if len(rings) == 0 {
+			return nil, fmt.Errorf("expected every polygon to have an outer ring")
+		}
+		poly := make(Polygon, 0, len(rings))
+		for _, positions := range rings {
+			ring, err := parseRing(positions)
+			if err != nil {
+				return nil, err
+			}
+			poly = append(poly, ring)
+		}
+		area = append(area, poly)
+	}
+	return area, nil
+}
+
+// parseRing converts the positions of a linear ring, which must be closed
+// and have at least four positions
+func parseRing(positions [][]float64) ([]Point, error) {
+	if len(positions) < 4 {
+		return nil, fmt.Errorf("expected rings of at least 4 positions, got %d", len(positions))
+	}
+	ring := make([]Point, len(positions))
+	for i, pos := range positions {
+		if len(pos) < 2 {
+			return nil, fmt.Errorf("expected positions as [longitude, latitude], got %v", pos)
+		}
+		ring[i] = Point{Lat: pos[1], Lon: pos[0]}
+		if !ring[i].Valid() {
+			return nil, fmt.Errorf("position %v is out of range; positions are [longitude, latitude]", pos)
+		}
+	}
+	if ring[0] != ring[len(ring)-1] {
+		return nil, fmt.Errorf("expected closed rings whose last position repeats the first")
+	}
+	return ring, nil
+}
@@ -0,0 +1,156 @@
+// Package geofence checks vehicle positions against the operating zones of
+// the fleet, so that vehicle_service can notice vehicles leaving the service
+// area during a trip and refuse or charge returns outside of it.
+//
+// A vehicle may be returned inside a service area and outside every
+// no-parking zone. When its return station has a lot, it must be inside the
+// lot instead. Without any service area, the whole map is the service area.
+package geofence
+
+import (
+	"car_system/vehicle_service/geo"
+	"car_system/vehicle_service/models"
+	"fmt"
+	"math"
+	"time"
+)
+
+// CodeOutsideZone reports a reservation completed with its vehicle outside
+// the allowed return zone. It is a 409, because the renter can move the
+// vehicle and try again.
+const CodeOutsideZone = "RETURN_OUTSIDE_ZONE"
+
+// What a Policy does with a vehicle returned outside the allowed return zone
+const (
+	OutOfZoneReject = "reject"
+	OutOfZoneFee    = "fee"
+	OutOfZoneIgnore = "off"
+)
+
+// Policy decides how returns outside the allowed return zone are handled
+type Policy struct {
+	// OutOfZone is OutOfZoneReject, OutOfZoneFee or OutOfZoneIgnore
+	OutOfZone string
+	// Fee is charged for a return outside the zone with OutOfZoneFee
+	Fee float64
+	// MaxPositionAge is how old the last reported position of a vehicle may
+	// be to check its return. An older position may not be where the vehicle
+	// is returned, so it is not checked. 0 checks a position of any age.
+	MaxPositionAge time.Duration
+}
+
+// Stale reports whether a position reported at reportedAt is too old to
+// check a return at now
+func (p Policy) Stale(reportedAt, now time.Time) bool {
+	return p.MaxPositionAge > 0 && now.Sub(reportedAt) > p.MaxPositionAge
+}
+
+// Settings is the configuration of a Policy
+type Settings struct {
+	OutOfZone      string        `env:"GEOFENCE_OUT_OF_ZONE" default:"reject" usage:"What to do with a vehicle returned outside the allowed return zone (reject, fee or off)"`
+	Fee            float64       `env:"GEOFENCE_OUT_OF_ZONE_FEE" default:"50" usage:"Fee charged for a return outside the allowed return zone with GEOFENCE_OUT_OF_ZONE=fee"`
+	MaxPositionAge time.Duration `env:"GEOFENCE_MAX_POSITION_AGE" default:"15m" usage:"How recent the last reported position of a vehicle must be to check its return zone; 0 accepts any age"`
+}
+
+// Policy builds the policy described by s
+func (s Settings) Policy() (Policy, error) {
+	if s.Fee < 0 || math.IsNaN(s.Fee) {
+		return Policy{}, fmt.Errorf("GEOFENCE_OUT_OF_ZONE_FEE must not be negative, got %g", s.Fee)
+	}
+	if s.MaxPositionAge < 0 {
+		return Policy{}, fmt.Errorf("GEOFENCE_MAX_POSITION_AGE must not be negative, got %s", s.MaxPositionAge)
+	}
+	switch s.OutOfZone {
+	case "":
+		s.OutOfZone = OutOfZoneIgnore
+	case OutOfZoneReject, OutOfZoneFee, OutOfZoneIgnore:
+	default:
+		return Policy{}, fmt.Errorf("GEOFENCE_OUT_OF_ZONE must be reject, fee or off, got %q", s.OutOfZone)
+	}
+	return Policy{OutOfZone: s.OutOfZone, Fee: s.Fee, MaxPositionAge: s.MaxPositionAge}, nil
+}
+
+// zone is a geofence with its parsed geometry
+type zone struct {
+	models.Geofence
+	area geo.Area
+}
+
+// Zones are the geofences of the fleet, ready to check positions against
+type Zones []zone
+
+// Compile parses the geometry of geofences. Geofences that do not parse,
+// which the admin API keeps out of the store, are skipped.
+func Compile(geofences []models.Geofence) Zones {
+	zones := make(Zones, 0, len(geofences))
+	for _, g := range geofences {
+		if area, err := geo.ParseGeoJSON(g.Geometry); err == nil {
+			zones = append(zones, zone{Geofence: g, area: area})
+		}
+	}
+	return zones
+}
+
+// Load compiles the stored geofences
+func Load() (Zones, error) {
+	geofences, err := models.GetGeofences()
+	if err != nil {
+		return nil, err
+	}
+	return Compile(geofences), nil
+}
+
+// HasServiceArea reports whether any service area is drawn
+func (z Zones) HasServiceArea() bool {
+	for _, zone := range z {
+		if zone.Kind == models.ZoneServiceArea {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckReturn tells whether a vehicle at p may be returned to stationID. It
+// returns an empty string when it may, or the reason why it may not.
+func (z Zones) CheckReturn(p geo.Point, stationID int) string {
+	var lots []zone
+	for _, zone := range z {
+		if zone.Kind == models.ZoneStationLot && zone.StationID != nil && *zone.StationID == stationID {
+			lots = append(lots, zone)
+		}
+	}
+	if len(lots) > 0 {
+		for _, lot := range lots {
+			if lot.area.Contains(p) {
+				return ""
+			}
+		}
+		return "The vehicle must be returned inside " + lots[0].Name
+	}
+
+	inServiceArea := !z.HasServiceArea()
+	for _, zone := range z {
+		switch {
+		case zone.Kind == models.ZoneNoParking && zone.area.Contains(p):
+			return "The vehicle must not be returned inside the no-parking zone " + zone.Name
+		case zone.Kind == models.ZoneServiceArea && zone.area.Contains(p):
+			inServiceArea = true
+		}
+	}
+	if !inServiceArea {
+		return "The vehicle must be returned inside the service area"
+	}
+	return ""
+}
+
+// Exits returns the service areas that contain from but not to, that is the
+// ones a vehicle driving from one position to the other has left
+func (z Zones) Exits(from, to geo.Point) []models.Geofence {
+	var exits []models.Geofence
+	for _, zone := range z {
+		if zone.Kind == models.ZoneServiceArea && zone.area.Contains(from) && !zone.area.Contains(to) {
+			exits = append(exits, zone.Geofence)
+		}
+	}
+	return exits
+}
@@ -0,0 +1,84 @@
+package geofence
+
+import (
+	"car_system/common/settings"
+	"car_system/vehicle_service/geo"
+	"car_system/vehicle_service/models"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// square returns a GeoJSON polygon from (lon, lat) to (lon+size, lat+size)
+func square(lon, lat, size float64) json.RawMessage {
+	b, _ := json.Marshal(map[string]interface{}{
+		"type":        "Polygon",
+		"coordinates": [][][]float64{{{lon, lat}, {lon + size, lat}, {lon + size, lat + size}, {lon, lat + size}, {lon, lat}}},
+	})
+	return b
+}
+
+func TestCheckReturn(t *testing.T) {
+	lotStation := 1
+	zones := Compile([]models.Geofence{
+		{GeofenceID: 1, Name: "Singapore", Kind: models.ZoneServiceArea, Geometry: square(103.6, 1.2, 0.4)},
+		{GeofenceID: 2, Name: "Marina Bay", Kind: models.ZoneNoParking, Geometry: square(103.85, 1.28, 0.01)},
+		{GeofenceID: 3, Name: "Downtown lot", Kind: models.ZoneStationLot, StationID: &lotStation, Geometry: square(103.852, 1.279, 0.001)},
+		{GeofenceID: 4, Name: "Broken", Kind: models.ZoneNoParking, Geometry: json.RawMessage(`{}`)},
+	})
+	if len(zones) != 3 {
+		t.Fatalf("compiled %d zones, want 3 without the broken one", len(zones))
+	}
+
+	for _, tc := range []struct {
+		name    string
+		p       geo.Point
+		station int
+		want    string
+	}{
+		{"in the lot", geo.Point{Lat: 1.2795, Lon: 103.8525}, 1, ""},
+		{"next to the lot", geo.Point{Lat: 1.2900, Lon: 103.8400}, 1, "The vehicle must be returned inside Downtown lot"},
+		{"in the service area", geo.Point{Lat: 1.2900, Lon: 103.8400}, 2, ""},
+		{"in the no-parking zone", geo.Point{Lat: 1.2850, Lon: 103.8550}, 2, "The vehicle must not be returned inside the no-parking zone Marina Bay"},
+		{"in Malaysia", geo.Point{Lat: 1.6500, Lon: 103.7600}, 2, "The vehicle must be returned inside the service area"},
+	} {
+		if got := zones.CheckReturn(tc.p, tc.station); got != tc.want {
+			t.Errorf("%s: CheckReturn = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	if got := Zones(nil).CheckReturn(geo.Point{Lat: 48.8566, Lon: 2.3522}, 2); got != "" {
+		t.Errorf("without geofences: CheckReturn = %q, want any position allowed", got)
+	}
+
+	exits := zones.Exits(geo.Point{Lat: 1.55, Lon: 103.76}, geo.Point{Lat: 1.65, Lon: 103.76})
+	if len(exits) != 1 || exits[0].Name != "Singapore" {
+		t.Errorf("Exits across the border = %v, want Singapore", exits)
+	}
+	if exits := zones.Exits(geo.Point{Lat: 1.29, Lon: 103.84}, geo.Point{Lat: 1.285, Lon: 103.855}); len(exits) != 0 {
+		t.Errorf("Exits into a no-parking zone = %v, want none", exits)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	var s Settings
+	if _, err := settings.Load("test", &s, nil); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := s.Policy()
+	if err != nil || policy.OutOfZone != OutOfZoneReject || policy.Fee != 50 || policy.MaxPositionAge != 15*time.Minute {
+		t.Errorf("default policy = %+v, %v; want reject with a fee of 50 for positions up to 15m old", policy, err)
+	}
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	if policy.Stale(now.Add(-15*time.Minute), now) || !policy.Stale(now.Add(-16*time.Minute), now) {
+		t.Error("a position is stale once it is more than 15m old")
+	}
+	if (Policy{}).Stale(now.AddDate(0, -1, 0), now) {
+		t.Error("without a maximum age no position is stale")
+	}
+	for _, bad := range []Settings{{OutOfZone: "tow"}, {OutOfZone: OutOfZoneFee, Fee: -1}, {MaxPositionAge: -time.Minute}} {
+		if _, err := bad.Policy(); err == nil {
+			t.Errorf("%+v.Policy() succeeded, want an error", bad)
+		}
+	}
+}
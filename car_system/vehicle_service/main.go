@@ -1,41 +1,174 @@
 package main
 
 import (
+	"car_system/common/httpserver"
+	"car_system/common/logging"
+	"car_system/common/metrics"
+	"car_system/common/migrate"
+	"car_system/common/settings"
 	"car_system/vehicle_service/config"
-	"car_system/vehicle_service/controllers"
-	"log"
-	"net/http"
-
-	"github.com/gorilla/handlers"
-	"github.com/gorilla/mux"
+	"car_system/vehicle_service/migrations"
+	"car_system/vehicle_service/models"
+	"car_system/vehicle_service/server"
+	"car_system/vehicle_service/telemetry"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 )
 
 func main() {
-	// Connect to the database
-	config.ConnectDB()
-	defer config.DB.Close()
+	// Resolve the configuration from defaults, .env, the environment and flags
+	cfg, args, err := config.Load(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// Print the resolved configuration with secrets redacted
+	if len(args) > 0 && args[0] == "config" {
+		settings.Print(os.Stdout, &cfg)
+		return
+	}
+
+	// Print the telemetry token of a vehicle, to be provisioned on the vehicle
+	if len(args) > 0 && args[0] == "telemetry-token" {
+		vehicleID := 0
+		if len(args) == 2 {
+			vehicleID, _ = strconv.Atoi(args[1])
+		}
+		if vehicleID <= 0 || cfg.Telemetry.Secret == "" {
+			fmt.Fprintln(os.Stderr, "usage: vehicle_service telemetry-token <vehicle id>, with TELEMETRY_SECRET set")
+			os.Exit(2)
+		}
+		fmt.Println(telemetry.TokenFor(cfg.Telemetry.Secret, vehicleID))
+		return
+	}
+
+	// Set up structured logging
+	logger := logging.New("vehicle_service", cfg.LogLevel)
+	slog.SetDefault(logger)
+	logger.Info("Configuration loaded", "config", settings.Map(&cfg))
+
+	// Select the storage backend: MySQL by default, in-memory with STORAGE_BACKEND=memory
+	if cfg.StorageBackend == "memory" {
+		logger.Info("Using in-memory storage")
+		store := models.NewMemoryStore()
+		store.SeedSampleFleet()
+		models.UseRepositories(store.Repositories())
+	} else {
+		// Connect to the database
+		config.ConnectDB(cfg)
+		defer config.DB.Close()
+
+		if !prepareDatabase(logger, cfg, args) {
+			return
+		}
+		models.UseRepositories(models.NewMySQLRepositories(config.DB))
+	}
 
-	// Set up router
-	router := mux.NewRouter()
+	// Expose request, reservation and connection pool metrics
+	appMetrics := metrics.New("vehicle_service")
+	if config.DB != nil {
+		appMetrics.RegisterDB(config.DB, cfg.DB.Name)
+	}
 
-	// Define API routes
-	router.HandleFunc("/available-vehicles", controllers.GetAvailableVehicles).Methods("GET")
-	router.HandleFunc("/create-reservation", controllers.CreateReservation).Methods("POST")
-	router.HandleFunc("/latest-reservation", controllers.GetLatestReservation).Methods("GET")
+	// Build the booking rules, which Config.Validate has already checked
+	bookingPolicy, err := cfg.Booking.Policy()
+	if err != nil {
+		logger.Error("Invalid booking policy", "error", err)
+		return
+	}
+	forecaster, err := cfg.Charging.Forecaster()
+	if err != nil {
+		logger.Error("Invalid charge forecast settings", "error", err)
+		return
+	}
+	estimator, err := cfg.Range.Estimator()
+	if err != nil {
+		logger.Error("Invalid range settings", "error", err)
+		return
+	}
+	zonePolicy, err := cfg.Geofence.Policy()
+	if err != nil {
+		logger.Error("Invalid geofence settings", "error", err)
+		return
+	}
 
-	// Serve static files
-	staticDir := "./static/" // Directory where your static files are located
-	router.PathPrefix("/").Handler(http.StripPrefix("/", http.FileServer(http.Dir(staticDir))))
+	// Set up the router and middleware
+	handler := server.NewHandler(server.Options{
+		Logger:           logger,
+		Metrics:          appMetrics,
+		DB:               config.DB,
+		StaticDir:        cfg.StaticDir,
+		CORSOrigins:      cfg.CORSAllowedOrigins,
+		ValidateRequests: cfg.ValidateRequests,
+		Legacy:           cfg.Legacy,
+		Idempotency:      cfg.Idempotency,
+		Booking:          bookingPolicy,
+		Charging:         forecaster,
+		Range:            estimator,
+		Telemetry:        cfg.Telemetry,
+		Geofence:         zonePolicy,
+		Maintenance:      cfg.Maintenance,
+		ServiceToken:     cfg.ServiceToken,
+		AdminToken:       cfg.AdminToken,
+	})
+
+	// Start the server and drain in-flight requests on SIGINT/SIGTERM
+	srv := httpserver.New(fmt.Sprintf(":%d", cfg.Port), handler, cfg.HTTP)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Downsample and delete expired telemetry in the background
+	go cfg.Telemetry.RunCompaction(ctx, logger)
+	// Start the work orders whose planned window has opened
+	go cfg.Maintenance.RunSync(ctx, logger)
+
+	logger.Info("Vehicle-service running", "port", cfg.Port)
+	if err := httpserver.Run(ctx, srv, cfg.HTTP.ShutdownTimeout); err != nil {
+		logger.Error("Server stopped", "error", err)
+		return
+	}
+	logger.Info("Vehicle-service shut down gracefully")
+}
 
-	// Enable CORS for cross-origin requests
-	cors := handlers.CORS(
-		handlers.AllowedOrigins([]string{"http://localhost:8080"}), // Frontend origin
-		handlers.AllowedMethods([]string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
-		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
-		handlers.AllowCredentials(),
-	)
+// prepareDatabase runs a migrate subcommand (up, down, status, seed, force) or
+// applies pending migrations. It returns false when the service should not
+// start serving requests.
+func prepareDatabase(logger *slog.Logger, cfg config.Config, args []string) bool {
+	migrator, err := migrations.New(config.DB)
+	if err != nil {
+		logger.Error("Failed to load migrations", "error", err)
+		return false
+	}
 
-	log.Println("User-service running on port 8081...")
-	log.Fatal(http.ListenAndServe(":8081", cors(router)))
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := migrate.RunCommand(context.Background(), migrator, args[1:], os.Stdout); err != nil {
+			logger.Error("Migration command failed", "error", err)
+			config.DB.Close()
+			os.Exit(1)
+		}
+		return false
+	}
 
+	// Bring the schema up to date before serving requests
+	if cfg.AutoMigrate {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			logger.Error("Failed to apply migrations", "error", err)
+			return false
+		}
+		logger.Info("Database schema is up to date", "applied", applied)
+	}
+	return true
 }
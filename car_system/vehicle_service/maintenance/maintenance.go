@@ -0,0 +1,186 @@
+// Package maintenance schedules the work orders of the fleet and tells which
+// vehicles are due for service.
+//
+// A vehicle is serviced every ServiceIntervalKM: its next service falls due
+// that far past the mileage of its last completed service, or at the next
+// multiple of the interval when it has never been serviced. A work order with
+// a mileage trigger falls due at that mileage instead, when it comes first.
+// Scheduled work orders start by themselves once their planned window opens.
+package maintenance
+
+import (
+	"car_system/vehicle_service/models"
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// Settings configures the service intervals and the start of work orders
+type Settings struct {
+	ServiceIntervalKM int           `env:"MAINTENANCE_SERVICE_INTERVAL_KM" default:"15000" usage:"Distance between two services of a vehicle; 0 reports no service due"`
+	DueWithinKM       int           `env:"MAINTENANCE_DUE_WITHIN_KM" default:"1000" usage:"How close to its next service a vehicle is reported due"`
+	SyncEvery         time.Duration `env:"MAINTENANCE_SYNC_EVERY" default:"1m" usage:"How often the work orders whose planned window has opened are started"`
+}
+
+// Validate checks the settings. The zero value is valid, reports no service
+// due and leaves work orders to be started by hand.
+func (s Settings) Validate() error {
+	switch {
+	case s.ServiceIntervalKM < 0:
+		return fmt.Errorf("MAINTENANCE_SERVICE_INTERVAL_KM must not be negative, got %d", s.ServiceIntervalKM)
+	case s.DueWithinKM < 0:
+		return fmt.Errorf("MAINTENANCE_DUE_WITHIN_KM must not be negative, got %d", s.DueWithinKM)
+	case s.SyncEvery < 0:
+		return fmt.Errorf("MAINTENANCE_SYNC_EVERY must not be negative, got %s", s.SyncEvery)
+	}
+	return nil
+}
+
+// NextServiceKM returns the mileage the next service of a vehicle at mileage
+// falls due at, given the mileage of its last service, or 0 without an
+// interval
+func (s Settings) NextServiceKM(mileage int, lastService *int) int {
+	if s.ServiceIntervalKM <= 0 {
+		return 0
+	}
+	if lastService != nil {
+		return *lastService + s.ServiceIntervalKM
+	}
+	return (mileage/s.ServiceIntervalKM + 1) * s.ServiceIntervalKM
+}
+
+// Due is a vehicle that is due, or close to due, for maintenance
+type Due struct {
+	VehicleID    int    `json:"vehicle_id"`
+	LicensePlate string `json:"license_plate"`
+	Mileage      int    `json:"mileage"`
+	// NextServiceKM is the mileage the periodic service falls due at, and
+	// DueAtKM the one the first maintenance falls due at, which is earlier
+	// when an open work order has a lower mileage trigger
+	NextServiceKM int `json:"next_service_km"`
+	DueAtKM       int `json:"due_at_km"`
+	// RemainingKM is the distance left until DueAtKM; it is negative when
+	// the maintenance is overdue
+	RemainingKM int `json:"remaining_km"`
+	// WorkOrderID is the open work order planned for it, if any
+	WorkOrderID *int `json:"work_order_id,omitempty"`
+}
+
+// DueList returns the vehicles whose maintenance falls due within
+// DueWithinKM, most urgent first, given every work order of the fleet
+func (s Settings) DueList(vehicles []models.Vehicle, orders []models.WorkOrder) []Due {
+	var due []Due
+	for _, v := range vehicles {
+		var lastService *int
+		var open []models.WorkOrder
+		for _, o := range orders {
+			switch {
+			case o.VehicleID != v.VehicleID:
+			case o.Open():
+				open = append(open, o)
+			case o.Type == models.WorkService && o.Status == models.WorkOrderCompleted && o.CompletedMileage != nil:
+				if lastService == nil || *o.CompletedMileage > *lastService {
+					lastService = o.CompletedMileage
+				}
+			}
+		}
+
+		// The first mileage trigger or the periodic service, which an open
+		// service work order without a trigger is planned for
+		next := s.NextServiceKM(v.Mileage, lastService)
+		dueAt := next
+		var planned *models.WorkOrder
+		for i, o := range open {
+			if o.MileageTrigger != nil && (dueAt == 0 || *o.MileageTrigger < dueAt || (*o.MileageTrigger == dueAt && planned == nil)) {
+				dueAt, planned = *o.MileageTrigger, &open[i]
+			}
+		}
+		for i, o := range open {
+			if planned == nil && dueAt == next && o.Type == models.WorkService {
+				planned = &open[i]
+			}
+		}
+		if dueAt == 0 || dueAt-v.Mileage > s.DueWithinKM {
+			continue
+		}
+
+		d := Due{VehicleID: v.VehicleID, LicensePlate: v.LicensePlate, Mileage: v.Mileage, NextServiceKM: next, DueAtKM: dueAt, RemainingKM: dueAt - v.Mileage}
+		if planned != nil {
+			id := planned.WorkOrderID
+			d.WorkOrderID = &id
+		}
+		due = append(due, d)
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].RemainingKM < due[j].RemainingKM })
+	return due
+}
+
+// LastServiceKM returns the mileage of the last completed service of a
+// vehicle, or nil when it has never been serviced
+func LastServiceKM(vehicleID int) (*int, error) {
+	orders, err := models.GetWorkOrders(vehicleID, models.WorkOrderCompleted)
+	if err != nil {
+		return nil, err
+	}
+	var last *int
+	for _, o := range orders {
+		if o.Type == models.WorkService && o.CompletedMileage != nil && (last == nil || *o.CompletedMileage > *last) {
+			last = o.CompletedMileage
+		}
+	}
+	return last, nil
+}
+
+// Start starts the Scheduled work orders whose planned window is open at now,
+// which puts their vehicles Under Maintenance. Work orders whose window has
+// passed are left to be rescheduled or cancelled. It returns the work orders
+// started.
+func Start(now time.Time) ([]models.WorkOrder, error) {
+	orders, err := models.GetWorkOrders(0, models.WorkOrderScheduled)
+	if err != nil {
+		return nil, err
+	}
+	var started []models.WorkOrder
+	for _, o := range orders {
+		if o.PlannedStart.After(now) {
+			break
+		}
+		if !o.PlannedEnd.After(now) {
+			continue
+		}
+		ok, err := models.StartWorkOrder(o.WorkOrderID, now.UTC())
+		if err != nil {
+			return started, err
+		}
+		if ok {
+			started = append(started, o)
+		}
+	}
+	return started, nil
+}
+
+// RunSync calls Start every SyncEvery until ctx is done. It returns at once
+// when SyncEvery is zero.
+func (s Settings) RunSync(ctx context.Context, logger *slog.Logger) {
+	if s.SyncEvery <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.SyncEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			started, err := Start(now)
+			for _, o := range started {
+				logger.Info("Work order started", "work_order_id", o.WorkOrderID, "vehicle_id", o.VehicleID, "type", o.Type)
+			}
+			if err != nil {
+				logger.Error("Starting work orders failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package maintenance
+
+import (
+	"car_system/common/settings"
+	"car_system/vehicle_service/models"
+	"testing"
+	"time"
+)
+
+func TestNextServiceKM(t *testing.T) {
+	s := Settings{ServiceIntervalKM: 15000}
+	last := 14200
+	for _, tc := range []struct {
+		mileage     int
+		lastService *int
+		want        int
+	}{
+		{12000, nil, 15000},
+		{15000, nil, 30000},
+		{16000, &last, 29200},
+	} {
+		if got := s.NextServiceKM(tc.mileage, tc.lastService); got != tc.want {
+			t.Errorf("NextServiceKM(%d, %v) = %d, want %d", tc.mileage, tc.lastService, got, tc.want)
+		}
+	}
+	if got := (Settings{}).NextServiceKM(12000, nil); got != 0 {
+		t.Errorf("NextServiceKM without an interval = %d, want 0", got)
+	}
+}
+
+func TestDueList(t *testing.T) {
+	s := Settings{ServiceIntervalKM: 15000, DueWithinKM: 1000}
+	vehicles := []models.Vehicle{
+		{VehicleID: 1, LicensePlate: "ABC123", Mileage: 14500},
+		{VehicleID: 2, LicensePlate: "XYZ789", Mileage: 15200},
+		{VehicleID: 3, LicensePlate: "JKL456", Mileage: 18000},
+		{VehicleID: 4, LicensePlate: "DEF321", Mileage: 20000},
+	}
+	km := func(v int) *int { return &v }
+	orders := []models.WorkOrder{
+		// Vehicle 1 has its first service planned
+		{WorkOrderID: 1, VehicleID: 1, Type: models.WorkService, Status: models.WorkOrderScheduled, MileageTrigger: km(15000)},
+		// Vehicle 2 was serviced late, so its next service is counted from then
+		{WorkOrderID: 2, VehicleID: 2, Type: models.WorkService, Status: models.WorkOrderCompleted, CompletedMileage: km(15100)},
+		// Vehicle 3 needs new tires before its next service
+		{WorkOrderID: 3, VehicleID: 3, Type: models.WorkTires, Status: models.WorkOrderScheduled, MileageTrigger: km(18400)},
+		// A cancelled repair of vehicle 4 is not due
+		{WorkOrderID: 4, VehicleID: 4, Type: models.WorkRepair, Status: models.WorkOrderCancelled, MileageTrigger: km(20100)},
+	}
+
+	due := s.DueList(vehicles, orders)
+	if len(due) != 2 {
+		t.Fatalf("due = %+v, want vehicles 3 and 1", due)
+	}
+	if d := due[0]; d.VehicleID != 3 || d.DueAtKM != 18400 || d.NextServiceKM != 30000 || d.RemainingKM != 400 || d.WorkOrderID == nil || *d.WorkOrderID != 3 {
+		t.Errorf("first due = %+v, want the tires of vehicle 3 in 400 km", d)
+	}
+	if d := due[1]; d.VehicleID != 1 || d.DueAtKM != 15000 || d.RemainingKM != 500 || d.WorkOrderID == nil || *d.WorkOrderID != 1 {
+		t.Errorf("second due = %+v, want the planned service of vehicle 1", d)
+	}
+
+	// Overdue vehicles come first, and nothing is due without an interval
+	// but the mileage triggers
+	vehicles[0].Mileage = 15300
+	if due := s.DueList(vehicles, orders); len(due) != 2 || due[0].VehicleID != 1 || due[0].RemainingKM != -300 {
+		t.Errorf("due = %+v, want vehicle 1 overdue first", due)
+	}
+	if due := (Settings{DueWithinKM: 1000}).DueList(vehicles[1:], orders); len(due) != 1 || due[0].VehicleID != 3 {
+		t.Errorf("due without an interval = %+v, want the tires of vehicle 3", due)
+	}
+}
+
+func TestStart(t *testing.T) {
+	store := models.NewMemoryStore()
+	store.SeedSampleFleet()
+	models.UseRepositories(store.Repositories())
+
+	at := func(hour int) time.Time { return time.Date(2030, 1, 1, hour, 0, 0, 0, time.UTC) }
+	for _, o := range []models.WorkOrder{
+		{VehicleID: 1, Type: models.WorkService, PlannedStart: at(8), PlannedEnd: at(12)},
+		{VehicleID: 2, Type: models.WorkRepair, PlannedStart: at(6), PlannedEnd: at(9)},
+		{VehicleID: 3, Type: models.WorkTires, PlannedStart: at(11), PlannedEnd: at(12)},
+	} {
+		if err := models.CreateWorkOrder(&o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the work order whose window is open at 10:00 starts; the repair
+	// whose window has passed is left to be replanned
+	started, err := Start(at(10))
+	if err != nil || len(started) != 1 || started[0].WorkOrderID != 1 {
+		t.Fatalf("Start = %+v, %v; want work order 1", started, err)
+	}
+	if v, _ := models.GetVehicleByID(1); v.Status != models.VehicleUnderMaintenance {
+		t.Errorf("vehicle 1 is %q, want it Under Maintenance", v.Status)
+	}
+	if o, _ := models.GetWorkOrderByID(2); o.Status != models.WorkOrderScheduled {
+		t.Errorf("expired work order is %q, want it still Scheduled", o.Status)
+	}
+	if started, _ := Start(at(10)); len(started) != 0 {
+		t.Errorf("second Start = %+v, want nothing left to start", started)
+	}
+}
+
+func TestDecommissionedVehicle(t *testing.T) {
+	store := models.NewMemoryStore()
+	store.SeedSampleFleet()
+	models.UseRepositories(store.Repositories())
+	retired := store.AddVehicle(models.Vehicle{LicensePlate: "OLD001", Model: "Nissan Leaf", Location: "Suburban Hub", Status: models.VehicleDecommissioned})
+
+	// Work on a decommissioned vehicle leaves it out of the fleet
+	at := func(hour int) time.Time { return time.Date(2030, 1, 1, hour, 0, 0, 0, time.UTC) }
+	order := models.WorkOrder{VehicleID: retired, Type: models.WorkRepair, PlannedStart: at(8), PlannedEnd: at(12)}
+	if err := models.CreateWorkOrder(&order); err != nil {
+		t.Fatal(err)
+	}
+	if started, err := Start(at(9)); err != nil || len(started) != 1 {
+		t.Fatalf("Start = %+v, %v; want the repair started", started, err)
+	}
+	if v, _ := models.GetVehicleByID(retired); v.Status != models.VehicleDecommissioned {
+		t.Errorf("started: vehicle is %q, want it still Decommissioned", v.Status)
+	}
+	if ok, err := models.CompleteWorkOrder(order.WorkOrderID, at(11), ""); !ok || err != nil {
+		t.Fatalf("CompleteWorkOrder = %v, %v", ok, err)
+	}
+	if v, _ := models.GetVehicleByID(retired); v.Status != models.VehicleDecommissioned {
+		t.Errorf("completed: vehicle is %q, want it still Decommissioned", v.Status)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	var s Settings
+	if _, err := settings.Load("test", &s, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if s.ServiceIntervalKM != 15000 || s.DueWithinKM != 1000 || s.SyncEvery != time.Minute {
+		t.Errorf("settings = %+v", s)
+	}
+
+	for _, bad := range []Settings{
+		{ServiceIntervalKM: -1},
+		{DueWithinKM: -1},
+		{SyncEvery: -time.Minute},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("%+v.Validate() succeeded, want an error", bad)
+		}
+	}
+}
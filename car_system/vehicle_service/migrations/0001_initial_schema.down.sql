@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS Rental;
+DROP TABLE IF EXISTS Reservation;
+DROP TABLE IF EXISTS Vehicle;
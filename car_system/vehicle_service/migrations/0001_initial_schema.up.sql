@@ -0,0 +1,43 @@
+-- Baseline of the vehicle_service schema. IF NOT EXISTS lets databases created
+-- from the old schema.sql adopt the migration history without data loss.
+
+-- Vehicle Table
+CREATE TABLE IF NOT EXISTS Vehicle (
+    vehicle_id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    license_plate VARCHAR(20) UNIQUE NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    charge_level DECIMAL(5, 2) NOT NULL,
+    location VARCHAR(255) NOT NULL,
+    rental_rate DECIMAL(10, 2) NOT NULL,
+    mileage INT NOT NULL,
+    status ENUM('Operational', 'Decommissioned', 'Under Maintenance') DEFAULT 'Operational',
+    battery_capacity_kwh DECIMAL(5, 2) DEFAULT NULL,
+    reservation_status VARCHAR(50) DEFAULT 'Available'
+);
+
+-- Reservation Table
+CREATE TABLE IF NOT EXISTS Reservation (
+    reservation_id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    vehicle_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    start_time DATETIME NOT NULL,
+    end_time DATETIME NOT NULL,
+    expected_charge_level DECIMAL(5, 2) NOT NULL,
+    status ENUM('Active', 'Completed', 'Cancelled') NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (vehicle_id) REFERENCES Vehicle(vehicle_id),
+    CHECK (start_time < end_time)
+);
+
+-- Rental Table
+CREATE TABLE IF NOT EXISTS Rental (
+    rental_id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    reservation_id INT UNSIGNED NOT NULL,
+    start_date DATETIME NOT NULL,
+    end_date DATETIME NOT NULL,
+    rental_fee DECIMAL(10, 2) NOT NULL,
+    payment_status ENUM('Pending', 'Paid', 'Refunded') NOT NULL,
+    payment_amount DECIMAL(10, 2) DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (reservation_id) REFERENCES Reservation(reservation_id)
+);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS VehicleTelemetryRollup;
+DROP TABLE IF EXISTS VehicleTelemetry;
+
+ALTER TABLE Vehicle
+    DROP COLUMN telemetry_at,
+    DROP COLUMN locked,
+    DROP COLUMN longitude,
+    DROP COLUMN latitude;
@@ -0,0 +1,41 @@
+-- Telemetry reported by the vehicles, see POST /v1/vehicles/{id}/telemetry.
+-- Raw readings are kept for TELEMETRY_RAW_RETENTION, then replaced by one
+-- rollup per TELEMETRY_DOWNSAMPLE_INTERVAL, kept for TELEMETRY_RETENTION.
+
+-- Last reported state of each vehicle; charge_level and mileage are updated too
+ALTER TABLE Vehicle
+    ADD COLUMN latitude DOUBLE DEFAULT NULL,
+    ADD COLUMN longitude DOUBLE DEFAULT NULL,
+    ADD COLUMN locked BOOLEAN DEFAULT NULL,
+    ADD COLUMN telemetry_at DATETIME(3) DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS VehicleTelemetry (
+    vehicle_id INT UNSIGNED NOT NULL,
+    recorded_at DATETIME(3) NOT NULL,
+    latitude DOUBLE NOT NULL,
+    longitude DOUBLE NOT NULL,
+    charge_level DECIMAL(5, 2) NOT NULL,
+    odometer_km DECIMAL(10, 1) NOT NULL,
+    locked BOOLEAN NOT NULL,
+    speed_kmh DECIMAL(5, 1) NOT NULL,
+    PRIMARY KEY (vehicle_id, recorded_at),
+    INDEX idx_telemetry_recorded_at (recorded_at),
+    FOREIGN KEY (vehicle_id) REFERENCES Vehicle(vehicle_id)
+);
+
+CREATE TABLE IF NOT EXISTS VehicleTelemetryRollup (
+    vehicle_id INT UNSIGNED NOT NULL,
+    bucket_start DATETIME NOT NULL,
+    bucket_end DATETIME NOT NULL,
+    reading_count INT NOT NULL,
+    latitude DOUBLE NOT NULL,
+    longitude DOUBLE NOT NULL,
+    charge_level DECIMAL(5, 2) NOT NULL,
+    odometer_km DECIMAL(10, 1) NOT NULL,
+    locked BOOLEAN NOT NULL,
+    avg_speed_kmh DECIMAL(5, 1) NOT NULL,
+    max_speed_kmh DECIMAL(5, 1) NOT NULL,
+    PRIMARY KEY (vehicle_id, bucket_start),
+    INDEX idx_telemetry_rollup_bucket_start (bucket_start),
+    FOREIGN KEY (vehicle_id) REFERENCES Vehicle(vehicle_id)
+);
@@ -0,0 +1,11 @@
+ALTER TABLE Vehicle ADD COLUMN location VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE Vehicle v
+JOIN Station s ON s.station_id = v.station_id
+SET v.location = s.name;
+
+ALTER TABLE Vehicle ALTER COLUMN location DROP DEFAULT;
+ALTER TABLE Vehicle DROP FOREIGN KEY fk_vehicle_station;
+ALTER TABLE Vehicle DROP COLUMN station_id;
+
+DROP TABLE IF EXISTS Station;
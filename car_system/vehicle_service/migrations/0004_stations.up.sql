@@ -0,0 +1,41 @@
+-- Stations replace the free-text Vehicle.location. Every distinct location
+-- becomes a station of that name; the stations of the sample fleet get their
+-- position, address, capacity and opening hours, the others only a name until
+-- they are filled in.
+
+CREATE TABLE IF NOT EXISTS Station (
+    station_id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL,
+    address VARCHAR(255) NOT NULL DEFAULT '',
+    latitude DOUBLE DEFAULT NULL,
+    longitude DOUBLE DEFAULT NULL,
+    -- Parking spaces; 0 is not limited
+    capacity INT UNSIGNED NOT NULL DEFAULT 0,
+    -- Daily opening hours as HH:MM-HH:MM; empty is open around the clock
+    opening_hours VARCHAR(11) NOT NULL DEFAULT ''
+);
+
+INSERT IGNORE INTO Station (name)
+SELECT DISTINCT location FROM Vehicle;
+
+UPDATE Station SET address = '1 Raffles Place, Singapore 048616', latitude = 1.2796, longitude = 103.8525, capacity = 20
+WHERE name = 'Downtown Station';
+UPDATE Station SET address = '60 Airport Boulevard, Singapore 819643', latitude = 1.3644, longitude = 103.9915, capacity = 40
+WHERE name = 'Airport Terminal';
+UPDATE Station SET address = '1 Jurong West Central 2, Singapore 648886', latitude = 1.3496, longitude = 103.7490, capacity = 15, opening_hours = '06:00-23:00'
+WHERE name = 'Suburban Hub';
+UPDATE Station SET address = '1 St Andrew''s Road, Singapore 178957', latitude = 1.2931, longitude = 103.8520, capacity = 25, opening_hours = '07:00-23:00'
+WHERE name = 'City Center';
+UPDATE Station SET address = '30 Scotts Road, Singapore 228220', latitude = 1.3008, longitude = 103.8391, capacity = 10, opening_hours = '05:30-00:30'
+WHERE name = 'Train Station';
+
+ALTER TABLE Vehicle ADD COLUMN station_id INT UNSIGNED DEFAULT NULL;
+
+UPDATE Vehicle v
+JOIN Station s ON s.name = v.location
+SET v.station_id = s.station_id;
+
+ALTER TABLE Vehicle
+    MODIFY station_id INT UNSIGNED NOT NULL,
+    ADD CONSTRAINT fk_vehicle_station FOREIGN KEY (station_id) REFERENCES Station(station_id),
+    DROP COLUMN location;
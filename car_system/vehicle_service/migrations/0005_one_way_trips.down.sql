@@ -0,0 +1,10 @@
+ALTER TABLE Reservation
+    DROP FOREIGN KEY fk_reservation_pickup_station,
+    DROP FOREIGN KEY fk_reservation_return_station;
+
+ALTER TABLE Reservation
+    DROP INDEX idx_reservation_transfers,
+    DROP COLUMN pickup_station_id,
+    DROP COLUMN return_station_id,
+    DROP COLUMN one_way_fee,
+    DROP COLUMN completed_at;
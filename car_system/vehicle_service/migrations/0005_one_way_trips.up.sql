@@ -0,0 +1,20 @@
+-- One-way trips: a reservation is picked up at one station and returned to
+-- another, for an extra fee. Existing reservations are round trips from the
+-- current station of their vehicle.
+
+ALTER TABLE Reservation
+    ADD COLUMN pickup_station_id INT UNSIGNED DEFAULT NULL,
+    ADD COLUMN return_station_id INT UNSIGNED DEFAULT NULL,
+    ADD COLUMN one_way_fee DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN completed_at DATETIME DEFAULT NULL;
+
+UPDATE Reservation r
+JOIN Vehicle v ON v.vehicle_id = r.vehicle_id
+SET r.pickup_station_id = v.station_id, r.return_station_id = v.station_id;
+
+ALTER TABLE Reservation
+    MODIFY pickup_station_id INT UNSIGNED NOT NULL,
+    MODIFY return_station_id INT UNSIGNED NOT NULL,
+    ADD CONSTRAINT fk_reservation_pickup_station FOREIGN KEY (pickup_station_id) REFERENCES Station(station_id),
+    ADD CONSTRAINT fk_reservation_return_station FOREIGN KEY (return_station_id) REFERENCES Station(station_id),
+    ADD INDEX idx_reservation_transfers (status, pickup_station_id, return_station_id);
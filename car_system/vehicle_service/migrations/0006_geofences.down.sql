@@ -0,0 +1,4 @@
+ALTER TABLE Reservation DROP COLUMN out_of_zone_fee;
+
+DROP TABLE IF EXISTS ZoneExit;
+DROP TABLE IF EXISTS Geofence;
@@ -0,0 +1,31 @@
+-- Geofences: the service area, no-parking zones and station lots, drawn as
+-- GeoJSON polygons. Zone exits are the service areas left by vehicles during
+-- a reservation, detected from their telemetry.
+
+CREATE TABLE IF NOT EXISTS Geofence (
+    geofence_id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    kind ENUM('service_area', 'no_parking', 'station_lot') NOT NULL,
+    station_id INT UNSIGNED DEFAULT NULL,
+    geometry JSON NOT NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (station_id) REFERENCES Station(station_id) ON DELETE CASCADE
+);
+
+-- geofence_id is not a foreign key, so that the exits outlive a deleted
+-- geofence; geofence_name keeps its name
+CREATE TABLE IF NOT EXISTS ZoneExit (
+    zone_exit_id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    geofence_id INT UNSIGNED NOT NULL,
+    geofence_name VARCHAR(100) NOT NULL,
+    vehicle_id INT UNSIGNED NOT NULL,
+    reservation_id INT UNSIGNED NOT NULL,
+    recorded_at DATETIME(3) NOT NULL,
+    latitude DOUBLE NOT NULL,
+    longitude DOUBLE NOT NULL,
+    INDEX idx_zone_exit_reservation (reservation_id, recorded_at),
+    FOREIGN KEY (vehicle_id) REFERENCES Vehicle(vehicle_id),
+    FOREIGN KEY (reservation_id) REFERENCES Reservation(reservation_id)
+);
+
+ALTER TABLE Reservation ADD COLUMN out_of_zone_fee DECIMAL(10, 2) NOT NULL DEFAULT 0;
@@ -0,0 +1,22 @@
+-- Maintenance work orders. An open work order (Scheduled or In Progress)
+-- blocks its vehicle during its planned window, and the vehicle is Under
+-- Maintenance while the work order is In Progress.
+
+CREATE TABLE IF NOT EXISTS WorkOrder (
+    work_order_id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    vehicle_id INT UNSIGNED NOT NULL,
+    type ENUM('service', 'inspection', 'repair', 'tires', 'battery') NOT NULL,
+    status ENUM('Scheduled', 'In Progress', 'Completed', 'Cancelled') NOT NULL DEFAULT 'Scheduled',
+    planned_start DATETIME NOT NULL,
+    planned_end DATETIME NOT NULL,
+    mileage_trigger INT DEFAULT NULL,
+    notes VARCHAR(1000) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    started_at DATETIME DEFAULT NULL,
+    completed_at DATETIME DEFAULT NULL,
+    completed_mileage INT DEFAULT NULL,
+    completion_notes VARCHAR(1000) DEFAULT NULL,
+    INDEX idx_work_order_window (vehicle_id, planned_start, planned_end),
+    FOREIGN KEY (vehicle_id) REFERENCES Vehicle(vehicle_id),
+    CHECK (planned_start < planned_end)
+);
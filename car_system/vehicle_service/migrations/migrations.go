@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"car_system/common/migrate"
+	"database/sql"
+	"embed"
+	"io/fs"
+)
+
+//go:embed *.sql
+var migrationFiles embed.FS
+
+//go:embed seed/*.sql
+var seedFiles embed.FS
+
+// New returns a migrator for the vehicle_service schema
+func New(db *sql.DB) (*migrate.Migrator, error) {
+	seeds, err := fs.Sub(seedFiles, "seed")
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(db, "vehicle_service_migrations", migrationFiles, seeds)
+}
@@ -0,0 +1,38 @@
+-- Sample data for local development. Apply with: go run . migrate seed
+
+-- Station Data
+INSERT IGNORE INTO Station (name, address, latitude, longitude, capacity, opening_hours)
+VALUES
+('Downtown Station', '1 Raffles Place, Singapore 048616', 1.2796, 103.8525, 20, ''),
+('Airport Terminal', '60 Airport Boulevard, Singapore 819643', 1.3644, 103.9915, 40, ''),
+('Suburban Hub', '1 Jurong West Central 2, Singapore 648886', 1.3496, 103.7490, 15, '06:00-23:00'),
+('City Center', '1 St Andrew''s Road, Singapore 178957', 1.2931, 103.8520, 25, '07:00-23:00'),
+('Train Station', '30 Scotts Road, Singapore 228220', 1.3008, 103.8391, 10, '05:30-00:30');
+
+-- Vehicle Data
+INSERT IGNORE INTO Vehicle (license_plate, model, charge_level, station_id, rental_rate, mileage, status, battery_capacity_kwh)
+VALUES
+('ABC123', 'Tesla Model 3', 80.00, (SELECT station_id FROM Station WHERE name = 'Downtown Station'), 50.00, 12000, 'Operational', 75.00),
+('XYZ789', 'Nissan Leaf', 90.00, (SELECT station_id FROM Station WHERE name = 'Airport Terminal'), 40.00, 15000, 'Operational', 62.00),
+('JKL456', 'Chevrolet Bolt', 60.00, (SELECT station_id FROM Station WHERE name = 'Suburban Hub'), 45.00, 18000, 'Operational', 65.00),
+('DEF321', 'Hyundai Kona Electric', 50.00, (SELECT station_id FROM Station WHERE name = 'City Center'), 55.00, 20000, 'Operational', 64.00),
+('GHI654', 'BMW i3', 70.00, (SELECT station_id FROM Station WHERE name = 'Train Station'), 60.00, 22000, 'Operational', 42.00);
+
+-- Reservation Data
+-- Round trips from the station of each vehicle
+INSERT INTO Reservation (vehicle_id, user_id, start_time, end_time, expected_charge_level, status, pickup_station_id, return_station_id)
+VALUES
+(1, 1, '2024-12-10 08:00:00', '2024-12-10 12:00:00', 80.00, 'Active', (SELECT station_id FROM Vehicle WHERE vehicle_id = 1), (SELECT station_id FROM Vehicle WHERE vehicle_id = 1)),
+(2, 1, '2024-12-11 09:00:00', '2024-12-11 15:00:00', 90.00, 'Completed', (SELECT station_id FROM Vehicle WHERE vehicle_id = 2), (SELECT station_id FROM Vehicle WHERE vehicle_id = 2)),
+(3, 2, '2024-12-12 10:00:00', '2024-12-12 14:00:00', 75.00, 'Active', (SELECT station_id FROM Vehicle WHERE vehicle_id = 3), (SELECT station_id FROM Vehicle WHERE vehicle_id = 3)),
+(4, 3, '2024-12-13 11:00:00', '2024-12-13 16:00:00', 50.00, 'Cancelled', (SELECT station_id FROM Vehicle WHERE vehicle_id = 4), (SELECT station_id FROM Vehicle WHERE vehicle_id = 4)),
+(5, 4, '2024-12-14 07:00:00', '2024-12-14 10:00:00', 85.00, 'Completed', (SELECT station_id FROM Vehicle WHERE vehicle_id = 5), (SELECT station_id FROM Vehicle WHERE vehicle_id = 5));
+
+-- Rental Data
+INSERT INTO Rental (reservation_id, start_date, end_date, rental_fee, payment_status, payment_amount)
+VALUES
+(1, '2024-12-10 08:00:00', '2024-12-10 12:00:00', 200.00, 'Paid', 200.00),
+(1, '2024-12-11 09:00:00', '2024-12-11 15:00:00', 240.00, 'Paid', 240.00),
+(3, '2024-12-12 10:00:00', '2024-12-12 14:00:00', 180.00, 'Pending', NULL),
+(4, '2024-12-13 11:00:00', '2024-12-13 16:00:00', 275.00, 'Refunded', 275.00),
+(5, '2024-12-14 07:00:00', '2024-12-14 10:00:00', 165.00, 'Paid', 165.00);
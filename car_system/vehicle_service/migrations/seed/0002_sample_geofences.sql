@@ -0,0 +1,11 @@
+-- Sample geofences: the whole of Singapore as the service area, a no-parking
+-- zone on the Marina Bay waterfront and the lot of Downtown Station
+
+INSERT INTO Geofence (name, kind, station_id, geometry, updated_at)
+VALUES
+('Singapore', 'service_area', NULL,
+ '{"type": "Polygon", "coordinates": [[[103.60, 1.20], [104.05, 1.20], [104.05, 1.47], [103.60, 1.47], [103.60, 1.20]]]}', NOW()),
+('Marina Bay waterfront', 'no_parking', NULL,
+ '{"type": "Polygon", "coordinates": [[[103.8585, 1.2815], [103.8615, 1.2815], [103.8615, 1.2845], [103.8585, 1.2845], [103.8585, 1.2815]]]}', NOW()),
+('Downtown Station lot', 'station_lot', (SELECT station_id FROM Station WHERE name = 'Downtown Station'),
+ '{"type": "Polygon", "coordinates": [[[103.8520, 1.2791], [103.8530, 1.2791], [103.8530, 1.2801], [103.8520, 1.2801], [103.8520, 1.2791]]]}', NOW());
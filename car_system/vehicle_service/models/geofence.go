@@ -0,0 +1,102 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Kinds of geofence
+const (
+	// ZoneServiceArea is where vehicles may be driven and returned
+	ZoneServiceArea = "service_area"
+	// ZoneNoParking is where vehicles must not be returned, even inside the
+	// service area
+	ZoneNoParking = "no_parking"
+	// ZoneStationLot is the parking lot of a station; a vehicle returned to
+	// a station with a lot must be inside it
+	ZoneStationLot = "station_lot"
+)
+
+// Geofence is a zone of the operating area, drawn as a GeoJSON polygon
+type Geofence struct {
+	GeofenceID int    `json:"geofence_id"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	// StationID is the station of a ZoneStationLot, and unset for the other
+	// kinds
+	StationID *int `json:"station_id,omitempty"`
+	// Geometry is a GeoJSON Polygon or MultiPolygon geometry
+	Geometry  json.RawMessage `json:"geometry"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// ZoneExit records a vehicle leaving a service area during a reservation, at
+// the first reading outside of it
+type ZoneExit struct {
+	GeofenceID    int       `json:"geofence_id"`
+	GeofenceName  string    `json:"geofence_name"`
+	VehicleID     int       `json:"vehicle_id"`
+	ReservationID int       `json:"reservation_id"`
+	RecordedAt    time.Time `json:"recorded_at"`
+	Latitude      float64   `json:"latitude"`
+	Longitude     float64   `json:"longitude"`
+}
+
+// GeofenceRepository stores the geofences and the zone exits detected
+// against them
+type GeofenceRepository interface {
+	// List returns every geofence ordered by ID
+	List() ([]Geofence, error)
+	// FindByID returns the geofence, or nil when it does not exist
+	FindByID(geofenceID int) (*Geofence, error)
+	// Create inserts geofence and sets its GeofenceID and UpdatedAt
+	Create(geofence *Geofence) error
+	// Update replaces the stored geofence of the same ID and sets its
+	// UpdatedAt. It reports whether the geofence exists.
+	Update(geofence *Geofence) (bool, error)
+	// Delete removes a geofence and reports whether it existed. The zone
+	// exits detected against it are kept.
+	Delete(geofenceID int) (bool, error)
+	// RecordExits stores zone exits
+	RecordExits(exits []ZoneExit) error
+	// ExitsOf returns the zone exits of a reservation ordered by time
+	ExitsOf(reservationID int) ([]ZoneExit, error)
+}
+
+// GetGeofences returns every geofence ordered by ID
+func GetGeofences() ([]Geofence, error) {
+	return repos.Geofences.List()
+}
+
+// GetGeofenceByID fetches a single geofence, or nil when it does not exist
+func GetGeofenceByID(geofenceID int) (*Geofence, error) {
+	return repos.Geofences.FindByID(geofenceID)
+}
+
+// CreateGeofence stores a new geofence
+func CreateGeofence(geofence *Geofence) error {
+	return repos.Geofences.Create(geofence)
+}
+
+// UpdateGeofence replaces a stored geofence and reports whether it exists
+func UpdateGeofence(geofence *Geofence) (bool, error) {
+	return repos.Geofences.Update(geofence)
+}
+
+// DeleteGeofence removes a geofence and reports whether it existed
+func DeleteGeofence(geofenceID int) (bool, error) {
+	return repos.Geofences.Delete(geofenceID)
+}
+
+// RecordZoneExits stores zone exits
+func RecordZoneExits(exits []ZoneExit) error {
+	if len(exits) == 0 {
+		return nil
+	}
+	return repos.Geofences.RecordExits(exits)
+}
+
+// GetZoneExits returns the zone exits of a reservation ordered by time
+func GetZoneExits(reservationID int) ([]ZoneExit, error) {
+	return repos.Geofences.ExitsOf(reservationID)
+}
@@ -0,0 +1,140 @@
+package models
+
+import "time"
+
+// Vehicle statuses. The work orders switch a vehicle between the first two;
+// a decommissioned vehicle stays out of the fleet.
+const (
+	VehicleOperational      = "Operational"
+	VehicleUnderMaintenance = "Under Maintenance"
+	VehicleDecommissioned   = "Decommissioned"
+)
+
+// Types of work order
+const (
+	// WorkService is the periodic service due every service interval
+	WorkService    = "service"
+	WorkInspection = "inspection"
+	WorkRepair     = "repair"
+	WorkTires      = "tires"
+	WorkBattery    = "battery"
+)
+
+// WorkTypes are the types of work order, in the order they are documented
+var WorkTypes = []string{WorkService, WorkInspection, WorkRepair, WorkTires, WorkBattery}
+
+// Work order statuses. A work order is open while it is Scheduled or In
+// Progress, and only an open work order blocks its vehicle.
+const (
+	WorkOrderScheduled  = "Scheduled"
+	WorkOrderInProgress = "In Progress"
+	WorkOrderCompleted  = "Completed"
+	WorkOrderCancelled  = "Cancelled"
+)
+
+// WorkOrder is maintenance planned on a vehicle. The vehicle cannot be
+// reserved during the planned window, and it is Under Maintenance while the
+// work order is In Progress.
+type WorkOrder struct {
+	WorkOrderID  int       `json:"work_order_id"`
+	VehicleID    int       `json:"vehicle_id"`
+	Type         string    `json:"type"`
+	Status       string    `json:"status"`
+	PlannedStart time.Time `json:"planned_start"`
+	PlannedEnd   time.Time `json:"planned_end"`
+	// MileageTrigger is the mileage the work falls due at, if it depends on
+	// the mileage
+	MileageTrigger *int       `json:"mileage_trigger,omitempty"`
+	Notes          string     `json:"notes"`
+	CreatedAt      time.Time  `json:"created_at"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	// CompletedMileage is the mileage of the vehicle when the work order was
+	// completed, from which the next service is counted
+	CompletedMileage *int   `json:"completed_mileage,omitempty"`
+	CompletionNotes  string `json:"completion_notes,omitempty"`
+}
+
+// Open reports whether the work order is Scheduled or In Progress
+func (o WorkOrder) Open() bool {
+	return o.Status == WorkOrderScheduled || o.Status == WorkOrderInProgress
+}
+
+// WorkOrderRepository stores the maintenance work orders
+type WorkOrderRepository interface {
+	// List returns the work orders of a vehicle, or of every vehicle with
+	// 0, in a status, or in any with "", ordered by planned start
+	List(vehicleID int, status string) ([]WorkOrder, error)
+	// FindByID returns the work order, or nil when it does not exist
+	FindByID(workOrderID int) (*WorkOrder, error)
+	// ListOpenOverlapping returns the open work orders of a vehicle whose
+	// planned window overlaps [startTime, endTime), ordered by planned start
+	ListOpenOverlapping(vehicleID int, startTime, endTime time.Time) ([]WorkOrder, error)
+	// Create inserts a Scheduled work order and sets its WorkOrderID,
+	// Status and CreatedAt
+	Create(order *WorkOrder) error
+	// Update replaces the type, planned window, mileage trigger and notes of
+	// an open work order. It reports whether the work order is open.
+	Update(order *WorkOrder) (bool, error)
+	// Start marks a Scheduled work order In Progress at startedAt, moving
+	// its planned start back to startedAt when it starts early, and puts its
+	// vehicle Under Maintenance. It reports whether the work order was
+	// Scheduled.
+	Start(workOrderID int, startedAt time.Time) (bool, error)
+	// Complete marks an In Progress work order Completed at completedAt with
+	// notes and the mileage of its vehicle. The vehicle is Operational again
+	// unless another of its work orders is In Progress. It reports whether
+	// the work order was In Progress.
+	Complete(workOrderID int, completedAt time.Time, notes string) (bool, error)
+	// Cancel marks a Scheduled work order Cancelled and reports whether it
+	// was Scheduled
+	Cancel(workOrderID int) (bool, error)
+}
+
+// GetWorkOrders returns the work orders of a vehicle, or of every vehicle
+// with 0, in a status, or in any with "", ordered by planned start
+func GetWorkOrders(vehicleID int, status string) ([]WorkOrder, error) {
+	return repos.WorkOrders.List(vehicleID, status)
+}
+
+// GetWorkOrderByID fetches a single work order, or nil when it does not exist
+func GetWorkOrderByID(workOrderID int) (*WorkOrder, error) {
+	return repos.WorkOrders.FindByID(workOrderID)
+}
+
+// GetOpenWorkOrdersBetween returns the open work orders of a vehicle whose
+// planned window overlaps [from, to), ordered by planned start
+func GetOpenWorkOrdersBetween(vehicleID int, from, to time.Time) ([]WorkOrder, error) {
+	return repos.WorkOrders.ListOpenOverlapping(vehicleID, from, to)
+}
+
+// CreateWorkOrder stores a new Scheduled work order
+func CreateWorkOrder(order *WorkOrder) error {
+	order.Status = WorkOrderScheduled
+	return repos.WorkOrders.Create(order)
+}
+
+// UpdateWorkOrder replaces the plan of an open work order and reports whether
+// it is open
+func UpdateWorkOrder(order *WorkOrder) (bool, error) {
+	return repos.WorkOrders.Update(order)
+}
+
+// StartWorkOrder starts a Scheduled work order at startedAt and puts its
+// vehicle Under Maintenance. It reports whether the work order was Scheduled.
+func StartWorkOrder(workOrderID int, startedAt time.Time) (bool, error) {
+	return repos.WorkOrders.Start(workOrderID, startedAt)
+}
+
+// CompleteWorkOrder completes an In Progress work order at completedAt and
+// puts its vehicle back in operation. It reports whether the work order was
+// In Progress.
+func CompleteWorkOrder(workOrderID int, completedAt time.Time, notes string) (bool, error) {
+	return repos.WorkOrders.Complete(workOrderID, completedAt, notes)
+}
+
+// CancelWorkOrder cancels a Scheduled work order and reports whether it was
+// Scheduled
+func CancelWorkOrder(workOrderID int) (bool, error) {
+	return repos.WorkOrders.Cancel(workOrderID)
+}
@@ -0,0 +1,630 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory backend for local development and tests
+type MemoryStore struct {
+	mu                sync.RWMutex
+	nextVehicleID     int
+	nextReservationID int
+	nextGeofenceID    int
+	nextWorkOrderID   int
+	vehicles          map[int]Vehicle
+	stations          []Station
+	reservations      []Reservation
+	readings          []TelemetryReading
+	rollups           []TelemetryRollup
+	geofences         []Geofence
+	zoneExits         []ZoneExit
+	workOrders        []WorkOrder
+}
+
+// NewMemoryStore returns an empty store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		nextVehicleID:     1,
+		nextReservationID: 1,
+		nextGeofenceID:    1,
+		nextWorkOrderID:   1,
+		vehicles:          map[int]Vehicle{},
+	}
+}
+
+// NewMemoryRepositories returns repositories backed by a new MemoryStore
+func NewMemoryRepositories() Repositories {
+	return NewMemoryStore().Repositories()
+}
+
+// Repositories returns the repository views over the store
+func (s *MemoryStore) Repositories() Repositories {
+	return Repositories{
+		Vehicles:     memoryVehicleRepository{s},
+		Reservations: memoryReservationRepository{s},
+		Telemetry:    memoryTelemetryRepository{s},
+		Stations:     memoryStationRepository{s},
+		Geofences:    memoryGeofenceRepository{s},
+		WorkOrders:   memoryWorkOrderRepository{s},
+	}
+}
+
+// AddStation inserts a station and returns its ID
+func (s *MemoryStore) AddStation(station Station) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addStation(station)
+}
+
+func (s *MemoryStore) addStation(station Station) int {
+	station.StationID = len(s.stations) + 1
+	s.stations = append(s.stations, station)
+	return station.StationID
+}
+
+// AddVehicle inserts a vehicle and returns its ID. A vehicle without a
+// StationID is parked at the station named by its Location, which is added
+// without a position when there is none of that name.
+func (s *MemoryStore) AddVehicle(v Vehicle) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v.VehicleID = s.nextVehicleID
+	s.nextVehicleID++
+	if v.Status == "" {
+		v.Status = "Operational"
+	}
+	if v.ReservationStatus == "" {
+		v.ReservationStatus = "Available"
+	}
+	if v.StationID == 0 {
+		for _, station := range s.stations {
+			if station.Name == v.Location {
+				v.StationID = station.StationID
+			}
+		}
+		if v.StationID == 0 {
+			v.StationID = s.addStation(Station{Name: v.Location})
+		}
+	}
+	if v.StationID <= len(s.stations) {
+		v.Location = s.stations[v.StationID-1].Name
+	}
+	s.vehicles[v.VehicleID] = v
+	return v.VehicleID
+}
+
+// sampleStations are the stations of the sample seed data
+func sampleStations() []Station {
+	deg := func(v float64) *float64 { return &v }
+	return []Station{
+		{Name: "Downtown Station", Address: "1 Raffles Place, Singapore 048616", Latitude: deg(1.2796), Longitude: deg(103.8525), Capacity: 20},
+		{Name: "Airport Terminal", Address: "60 Airport Boulevard, Singapore 819643", Latitude: deg(1.3644), Longitude: deg(103.9915), Capacity: 40},
+		{Name: "Suburban Hub", Address: "1 Jurong West Central 2, Singapore 648886", Latitude: deg(1.3496), Longitude: deg(103.7490), Capacity: 15, OpeningHours: "06:00-23:00"},
+		{Name: "City Center", Address: "1 St Andrew's Road, Singapore 178957", Latitude: deg(1.2931), Longitude: deg(103.8520), Capacity: 25, OpeningHours: "07:00-23:00"},
+		{Name: "Train Station", Address: "30 Scotts Road, Singapore 228220", Latitude: deg(1.3008), Longitude: deg(103.8391), Capacity: 10, OpeningHours: "05:30-00:30"},
+	}
+}
+
+// sampleGeofences are the geofences of the sample seed data; the lot is the
+// lot of the first station
+func sampleGeofences() []Geofence {
+	downtown := 1
+	return []Geofence{
+		{Name: "Singapore", Kind: ZoneServiceArea,
+			Geometry: json.RawMessage(`{"type": "Polygon", "coordinates": [[[103.60, 1.20], [104.05, 1.20], [104.05, 1.47], [103.60, 1.47], [103.60, 1.20]]]}`)},
+		{Name: "Marina Bay waterfront", Kind: ZoneNoParking,
+			Geometry: json.RawMessage(`{"type": "Polygon", "coordinates": [[[103.8585, 1.2815], [103.8615, 1.2815], [103.8615, 1.2845], [103.8585, 1.2845], [103.8585, 1.2815]]]}`)},
+		{Name: "Downtown Station lot", Kind: ZoneStationLot, StationID: &downtown,
+			Geometry: json.RawMessage(`{"type": "Polygon", "coordinates": [[[103.8520, 1.2791], [103.8530, 1.2791], [103.8530, 1.2801], [103.8520, 1.2801], [103.8520, 1.2791]]]}`)},
+	}
+}
+
+// SeedSampleFleet adds the stations, vehicles and geofences of the sample
+// seed data
+func (s *MemoryStore) SeedSampleFleet() {
+	for _, station := range sampleStations() {
+		s.AddStation(station)
+	}
+	for _, v := range []Vehicle{
+		{LicensePlate: "ABC123", Model: "Tesla Model 3", ChargeLevel: 80, Location: "Downtown Station", RentalRate: 50, Mileage: 12000, BatteryCapacityKWH: 75},
+		{LicensePlate: "XYZ789", Model: "Nissan Leaf", ChargeLevel: 90, Location: "Airport Terminal", RentalRate: 40, Mileage: 15000, BatteryCapacityKWH: 62},
+		{LicensePlate: "JKL456", Model: "Chevrolet Bolt", ChargeLevel: 60, Location: "Suburban Hub", RentalRate: 45, Mileage: 18000, BatteryCapacityKWH: 65},
+		{LicensePlate: "DEF321", Model: "Hyundai Kona Electric", ChargeLevel: 50, Location: "City Center", RentalRate: 55, Mileage: 20000, BatteryCapacityKWH: 64},
+		{LicensePlate: "GHI654", Model: "BMW i3", ChargeLevel: 70, Location: "Train Station", RentalRate: 60, Mileage: 22000, BatteryCapacityKWH: 42},
+	} {
+		s.AddVehicle(v)
+	}
+	for _, g := range sampleGeofences() {
+		memoryGeofenceRepository{s}.Create(&g)
+	}
+}
+
+// Reservations returns a copy of every stored reservation in creation order
+func (s *MemoryStore) Reservations() []Reservation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Reservation(nil), s.reservations...)
+}
+
+type memoryVehicleRepository struct {
+	s *MemoryStore
+}
+
+func (r memoryVehicleRepository) ListAvailable() ([]Vehicle, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	var vehicles []Vehicle
+	for _, v := range r.s.vehicles {
+		if v.ReservationStatus == "Available" {
+			vehicles = append(vehicles, v)
+		}
+	}
+	sort.Slice(vehicles, func(i, j int) bool { return vehicles[i].VehicleID < vehicles[j].VehicleID })
+	return vehicles, nil
+}
+
+func (r memoryVehicleRepository) FindByID(vehicleID int) (*Vehicle, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	v, ok := r.s.vehicles[vehicleID]
+	if !ok {
+		return nil, nil
+	}
+	return &v, nil
+}
+
+func (r memoryVehicleRepository) ApplyTelemetry(reading TelemetryReading) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	v, ok := r.s.vehicles[reading.VehicleID]
+	if !ok {
+		return fmt.Errorf("vehicle %d does not exist", reading.VehicleID)
+	}
+	if v.TelemetryAt != nil && !reading.RecordedAt.After(*v.TelemetryAt) {
+		return nil
+	}
+	at, locked := reading.RecordedAt, reading.Locked
+	lat, lon := reading.Latitude, reading.Longitude
+	v.ChargeLevel = reading.ChargeLevel
+	v.Mileage = int(reading.OdometerKM)
+	v.Latitude, v.Longitude, v.Locked, v.TelemetryAt = &lat, &lon, &locked, &at
+	r.s.vehicles[v.VehicleID] = v
+	return nil
+}
+
+func (r memoryVehicleRepository) CountAtStation(stationID int) (int, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	count := 0
+	for _, v := range r.s.vehicles {
+		if v.StationID == stationID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type memoryStationRepository struct {
+	s *MemoryStore
+}
+
+func (r memoryStationRepository) List() ([]Station, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return append([]Station(nil), r.s.stations...), nil
+}
+
+func (r memoryStationRepository) FindByID(stationID int) (*Station, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	if stationID <= 0 || stationID > len(r.s.stations) {
+		return nil, nil
+	}
+	station := r.s.stations[stationID-1]
+	return &station, nil
+}
+
+type memoryReservationRepository struct {
+	s *MemoryStore
+}
+
+func (r memoryReservationRepository) CountOverlapping(vehicleID int, startTime, endTime time.Time) (int, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	count := 0
+	for _, res := range r.s.reservations {
+		if res.VehicleID == vehicleID && res.Status == "Active" && res.StartTime.Before(endTime) && res.EndTime.After(startTime) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r memoryReservationRepository) ListOverlapping(vehicleID int, startTime, endTime time.Time) ([]Reservation, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	var reservations []Reservation
+	for _, res := range r.s.reservations {
+		if res.VehicleID == vehicleID && res.StartTime.Before(endTime) && res.EndTime.After(startTime) {
+			reservations = append(reservations, res)
+		}
+	}
+	sort.Slice(reservations, func(i, j int) bool { return reservations[i].StartTime.Before(reservations[j].StartTime) })
+	return reservations, nil
+}
+
+func (r memoryReservationRepository) CountUpcoming(userID int, now time.Time) (int, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	count := 0
+	for _, res := range r.s.reservations {
+		if res.UserID == userID && res.Status == "Active" && res.EndTime.After(now) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r memoryReservationRepository) Create(reservation *Reservation) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.vehicles[reservation.VehicleID]; !ok {
+		return fmt.Errorf("vehicle %d does not exist", reservation.VehicleID)
+	}
+	if !reservation.StartTime.Before(reservation.EndTime) {
+		return fmt.Errorf("start_time must be before end_time")
+	}
+	reservation.ReservationID = r.s.nextReservationID
+	r.s.nextReservationID++
+	reservation.CreatedAt = time.Now().UTC()
+	r.s.reservations = append(r.s.reservations, *reservation)
+	return nil
+}
+
+func (r memoryReservationRepository) LatestByUserID(userID int) (*Reservation, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	// Reservations are appended in creation order, so the last match is the latest
+	for i := len(r.s.reservations) - 1; i >= 0; i-- {
+		res := r.s.reservations[i]
+		if res.UserID == userID {
+			res.RentalRate = r.s.vehicles[res.VehicleID].RentalRate
+			return &res, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r memoryReservationRepository) FindByID(reservationID int) (*Reservation, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	for _, res := range r.s.reservations {
+		if res.ReservationID == reservationID {
+			res.RentalRate = r.s.vehicles[res.VehicleID].RentalRate
+			return &res, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r memoryReservationRepository) ListTransfers(stationID int) ([]Reservation, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	var reservations []Reservation
+	for _, res := range r.s.reservations {
+		if res.Status == "Active" && res.OneWay() && (res.PickupStationID == stationID || res.ReturnStationID == stationID) {
+			reservations = append(reservations, res)
+		}
+	}
+	return reservations, nil
+}
+
+func (r memoryReservationRepository) Complete(reservationID int, completedAt time.Time, outOfZoneFee float64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i, res := range r.s.reservations {
+		if res.ReservationID != reservationID {
+			continue
+		}
+		if res.Status != "Active" {
+			return fmt.Errorf("reservation %d is %s", reservationID, res.Status)
+		}
+		res.Status, res.CompletedAt, res.OutOfZoneFee = "Completed", &completedAt, outOfZoneFee
+		r.s.reservations[i] = res
+		if res.ReturnStationID > 0 && res.ReturnStationID <= len(r.s.stations) {
+			v := r.s.vehicles[res.VehicleID]
+			v.StationID, v.Location = res.ReturnStationID, r.s.stations[res.ReturnStationID-1].Name
+			r.s.vehicles[v.VehicleID] = v
+		}
+		return nil
+	}
+	return fmt.Errorf("reservation %d does not exist", reservationID)
+}
+
+type memoryTelemetryRepository struct {
+	s *MemoryStore
+}
+
+func (r memoryTelemetryRepository) Insert(readings []TelemetryReading) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, reading := range readings {
+		if _, ok := r.s.vehicles[reading.VehicleID]; !ok {
+			return fmt.Errorf("vehicle %d does not exist", reading.VehicleID)
+		}
+	}
+next:
+	for _, reading := range readings {
+		for i, stored := range r.s.readings {
+			if stored.VehicleID == reading.VehicleID && stored.RecordedAt.Equal(reading.RecordedAt) {
+				r.s.readings[i] = reading
+				continue next
+			}
+		}
+		r.s.readings = append(r.s.readings, reading)
+	}
+	return nil
+}
+
+func (r memoryTelemetryRepository) Readings(vehicleID int, from, to time.Time) ([]TelemetryReading, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	var readings []TelemetryReading
+	for _, reading := range r.s.readings {
+		if reading.VehicleID == vehicleID && !reading.RecordedAt.Before(from) && reading.RecordedAt.Before(to) {
+			readings = append(readings, reading)
+		}
+	}
+	sort.Slice(readings, func(i, j int) bool { return readings[i].RecordedAt.Before(readings[j].RecordedAt) })
+	return readings, nil
+}
+
+func (r memoryTelemetryRepository) Rollups(vehicleID int, from, to time.Time) ([]TelemetryRollup, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	var rollups []TelemetryRollup
+	for _, rollup := range r.s.rollups {
+		if rollup.VehicleID == vehicleID && !rollup.BucketStart.Before(from) && rollup.BucketStart.Before(to) {
+			rollups = append(rollups, rollup)
+		}
+	}
+	sort.Slice(rollups, func(i, j int) bool { return rollups[i].BucketStart.Before(rollups[j].BucketStart) })
+	return rollups, nil
+}
+
+func (r memoryTelemetryRepository) Compact(rawBefore, rollupBefore time.Time, interval time.Duration) (int, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var expired, kept []TelemetryReading
+	for _, reading := range r.s.readings {
+		if reading.RecordedAt.Before(rawBefore) {
+			expired = append(expired, reading)
+		} else {
+			kept = append(kept, reading)
+		}
+	}
+	r.s.readings = kept
+
+	rollups := r.s.rollups[:0]
+	deleted := 0
+	for _, rollup := range append(r.s.rollups, Downsample(expired, interval)...) {
+		if rollup.BucketStart.Before(rollupBefore) {
+			deleted++
+			continue
+		}
+		rollups = append(rollups, rollup)
+	}
+	r.s.rollups = rollups
+	return len(expired), deleted, nil
+}
+
+type memoryGeofenceRepository struct {
+	s *MemoryStore
+}
+
+func (r memoryGeofenceRepository) List() ([]Geofence, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return append([]Geofence(nil), r.s.geofences...), nil
+}
+
+func (r memoryGeofenceRepository) FindByID(geofenceID int) (*Geofence, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	for _, g := range r.s.geofences {
+		if g.GeofenceID == geofenceID {
+			return &g, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r memoryGeofenceRepository) Create(geofence *Geofence) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	geofence.GeofenceID = r.s.nextGeofenceID
+	r.s.nextGeofenceID++
+	geofence.UpdatedAt = time.Now().UTC()
+	r.s.geofences = append(r.s.geofences, *geofence)
+	return nil
+}
+
+func (r memoryGeofenceRepository) Update(geofence *Geofence) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i, g := range r.s.geofences {
+		if g.GeofenceID == geofence.GeofenceID {
+			geofence.UpdatedAt = time.Now().UTC()
+			r.s.geofences[i] = *geofence
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memoryGeofenceRepository) Delete(geofenceID int) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i, g := range r.s.geofences {
+		if g.GeofenceID == geofenceID {
+			r.s.geofences = append(r.s.geofences[:i], r.s.geofences[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memoryGeofenceRepository) RecordExits(exits []ZoneExit) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.zoneExits = append(r.s.zoneExits, exits...)
+	return nil
+}
+
+func (r memoryGeofenceRepository) ExitsOf(reservationID int) ([]ZoneExit, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	var exits []ZoneExit
+	for _, exit := range r.s.zoneExits {
+		if exit.ReservationID == reservationID {
+			exits = append(exits, exit)
+		}
+	}
+	sort.SliceStable(exits, func(i, j int) bool { return exits[i].RecordedAt.Before(exits[j].RecordedAt) })
+	return exits, nil
+}
+
+type memoryWorkOrderRepository struct {
+	s *MemoryStore
+}
+
+func (r memoryWorkOrderRepository) List(vehicleID int, status string) ([]WorkOrder, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	var orders []WorkOrder
+	for _, o := range r.s.workOrders {
+		if (vehicleID == 0 || o.VehicleID == vehicleID) && (status == "" || o.Status == status) {
+			orders = append(orders, o)
+		}
+	}
+	sort.SliceStable(orders, func(i, j int) bool { return orders[i].PlannedStart.Before(orders[j].PlannedStart) })
+	return orders, nil
+}
+
+func (r memoryWorkOrderRepository) FindByID(workOrderID int) (*WorkOrder, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	if i := r.s.workOrderIndex(workOrderID); i >= 0 {
+		o := r.s.workOrders[i]
+		return &o, nil
+	}
+	return nil, nil
+}
+
+func (r memoryWorkOrderRepository) ListOpenOverlapping(vehicleID int, startTime, endTime time.Time) ([]WorkOrder, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	var orders []WorkOrder
+	for _, o := range r.s.workOrders {
+		if o.VehicleID == vehicleID && o.Open() && o.PlannedStart.Before(endTime) && o.PlannedEnd.After(startTime) {
+			orders = append(orders, o)
+		}
+	}
+	sort.SliceStable(orders, func(i, j int) bool { return orders[i].PlannedStart.Before(orders[j].PlannedStart) })
+	return orders, nil
+}
+
+func (r memoryWorkOrderRepository) Create(order *WorkOrder) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.vehicles[order.VehicleID]; !ok {
+		return fmt.Errorf("vehicle %d does not exist", order.VehicleID)
+	}
+	order.WorkOrderID = r.s.nextWorkOrderID
+	r.s.nextWorkOrderID++
+	order.Status = WorkOrderScheduled
+	order.CreatedAt = time.Now().UTC()
+	r.s.workOrders = append(r.s.workOrders, *order)
+	return nil
+}
+
+func (r memoryWorkOrderRepository) Update(order *WorkOrder) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.s.workOrderIndex(order.WorkOrderID)
+	if i < 0 || !r.s.workOrders[i].Open() {
+		return false, nil
+	}
+	stored := &r.s.workOrders[i]
+	stored.Type, stored.PlannedStart, stored.PlannedEnd, stored.MileageTrigger, stored.Notes = order.Type, order.PlannedStart, order.PlannedEnd, order.MileageTrigger, order.Notes
+	*order = *stored
+	return true, nil
+}
+
+func (r memoryWorkOrderRepository) Start(workOrderID int, startedAt time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.s.workOrderIndex(workOrderID)
+	if i < 0 || r.s.workOrders[i].Status != WorkOrderScheduled {
+		return false, nil
+	}
+	o := &r.s.workOrders[i]
+	o.Status, o.StartedAt = WorkOrderInProgress, &startedAt
+	if startedAt.Before(o.PlannedStart) {
+		o.PlannedStart = startedAt
+	}
+	if v := r.s.vehicles[o.VehicleID]; v.Status != VehicleDecommissioned {
+		v.Status = VehicleUnderMaintenance
+		r.s.vehicles[v.VehicleID] = v
+	}
+	return true, nil
+}
+
+func (r memoryWorkOrderRepository) Complete(workOrderID int, completedAt time.Time, notes string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.s.workOrderIndex(workOrderID)
+	if i < 0 || r.s.workOrders[i].Status != WorkOrderInProgress {
+		return false, nil
+	}
+	o := &r.s.workOrders[i]
+	v := r.s.vehicles[o.VehicleID]
+	mileage := v.Mileage
+	o.Status, o.CompletedAt, o.CompletedMileage, o.CompletionNotes = WorkOrderCompleted, &completedAt, &mileage, notes
+	if v.Status != VehicleUnderMaintenance {
+		return true, nil
+	}
+	for _, other := range r.s.workOrders {
+		if other.VehicleID == v.VehicleID && other.Status == WorkOrderInProgress {
+			return true, nil
+		}
+	}
+	v.Status = VehicleOperational
+	r.s.vehicles[v.VehicleID] = v
+	return true, nil
+}
+
+func (r memoryWorkOrderRepository) Cancel(workOrderID int) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.s.workOrderIndex(workOrderID)
+	if i < 0 || r.s.workOrders[i].Status != WorkOrderScheduled {
+		return false, nil
+	}
+	r.s.workOrders[i].Status = WorkOrderCancelled
+	return true, nil
+}
+
+// workOrderIndex returns the index of a work order in s.workOrders, or -1
+func (s *MemoryStore) workOrderIndex(workOrderID int) int {
+	for i, o := range s.workOrders {
+		if o.WorkOrderID == workOrderID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,863 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// NewMySQLRepositories returns repositories backed by the vehicle_service database
+func NewMySQLRepositories(db *sql.DB) Repositories {
+	return Repositories{
+		Vehicles:     &mysqlVehicleRepository{db: db},
+		Reservations: &mysqlReservationRepository{db: db},
+		Telemetry:    &mysqlTelemetryRepository{db: db},
+		Stations:     &mysqlStationRepository{db: db},
+		Geofences:    &mysqlGeofenceRepository{db: db},
+		WorkOrders:   &mysqlWorkOrderRepository{db: db},
+	}
+}
+
+type mysqlVehicleRepository struct {
+	db *sql.DB
+}
+
+func (r *mysqlVehicleRepository) ListAvailable() ([]Vehicle, error) {
+	query := `
+		SELECT 
+			v.vehicle_id, v.license_plate, v.model, v.charge_level, s.name, v.station_id, v.rental_rate, v.mileage, v.status, v.battery_capacity_kwh, v.reservation_status,
+			v.latitude, v.longitude, v.locked, v.telemetry_at
+		FROM Vehicle v
+		JOIN Station s ON s.station_id = v.station_id
+		WHERE v.reservation_status = 'Available'
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		slog.Error("Error querying available vehicles", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vehicles []Vehicle
+	for rows.Next() {
+		var v Vehicle
+		var state reportedState
+		if err := rows.Scan(&v.VehicleID, &v.LicensePlate, &v.Model, &v.ChargeLevel, &v.Location, &v.StationID, &v.RentalRate, &v.Mileage, &v.Status, &v.BatteryCapacityKWH, &v.ReservationStatus,
+			&state.latitude, &state.longitude, &state.locked, &state.at); err != nil {
+			slog.Error("Error scanning vehicle", "error", err)
+			return nil, err
+		}
+		if err := state.apply(&v); err != nil {
+			return nil, err
+		}
+		vehicles = append(vehicles, v)
+	}
+
+	return vehicles, rows.Err()
+}
+
+func (r *mysqlVehicleRepository) FindByID(vehicleID int) (*Vehicle, error) {
+	query := `
+		SELECT
+			v.vehicle_id, v.license_plate, v.model, v.charge_level, s.name, v.station_id, v.rental_rate, v.mileage, v.status, v.battery_capacity_kwh, v.reservation_status,
+			v.latitude, v.longitude, v.locked, v.telemetry_at
+		FROM Vehicle v
+		JOIN Station s ON s.station_id = v.station_id
+		WHERE v.vehicle_id = ?
+	`
+	var v Vehicle
+	var batteryCapacity sql.NullFloat64
+	var state reportedState
+	err := r.db.QueryRow(query, vehicleID).Scan(&v.VehicleID, &v.LicensePlate, &v.Model, &v.ChargeLevel, &v.Location, &v.StationID, &v.RentalRate, &v.Mileage, &v.Status, &batteryCapacity, &v.ReservationStatus,
+		&state.latitude, &state.longitude, &state.locked, &state.at)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	v.BatteryCapacityKWH = batteryCapacity.Float64
+	return &v, state.apply(&v)
+}
+
+func (r *mysqlVehicleRepository) ApplyTelemetry(reading TelemetryReading) error {
+	query := `
+		UPDATE Vehicle
+		SET charge_level = ?, mileage = ?, latitude = ?, longitude = ?, locked = ?, telemetry_at = ?
+		WHERE vehicle_id = ? AND (telemetry_at IS NULL OR telemetry_at < ?)
+	`
+	at := reading.RecordedAt.UTC()
+	_, err := r.db.Exec(query, reading.ChargeLevel, int(reading.OdometerKM), reading.Latitude, reading.Longitude, reading.Locked, at, reading.VehicleID, at)
+	return err
+}
+
+func (r *mysqlVehicleRepository) CountAtStation(stationID int) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM Vehicle WHERE station_id = ?`, stationID).Scan(&count)
+	return count, err
+}
+
+// reportedState holds the nullable telemetry columns of a Vehicle row
+type reportedState struct {
+	latitude, longitude sql.NullFloat64
+	locked              sql.NullBool
+	at                  sql.NullString
+}
+
+// apply copies the reported state to v when the vehicle has reported one
+func (s reportedState) apply(v *Vehicle) error {
+	if !s.at.Valid {
+		return nil
+	}
+	at, err := parseDateTime(s.at.String)
+	if err != nil {
+		return err
+	}
+	v.TelemetryAt = &at
+	v.Latitude, v.Longitude, v.Locked = &s.latitude.Float64, &s.longitude.Float64, &s.locked.Bool
+	return nil
+}
+
+type mysqlStationRepository struct {
+	db *sql.DB
+}
+
+// stationColumns are the columns scanned by scanStation
+const stationColumns = "station_id, name, address, latitude, longitude, capacity, opening_hours"
+
+// scanStation scans the stationColumns of a row
+func scanStation(row interface{ Scan(dest ...any) error }) (Station, error) {
+	var s Station
+	var lat, lon sql.NullFloat64
+	if err := row.Scan(&s.StationID, &s.Name, &s.Address, &lat, &lon, &s.Capacity, &s.OpeningHours); err != nil {
+		return Station{}, err
+	}
+	if lat.Valid && lon.Valid {
+		s.Latitude, s.Longitude = &lat.Float64, &lon.Float64
+	}
+	return s, nil
+}
+
+func (r *mysqlStationRepository) List() ([]Station, error) {
+	rows, err := r.db.Query("SELECT " + stationColumns + " FROM Station ORDER BY station_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stations []Station
+	for rows.Next() {
+		s, err := scanStation(rows)
+		if err != nil {
+			return nil, err
+		}
+		stations = append(stations, s)
+	}
+	return stations, rows.Err()
+}
+
+func (r *mysqlStationRepository) FindByID(stationID int) (*Station, error) {
+	s, err := scanStation(r.db.QueryRow("SELECT "+stationColumns+" FROM Station WHERE station_id = ?", stationID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+type mysqlReservationRepository struct {
+	db *sql.DB
+}
+
+func (r *mysqlReservationRepository) CountOverlapping(vehicleID int, startTime, endTime time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM Reservation
+		WHERE vehicle_id = ?
+		  AND status = 'Active'
+		  AND ((start_time < ? AND end_time > ?)
+		    OR (start_time < ? AND end_time > ?))
+	`
+	var count int
+	err := r.db.QueryRow(query, vehicleID, endTime, startTime, endTime, startTime).Scan(&count)
+	return count, err
+}
+
+// reservationColumns are the columns scanned by scanReservation
+const reservationColumns = "reservation_id, vehicle_id, user_id, start_time, end_time, expected_charge_level, status, created_at, pickup_station_id, return_station_id, one_way_fee, completed_at, out_of_zone_fee"
+
+// scanReservation scans the reservationColumns of a row, followed by the
+// columns scanned into extra
+func scanReservation(row interface{ Scan(dest ...any) error }, extra ...any) (Reservation, error) {
+	var res Reservation
+	var startTimeStr, endTimeStr, createdAtStr string
+	var completedAt sql.NullString
+	dest := []any{&res.ReservationID, &res.VehicleID, &res.UserID, &startTimeStr, &endTimeStr, &res.ExpectedChargeLevel, &res.Status, &createdAtStr,
+		&res.PickupStationID, &res.ReturnStationID, &res.OneWayFee, &completedAt, &res.OutOfZoneFee}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return res, err
+	}
+	var err error
+	if res.StartTime, err = parseDateTime(startTimeStr); err != nil {
+		return res, err
+	}
+	if res.EndTime, err = parseDateTime(endTimeStr); err != nil {
+		return res, err
+	}
+	if res.CreatedAt, err = parseDateTime(createdAtStr); err != nil {
+		return res, err
+	}
+	if completedAt.Valid {
+		at, err := parseDateTime(completedAt.String)
+		if err != nil {
+			return res, err
+		}
+		res.CompletedAt = &at
+	}
+	return res, nil
+}
+
+// queryReservations runs a query selecting the reservationColumns
+func (r *mysqlReservationRepository) queryReservations(query string, args ...any) ([]Reservation, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reservations []Reservation
+	for rows.Next() {
+		res, err := scanReservation(rows)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, res)
+	}
+	return reservations, rows.Err()
+}
+
+func (r *mysqlReservationRepository) ListOverlapping(vehicleID int, startTime, endTime time.Time) ([]Reservation, error) {
+	query := `
+		SELECT ` + reservationColumns + `
+		FROM Reservation
+		WHERE vehicle_id = ? AND start_time < ? AND end_time > ?
+		ORDER BY start_time
+	`
+	return r.queryReservations(query, vehicleID, endTime.UTC(), startTime.UTC())
+}
+
+func (r *mysqlReservationRepository) ListTransfers(stationID int) ([]Reservation, error) {
+	query := `
+		SELECT ` + reservationColumns + `
+		FROM Reservation
+		WHERE status = 'Active' AND pickup_station_id <> return_station_id
+		  AND (pickup_station_id = ? OR return_station_id = ?)
+	`
+	return r.queryReservations(query, stationID, stationID)
+}
+
+func (r *mysqlReservationRepository) FindByID(reservationID int) (*Reservation, error) {
+	query := `
+		SELECT ` + reservationColumns + `, (SELECT rental_rate FROM Vehicle v WHERE v.vehicle_id = Reservation.vehicle_id)
+		FROM Reservation
+		WHERE reservation_id = ?
+	`
+	var rentalRate float64
+	res, err := scanReservation(r.db.QueryRow(query, reservationID), &rentalRate)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.RentalRate = rentalRate
+	return &res, nil
+}
+
+func (r *mysqlReservationRepository) Complete(reservationID int, completedAt time.Time, outOfZoneFee float64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE Reservation
+		SET status = 'Completed', completed_at = ?, out_of_zone_fee = ?
+		WHERE reservation_id = ? AND status = 'Active'
+	`
+	result, err := tx.Exec(query, completedAt.UTC(), outOfZoneFee, reservationID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("reservation %d is not Active: %v", reservationID, err)
+	}
+	query = `
+		UPDATE Vehicle v
+		JOIN Reservation r ON r.vehicle_id = v.vehicle_id
+		SET v.station_id = r.return_station_id
+		WHERE r.reservation_id = ?
+	`
+	if _, err := tx.Exec(query, reservationID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// parseDateTime parses a DATETIME column scanned as a string, as the DSN does
+// not set parseTime. Fractional seconds are accepted too.
+func parseDateTime(s string) (time.Time, error) {
+	return time.Parse("2006-01-02 15:04:05", s)
+}
+
+func (r *mysqlReservationRepository) CountUpcoming(userID int, now time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM Reservation
+		WHERE user_id = ? AND status = 'Active' AND end_time > ?
+	`
+	var count int
+	err := r.db.QueryRow(query, userID, now.UTC()).Scan(&count)
+	return count, err
+}
+
+func (r *mysqlReservationRepository) Create(reservation *Reservation) error {
+	query := `
+		INSERT INTO Reservation (vehicle_id, user_id, start_time, end_time, expected_charge_level, status, pickup_station_id, return_station_id, one_way_fee)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, reservation.VehicleID, reservation.UserID, reservation.StartTime, reservation.EndTime, reservation.ExpectedChargeLevel, reservation.Status,
+		reservation.PickupStationID, reservation.ReturnStationID, reservation.OneWayFee)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	reservation.ReservationID = int(id)
+	reservation.CreatedAt = time.Now().UTC()
+	return nil
+}
+
+func (r *mysqlReservationRepository) LatestByUserID(userID int) (*Reservation, error) {
+	query := `
+		SELECT ` + reservationColumns + `, (SELECT rental_rate FROM Vehicle v WHERE v.vehicle_id = Reservation.vehicle_id)
+		FROM Reservation
+		WHERE user_id = ?
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	var rentalRate float64
+	res, err := scanReservation(r.db.QueryRow(query, userID), &rentalRate)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.RentalRate = rentalRate
+	return &res, nil
+}
+
+type mysqlTelemetryRepository struct {
+	db *sql.DB
+}
+
+func (r *mysqlTelemetryRepository) Insert(readings []TelemetryReading) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO VehicleTelemetry (vehicle_id, recorded_at, latitude, longitude, charge_level, odometer_km, locked, speed_kmh)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			latitude = VALUES(latitude), longitude = VALUES(longitude), charge_level = VALUES(charge_level),
+			odometer_km = VALUES(odometer_km), locked = VALUES(locked), speed_kmh = VALUES(speed_kmh)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, t := range readings {
+		if _, err := stmt.Exec(t.VehicleID, t.RecordedAt.UTC(), t.Latitude, t.Longitude, t.ChargeLevel, t.OdometerKM, t.Locked, t.SpeedKMH); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *mysqlTelemetryRepository) Readings(vehicleID int, from, to time.Time) ([]TelemetryReading, error) {
+	query := `
+		SELECT vehicle_id, recorded_at, latitude, longitude, charge_level, odometer_km, locked, speed_kmh
+		FROM VehicleTelemetry
+		WHERE vehicle_id = ? AND recorded_at >= ? AND recorded_at < ?
+		ORDER BY recorded_at
+	`
+	return queryReadings(r.db, query, vehicleID, from.UTC(), to.UTC())
+}
+
+// queryReadings runs a query selecting the columns of VehicleTelemetry
+func queryReadings(q interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}, query string, args ...any) ([]TelemetryReading, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var readings []TelemetryReading
+	for rows.Next() {
+		var t TelemetryReading
+		var recordedAt string
+		if err := rows.Scan(&t.VehicleID, &recordedAt, &t.Latitude, &t.Longitude, &t.ChargeLevel, &t.OdometerKM, &t.Locked, &t.SpeedKMH); err != nil {
+			return nil, err
+		}
+		if t.RecordedAt, err = parseDateTime(recordedAt); err != nil {
+			return nil, err
+		}
+		readings = append(readings, t)
+	}
+	return readings, rows.Err()
+}
+
+func (r *mysqlTelemetryRepository) Rollups(vehicleID int, from, to time.Time) ([]TelemetryRollup, error) {
+	query := `
+		SELECT vehicle_id, bucket_start, bucket_end, reading_count, latitude, longitude, charge_level, odometer_km, locked, avg_speed_kmh, max_speed_kmh
+		FROM VehicleTelemetryRollup
+		WHERE vehicle_id = ? AND bucket_start >= ? AND bucket_start < ?
+		ORDER BY bucket_start
+	`
+	rows, err := r.db.Query(query, vehicleID, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rollups []TelemetryRollup
+	for rows.Next() {
+		var t TelemetryRollup
+		var start, end string
+		if err := rows.Scan(&t.VehicleID, &start, &end, &t.ReadingCount, &t.Latitude, &t.Longitude, &t.ChargeLevel, &t.OdometerKM, &t.Locked, &t.AvgSpeedKMH, &t.MaxSpeedKMH); err != nil {
+			return nil, err
+		}
+		if t.BucketStart, err = parseDateTime(start); err != nil {
+			return nil, err
+		}
+		if t.BucketEnd, err = parseDateTime(end); err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, t)
+	}
+	return rollups, rows.Err()
+}
+
+func (r *mysqlTelemetryRepository) Compact(rawBefore, rollupBefore time.Time, interval time.Duration) (int, int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	expired, err := queryReadings(tx, `
+		SELECT vehicle_id, recorded_at, latitude, longitude, charge_level, odometer_km, locked, speed_kmh
+		FROM VehicleTelemetry
+		WHERE recorded_at < ?
+		FOR UPDATE
+	`, rawBefore.UTC())
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, t := range Downsample(expired, interval) {
+		_, err := tx.Exec(`
+			INSERT INTO VehicleTelemetryRollup
+				(vehicle_id, bucket_start, bucket_end, reading_count, latitude, longitude, charge_level, odometer_km, locked, avg_speed_kmh, max_speed_kmh)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, t.VehicleID, t.BucketStart.UTC(), t.BucketEnd.UTC(), t.ReadingCount, t.Latitude, t.Longitude, t.ChargeLevel, t.OdometerKM, t.Locked, t.AvgSpeedKMH, t.MaxSpeedKMH)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM VehicleTelemetry WHERE recorded_at < ?`, rawBefore.UTC()); err != nil {
+		return 0, 0, err
+	}
+	result, err := tx.Exec(`DELETE FROM VehicleTelemetryRollup WHERE bucket_start < ?`, rollupBefore.UTC())
+	if err != nil {
+		return 0, 0, err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(expired), int(deleted), tx.Commit()
+}
+
+type mysqlGeofenceRepository struct {
+	db *sql.DB
+}
+
+// geofenceColumns are the columns scanned by scanGeofence
+const geofenceColumns = "geofence_id, name, kind, station_id, geometry, updated_at"
+
+// scanGeofence scans the geofenceColumns of a row
+func scanGeofence(row interface{ Scan(dest ...any) error }) (Geofence, error) {
+	var g Geofence
+	var stationID sql.NullInt64
+	var geometry []byte
+	var updatedAt string
+	if err := row.Scan(&g.GeofenceID, &g.Name, &g.Kind, &stationID, &geometry, &updatedAt); err != nil {
+		return Geofence{}, err
+	}
+	if stationID.Valid {
+		id := int(stationID.Int64)
+		g.StationID = &id
+	}
+	g.Geometry = geometry
+	var err error
+	g.UpdatedAt, err = parseDateTime(updatedAt)
+	return g, err
+}
+
+func (r *mysqlGeofenceRepository) List() ([]Geofence, error) {
+	rows, err := r.db.Query("SELECT " + geofenceColumns + " FROM Geofence ORDER BY geofence_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var geofences []Geofence
+	for rows.Next() {
+		g, err := scanGeofence(rows)
+		if err != nil {
+			return nil, err
+		}
+		geofences = append(geofences, g)
+	}
+	return geofences, rows.Err()
+}
+
+func (r *mysqlGeofenceRepository) FindByID(geofenceID int) (*Geofence, error) {
+	g, err := scanGeofence(r.db.QueryRow("SELECT "+geofenceColumns+" FROM Geofence WHERE geofence_id = ?", geofenceID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (r *mysqlGeofenceRepository) Create(geofence *Geofence) error {
+	geofence.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	result, err := r.db.Exec(`INSERT INTO Geofence (name, kind, station_id, geometry, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		geofence.Name, geofence.Kind, geofence.StationID, string(geofence.Geometry), geofence.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	geofence.GeofenceID = int(id)
+	return nil
+}
+
+func (r *mysqlGeofenceRepository) Update(geofence *Geofence) (bool, error) {
+	updatedAt := time.Now().UTC().Truncate(time.Second)
+	result, err := r.db.Exec(`UPDATE Geofence SET name = ?, kind = ?, station_id = ?, geometry = ?, updated_at = ? WHERE geofence_id = ?`,
+		geofence.Name, geofence.Kind, geofence.StationID, string(geofence.Geometry), updatedAt, geofence.GeofenceID)
+	if err != nil {
+		return false, err
+	}
+	// MySQL reports the rows matched rather than changed only with
+	// clientFoundRows, so a missing geofence is told apart by a lookup
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		if g, err := r.FindByID(geofence.GeofenceID); err != nil || g == nil {
+			return false, err
+		}
+	}
+	geofence.UpdatedAt = updatedAt
+	return true, nil
+}
+
+func (r *mysqlGeofenceRepository) Delete(geofenceID int) (bool, error) {
+	result, err := r.db.Exec("DELETE FROM Geofence WHERE geofence_id = ?", geofenceID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *mysqlGeofenceRepository) RecordExits(exits []ZoneExit) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO ZoneExit (geofence_id, geofence_name, vehicle_id, reservation_id, recorded_at, latitude, longitude)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, e := range exits {
+		if _, err := stmt.Exec(e.GeofenceID, e.GeofenceName, e.VehicleID, e.ReservationID, e.RecordedAt.UTC(), e.Latitude, e.Longitude); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *mysqlGeofenceRepository) ExitsOf(reservationID int) ([]ZoneExit, error) {
+	query := `
+		SELECT geofence_id, geofence_name, vehicle_id, reservation_id, recorded_at, latitude, longitude
+		FROM ZoneExit
+		WHERE reservation_id = ?
+		ORDER BY recorded_at
+	`
+	rows, err := r.db.Query(query, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exits []ZoneExit
+	for rows.Next() {
+		var e ZoneExit
+		var recordedAt string
+		if err := rows.Scan(&e.GeofenceID, &e.GeofenceName, &e.VehicleID, &e.ReservationID, &recordedAt, &e.Latitude, &e.Longitude); err != nil {
+			return nil, err
+		}
+		if e.RecordedAt, err = parseDateTime(recordedAt); err != nil {
+			return nil, err
+		}
+		exits = append(exits, e)
+	}
+	return exits, rows.Err()
+}
+
+type mysqlWorkOrderRepository struct {
+	db *sql.DB
+}
+
+// workOrderColumns are the columns scanned by scanWorkOrder
+const workOrderColumns = "work_order_id, vehicle_id, type, status, planned_start, planned_end, mileage_trigger, notes, created_at, started_at, completed_at, completed_mileage, completion_notes"
+
+// scanWorkOrder scans the workOrderColumns of a row
+func scanWorkOrder(row interface{ Scan(dest ...any) error }) (WorkOrder, error) {
+	var o WorkOrder
+	var plannedStart, plannedEnd, createdAt string
+	var startedAt, completedAt, completionNotes sql.NullString
+	var mileageTrigger, completedMileage sql.NullInt64
+	if err := row.Scan(&o.WorkOrderID, &o.VehicleID, &o.Type, &o.Status, &plannedStart, &plannedEnd, &mileageTrigger, &o.Notes, &createdAt,
+		&startedAt, &completedAt, &completedMileage, &completionNotes); err != nil {
+		return o, err
+	}
+	var err error
+	if o.PlannedStart, err = parseDateTime(plannedStart); err != nil {
+		return o, err
+	}
+	if o.PlannedEnd, err = parseDateTime(plannedEnd); err != nil {
+		return o, err
+	}
+	if o.CreatedAt, err = parseDateTime(createdAt); err != nil {
+		return o, err
+	}
+	for _, t := range []struct {
+		column sql.NullString
+		field  **time.Time
+	}{{startedAt, &o.StartedAt}, {completedAt, &o.CompletedAt}} {
+		if t.column.Valid {
+			at, err := parseDateTime(t.column.String)
+			if err != nil {
+				return o, err
+			}
+			*t.field = &at
+		}
+	}
+	if mileageTrigger.Valid {
+		km := int(mileageTrigger.Int64)
+		o.MileageTrigger = &km
+	}
+	if completedMileage.Valid {
+		km := int(completedMileage.Int64)
+		o.CompletedMileage = &km
+	}
+	o.CompletionNotes = completionNotes.String
+	return o, nil
+}
+
+// queryWorkOrders runs a query selecting the workOrderColumns
+func (r *mysqlWorkOrderRepository) queryWorkOrders(query string, args ...any) ([]WorkOrder, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []WorkOrder
+	for rows.Next() {
+		o, err := scanWorkOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+	}
+	return orders, rows.Err()
+}
+
+func (r *mysqlWorkOrderRepository) List(vehicleID int, status string) ([]WorkOrder, error) {
+	query := `
+		SELECT ` + workOrderColumns + `
+		FROM WorkOrder
+		WHERE (? = 0 OR vehicle_id = ?) AND (? = '' OR status = ?)
+		ORDER BY planned_start, work_order_id
+	`
+	return r.queryWorkOrders(query, vehicleID, vehicleID, status, status)
+}
+
+func (r *mysqlWorkOrderRepository) FindByID(workOrderID int) (*WorkOrder, error) {
+	o, err := scanWorkOrder(r.db.QueryRow("SELECT "+workOrderColumns+" FROM WorkOrder WHERE work_order_id = ?", workOrderID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *mysqlWorkOrderRepository) ListOpenOverlapping(vehicleID int, startTime, endTime time.Time) ([]WorkOrder, error) {
+	query := `
+		SELECT ` + workOrderColumns + `
+		FROM WorkOrder
+		WHERE vehicle_id = ? AND status IN ('Scheduled', 'In Progress') AND planned_start < ? AND planned_end > ?
+		ORDER BY planned_start
+	`
+	return r.queryWorkOrders(query, vehicleID, endTime.UTC(), startTime.UTC())
+}
+
+func (r *mysqlWorkOrderRepository) Create(order *WorkOrder) error {
+	query := `
+		INSERT INTO WorkOrder (vehicle_id, type, status, planned_start, planned_end, mileage_trigger, notes)
+		VALUES (?, ?, 'Scheduled', ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, order.VehicleID, order.Type, order.PlannedStart.UTC(), order.PlannedEnd.UTC(), order.MileageTrigger, order.Notes)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	order.WorkOrderID = int(id)
+	order.Status = WorkOrderScheduled
+	order.CreatedAt = time.Now().UTC()
+	return nil
+}
+
+func (r *mysqlWorkOrderRepository) Update(order *WorkOrder) (bool, error) {
+	query := `
+		UPDATE WorkOrder
+		SET type = ?, planned_start = ?, planned_end = ?, mileage_trigger = ?, notes = ?
+		WHERE work_order_id = ? AND status IN ('Scheduled', 'In Progress')
+	`
+	if _, err := r.db.Exec(query, order.Type, order.PlannedStart.UTC(), order.PlannedEnd.UTC(), order.MileageTrigger, order.Notes, order.WorkOrderID); err != nil {
+		return false, err
+	}
+	// An unchanged row is not reported as affected, so the stored work order
+	// tells whether it is open
+	stored, err := r.FindByID(order.WorkOrderID)
+	if err != nil || stored == nil || !stored.Open() {
+		return false, err
+	}
+	*order = *stored
+	return true, nil
+}
+
+func (r *mysqlWorkOrderRepository) Start(workOrderID int, startedAt time.Time) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE WorkOrder
+		SET status = 'In Progress', started_at = ?, planned_start = LEAST(planned_start, ?)
+		WHERE work_order_id = ? AND status = 'Scheduled'
+	`
+	at := startedAt.UTC()
+	result, err := tx.Exec(query, at, at, workOrderID)
+	if err != nil {
+		return false, err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	query = `
+		UPDATE Vehicle v
+		JOIN WorkOrder o ON o.vehicle_id = v.vehicle_id
+		SET v.status = 'Under Maintenance'
+		WHERE o.work_order_id = ? AND v.status <> 'Decommissioned'
+	`
+	if _, err := tx.Exec(query, workOrderID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *mysqlWorkOrderRepository) Complete(workOrderID int, completedAt time.Time, notes string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE WorkOrder o
+		JOIN Vehicle v ON v.vehicle_id = o.vehicle_id
+		SET o.status = 'Completed', o.completed_at = ?, o.completed_mileage = v.mileage, o.completion_notes = ?
+		WHERE o.work_order_id = ? AND o.status = 'In Progress'
+	`
+	result, err := tx.Exec(query, completedAt.UTC(), notes, workOrderID)
+	if err != nil {
+		return false, err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	query = `
+		UPDATE Vehicle v
+		JOIN WorkOrder o ON o.vehicle_id = v.vehicle_id
+		SET v.status = 'Operational'
+		WHERE o.work_order_id = ? AND v.status = 'Under Maintenance'
+		  AND NOT EXISTS (SELECT 1 FROM (SELECT vehicle_id FROM WorkOrder WHERE status = 'In Progress') busy WHERE busy.vehicle_id = v.vehicle_id)
+	`
+	if _, err := tx.Exec(query, workOrderID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *mysqlWorkOrderRepository) Cancel(workOrderID int) (bool, error) {
+	result, err := r.db.Exec("UPDATE WorkOrder SET status = 'Cancelled' WHERE work_order_id = ? AND status = 'Scheduled'", workOrderID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// VehicleRepository stores the vehicle fleet
+type VehicleRepository interface {
+	ListAvailable() ([]Vehicle, error)
+	// FindByID returns the vehicle, or nil when it does not exist
+	FindByID(vehicleID int) (*Vehicle, error)
+	// ApplyTelemetry sets the charge level, mileage, position and lock state
+	// of the vehicle from reading, unless it holds a later reading already
+	ApplyTelemetry(reading TelemetryReading) error
+	// CountAtStation counts the vehicles parked at a station
+	CountAtStation(stationID int) (int, error)
+}
+
+// ReservationRepository stores vehicle reservations
+type ReservationRepository interface {
+	// CountOverlapping counts the Active reservations of a vehicle that
+	// overlap [startTime, endTime). A reservation completed early or
+	// cancelled no longer holds the vehicle.
+	CountOverlapping(vehicleID int, startTime, endTime time.Time) (int, error)
+	// ListOverlapping returns the reservations of a vehicle that overlap
+	// [startTime, endTime), ordered by start time
+	ListOverlapping(vehicleID int, startTime, endTime time.Time) ([]Reservation, error)
+	// CountUpcoming counts the Active reservations of a user that end after now
+	CountUpcoming(userID int, now time.Time) (int, error)
+	// Create inserts reservation and sets its ReservationID and CreatedAt
+	Create(reservation *Reservation) error
+	// LatestByUserID returns the most recently created reservation of a user
+	// with the vehicle's rental rate, or nil when there is none
+	LatestByUserID(userID int) (*Reservation, error)
+	// FindByID returns the reservation, or nil when it does not exist
+	FindByID(reservationID int) (*Reservation, error)
+	// ListTransfers returns the Active one-way reservations that leave from
+	// or return to a station
+	ListTransfers(stationID int) ([]Reservation, error)
+	// Complete marks the reservation Completed at completedAt with its
+	// OutOfZoneFee and moves its vehicle to the return station
+	Complete(reservationID int, completedAt time.Time, outOfZoneFee float64) error
+}
+
+// Repositories groups the storage backends used by the model functions
+type Repositories struct {
+	Vehicles     VehicleRepository
+	Reservations ReservationRepository
+	Telemetry    TelemetryRepository
+	Stations     StationRepository
+	Geofences    GeofenceRepository
+	WorkOrders   WorkOrderRepository
+}
+
+// repos is the storage backend selected at startup with UseRepositories
+var repos Repositories
+
+// UseRepositories selects the storage backend used by the model functions
+func UseRepositories(r Repositories) {
+	repos = r
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"car_system/vehicle_service/geo"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Station is a place where vehicles are parked between reservations
+type Station struct {
+	StationID int    `json:"station_id"`
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	// Latitude and Longitude are unset for a station migrated from a
+	// location name whose position is not known yet; it is left out of the
+	// nearby search
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	// Capacity is the number of parking spaces; 0 is not limited
+	Capacity int `json:"capacity"`
+	// OpeningHours is the daily opening time, as 06:00-22:00; empty is open
+	// around the clock
+	OpeningHours string `json:"opening_hours"`
+}
+
+// Position returns the position of the station, if it is known
+func (s Station) Position() (geo.Point, bool) {
+	if s.Latitude == nil || s.Longitude == nil {
+		return geo.Point{}, false
+	}
+	return geo.Point{Lat: *s.Latitude, Lon: *s.Longitude}, true
+}
+
+// ParseOpeningHours parses daily opening hours such as 06:00-22:00 into the
+// times of day the station opens and closes. Hours that close before they
+// open run past midnight. An empty value is open around the clock.
+func ParseOpeningHours(hours string) (open, close time.Duration, err error) {
+	if hours == "" {
+		return 0, 24 * time.Hour, nil
+	}
+	first, last, ok := strings.Cut(hours, "-")
+	if ok {
+		open, err = timeOfDay(first)
+	}
+	if ok && err == nil {
+		close, err = timeOfDay(last)
+	}
+	if !ok || err != nil {
+		return 0, 0, fmt.Errorf("expected opening hours as HH:MM-HH:MM, got %q", hours)
+	}
+	return open, close, nil
+}
+
+// timeOfDay parses HH:MM, or 24:00 for the end of the day
+func timeOfDay(s string) (time.Duration, error) {
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// OpenAt reports whether the station is open at t, with its opening hours
+// read in loc. Opening hours that do not parse are treated as open around
+// the clock.
+func (s Station) OpenAt(t time.Time, loc *time.Location) bool {
+	open, close, err := ParseOpeningHours(s.OpeningHours)
+	if err != nil || open == close || close-open == 24*time.Hour {
+		return true
+	}
+	t = t.In(loc)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	now := t.Sub(midnight)
+	if open < close {
+		return now >= open && now < close
+	}
+	return now >= open || now < close
+}
+
+// NearbyStation is a station found by a nearby search
+type NearbyStation struct {
+	Station
+	DistanceKM float64 `json:"distance_km"`
+	// Open tells whether the station is open at the start of the search
+	// window, or now
+	Open bool `json:"open"`
+	// AvailableVehicles counts the vehicles of the search parked there
+	AvailableVehicles int `json:"available_vehicles"`
+}
+
+// StationRepository stores the stations
+type StationRepository interface {
+	// List returns every station ordered by ID
+	List() ([]Station, error)
+	// FindByID returns the station, or nil when it does not exist
+	FindByID(stationID int) (*Station, error)
+}
+
+// GetStations returns every station ordered by ID
+func GetStations() ([]Station, error) {
+	return repos.Stations.List()
+}
+
+// GetStationByID fetches a single station, or nil when it does not exist
+func GetStationByID(stationID int) (*Station, error) {
+	return repos.Stations.FindByID(stationID)
+}
+
+// roundKM rounds a distance to 10 m
+func roundKM(km float64) float64 {
+	return math.Round(km*100) / 100
+}
+
+// StationsNear returns the stations within radiusKM of p, nearest first,
+// with their distance. Stations without a position are left out.
+func StationsNear(stations []Station, p geo.Point, radiusKM float64) []NearbyStation {
+	var nearby []NearbyStation
+	for _, s := range stations {
+		pos, ok := s.Position()
+		if !ok {
+			continue
+		}
+		if d := geo.DistanceKM(p, pos); d <= radiusKM {
+			nearby = append(nearby, NearbyStation{Station: s, DistanceKM: roundKM(d)})
+		}
+	}
+	sort.SliceStable(nearby, func(i, j int) bool { return nearby[i].DistanceKM < nearby[j].DistanceKM })
+	return nearby
+}
+
+// VehiclesNear returns the vehicles within radiusKM of p, nearest first, with
+// their DistanceKM set. A vehicle is where it last reported to be, or at its
+// station before its first report; vehicles without either position are left
+// out.
+func VehiclesNear(vehicles []Vehicle, stations []Station, p geo.Point, radiusKM float64) []Vehicle {
+	positions := make(map[int]geo.Point, len(stations))
+	for _, s := range stations {
+		if pos, ok := s.Position(); ok {
+			positions[s.StationID] = pos
+		}
+	}
+	var nearby []Vehicle
+	for _, v := range vehicles {
+		pos, ok := positions[v.StationID]
+		if v.Latitude != nil && v.Longitude != nil {
+			pos, ok = geo.Point{Lat: *v.Latitude, Lon: *v.Longitude}, true
+		}
+		if !ok {
+			continue
+		}
+		if d := geo.DistanceKM(p, pos); d <= radiusKM {
+			d = roundKM(d)
+			v.DistanceKM = &d
+			nearby = append(nearby, v)
+		}
+	}
+	sort.SliceStable(nearby, func(i, j int) bool { return *nearby[i].DistanceKM < *nearby[j].DistanceKM })
+	return nearby
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// TelemetryReading is the state reported by a vehicle at one point in time
+type TelemetryReading struct {
+	VehicleID   int       `json:"vehicle_id"`
+	RecordedAt  time.Time `json:"recorded_at"`
+	Latitude    float64   `json:"latitude"`
+	Longitude   float64   `json:"longitude"`
+	ChargeLevel float64   `json:"charge_level"`
+	OdometerKM  float64   `json:"odometer_km"`
+	Locked      bool      `json:"locked"`
+	SpeedKMH    float64   `json:"speed_kmh"`
+}
+
+// TelemetryRollup summarizes the readings of a vehicle in one interval once
+// the raw readings have expired. It keeps the last reported state and the
+// average and top speed.
+type TelemetryRollup struct {
+	VehicleID    int       `json:"vehicle_id"`
+	BucketStart  time.Time `json:"bucket_start"`
+	BucketEnd    time.Time `json:"bucket_end"`
+	ReadingCount int       `json:"reading_count"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	ChargeLevel  float64   `json:"charge_level"`
+	OdometerKM   float64   `json:"odometer_km"`
+	Locked       bool      `json:"locked"`
+	AvgSpeedKMH  float64   `json:"avg_speed_kmh"`
+	MaxSpeedKMH  float64   `json:"max_speed_kmh"`
+}
+
+// TelemetryRepository stores the telemetry of the fleet
+type TelemetryRepository interface {
+	// Insert stores readings; a reading with the same vehicle and time as a
+	// stored one replaces it
+	Insert(readings []TelemetryReading) error
+	// Readings returns the raw readings of a vehicle in [from, to), ordered
+	// by time
+	Readings(vehicleID int, from, to time.Time) ([]TelemetryReading, error)
+	// Rollups returns the rollups of a vehicle whose bucket starts in
+	// [from, to), ordered by time
+	Rollups(vehicleID int, from, to time.Time) ([]TelemetryRollup, error)
+	// Compact replaces the raw readings before rawBefore with one rollup per
+	// interval and deletes the rollups before rollupBefore. It returns how
+	// many readings were rolled up and how many rollups were deleted.
+	Compact(rawBefore, rollupBefore time.Time, interval time.Duration) (rolledUp, deleted int, err error)
+}
+
+// Downsample groups readings by vehicle and by interval, counted from the
+// zero time, into one rollup each, ordered by vehicle and time
+func Downsample(readings []TelemetryReading, interval time.Duration) []TelemetryRollup {
+	sorted := append([]TelemetryReading(nil), readings...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].VehicleID != sorted[j].VehicleID {
+			return sorted[i].VehicleID < sorted[j].VehicleID
+		}
+		return sorted[i].RecordedAt.Before(sorted[j].RecordedAt)
+	})
+
+	var rollups []TelemetryRollup
+	for _, reading := range sorted {
+		start := reading.RecordedAt.Truncate(interval)
+		if n := len(rollups); n == 0 || rollups[n-1].VehicleID != reading.VehicleID || !rollups[n-1].BucketStart.Equal(start) {
+			rollups = append(rollups, TelemetryRollup{VehicleID: reading.VehicleID, BucketStart: start, BucketEnd: start.Add(interval)})
+		}
+		r := &rollups[len(rollups)-1]
+		r.ReadingCount++
+		r.Latitude, r.Longitude = reading.Latitude, reading.Longitude
+		r.ChargeLevel, r.OdometerKM, r.Locked = reading.ChargeLevel, reading.OdometerKM, reading.Locked
+		r.MaxSpeedKMH = math.Max(r.MaxSpeedKMH, reading.SpeedKMH)
+		// Summed here, averaged below
+		r.AvgSpeedKMH += reading.SpeedKMH
+	}
+	for i := range rollups {
+		rollups[i].AvgSpeedKMH = math.Round(rollups[i].AvgSpeedKMH/float64(rollups[i].ReadingCount)*10) / 10
+	}
+	return rollups
+}
+
+// RecordTelemetry stores readings and brings the vehicle up to date with the
+// latest of them, unless it already reflects a later one
+func RecordTelemetry(readings []TelemetryReading) error {
+	if len(readings) == 0 {
+		return nil
+	}
+	if err := repos.Telemetry.Insert(readings); err != nil {
+		return err
+	}
+	latest := readings[0]
+	for _, reading := range readings[1:] {
+		if reading.RecordedAt.After(latest.RecordedAt) {
+			latest = reading
+		}
+	}
+	return repos.Vehicles.ApplyTelemetry(latest)
+}
+
+// GetTelemetry returns the raw readings and the rollups of a vehicle in
+// [from, to)
+func GetTelemetry(vehicleID int, from, to time.Time) ([]TelemetryReading, []TelemetryRollup, error) {
+	readings, err := repos.Telemetry.Readings(vehicleID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	rollups, err := repos.Telemetry.Rollups(vehicleID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	return readings, rollups, nil
+}
+
+// CompactTelemetry downsamples the expired raw readings and deletes the
+// expired rollups, see TelemetryRepository.Compact
+func CompactTelemetry(rawBefore, rollupBefore time.Time, interval time.Duration) (int, int, error) {
+	return repos.Telemetry.Compact(rawBefore, rollupBefore, interval)
+}
@@ -1,8 +1,7 @@
 package models
 
 import (
-	"car_system/vehicle_service/config"
-	"log"
+	"sort"
 	"time"
 )
 
@@ -17,139 +16,337 @@ type Reservation struct {
 	Status              string    `json:"status"`
 	CreatedAt           time.Time `json:"created_at"`
 	RentalRate          float64   `json:"vehicle_rental_rate"`
+	// PickupStationID is where the vehicle is at StartTime and
+	// ReturnStationID where it is returned; they differ for a one-way trip,
+	// which is charged OneWayFee
+	PickupStationID int        `json:"pickup_station_id"`
+	ReturnStationID int        `json:"return_station_id"`
+	OneWayFee       float64    `json:"one_way_fee"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	// OutOfZoneFee is charged when the vehicle was returned outside the
+	// allowed return zone
+	OutOfZoneFee float64 `json:"out_of_zone_fee"`
+}
+
+// OneWay reports whether the reservation returns the vehicle to another
+// station than the one it is picked up at
+func (r Reservation) OneWay() bool {
+	return r.ReturnStationID != r.PickupStationID
 }
 
 // Vehicle represents a vehicle in the database
 type Vehicle struct {
-	VehicleID          int     `json:"vehicle_id"`
-	LicensePlate       string  `json:"license_plate"`
-	Model              string  `json:"model"`
-	ChargeLevel        float64 `json:"charge_level"`
+	VehicleID    int     `json:"vehicle_id"`
+	LicensePlate string  `json:"license_plate"`
+	Model        string  `json:"model"`
+	ChargeLevel  float64 `json:"charge_level"`
+	// StationID is the station the vehicle is parked at, and Location its name
 	Location           string  `json:"location"`
+	StationID          int     `json:"station_id"`
 	RentalRate         float64 `json:"rental_rate"`
 	Mileage            int     `json:"mileage"`
 	Status             string  `json:"status"`
 	BatteryCapacityKWH float64 `json:"battery_capacity_kwh,omitempty"`
 	ReservationStatus  string  `json:"reservation_status"`
 	Cleanliness        string  `json:"cleanliness"`
+	// Latitude, Longitude and Locked are the last state reported by the
+	// vehicle at TelemetryAt; they are unset before its first report
+	Latitude    *float64   `json:"latitude,omitempty"`
+	Longitude   *float64   `json:"longitude,omitempty"`
+	Locked      *bool      `json:"locked,omitempty"`
+	TelemetryAt *time.Time `json:"telemetry_at,omitempty"`
+	// PredictedChargeLevel is the forecast charge level at the pickup time
+	// of a search; it is not stored
+	PredictedChargeLevel *float64 `json:"predicted_charge_level,omitempty"`
+	// EstimatedRangeKM is how far the vehicle can drive on its current or
+	// predicted charge; it is not stored
+	EstimatedRangeKM *float64 `json:"estimated_range_km,omitempty"`
+	// DistanceKM is the distance from the position of a nearby search; it
+	// is not stored
+	DistanceKM *float64 `json:"distance_km,omitempty"`
 }
 
-// GetAllVehicles retrieves all vehicles from the database
+// endOfTime bounds the queries for all future reservations; it is the
+// largest DATETIME MySQL stores
+var endOfTime = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// GetAvailableVehicles retrieves the vehicles that can be reserved now, that
+// is all but those Under Maintenance
 func GetAvailableVehicles() ([]Vehicle, error) {
-	query := `
-		SELECT 
-			vehicle_id, license_plate, model, charge_level, location, rental_rate, mileage, status, battery_capacity_kwh, reservation_status
-		FROM Vehicle
-		WHERE reservation_status = 'Available'
-	`
-
-	rows, err := config.DB.Query(query)
+	vehicles, err := repos.Vehicles.ListAvailable()
 	if err != nil {
-		log.Printf("Error querying available vehicles: %v\n", err)
 		return nil, err
 	}
-	defer rows.Close()
-
-	var vehicles []Vehicle
-	for rows.Next() {
-		var v Vehicle
-		if err := rows.Scan(&v.VehicleID, &v.LicensePlate, &v.Model, &v.ChargeLevel, &v.Location, &v.RentalRate, &v.Mileage, &v.Status, &v.BatteryCapacityKWH, &v.ReservationStatus); err != nil {
-			log.Printf("Error scanning vehicle: %v\n", err)
-			return nil, err
+	available := vehicles[:0]
+	for _, v := range vehicles {
+		if v.Status != VehicleUnderMaintenance {
+			available = append(available, v)
 		}
-		vehicles = append(vehicles, v)
 	}
+	return available, nil
+}
+
+// GetFleet retrieves the vehicles listed by GetAvailableVehicles together
+// with those Under Maintenance
+func GetFleet() ([]Vehicle, error) {
+	return repos.Vehicles.ListAvailable()
+}
+
+// GetVehicleByID fetches a single vehicle, or nil when it does not exist
+func GetVehicleByID(vehicleID int) (*Vehicle, error) {
+	return repos.Vehicles.FindByID(vehicleID)
+}
 
-	return vehicles, nil
+// CalendarEntry is a period in which a vehicle cannot be picked up
+type CalendarEntry struct {
+	// Kind is "reservation", "turnaround" for the cleaning and charging time
+	// after a reservation or a maintenance, "maintenance" for the planned
+	// window of an open work order or a vehicle Under Maintenance, or
+	// "decommissioned"
+	Kind          string    `json:"kind"`
+	ReservationID int       `json:"reservation_id,omitempty"`
+	WorkOrderID   int       `json:"work_order_id,omitempty"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
 }
 
-// IsVehicleAvailable checks if a vehicle is available for a specific time range
-func IsVehicleAvailable(vehicleID int, startTime, endTime time.Time) (bool, error) {
-	query := `
-		SELECT COUNT(*)
-		FROM Reservation
-		WHERE vehicle_id = ?
-		  AND ((start_time < ? AND end_time > ?)
-		    OR (start_time < ? AND end_time > ?))
-	`
-	var count int
-	err := config.DB.QueryRow(query, vehicleID, endTime, startTime, endTime, startTime).Scan(&count)
+// IsVehicleAvailable checks if a vehicle can be reserved for a specific time
+// range. It follows the calendar of the vehicle: neither the range nor the
+// turnaround after it may overlap an entry, so the range also has to start
+// once the turnaround after the previous reservation or maintenance is over.
+func IsVehicleAvailable(vehicle Vehicle, startTime, endTime time.Time, turnaround time.Duration) (bool, error) {
+	entries, err := GetVehicleCalendar(vehicle, startTime, endTime.Add(turnaround), turnaround)
 	if err != nil {
 		return false, err
 	}
-	return count == 0, nil
+	return len(entries) == 0, nil
 }
 
-// CreateReservation inserts a new reservation into the database
-func CreateReservation(reservation *Reservation) error {
-	query := `
-		INSERT INTO Reservation (vehicle_id, user_id, start_time, end_time, expected_charge_level, status)
-		VALUES (?, ?, ?, ?, ?, 'Active')
-	`
-	_, err := config.DB.Exec(query, reservation.VehicleID, reservation.UserID, reservation.StartTime, reservation.EndTime, reservation.ExpectedChargeLevel)
-	return err
+// GetVehiclesAvailableBetween returns the available vehicles that can be
+// reserved for [startTime, endTime), given the turnaround of each model
+func GetVehiclesAvailableBetween(startTime, endTime time.Time, turnaround func(model string) time.Duration) ([]Vehicle, error) {
+	vehicles, err := repos.Vehicles.ListAvailable()
+	if err != nil {
+		return nil, err
+	}
+	free := vehicles[:0]
+	for _, v := range vehicles {
+		available, err := IsVehicleAvailable(v, startTime, endTime, turnaround(v.Model))
+		if err != nil {
+			return nil, err
+		}
+		if available {
+			free = append(free, v)
+		}
+	}
+	return free, nil
 }
 
-// GetLatestReservationByUserID fetches the latest reservation for a given user
-func GetLatestReservationByUserID(userID int) (*Reservation, error) {
-	query := `
-		SELECT 
-    r.reservation_id,
-    r.vehicle_id,
-    r.user_id,
-    r.start_time,
-    r.end_time,
-    r.expected_charge_level,
-    r.status,
-    r.created_at,
-    v.rental_rate
-FROM Reservation r
-JOIN Vehicle v ON r.vehicle_id = v.vehicle_id
-WHERE r.user_id = ?
-ORDER BY r.created_at DESC
-LIMIT 1
-
-	`
-
-	var reservation Reservation
-	var startTimeStr, endTimeStr, createdAtStr string
-
-	err := config.DB.QueryRow(query, userID).Scan(
-		&reservation.ReservationID,
-		&reservation.VehicleID,
-		&reservation.UserID,
-		&startTimeStr,
-		&endTimeStr,
-		&reservation.ExpectedChargeLevel,
-		&reservation.Status,
-		&createdAtStr,
-		&reservation.RentalRate, // Include rental rate
-	)
+// GetVehicleCalendar returns the periods overlapping [from, to) in which a
+// vehicle cannot be picked up, ordered by start time: its Active reservations
+// and the planned windows of its open work orders, each with the turnaround
+// after it. A vehicle Under Maintenance is held until its work in progress is
+// done, or for the whole range when no work is in progress, and a
+// decommissioned vehicle for the whole range.
+func GetVehicleCalendar(vehicle Vehicle, from, to time.Time, turnaround time.Duration) ([]CalendarEntry, error) {
+	if vehicle.Status == VehicleDecommissioned {
+		return []CalendarEntry{{Kind: "decommissioned", StartTime: from, EndTime: to}}, nil
+	}
 
+	// A reservation that ended just before from may still be turning around
+	reservations, err := repos.Reservations.ListOverlapping(vehicle.VehicleID, from.Add(-turnaround), to)
 	if err != nil {
-		log.Printf("Error fetching latest reservation for user ID %d: %v", userID, err)
 		return nil, err
 	}
+	entries := []CalendarEntry{}
+	for _, res := range reservations {
+		// Cancelled and completed reservations no longer hold the vehicle
+		if res.Status != "Active" {
+			continue
+		}
+		if res.StartTime.Before(to) && res.EndTime.After(from) {
+			entries = append(entries, CalendarEntry{Kind: "reservation", ReservationID: res.ReservationID, StartTime: res.StartTime, EndTime: res.EndTime})
+		}
+		if end := res.EndTime.Add(turnaround); turnaround > 0 && res.EndTime.Before(to) && end.After(from) {
+			entries = append(entries, CalendarEntry{Kind: "turnaround", ReservationID: res.ReservationID, StartTime: res.EndTime, EndTime: end})
+		}
+	}
 
-	// Parse time strings
-	const layout = "2006-01-02 15:04:05"
-	reservation.StartTime, err = time.Parse(layout, startTimeStr)
+	orders, err := repos.WorkOrders.ListOpenOverlapping(vehicle.VehicleID, from.Add(-turnaround), to)
 	if err != nil {
-		log.Printf("Error parsing start_time for reservation: %v", err)
 		return nil, err
 	}
+	if vehicle.Status == VehicleUnderMaintenance {
+		inProgress, err := repos.WorkOrders.List(vehicle.VehicleID, WorkOrderInProgress)
+		if err != nil {
+			return nil, err
+		}
+		if len(inProgress) == 0 {
+			// Without work in progress nothing tells when the vehicle is back
+			entries = append(entries, CalendarEntry{Kind: "maintenance", StartTime: from, EndTime: to})
+		}
+		scheduled := orders[:0]
+		for _, order := range orders {
+			if order.Status != WorkOrderInProgress {
+				scheduled = append(scheduled, order)
+			}
+		}
+		orders = scheduled
+		for _, order := range inProgress {
+			// Work in progress holds the vehicle already, whatever its plan said
+			if order.PlannedStart.After(from) {
+				order.PlannedStart = from
+			}
+			orders = append(orders, order)
+		}
+	}
+	for _, order := range orders {
+		if order.PlannedStart.Before(to) && order.PlannedEnd.After(from) {
+			entries = append(entries, CalendarEntry{Kind: "maintenance", WorkOrderID: order.WorkOrderID, StartTime: order.PlannedStart, EndTime: order.PlannedEnd})
+		}
+		if end := order.PlannedEnd.Add(turnaround); turnaround > 0 && order.PlannedEnd.Before(to) && end.After(from) {
+			entries = append(entries, CalendarEntry{Kind: "turnaround", WorkOrderID: order.WorkOrderID, StartTime: order.PlannedEnd, EndTime: end})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].StartTime.Before(entries[j].StartTime) })
+	return entries, nil
+}
 
-	reservation.EndTime, err = time.Parse(layout, endTimeStr)
+// GetUpcomingReservations returns the Active reservations of a vehicle that
+// have not ended by now, ordered by start time
+func GetUpcomingReservations(vehicleID int, now time.Time) ([]Reservation, error) {
+	reservations, err := repos.Reservations.ListOverlapping(vehicleID, now, endOfTime)
 	if err != nil {
-		log.Printf("Error parsing end_time for reservation: %v", err)
 		return nil, err
 	}
+	active := reservations[:0]
+	for _, res := range reservations {
+		if res.Status == "Active" {
+			active = append(active, res)
+		}
+	}
+	return active, nil
+}
 
-	reservation.CreatedAt, err = time.Parse(layout, createdAtStr)
+// GetReservationsBetween returns the reservations of a vehicle that overlap
+// [from, to), ordered by start time
+func GetReservationsBetween(vehicleID int, from, to time.Time) ([]Reservation, error) {
+	return repos.Reservations.ListOverlapping(vehicleID, from, to)
+}
+
+// CountUpcomingReservations counts the reservations a user holds that have
+// not ended by now, which count against the booking limit of their tier
+func CountUpcomingReservations(userID int, now time.Time) (int, error) {
+	return repos.Reservations.CountUpcoming(userID, now)
+}
+
+// CreateReservation inserts a new reservation into the database
+func CreateReservation(reservation *Reservation) error {
+	reservation.Status = "Active"
+	return repos.Reservations.Create(reservation)
+}
+
+// GetReservationByID fetches a single reservation, or nil when it does not
+// exist
+func GetReservationByID(reservationID int) (*Reservation, error) {
+	return repos.Reservations.FindByID(reservationID)
+}
+
+// StationAt returns the station vehicle is parked at by t: the return
+// station of its last Active reservation ending by then, or its current
+// station
+func StationAt(vehicle Vehicle, t time.Time) (int, error) {
+	reservations, err := repos.Reservations.ListOverlapping(vehicle.VehicleID, time.Time{}, t)
+	if err != nil {
+		return 0, err
+	}
+	stationID := vehicle.StationID
+	for _, res := range reservations {
+		if res.Status == "Active" && !res.EndTime.After(t) {
+			stationID = res.ReturnStationID
+		}
+	}
+	return stationID, nil
+}
+
+// NextReservation returns the first Active reservation of a vehicle that
+// starts at or after t, or nil when there is none
+func NextReservation(vehicleID int, t time.Time) (*Reservation, error) {
+	reservations, err := repos.Reservations.ListOverlapping(vehicleID, t, endOfTime)
 	if err != nil {
-		log.Printf("Error parsing created_at for reservation: %v", err)
 		return nil, err
 	}
+	for _, res := range reservations {
+		if res.Status == "Active" && !res.StartTime.Before(t) {
+			return &res, nil
+		}
+	}
+	return nil, nil
+}
+
+// FreeSpacesFrom returns the fewest free parking spaces station has from t
+// on, given the vehicles parked there and the Active one-way reservations
+// leaving or arriving. It returns -1 for a station without a capacity.
+func FreeSpacesFrom(station Station, t time.Time) (int, error) {
+	if station.Capacity == 0 {
+		return -1, nil
+	}
+	parked, err := repos.Vehicles.CountAtStation(station.StationID)
+	if err != nil {
+		return 0, err
+	}
+	transfers, err := repos.Reservations.ListTransfers(station.StationID)
+	if err != nil {
+		return 0, err
+	}
 
-	return &reservation, nil
+	// A one-way reservation frees its space at its start and takes one at
+	// its end. The occupancy at t counts every change until then; each
+	// later change gives the occupancy from its time on.
+	type change struct {
+		at    time.Time
+		delta int
+	}
+	var changes []change
+	for _, res := range transfers {
+		if res.PickupStationID == station.StationID {
+			changes = append(changes, change{res.StartTime, -1})
+		}
+		if res.ReturnStationID == station.StationID {
+			changes = append(changes, change{res.EndTime, +1})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].at.Before(changes[j].at) })
+	occupied := parked
+	for _, c := range changes {
+		if !c.at.After(t) {
+			occupied += c.delta
+		}
+	}
+	peak := occupied
+	for _, c := range changes {
+		if c.at.After(t) {
+			occupied += c.delta
+			peak = max(peak, occupied)
+		}
+	}
+	return max(station.Capacity-peak, 0), nil
+}
+
+// CompleteReservation marks an Active reservation Completed at completedAt,
+// charges it outOfZoneFee and parks its vehicle at the return station
+func CompleteReservation(reservation *Reservation, completedAt time.Time, outOfZoneFee float64) error {
+	if err := repos.Reservations.Complete(reservation.ReservationID, completedAt, outOfZoneFee); err != nil {
+		return err
+	}
+	reservation.Status = "Completed"
+	reservation.CompletedAt = &completedAt
+	reservation.OutOfZoneFee = outOfZoneFee
+	return nil
+}
+
+// GetLatestReservationByUserID fetches the latest reservation for a given user.
+// It returns nil when the user has no reservations.
+func GetLatestReservationByUserID(userID int) (*Reservation, error) {
+	return repos.Reservations.LatestByUserID(userID)
 }
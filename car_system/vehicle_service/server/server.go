@@ -0,0 +1,167 @@
+package server
+
+import (
+	"car_system/common/apispec"
+	"car_system/common/deprecation"
+	"car_system/common/health"
+	"car_system/common/idempotency"
+	"car_system/common/logging"
+	"car_system/common/metrics"
+	"car_system/vehicle_service/api"
+	"car_system/vehicle_service/booking"
+	"car_system/vehicle_service/charging"
+	"car_system/vehicle_service/controllers"
+	"car_system/vehicle_service/geofence"
+	"car_system/vehicle_service/maintenance"
+	"car_system/vehicle_service/telemetry"
+	"car_system/vehicle_service/trip"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+)
+
+// spec is the OpenAPI document of vehicle_service, served at /openapi.json
+var spec = apispec.MustLoad(api.Spec)
+
+// Options holds the dependencies of a vehicle_service instance
+type Options struct {
+	Logger  *slog.Logger
+	Metrics *metrics.Metrics
+	// DB is nil when the service runs on the in-memory backend
+	DB        *sql.DB
+	StaticDir string
+	// CORSOrigins are the browser origins allowed to call the API
+	CORSOrigins []string
+	// ValidateRequests rejects requests that do not match api/openapi.json
+	ValidateRequests bool
+	// Legacy holds the dates announced by the unversioned legacy routes
+	Legacy deprecation.Policy
+	// Idempotency configures the replay of requests retried with the same
+	// Idempotency-Key; the keys are stored in DB when it is set
+	Idempotency idempotency.Settings
+	// Booking holds the rules new reservations must satisfy. The zero value
+	// only enforces the booking limits sent by user_service.
+	Booking booking.Policy
+	// Charging forecasts the charge level of vehicles at pickup; nil disables
+	// the forecast in the search and the check of expected charge levels
+	Charging *charging.Forecaster
+	// Range estimates how far vehicles can drive; nil disables the range in
+	// the search and the check of planned distances
+	Range *trip.Estimator
+	// Telemetry holds the secret the vehicle telemetry tokens are derived
+	// from; without one every report is refused
+	Telemetry telemetry.Settings
+	// Geofence decides what happens to vehicles returned outside their
+	// return zone; the zero value does not check returns
+	Geofence geofence.Policy
+	// Maintenance holds the service interval the vehicles due for service
+	// are derived from; the zero value reports none
+	Maintenance maintenance.Settings
+	// ServiceToken authenticates user_service, the only caller whose
+	// membership_tier and booking_limit are accepted on reservations
+	ServiceToken string
+	// AdminToken authenticates the admin routes that manage the geofences
+	// and the work orders; without one they refuse every request
+	AdminToken string
+	// Clock returns the current time; nil means the system clock. The fleet
+	// simulator sets it to run the service on a virtual clock.
+	Clock func() time.Time
+}
+
+// NewHandler builds the vehicle_service router wrapped in the CORS, logging
+// and metrics middleware
+func NewHandler(opts Options) http.Handler {
+	controllers.RegisterMetrics(opts.Metrics.Registerer)
+	controllers.UseBookingPolicy(opts.Booking)
+	controllers.UseChargeForecast(opts.Charging)
+	controllers.UseRangeEstimator(opts.Range)
+	controllers.UseTelemetry(opts.Telemetry)
+	controllers.UseGeofencePolicy(opts.Geofence)
+	controllers.UseMaintenance(opts.Maintenance)
+	controllers.UseAdminToken(opts.AdminToken)
+	controllers.UseServiceToken(opts.ServiceToken)
+	controllers.UseClock(opts.Clock)
+	router := newRouter(opts)
+
+	// Enable CORS for cross-origin requests
+	cors := handlers.CORS(
+		handlers.AllowedOrigins(opts.CORSOrigins),
+		handlers.AllowedMethods([]string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
+		handlers.AllowedHeaders([]string{"Content-Type", "Authorization", idempotency.KeyHeader}),
+		handlers.AllowCredentials(),
+	)
+
+	var handler http.Handler = router
+	if opts.ValidateRequests {
+		handler = spec.ValidateRequests(handler)
+	}
+	handler = idempotency.Middleware(idempotency.Options{
+		Store: idempotency.NewStore(opts.DB),
+		TTL:   opts.Idempotency.TTL,
+	})(handler)
+
+	return logging.Middleware(opts.Logger)(opts.Metrics.InstrumentRouter(router, cors(handler)))
+}
+
+// newRouter registers the API, probe, metrics and static routes
+func newRouter(opts Options) *mux.Router {
+	// Set up router
+	router := mux.NewRouter()
+
+	// Define API routes
+	router.HandleFunc("/v1/vehicles", controllers.GetAvailableVehicles).Methods("GET")
+	router.HandleFunc("/v1/vehicles/{id}", controllers.GetVehicleDetails).Methods("GET")
+	router.HandleFunc("/v1/vehicles/{id}/calendar", controllers.GetVehicleCalendar).Methods("GET")
+	router.HandleFunc("/v1/vehicles/{id}/telemetry", controllers.IngestTelemetry).Methods("POST")
+	router.HandleFunc("/v1/vehicles/{id}/telemetry", controllers.GetTelemetry).Methods("GET")
+	router.HandleFunc("/v1/stations", controllers.ListStations).Methods("GET")
+	router.HandleFunc("/v1/stations/nearby", controllers.FindNearbyStations).Methods("GET")
+	router.HandleFunc("/v1/geofences", controllers.ListGeofences).Methods("GET")
+	router.HandleFunc("/v1/geofences", controllers.CreateGeofence).Methods("POST")
+	router.HandleFunc("/v1/geofences/{id}", controllers.GetGeofence).Methods("GET")
+	router.HandleFunc("/v1/geofences/{id}", controllers.UpdateGeofence).Methods("PUT")
+	router.HandleFunc("/v1/geofences/{id}", controllers.DeleteGeofence).Methods("DELETE")
+	router.HandleFunc("/v1/work-orders", controllers.ListWorkOrders).Methods("GET")
+	router.HandleFunc("/v1/work-orders", controllers.CreateWorkOrder).Methods("POST")
+	router.HandleFunc("/v1/work-orders/{id}", controllers.GetWorkOrder).Methods("GET")
+	router.HandleFunc("/v1/work-orders/{id}", controllers.UpdateWorkOrder).Methods("PUT")
+	router.HandleFunc("/v1/work-orders/{id}/start", controllers.StartWorkOrder).Methods("POST")
+	router.HandleFunc("/v1/work-orders/{id}/complete", controllers.CompleteWorkOrder).Methods("POST")
+	router.HandleFunc("/v1/work-orders/{id}/cancel", controllers.CancelWorkOrder).Methods("POST")
+	router.HandleFunc("/v1/maintenance/due", controllers.GetServiceDue).Methods("GET")
+	router.HandleFunc("/v1/reservations", controllers.CreateReservation).Methods("POST")
+	router.HandleFunc("/v1/reservations/latest", controllers.GetLatestReservation).Methods("GET")
+	router.HandleFunc("/v1/reservations/{id}", controllers.GetReservation).Methods("GET")
+	router.HandleFunc("/v1/reservations/{id}/complete", controllers.CompleteReservation).Methods("POST")
+	router.HandleFunc("/v1/reservations/{id}/zone-exits", controllers.GetZoneExits).Methods("GET")
+
+	// Legacy aliases of the routes above, kept until the sunset date
+	legacy := deprecation.New(opts.Metrics.Registerer, opts.Legacy)
+	legacy.Handle(router, "/available-vehicles", "/v1/vehicles", controllers.GetAvailableVehicles).Methods("GET")
+	legacy.Handle(router, "/get-vehicle-details", "/v1/vehicles/{id}", controllers.GetVehicleDetails).Methods("GET")
+	legacy.Handle(router, "/create-reservation", "/v1/reservations", controllers.CreateReservation).Methods("POST")
+	legacy.Handle(router, "/latest-reservation", "/v1/reservations/latest", controllers.GetLatestReservation).Methods("GET")
+
+	// OpenAPI document
+	router.Handle("/openapi.json", spec.Handler()).Methods("GET")
+
+	// Prometheus metrics
+	router.Handle("/metrics", opts.Metrics.Handler()).Methods("GET")
+
+	// Liveness and readiness probes
+	checker := health.New("vehicle_service", 2*time.Second)
+	if opts.DB != nil {
+		checker.Add("database", health.DBCheck(opts.DB))
+	}
+	router.Handle("/healthz", checker.LiveHandler()).Methods("GET")
+	router.Handle("/readyz", checker.ReadyHandler()).Methods("GET")
+
+	// Serve static files
+	router.PathPrefix("/").Handler(http.StripPrefix("/", http.FileServer(http.Dir(opts.StaticDir))))
+
+	return router
+}
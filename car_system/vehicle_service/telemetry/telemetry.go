@@ -0,0 +1,94 @@
+// Package telemetry authenticates the vehicles reporting their state to
+// vehicle_service and keeps the stored readings within their retention.
+//
+// Every vehicle signs its reports with its own token, the HMAC-SHA256 of its
+// ID under TELEMETRY_SECRET, so a leaked token only lets its holder report
+// for one vehicle. Raw readings are kept for RawRetention and then replaced
+// by one rollup per DownsampleInterval, which is kept for Retention.
+package telemetry
+
+import (
+	"car_system/vehicle_service/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// Settings configures the ingestion and retention of telemetry
+type Settings struct {
+	Secret             string        `env:"TELEMETRY_SECRET" secret:"true" usage:"Key the per-vehicle telemetry tokens are derived from; telemetry is refused while it is empty"`
+	RawRetention       time.Duration `env:"TELEMETRY_RAW_RETENTION" default:"168h" usage:"How long raw telemetry readings are kept before they are downsampled"`
+	DownsampleInterval time.Duration `env:"TELEMETRY_DOWNSAMPLE_INTERVAL" default:"15m" usage:"Length of the intervals raw readings are rolled up into"`
+	Retention          time.Duration `env:"TELEMETRY_RETENTION" default:"2160h" usage:"How long rolled-up telemetry is kept"`
+	CompactEvery       time.Duration `env:"TELEMETRY_COMPACT_EVERY" default:"1h" usage:"How often expired telemetry is downsampled and deleted"`
+}
+
+// Validate checks the settings. The zero value is valid and keeps readings
+// until they are compacted by hand.
+func (s Settings) Validate() error {
+	switch {
+	case s.RawRetention < 0:
+		return fmt.Errorf("TELEMETRY_RAW_RETENTION must not be negative, got %s", s.RawRetention)
+	case s.DownsampleInterval < 0:
+		return fmt.Errorf("TELEMETRY_DOWNSAMPLE_INTERVAL must not be negative, got %s", s.DownsampleInterval)
+	case s.Retention < s.RawRetention:
+		return fmt.Errorf("TELEMETRY_RETENTION must not be shorter than TELEMETRY_RAW_RETENTION, got %s", s.Retention)
+	case s.CompactEvery < 0:
+		return fmt.Errorf("TELEMETRY_COMPACT_EVERY must not be negative, got %s", s.CompactEvery)
+	}
+	return nil
+}
+
+// TokenFor returns the token vehicleID authenticates its reports with
+func TokenFor(secret string, vehicleID int) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("vehicle:" + strconv.Itoa(vehicleID)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether token is the token of vehicleID. No token is valid
+// without a secret.
+func (s Settings) Verify(vehicleID int, token string) bool {
+	if s.Secret == "" || token == "" {
+		return false
+	}
+	return hmac.Equal([]byte(token), []byte(TokenFor(s.Secret, vehicleID)))
+}
+
+// Compact downsamples the raw readings older than RawRetention and deletes
+// the rollups older than Retention. Only whole intervals are rolled up, so a
+// rollup never needs to be merged with later readings.
+func (s Settings) Compact(now time.Time) (rolledUp, deleted int, err error) {
+	rawBefore := now.Add(-s.RawRetention).Truncate(s.DownsampleInterval)
+	return models.CompactTelemetry(rawBefore, now.Add(-s.Retention), s.DownsampleInterval)
+}
+
+// RunCompaction calls Compact every CompactEvery until ctx is done. It
+// returns at once when CompactEvery or the retention is zero.
+func (s Settings) RunCompaction(ctx context.Context, logger *slog.Logger) {
+	if s.CompactEvery <= 0 || s.RawRetention <= 0 || s.DownsampleInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.CompactEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			rolledUp, deleted, err := s.Compact(now)
+			if err != nil {
+				logger.Error("Telemetry compaction failed", "error", err)
+				continue
+			}
+			if rolledUp > 0 || deleted > 0 {
+				logger.Info("Telemetry compacted", "readings_rolled_up", rolledUp, "rollups_deleted", deleted)
+			}
+		}
+	}
+}
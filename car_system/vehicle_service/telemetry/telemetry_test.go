@@ -0,0 +1,89 @@
+package telemetry
+
+import (
+	"car_system/common/settings"
+	"car_system/vehicle_service/models"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	s := Settings{Secret: "fleet-secret"}
+	token := TokenFor(s.Secret, 1)
+
+	if !s.Verify(1, token) {
+		t.Error("the token of vehicle 1 was refused")
+	}
+	if s.Verify(2, token) {
+		t.Error("the token of vehicle 1 was accepted for vehicle 2")
+	}
+	if s.Verify(1, "") || (Settings{}).Verify(1, TokenFor("", 1)) {
+		t.Error("a token was accepted without a token or a secret")
+	}
+}
+
+func TestCompact(t *testing.T) {
+	store := models.NewMemoryStore()
+	store.SeedSampleFleet()
+	models.UseRepositories(store.Repositories())
+
+	now := time.Date(2030, 1, 8, 10, 7, 0, 0, time.UTC)
+	s := Settings{RawRetention: 7 * 24 * time.Hour, DownsampleInterval: 15 * time.Minute, Retention: 30 * 24 * time.Hour}
+	old := now.Add(-s.RawRetention - time.Hour).Truncate(time.Hour)
+	err := models.RecordTelemetry([]models.TelemetryReading{
+		{VehicleID: 1, RecordedAt: old.Add(-40 * 24 * time.Hour), ChargeLevel: 90},
+		{VehicleID: 1, RecordedAt: old.Add(1 * time.Minute), ChargeLevel: 80, SpeedKMH: 30},
+		{VehicleID: 1, RecordedAt: old.Add(5 * time.Minute), ChargeLevel: 79, SpeedKMH: 50},
+		{VehicleID: 1, RecordedAt: old.Add(20 * time.Minute), ChargeLevel: 78, SpeedKMH: 40},
+		// Still raw: its interval has not entirely expired
+		{VehicleID: 1, RecordedAt: now.Add(-s.RawRetention), ChargeLevel: 70},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rolledUp, deleted, err := s.Compact(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rolledUp != 4 || deleted != 1 {
+		t.Errorf("rolled up %d readings and deleted %d rollups, want 4 and 1", rolledUp, deleted)
+	}
+
+	readings, rollups, err := models.GetTelemetry(1, old.Add(-time.Hour), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(readings) != 1 || readings[0].ChargeLevel != 70 {
+		t.Errorf("raw readings = %+v, want the one at 70%%", readings)
+	}
+	if len(rollups) != 2 {
+		t.Fatalf("rollups = %+v, want two", rollups)
+	}
+	if r := rollups[0]; r.ReadingCount != 2 || r.ChargeLevel != 79 || r.AvgSpeedKMH != 40 || r.MaxSpeedKMH != 50 || !r.BucketEnd.Equal(old.Add(15*time.Minute)) {
+		t.Errorf("first rollup = %+v, want 2 readings ending at 79%%, 40 km/h on average and 50 at most", r)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	var s Settings
+	if _, err := settings.Load("test", &s, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if s.RawRetention != 7*24*time.Hour || s.DownsampleInterval != 15*time.Minute || s.Retention != 90*24*time.Hour {
+		t.Errorf("settings = %+v", s)
+	}
+
+	for _, bad := range []Settings{
+		{RawRetention: -time.Hour},
+		{RawRetention: 48 * time.Hour, Retention: 24 * time.Hour},
+		{CompactEvery: -time.Minute},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("%+v.Validate() succeeded, want an error", bad)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package trip estimates how far a vehicle can drive on its charge, so that
+// vehicle_service can tell renters which vehicles can make a planned trip.
+//
+// The range of a vehicle is the energy left in its battery, charge level
+// times battery_capacity_kwh, divided by the consumption of its model in kWh
+// per km. A trip is feasible when the range covers the distance plus the
+// safety margin.
+package trip
+
+import (
+	"car_system/vehicle_service/models"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CodeOutOfRange reports a reservation whose planned distance exceeds the
+// estimated range of the vehicle. It is a 409 like CHARGE_LEVEL_UNREACHABLE,
+// because it depends on the charge of the vehicle at pickup.
+const CodeOutOfRange = "TRIP_OUT_OF_RANGE"
+
+// What an Estimator does with a reservation whose planned distance exceeds
+// the estimated range
+const (
+	OutOfRangeReject = "reject"
+	OutOfRangeWarn   = "warn"
+	OutOfRangeIgnore = "off"
+)
+
+// Estimator estimates driving ranges
+type Estimator struct {
+	// KWhPerKM is the consumption of models without their own setting, and
+	// Models the consumption of individual models
+	KWhPerKM float64
+	Models   ModelConsumption
+	// SafetyMargin is the share of a trip's distance added as a reserve,
+	// e.g. 0.15 for 15%
+	SafetyMargin float64
+	// OutOfRange is OutOfRangeReject, OutOfRangeWarn or OutOfRangeIgnore
+	OutOfRange string
+}
+
+// ConsumptionOf returns the consumption of model in kWh per km
+func (e Estimator) ConsumptionOf(model string) float64 {
+	if c, ok := e.Models[model]; ok {
+		return c
+	}
+	return e.KWhPerKM
+}
+
+// RangeKM returns how many whole km v can drive from charge level percent. A
+// vehicle without a battery capacity or consumption has no estimated range.
+func (e Estimator) RangeKM(v models.Vehicle, level float64) float64 {
+	consumption := e.ConsumptionOf(v.Model)
+	if v.BatteryCapacityKWH <= 0 || consumption <= 0 {
+		return 0
+	}
+	return math.Floor(level / 100 * v.BatteryCapacityKWH / consumption)
+}
+
+// RequiredKM returns the range a trip of distance km needs with the margin
+func (e Estimator) RequiredKM(distance float64) float64 {
+	return math.Ceil(distance * (1 + e.SafetyMargin))
+}
+
+// CanDrive reports whether v can drive distance km from charge level percent
+func (e Estimator) CanDrive(v models.Vehicle, level, distance float64) bool {
+	return e.RangeKM(v, level) >= e.RequiredKM(distance)
+}
+
+// Shortfall describes a trip the vehicle cannot make
+func (e Estimator) Shortfall(rangeKM, distance float64) string {
+	return fmt.Sprintf("The vehicle is estimated to drive %g km at pickup; a %g km trip needs %g km with the %g%% safety margin",
+		rangeKM, distance, e.RequiredKM(distance), math.Round(e.SafetyMargin*100))
+}
+
+// ModelConsumption maps vehicle models to their consumption in kWh per km. As
+// text it is written "<model>=<kWh/km>" with models separated by ";", e.g.
+// "Tesla Model 3=0.15;Nissan Leaf=0.17".
+type ModelConsumption map[string]float64
+
+// UnmarshalText lets model consumption be loaded by common/settings
+func (m *ModelConsumption) UnmarshalText(text []byte) error {
+	parsed := ModelConsumption{}
+	for _, entry := range strings.Split(string(text), ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		model, value, ok := strings.Cut(entry, "=")
+		model = strings.TrimSpace(model)
+		if !ok || model == "" {
+			return fmt.Errorf("expected <model>=<kWh/km>, got %q", entry)
+		}
+		c, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || c <= 0 {
+			return fmt.Errorf("expected a consumption in kWh/km such as 0.15 for model %s, got %q", model, value)
+		}
+		parsed[model] = c
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalText is the inverse of UnmarshalText, with the models sorted
+func (m ModelConsumption) MarshalText() ([]byte, error) {
+	names := make([]string, 0, len(m))
+	for model := range m {
+		names = append(names, model)
+	}
+	sort.Strings(names)
+
+	entries := make([]string, 0, len(names))
+	for _, model := range names {
+		entries = append(entries, model+"="+strconv.FormatFloat(m[model], 'f', -1, 64))
+	}
+	return []byte(strings.Join(entries, ";")), nil
+}
+
+// Settings is the configuration of an Estimator. The default consumption of
+// each model of the sample fleet is its rated consumption.
+type Settings struct {
+	KWhPerKM     float64          `env:"RANGE_CONSUMPTION_KWH_PER_KM" default:"0.18" usage:"Consumption in kWh/km of models without their own setting"`
+	Models       ModelConsumption `env:"RANGE_CONSUMPTION_BY_MODEL" default:"BMW i3=0.16;Chevrolet Bolt=0.16;Hyundai Kona Electric=0.15;Nissan Leaf=0.17;Tesla Model 3=0.15" usage:"Per-model consumption in kWh/km, e.g. Tesla Model 3=0.15;Nissan Leaf=0.17"`
+	SafetyMargin float64          `env:"RANGE_SAFETY_MARGIN" default:"0.15" usage:"Share of a trip's distance kept as a reserve"`
+	OutOfRange   string           `env:"RANGE_OUT_OF_RANGE" default:"reject" usage:"What to do with a reservation whose planned distance exceeds the range (reject, warn or off)"`
+}
+
+// Estimator builds the estimator described by s
+func (s Settings) Estimator() (*Estimator, error) {
+	switch {
+	case s.KWhPerKM < 0:
+		return nil, fmt.Errorf("RANGE_CONSUMPTION_KWH_PER_KM must not be negative, got %g", s.KWhPerKM)
+	case s.SafetyMargin < 0 || s.SafetyMargin > 1:
+		return nil, fmt.Errorf("RANGE_SAFETY_MARGIN must be between 0 and 1, got %g", s.SafetyMargin)
+	}
+	switch s.OutOfRange {
+	case "":
+		s.OutOfRange = OutOfRangeIgnore
+	case OutOfRangeReject, OutOfRangeWarn, OutOfRangeIgnore:
+	default:
+		return nil, fmt.Errorf("RANGE_OUT_OF_RANGE must be reject, warn or off, got %q", s.OutOfRange)
+	}
+	return &Estimator{
+		KWhPerKM:     s.KWhPerKM,
+		Models:       s.Models,
+		SafetyMargin: s.SafetyMargin,
+		OutOfRange:   s.OutOfRange,
+	}, nil
+}
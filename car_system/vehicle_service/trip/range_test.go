@@ -0,0 +1,87 @@
+package trip
+
+import (
+	"car_system/common/settings"
+	"car_system/vehicle_service/models"
+	"testing"
+)
+
+var estimator = Estimator{
+	KWhPerKM:     0.2,
+	Models:       ModelConsumption{"Tesla Model 3": 0.15},
+	SafetyMargin: 0.15,
+	OutOfRange:   OutOfRangeReject,
+}
+
+var tesla = models.Vehicle{Model: "Tesla Model 3", ChargeLevel: 80, BatteryCapacityKWH: 75}
+
+func TestRangeKM(t *testing.T) {
+	other := models.Vehicle{Model: "Renault Zoe", BatteryCapacityKWH: 52}
+	unknownBattery := models.Vehicle{Model: "Tesla Model 3"}
+
+	for name, tc := range map[string]struct {
+		vehicle models.Vehicle
+		level   float64
+		want    float64
+	}{
+		"model consumption":        {tesla, 80, 400},
+		"default consumption":      {other, 50, 130},
+		"rounded down":             {tesla, 33, 165},
+		"empty":                    {tesla, 0, 0},
+		"unknown battery capacity": {unknownBattery, 100, 0},
+	} {
+		if got := estimator.RangeKM(tc.vehicle, tc.level); got != tc.want {
+			t.Errorf("%s: got %g km, want %g km", name, got, tc.want)
+		}
+	}
+
+	// 348 km need 400.2 km with the margin, one more than the range
+	if !estimator.CanDrive(tesla, 80, 347) || estimator.CanDrive(tesla, 80, 348) {
+		t.Errorf("RequiredKM(347) = %g, RequiredKM(348) = %g; want the range of 400 km to cover only the first", estimator.RequiredKM(347), estimator.RequiredKM(348))
+	}
+	if msg := estimator.Shortfall(400, 400); msg != "The vehicle is estimated to drive 400 km at pickup; a 400 km trip needs 460 km with the 15% safety margin" {
+		t.Errorf("message = %q", msg)
+	}
+}
+
+func TestModelConsumptionText(t *testing.T) {
+	var consumption ModelConsumption
+	if err := consumption.UnmarshalText([]byte("Tesla Model 3=0.15; Nissan Leaf = 0.17")); err != nil {
+		t.Fatal(err)
+	}
+	if consumption["Tesla Model 3"] != 0.15 || consumption["Nissan Leaf"] != 0.17 {
+		t.Errorf("parsed %+v", consumption)
+	}
+	if text, _ := consumption.MarshalText(); string(text) != "Nissan Leaf=0.17;Tesla Model 3=0.15" {
+		t.Errorf("MarshalText = %q", text)
+	}
+	for _, text := range []string{"Tesla Model 3", "=0.15", "Tesla Model 3=frugal", "Tesla Model 3=0"} {
+		if err := consumption.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func TestSettings(t *testing.T) {
+	var s Settings
+	if _, err := settings.Load("test", &s, nil); err != nil {
+		t.Fatal(err)
+	}
+	e, err := s.Estimator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.ConsumptionOf("Nissan Leaf") != 0.17 || e.ConsumptionOf("Renault Zoe") != 0.18 || e.SafetyMargin != 0.15 || e.OutOfRange != OutOfRangeReject {
+		t.Errorf("estimator = %+v", e)
+	}
+
+	for _, bad := range []Settings{
+		{KWhPerKM: -0.1},
+		{SafetyMargin: 2},
+		{OutOfRange: "sometimes"},
+	} {
+		if _, err := bad.Estimator(); err == nil {
+			t.Errorf("%+v.Estimator() succeeded, want an error", bad)
+		}
+	}
+}